@@ -0,0 +1,86 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// changeLogCacheSize is the maximum number of changelogs that are kept in the changeLogCache
+const changeLogCacheSize = 256
+
+// defaultChangeLogCacheTTL is how long a generated changelog is reused, unless --changelog-cache-ttl is set
+const defaultChangeLogCacheTTL = 10 * time.Minute
+
+// changeLogCacheKey identifies a changelog by the release pull specs it was requested for
+type changeLogCacheKey struct {
+	architecture string
+	fromPull     string
+	toPull       string
+	format       string
+}
+
+// changeLogCacheEntry is a generated changelog along with the digests, of the releases, it was generated from
+type changeLogCacheEntry struct {
+	fromDigest string
+	toDigest   string
+	out        string
+	expires    time.Time
+}
+
+// changeLogCache keeps the most recently generated changelogs, so that the expensive ReleaseInfo.ChangeLog call is not
+// repeated for every render of the same changelog.  An entry is only reused until its TTL expires and as long as the
+// releases still resolve to the digests that it was generated from.  A TTL of zero disables the cache.
+type changeLogCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	lock  sync.Mutex
+	cache *lru.Cache
+}
+
+func newChangeLogCache(ttl time.Duration) *changeLogCache {
+	cache, err := lru.New(changeLogCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return &changeLogCache{
+		ttl:   ttl,
+		now:   time.Now,
+		cache: cache,
+	}
+}
+
+// get returns the cached changelog if it has not expired and was generated from the same digests
+func (c *changeLogCache) get(key changeLogCacheKey, fromDigest, toDigest string) (string, bool) {
+	if c == nil || c.ttl <= 0 {
+		return "", false
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	obj, ok := c.cache.Get(key)
+	if !ok {
+		return "", false
+	}
+	entry := obj.(changeLogCacheEntry)
+	if entry.fromDigest != fromDigest || entry.toDigest != toDigest || !c.now().Before(entry.expires) {
+		c.cache.Remove(key)
+		return "", false
+	}
+	return entry.out, true
+}
+
+func (c *changeLogCache) add(key changeLogCacheKey, fromDigest, toDigest, out string) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.cache.Add(key, changeLogCacheEntry{
+		fromDigest: fromDigest,
+		toDigest:   toDigest,
+		out:        out,
+		expires:    c.now().Add(c.ttl),
+	})
+}
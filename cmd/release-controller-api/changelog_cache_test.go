@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fakeCountingReleaseInfo counts the generated changelogs, of the releases resolving to the digests
+type fakeCountingReleaseInfo struct {
+	fakeArchitectureReleaseInfo
+	digests    map[string]string
+	changeLogs int
+}
+
+func (r *fakeCountingReleaseInfo) ImageInfo(image, architecture string) (string, error) {
+	return fmt.Sprintf(`{"name":%q,"digest":"sha256:%s","config":{"architecture":%q}}`, image, r.digests[image], architecture), nil
+}
+
+func (r *fakeCountingReleaseInfo) ChangeLog(from, to string, json bool) (string, error) {
+	r.changeLogs++
+	return fmt.Sprintf("Changes from %s to %s", from, to), nil
+}
+
+func TestChangeLogCache(t *testing.T) {
+	const (
+		fromPull = "quay.io/openshift-release-dev/ocp-release:4.14.0"
+		toPull   = "quay.io/openshift-release-dev/ocp-release:4.14.1"
+	)
+
+	testCases := []struct {
+		name               string
+		ttl                time.Duration
+		elapsed            time.Duration
+		toDigest           string
+		expectedChangeLogs int
+	}{
+		{
+			name:               "CacheHit",
+			ttl:                10 * time.Minute,
+			elapsed:            5 * time.Minute,
+			toDigest:           "b",
+			expectedChangeLogs: 1,
+		},
+		{
+			name:               "TTLExpired",
+			ttl:                10 * time.Minute,
+			elapsed:            10 * time.Minute,
+			toDigest:           "b",
+			expectedChangeLogs: 2,
+		},
+		{
+			name:               "DigestChanged",
+			ttl:                10 * time.Minute,
+			elapsed:            time.Minute,
+			toDigest:           "c",
+			expectedChangeLogs: 2,
+		},
+		{
+			name:               "Disabled",
+			ttl:                0,
+			toDigest:           "b",
+			expectedChangeLogs: 2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Now()
+			cache := newChangeLogCache(tc.ttl)
+			cache.now = func() time.Time { return now }
+			releaseInfo := &fakeCountingReleaseInfo{digests: map[string]string{fromPull: "a", toPull: "b"}}
+			c := &Controller{releaseInfo: releaseInfo, architecture: "amd64", changeLogCache: cache}
+
+			ch := make(chan renderResult, 1)
+			c.getChangeLog(ch, fromPull, "4.14.0", toPull, "4.14.1", "markdown")
+			first := <-ch
+			if first.err != nil {
+				t.Fatalf("unexpected error: %v", first.err)
+			}
+
+			now = now.Add(tc.elapsed)
+			releaseInfo.digests[toPull] = tc.toDigest
+			c.getChangeLog(ch, fromPull, "4.14.0", toPull, "4.14.1", "markdown")
+			second := <-ch
+			if second.err != nil {
+				t.Fatalf("unexpected error: %v", second.err)
+			}
+
+			if releaseInfo.changeLogs != tc.expectedChangeLogs {
+				t.Errorf("expected %d changelogs to be generated, got %d", tc.expectedChangeLogs, releaseInfo.changeLogs)
+			}
+			if tc.expectedChangeLogs == 1 && second.out != first.out {
+				t.Errorf("expected the cached changelog %q, got %q", first.out, second.out)
+			}
+		})
+	}
+}
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"sync"
+	"text/template"
+	"time"
+
 	releasepayloadlister "github.com/openshift/release-controller/pkg/client/listers/release/v1alpha1"
 	releasecontroller "github.com/openshift/release-controller/pkg/release-controller"
 
 	lru "github.com/hashicorp/golang-lru"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	kv1core "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
@@ -64,6 +69,40 @@ type Controller struct {
 
 	releasePayloadNamespace string
 	releasePayloadLister    releasepayloadlister.ReleasePayloadLister
+
+	// changelogHTMLTemplate is used to render the html version of the changelog page
+	changelogHTMLTemplate *template.Template
+
+	// basePath is prepended to the internal links generated in the changelog
+	basePath string
+
+	// changelogRobotsTxt controls whether robots.txt disallows crawling of the changelog and api endpoints
+	changelogRobotsTxt bool
+
+	// stripCommitTrailers are the commit message trailer keys, lower cased, that are removed from the changelog
+	stripCommitTrailers sets.String
+
+	// changelogAllowedFormats are the formats that the changelog may be requested in
+	changelogAllowedFormats sets.String
+
+	// changeLogCache keeps the recently generated changelogs
+	changeLogCache *changeLogCache
+
+	// changelogSpinnerTimeout is how long a changelog has to be generated before the loading message is shown, and
+	// changelogRenderTimeout how long, after that, before giving up on it
+	changelogSpinnerTimeout time.Duration
+	changelogRenderTimeout  time.Duration
+
+	// changelogComponentDiffTimeout is how long, after a changelog has been generated, the component images that
+	// changed are waited for
+	changelogComponentDiffTimeout time.Duration
+
+	// imageInfoCache keeps the image infos of the release images pulled by digest, or by a tag of a release image stream
+	imageInfoCache *imageInfoCache
+
+	// streamStats are the statistics, of every release stream, that are periodically recomputed by syncStreamStats
+	streamStatsLock sync.RWMutex
+	streamStats     []releasecontroller.APIStreamStats
 }
 
 // NewController instantiates a Controller to manage release objects.
@@ -77,6 +116,15 @@ func NewController(
 	artSuffix string,
 	releasePayloadNamespace string,
 	releasePayloadLister releasepayloadlister.ReleasePayloadLister,
+	changelogHTMLTemplate *template.Template,
+	basePath string,
+	changelogRobotsTxt bool,
+	stripCommitTrailers []string,
+	changelogAllowedFormats sets.String,
+	changelogCacheTTL time.Duration,
+	changelogSpinnerTimeout time.Duration,
+	changelogRenderTimeout time.Duration,
+	changelogComponentDiffTimeout time.Duration,
 ) *Controller {
 	// log events at v2 and send them to the server
 	broadcaster := record.NewBroadcaster()
@@ -111,7 +159,25 @@ func NewController(
 
 		releasePayloadNamespace: releasePayloadNamespace,
 		releasePayloadLister:    releasePayloadLister,
+
+		changelogHTMLTemplate: changelogHTMLTemplate,
+
+		basePath: basePath,
+
+		changelogRobotsTxt: changelogRobotsTxt,
+
+		stripCommitTrailers: newCommitTrailerSet(stripCommitTrailers),
+
+		changelogAllowedFormats: changelogAllowedFormats,
+
+		changeLogCache: newChangeLogCache(changelogCacheTTL),
+
+		changelogSpinnerTimeout: changelogSpinnerTimeout,
+		changelogRenderTimeout:  changelogRenderTimeout,
+
+		changelogComponentDiffTimeout: changelogComponentDiffTimeout,
 	}
+	c.imageInfoCache = newImageInfoCache(c.resolveImageID)
 
 	c.dashboards = []Dashboard{
 		{"Index", "/"},
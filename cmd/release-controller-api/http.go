@@ -141,6 +141,7 @@ func (c *Controller) userInterfaceHandler() http.Handler {
 	mux.HandleFunc("/graph", c.graphHandler)
 	mux.HandleFunc("/changelog", c.httpReleaseChangelog)
 	mux.HandleFunc("/archive/graph", c.httpGraphSave)
+	mux.HandleFunc("/robots.txt", c.httpRobotsTxt)
 
 	mux.HandleFunc("/releasetag/{tag}/json", c.httpReleaseInfoJson)
 	mux.HandleFunc("/releasetag/{tag}", c.httpReleaseInfo)
@@ -164,6 +165,8 @@ func (c *Controller) userInterfaceHandler() http.Handler {
 	mux.HandleFunc("/api/v1/releasestreams/accepted", c.apiAcceptedStreams)
 	mux.HandleFunc("/api/v1/releasestreams/rejected", c.apiRejectedStreams)
 	mux.HandleFunc("/api/v1/releasestreams/all", c.apiAllStreams)
+	mux.HandleFunc("/api/v1/streams/stats", c.apiStreamStats)
+	mux.HandleFunc("/api/v1/changelog", c.handleChangeLogJSON)
 
 	mux.HandleFunc("/api/v1/features/{tag}", c.apiFeatureInfo)
 	mux.HandleFunc("/features/{tag}", c.httpFeatureInfo)
@@ -653,9 +656,7 @@ func (c *Controller) apiReleaseInfo(w http.ResponseWriter, req *http.Request) {
 		if renderHTML.err == nil {
 			result := blackfriday.Run([]byte(renderHTML.out))
 			// make our links targets
-			result = reInternalLink.ReplaceAllFunc(result, func(s []byte) []byte {
-				return []byte(`<a target="_blank" ` + string(bytes.TrimPrefix(s, []byte("<a "))))
-			})
+			result = c.transformChangeLogLinks(result)
 			changeLog = result
 		}
 		if renderJSON.err == nil {
@@ -696,15 +697,25 @@ func (c *Controller) changeLogWorker(result *renderResult, tagInfo *releaseTagIn
 
 	select {
 	case *result = <-ch:
-	case <-time.After(500 * time.Millisecond):
+	case <-time.After(c.changeLogSpinnerTimeout()):
 		select {
 		case *result = <-ch:
-		case <-time.After(15 * time.Second):
-			result.err = fmt.Errorf("the changelog is still loading, if this is the first access it may take several minutes to clone all repositories")
+		case <-time.After(c.changeLogRenderTimeout()):
+			result.err = errChangeLogStillLoading
 		}
 	}
 }
 
+// robotsTxt keeps crawlers away from the changelog and api endpoints, which are expensive to generate
+const robotsTxt = "User-agent: *\nDisallow: /changelog\nDisallow: /api/v1/\n"
+
+func (c *Controller) httpRobotsTxt(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	if c.changelogRobotsTxt {
+		fmt.Fprint(w, robotsTxt)
+	}
+}
+
 func (c *Controller) httpGraphSave(w http.ResponseWriter, req *http.Request) {
 	start := time.Now()
 	defer func() { klog.V(4).Infof("rendered in %s", time.Now().Sub(start)) }()
@@ -721,16 +732,22 @@ func (c *Controller) httpReleaseChangelog(w http.ResponseWriter, req *http.Reque
 	defer func() { klog.V(4).Infof("rendered in %s", time.Now().Sub(start)) }()
 
 	var isHtml, isJson bool
-	switch req.URL.Query().Get("format") {
+	format := req.URL.Query().Get("format")
+	switch format {
 	case "html":
 		isHtml = true
 	case "json":
 		isJson = true
 	case "markdown", "":
+		format = "markdown"
 	default:
 		http.Error(w, fmt.Sprintf("unrecognized format= string: html, json, markdown, empty accepted"), http.StatusBadRequest)
 		return
 	}
+	if !c.changeLogFormatAllowed(format) {
+		http.Error(w, fmt.Sprintf("the %s changelog format is not enabled", format), http.StatusBadRequest)
+		return
+	}
 
 	from := req.URL.Query().Get("from")
 	if len(from) == 0 {
@@ -743,39 +760,20 @@ func (c *Controller) httpReleaseChangelog(w http.ResponseWriter, req *http.Reque
 		return
 	}
 
-	tags, ok := c.findReleaseStreamTags(false, from, to)
-	if !ok {
-		for k, v := range tags {
-			if v == nil {
-				http.Error(w, fmt.Sprintf("could not find tag: %s", k), http.StatusBadRequest)
-				return
-			}
-		}
-	}
-
-	fromBase := tags[from].Release.Target.Status.PublicDockerImageRepository
-	if len(fromBase) == 0 {
-		http.Error(w, fmt.Sprintf("release target %s does not have a configured registry", tags[from].Release.Target.Name), http.StatusBadRequest)
-		return
-	}
-	toBase := tags[to].Release.Target.Status.PublicDockerImageRepository
-	if len(toBase) == 0 {
-		http.Error(w, fmt.Sprintf("release target %s does not have a configured registry", tags[to].Release.Target.Name), http.StatusBadRequest)
+	fromPull, toPull, err := c.changeLogPullSpecs(from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	out, err := c.releaseInfo.ChangeLog(fromBase+":"+from, toBase+":"+to, isJson)
+	out, err := c.releaseInfo.ChangeLog(fromPull, toPull, isJson)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Internal error\n%v", err), http.StatusInternalServerError)
 		return
 	}
 
 	if isHtml {
-		result := blackfriday.Run([]byte(out))
-		w.Header().Set("Content-Type", "text/html;charset=UTF-8")
-		fmt.Fprintf(w, htmlPageStart, template.HTMLEscapeString(fmt.Sprintf("Change log for %s", to)))
-		w.Write(result)
-		fmt.Fprintln(w, htmlPageEnd)
+		c.renderChangeLogHTMLPage(w, from, to, out)
 		return
 	}
 
@@ -789,6 +787,8 @@ func (c *Controller) httpReleaseChangelog(w http.ResponseWriter, req *http.Reque
 		} else if c.architecture == "arm64" {
 			architecture = "aarch64"
 			archExtension = fmt.Sprintf("-%s", architecture)
+		} else if c.architecture == "multi" {
+			architecture = c.architecture
 		} else {
 			architecture = c.architecture
 			archExtension = fmt.Sprintf("-%s", architecture)
@@ -2,36 +2,245 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/openshift/release-controller/pkg/rhcos"
 	"github.com/russross/blackfriday"
 	"net/http"
+	"path/filepath"
 	"regexp"
+	"strings"
+	"sync"
+	"text/template"
 	"time"
 
 	releasecontroller "github.com/openshift/release-controller/pkg/release-controller"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
 )
 
 var (
-	reInternalLink = regexp.MustCompile(`<a href="[^"]+">`)
+	reInternalLink = regexp.MustCompile(`<a href="([^"]+)">`)
+
+	// defaultChangelogHTMLTemplate is the built-in template used to render the changelog page when
+	// no --changelog-html-template has been provided
+	defaultChangelogHTMLTemplate = template.Must(template.New("changelog").Parse(fmt.Sprintf(htmlPageStart, "Change log for {{ html .ToTag }}") + "{{ .RenderedMarkdown }}\n" + htmlPageEnd))
+)
+
+// multiArchChangeLogArchitectures are the architectures, in order, that make up a "multiarch" changelog
+var multiArchChangeLogArchitectures = []string{"amd64", "arm64", "s390x", "ppc64le"}
+
+const (
+	// defaultChangeLogSpinnerTimeout is how long a changelog has to be generated before the loading message is shown,
+	// unless --changelog-spinner-timeout is set
+	defaultChangeLogSpinnerTimeout = 500 * time.Millisecond
+
+	// defaultChangeLogRenderTimeout is how long, after the loading message is shown, a changelog has to be generated
+	// before giving up on it, in favour of an error, unless --changelog-render-timeout is set
+	defaultChangeLogRenderTimeout = 15 * time.Second
+
+	// defaultChangeLogComponentDiffTimeout is how long, after a changelog has been generated, the component images
+	// that changed are waited for, unless --changelog-component-diff-timeout is set
+	defaultChangeLogComponentDiffTimeout = 2 * time.Second
 )
 
+// changeLogSpinnerTimeout returns how long a changelog has to be generated before the loading message is shown
+func (c *Controller) changeLogSpinnerTimeout() time.Duration {
+	if c.changelogSpinnerTimeout > 0 {
+		return c.changelogSpinnerTimeout
+	}
+	return defaultChangeLogSpinnerTimeout
+}
+
+// changeLogRenderTimeout returns how long, after the loading message is shown, a changelog has to be generated before
+// giving up on it
+func (c *Controller) changeLogRenderTimeout() time.Duration {
+	if c.changelogRenderTimeout > 0 {
+		return c.changelogRenderTimeout
+	}
+	return defaultChangeLogRenderTimeout
+}
+
+// changeLogComponentDiffTimeout returns how long, after a changelog has been generated, the component images that
+// changed are waited for
+func (c *Controller) changeLogComponentDiffTimeout() time.Duration {
+	if c.changelogComponentDiffTimeout > 0 {
+		return c.changelogComponentDiffTimeout
+	}
+	return defaultChangeLogComponentDiffTimeout
+}
+
+// changeLogTimeout returns how long a changelog has to be generated, in total, before giving up on it.  The
+// /api/v1/changelog endpoint, and "multiarch" changelogs, which show no loading message, wait this long as well.
+func (c *Controller) changeLogTimeout() time.Duration {
+	return c.changeLogSpinnerTimeout() + c.changeLogRenderTimeout()
+}
+
+// errChangeLogStillLoading is reported when a changelog is not generated within changeLogTimeout
+var errChangeLogStillLoading = fmt.Errorf("the changelog is still loading, if this is the first access it may take several minutes to clone all repositories")
+
+// changeLogFormats are the formats, requested with format=, that a changelog can be rendered in
+var changeLogFormats = sets.NewString("html", "json", "markdown", "multiarch")
+
+// newChangeLogFormatSet returns the set of changelog formats that may be requested.  An empty list allows every format.
+func newChangeLogFormatSet(formats []string) (sets.String, error) {
+	allowed := sets.NewString()
+	for _, format := range formats {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if len(format) == 0 {
+			continue
+		}
+		if !changeLogFormats.Has(format) {
+			return nil, fmt.Errorf("unrecognized changelog format %q, must be one of: %s", format, strings.Join(changeLogFormats.List(), ", "))
+		}
+		allowed.Insert(format)
+	}
+	if allowed.Len() == 0 {
+		return changeLogFormats, nil
+	}
+	return allowed, nil
+}
+
+// changeLogFormatAllowed returns true if the changelog may be rendered in the format
+func (c *Controller) changeLogFormatAllowed(format string) bool {
+	return c.changelogAllowedFormats.Has(format)
+}
+
 type renderResult struct {
 	out string
 	err error
+	// stage is the step, of generating the changelog, that the err occurred in
+	stage string
+}
+
+// The stages, of generating a changelog, that an error can be reported for
+const (
+	changeLogStageImageInfo = "imageInfo"
+	changeLogStageChangeLog = "changelog"
+	changeLogStageTransform = "transform"
+	changeLogStageTimeout   = "timeout"
+	changeLogStageDecode    = "decode"
+)
+
+// ChangeLogError is returned, as JSON, when a changelog requested with format=json could not be generated
+type ChangeLogError struct {
+	Error string `json:"error"`
+	Stage string `json:"stage"`
+}
+
+// MultiArchChangeLog is returned, as JSON, when the changelog of one or more architectures could not be generated
+type MultiArchChangeLog struct {
+	ChangeLog string            `json:"changeLog,omitempty"`
+	Errors    map[string]string `json:"errors"`
+}
+
+// withBasePath prefixes root-relative paths with basePath.  Absolute URLs, protocol-relative URLs, fragments and
+// paths that are already prefixed are returned unchanged.
+func withBasePath(basePath, path string) string {
+	switch {
+	case len(basePath) == 0,
+		!strings.HasPrefix(path, "/"),
+		strings.HasPrefix(path, "//"),
+		path == basePath,
+		strings.HasPrefix(path, basePath+"/"):
+		return path
+	}
+	return basePath + path
+}
+
+// transformChangeLogLinks makes all the links, in the rendered changelog, open in a new tab and prefixes
+// internal links with the configured base path
+func (c *Controller) transformChangeLogLinks(result []byte) []byte {
+	return reInternalLink.ReplaceAllFunc(result, func(s []byte) []byte {
+		href := string(reInternalLink.FindSubmatch(s)[1])
+		return []byte(fmt.Sprintf(`<a target="_blank" href="%s">`, withBasePath(c.basePath, href)))
+	})
+}
+
+// ChangelogTemplateData is the data passed to the template that renders the changelog page
+type ChangelogTemplateData struct {
+	FromTag          string
+	ToTag            string
+	RenderedMarkdown string
+	Timestamp        time.Time
+}
+
+// loadChangelogHTMLTemplate parses the text/template located at path.  If path is empty, the built-in
+// template is returned.
+func loadChangelogHTMLTemplate(path string) (*template.Template, error) {
+	if len(path) == 0 {
+		return defaultChangelogHTMLTemplate, nil
+	}
+	tmpl, err := template.New(filepath.Base(path)).ParseFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse changelog html template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+func (c *Controller) renderChangeLogHTMLPage(w http.ResponseWriter, fromTag, toTag, markdown string) {
+	tmpl := c.changelogHTMLTemplate
+	if tmpl == nil {
+		tmpl = defaultChangelogHTMLTemplate
+	}
+	data := ChangelogTemplateData{
+		FromTag:          fromTag,
+		ToTag:            toTag,
+		RenderedMarkdown: string(blackfriday.Run([]byte(markdown))),
+		Timestamp:        time.Now(),
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		http.Error(w, fmt.Sprintf("Internal error\n%v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html;charset=UTF-8")
+	w.Write(buf.Bytes())
+}
+
+// newCommitTrailerSet returns the set of lower cased trailer keys, since git matches trailer keys case-insensitively
+func newCommitTrailerSet(trailers []string) sets.String {
+	keys := sets.NewString()
+	for _, trailer := range trailers {
+		if key := strings.ToLower(strings.TrimSpace(trailer)); len(key) > 0 {
+			keys.Insert(key)
+		}
+	}
+	return keys
+}
+
+// stripCommitTrailers removes the lines, of the markdown changelog, that are commit message trailers (i.e.
+// "Signed-off-by: Name <email>") whose key is in trailers.  Trailers with any other key are preserved.
+func stripCommitTrailers(markdown string, trailers sets.String) string {
+	if trailers.Len() == 0 {
+		return markdown
+	}
+	lines := strings.Split(markdown, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if key, _, ok := strings.Cut(strings.TrimSpace(line), ":"); ok && trailers.Has(strings.ToLower(key)) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
 }
 
 func (c *Controller) getChangeLog(ch chan renderResult, fromPull string, fromTag string, toPull string, toTag string, format string) {
-	fromImage, err := releasecontroller.GetImageInfo(c.releaseInfo, c.architecture, fromPull)
+	c.getArchitectureChangeLog(ch, c.architecture, fromPull, fromTag, toPull, toTag, format)
+}
+
+func (c *Controller) getArchitectureChangeLog(ch chan renderResult, architecture string, fromPull string, fromTag string, toPull string, toTag string, format string) {
+	fromImage, err := c.imageInfoCache.getImageInfo(c.releaseInfo, architecture, fromPull)
 	if err != nil {
-		ch <- renderResult{err: err}
+		ch <- renderResult{err: err, stage: changeLogStageImageInfo}
 		return
 	}
 
-	toImage, err := releasecontroller.GetImageInfo(c.releaseInfo, c.architecture, toPull)
+	toImage, err := c.imageInfoCache.getImageInfo(c.releaseInfo, architecture, toPull)
 	if err != nil {
-		ch <- renderResult{err: err}
+		ch <- renderResult{err: err, stage: changeLogStageImageInfo}
 		return
 	}
 
@@ -41,23 +250,33 @@ func (c *Controller) getChangeLog(ch chan renderResult, fromPull string, fromTag
 		isJson = true
 	}
 
-	// Generate the change log from image digests
-	out, err := c.releaseInfo.ChangeLog(fromImage.GenerateDigestPullSpec(), toImage.GenerateDigestPullSpec(), isJson)
-	if err != nil {
-		ch <- renderResult{err: err}
-		return
+	// Generate the change log from image digests, unless it was recently generated from the same digests
+	cacheKey := changeLogCacheKey{architecture: architecture, fromPull: fromPull, toPull: toPull, format: format}
+	out, ok := c.changeLogCache.get(cacheKey, fromImage.Digest, toImage.Digest)
+	if !ok {
+		out, err = c.releaseInfo.ChangeLog(fromImage.GenerateDigestPullSpec(), toImage.GenerateDigestPullSpec(), isJson)
+		if err != nil {
+			ch <- renderResult{err: err, stage: changeLogStageChangeLog}
+			return
+		}
+		c.changeLogCache.add(cacheKey, fromImage.Digest, toImage.Digest, out)
 	}
 
-	// There is an inconsistency with what is returned from ReleaseInfo (amd64) and what
-	// needs to be passed into the RHCOS diff engine (x86_64).
-	var architecture, archExtension string
+	// There is an inconsistency with what is returned from ReleaseInfo (amd64, arm64) and what
+	// needs to be passed into the RHCOS diff engine (x86_64, aarch64).  The legacy RHCOS streams
+	// of every architecture, other than x86_64, are suffixed with the architecture (i.e. rhcos-4.10-s390x).  Manifest
+	// list images have no RHCOS stream of their own, so the stream is not suffixed and no diff is generated.
+	var archExtension string
 
-	if toImage.Config.Architecture == "amd64" {
+	switch toImage.Config.Architecture {
+	case "amd64":
 		architecture = "x86_64"
-	} else if toImage.Config.Architecture == "arm64" {
+	case "arm64":
 		architecture = "aarch64"
 		archExtension = fmt.Sprintf("-%s", architecture)
-	} else {
+	case "multi":
+		architecture = toImage.Config.Architecture
+	default:
 		architecture = toImage.Config.Architecture
 		archExtension = fmt.Sprintf("-%s", architecture)
 	}
@@ -65,21 +284,165 @@ func (c *Controller) getChangeLog(ch chan renderResult, fromPull string, fromTag
 	if isJson {
 		out, err = rhcos.TransformJsonOutput(out, architecture, archExtension)
 		if err != nil {
-			ch <- renderResult{err: err}
+			ch <- renderResult{err: err, stage: changeLogStageTransform}
 			return
 		}
 		ch <- renderResult{out: out}
+		return
 	}
 
-	out, err = rhcos.TransformMarkDownOutput(out, fromTag, toTag, architecture, archExtension)
+	out = stripCommitTrailers(out, c.stripCommitTrailers)
+
+	out, err = rhcos.TransformMarkDownOutput(out, fromTag, toTag, c.basePath, architecture, archExtension)
 	if err != nil {
-		ch <- renderResult{err: err}
+		ch <- renderResult{err: err, stage: changeLogStageTransform}
 		return
 	}
 	ch <- renderResult{out: out}
 }
 
+// changeLogPullSpecs returns the pull specs of the from and to release tags, or an error if either of them can not be
+// found
+func (c *Controller) changeLogPullSpecs(from, to string) (string, string, error) {
+	tags, ok := c.findReleaseStreamTags(false, from, to)
+	if !ok {
+		for _, tag := range []string{from, to} {
+			if tags[tag] == nil {
+				return "", "", fmt.Errorf("could not find tag: %s", tag)
+			}
+		}
+	}
+
+	fromBase := tags[from].Release.Target.Status.PublicDockerImageRepository
+	if len(fromBase) == 0 {
+		return "", "", fmt.Errorf("release target %s does not have a configured registry", tags[from].Release.Target.Name)
+	}
+	toBase := tags[to].Release.Target.Status.PublicDockerImageRepository
+	if len(toBase) == 0 {
+		return "", "", fmt.Errorf("release target %s does not have a configured registry", tags[to].Release.Target.Name)
+	}
+	return fromBase + ":" + from, toBase + ":" + to, nil
+}
+
+// handleChangeLogJSON serves the changelog, between the from and to release tags, as the raw ChangeLog JSON.  Unlike
+// the changelog rendered on the release pages, errors are reported with the matching HTTP status code.
+func (c *Controller) handleChangeLogJSON(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	defer func() { klog.V(4).Infof("rendered in %s", time.Now().Sub(start)) }()
+
+	if !c.changeLogFormatAllowed("json") {
+		http.Error(w, "the json changelog format is not enabled", http.StatusBadRequest)
+		return
+	}
+
+	from := req.URL.Query().Get("from")
+	if len(from) == 0 {
+		http.Error(w, "from must be set to a valid tag", http.StatusBadRequest)
+		return
+	}
+	to := req.URL.Query().Get("to")
+	if len(to) == 0 {
+		http.Error(w, "to must be set to a valid tag", http.StatusBadRequest)
+		return
+	}
+
+	fromPull, toPull, err := c.changeLogPullSpecs(from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// buffered, so that a changelog finishing after the timeout does not leak its goroutine
+	ch := make(chan renderResult, 1)
+	go c.getChangeLog(ch, fromPull, from, toPull, to, "json")
+
+	var render renderResult
+	select {
+	case render = <-ch:
+	case <-time.After(c.changeLogTimeout()):
+		http.Error(w, errChangeLogStillLoading.Error(), http.StatusGatewayTimeout)
+		return
+	}
+	if render.err != nil {
+		http.Error(w, fmt.Sprintf("Internal error\n%v", render.err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintln(w, render.out)
+}
+
+// getMultiArchChangeLog generates the markdown changelog of every architecture, in multiArchChangeLogArchitectures,
+// concurrently and stitches them together under a header per architecture.  The errors, of the architectures that
+// could not be generated before the context expired, are returned keyed by architecture.
+func (c *Controller) getMultiArchChangeLog(ctx context.Context, fromPull string, fromTag string, toPull string, toTag string) (string, map[string]string) {
+	results := make([]renderResult, len(multiArchChangeLogArchitectures))
+
+	var wg sync.WaitGroup
+	for i, architecture := range multiArchChangeLogArchitectures {
+		wg.Add(1)
+		go func(i int, architecture string) {
+			defer wg.Done()
+			// buffered, so that a changelog finishing after the timeout does not leak its goroutine
+			ch := make(chan renderResult, 1)
+			go c.getArchitectureChangeLog(ch, architecture, fromPull, fromTag, toPull, toTag, "markdown")
+			select {
+			case results[i] = <-ch:
+			case <-ctx.Done():
+				results[i] = renderResult{err: fmt.Errorf("the %s changelog did not complete in time: %w", architecture, ctx.Err())}
+			}
+		}(i, architecture)
+	}
+	wg.Wait()
+
+	var markdown strings.Builder
+	failures := make(map[string]string)
+	for i, architecture := range multiArchChangeLogArchitectures {
+		if results[i].err != nil {
+			failures[architecture] = results[i].err.Error()
+			continue
+		}
+		fmt.Fprintf(&markdown, "### %s\n\n%s\n\n", architecture, results[i].out)
+	}
+	return markdown.String(), failures
+}
+
+// renderMultiArchChangeLog renders the changelog of every architecture on a single page.  If only some of the
+// architectures could be generated, a 207 Multi-Status is returned with the errors and the partial changelog.
+func (c *Controller) renderMultiArchChangeLog(w http.ResponseWriter, fromPull string, fromTag string, toPull string, toTag string) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.changeLogTimeout())
+	defer cancel()
+
+	markdown, failures := c.getMultiArchChangeLog(ctx, fromPull, fromTag, toPull, toTag)
+	if len(failures) == 0 {
+		c.renderChangeLogHTMLPage(w, fromTag, toTag, markdown)
+		return
+	}
+
+	status := http.StatusMultiStatus
+	if len(failures) == len(multiArchChangeLogArchitectures) {
+		status = http.StatusInternalServerError
+	}
+	data, err := json.MarshalIndent(&MultiArchChangeLog{ChangeLog: markdown, Errors: failures}, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
 func (c *Controller) renderChangeLog(w http.ResponseWriter, fromPull string, fromTag string, toPull string, toTag string, format string) {
+	switch format {
+	case "multiarch":
+		c.renderMultiArchChangeLog(w, fromPull, fromTag, toPull, toTag)
+		return
+	case "json":
+		c.renderJSONChangeLog(w, fromPull, fromTag, toPull, toTag)
+		return
+	}
+
 	flusher, ok := w.(http.Flusher)
 	if !ok {
 		flusher = nopFlusher{}
@@ -92,44 +455,40 @@ func (c *Controller) renderChangeLog(w http.ResponseWriter, fromPull string, fro
 	// run the changelog in a goroutine because it may take significant time
 	go c.getChangeLog(ch, fromPull, fromTag, toPull, toTag, format)
 
+	// buffered, so that component image diffs finishing after the changelog do not leak their goroutine
+	diffCh := make(chan componentImageDiffResult, 1)
+	go c.getComponentImageDiffs(diffCh, fromPull, toPull)
+
 	var render renderResult
 	select {
 	case render = <-ch:
-	case <-time.After(500 * time.Millisecond):
+	case <-time.After(c.changeLogSpinnerTimeout()):
 		fmt.Fprintf(w, `<p id="loading" class="alert alert-info">Loading changelog, this may take a while ...</p>`)
 		flusher.Flush()
 		select {
 		case render = <-ch:
-		case <-time.After(15 * time.Second):
-			render.err = fmt.Errorf("the changelog is still loading, if this is the first access it may take several minutes to clone all repositories")
+		case <-time.After(c.changeLogRenderTimeout()):
+			render.err = errChangeLogStillLoading
 		}
 		fmt.Fprintf(w, `<style>#loading{display: none;}</style>`)
 		flusher.Flush()
 	}
 	if render.err == nil {
-		switch format {
-		case "json":
-			var changeLog releasecontroller.ChangeLog
-			err := json.Unmarshal([]byte(render.out), &changeLog)
-			if err != nil {
-				fmt.Fprintf(w, `<p class="alert alert-danger">%s</p>`, fmt.Sprintf("Unable to show full changelog: %s", err))
-				return
-			}
-			data, err := json.MarshalIndent(&changeLog, "", "  ")
-			if err != nil {
-				fmt.Fprintf(w, `<p class="alert alert-danger">%s</p>`, fmt.Sprintf("Unable to show full changelog: %s", err))
-				return
+		result := blackfriday.Run([]byte(render.out))
+		// make our links targets
+		result = c.transformChangeLogLinks(result)
+		w.Write(result)
+		// the component images are only listed alongside a full changelog, and are left out, rather than delaying it,
+		// if they are not known by the time it has been generated
+		select {
+		case diffs := <-diffCh:
+			if diffs.err != nil {
+				klog.V(4).Infof("Unable to determine the component images that changed from %s to %s: %v", fromPull, toPull, diffs.err)
+			} else if err := renderComponentImageDiffs(w, diffs.diffs); err != nil {
+				klog.Errorf("Unable to render the component images that changed from %s to %s: %v", fromPull, toPull, err)
 			}
-			fmt.Fprintf(w, "<pre><code>")
-			w.Write(data)
-			fmt.Fprintf(w, "</pre></code>")
-		default:
-			result := blackfriday.Run([]byte(render.out))
-			// make our links targets
-			result = reInternalLink.ReplaceAllFunc(result, func(s []byte) []byte {
-				return []byte(`<a target="_blank" ` + string(bytes.TrimPrefix(s, []byte("<a "))))
-			})
-			w.Write(result)
+		case <-time.After(c.changeLogComponentDiffTimeout()):
+			klog.V(4).Infof("Timed out determining the component images that changed from %s to %s", fromPull, toPull)
 		}
 		fmt.Fprintln(w, "<hr>")
 	} else {
@@ -137,3 +496,47 @@ func (c *Controller) renderChangeLog(w http.ResponseWriter, fromPull string, fro
 		fmt.Fprintf(w, `<p class="alert alert-danger">%s</p>`, fmt.Sprintf("Unable to show full changelog: %s", render.err))
 	}
 }
+
+// renderJSONChangeLog renders the changelog as JSON.  Nothing is written until the changelog has been generated, so
+// that a failure is reported as a ChangeLogError, with a 500, rather than in the middle of a partial response.
+func (c *Controller) renderJSONChangeLog(w http.ResponseWriter, fromPull string, fromTag string, toPull string, toTag string) {
+	// buffered, so that a changelog finishing after the timeout does not leak its goroutine
+	ch := make(chan renderResult, 1)
+	go c.getChangeLog(ch, fromPull, fromTag, toPull, toTag, "json")
+
+	var render renderResult
+	select {
+	case render = <-ch:
+	case <-time.After(c.changeLogTimeout()):
+		render = renderResult{err: errChangeLogStillLoading, stage: changeLogStageTimeout}
+	}
+	if render.err != nil {
+		writeChangeLogError(w, render.err, render.stage)
+		return
+	}
+
+	var changeLog releasecontroller.ChangeLog
+	if err := json.Unmarshal([]byte(render.out), &changeLog); err != nil {
+		writeChangeLogError(w, err, changeLogStageDecode)
+		return
+	}
+	data, err := json.MarshalIndent(&changeLog, "", "  ")
+	if err != nil {
+		writeChangeLogError(w, err, changeLogStageDecode)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// writeChangeLogError responds with a 500 and the error, and the stage it occurred in, as a JSON ChangeLogError
+func writeChangeLogError(w http.ResponseWriter, err error, stage string) {
+	data, marshalErr := json.Marshal(&ChangeLogError{Error: err.Error(), Stage: stage})
+	if marshalErr != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+	w.Write(data)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+)
+
+// componentImageDiff is a component image of a release that was added, removed or rebuilt, i.e. whose digest changed,
+// between two releases
+type componentImageDiff struct {
+	Name string
+	// From is the pull spec, by digest, of the component image in the older release.  Empty if it was added.
+	From string
+	// To is the pull spec, by digest, of the component image in the newer release.  Empty if it was removed.
+	To string
+}
+
+type componentImageDiffResult struct {
+	diffs []componentImageDiff
+	err   error
+}
+
+var componentImageDiffsTemplate = template.Must(template.New("componentImageDiffs").Funcs(template.FuncMap{
+	"digest":      imageDigest,
+	"registryURL": registryURL,
+}).Parse(`<details id="component-images">
+<summary>Component images ({{ len . }} changed)</summary>
+<table class="table table-condensed">
+<thead><tr><th>Component</th><th>Old digest</th><th>New digest</th></tr></thead>
+<tbody>
+{{ range . -}}
+<tr><td>{{ .Name }}</td><td>{{ if .From }}<a target="_blank" href="{{ registryURL .From }}"><code>{{ digest .From }}</code></a>{{ else }}<em>added</em>{{ end }}</td><td>{{ if .To }}<a target="_blank" href="{{ registryURL .To }}"><code>{{ digest .To }}</code></a>{{ else }}<em>removed</em>{{ end }}</td></tr>
+{{ end -}}
+</tbody>
+</table>
+</details>
+`))
+
+// getComponentImageDiffs sends the component images that changed between the release images
+func (c *Controller) getComponentImageDiffs(ch chan componentImageDiffResult, fromPull, toPull string) {
+	from, err := c.componentImages(fromPull)
+	if err != nil {
+		ch <- componentImageDiffResult{err: err}
+		return
+	}
+	to, err := c.componentImages(toPull)
+	if err != nil {
+		ch <- componentImageDiffResult{err: err}
+		return
+	}
+	ch <- componentImageDiffResult{diffs: diffComponentImages(from, to)}
+}
+
+// componentImages returns the pull specs, by component name, of the component images referenced by the release image
+func (c *Controller) componentImages(pullSpec string) (map[string]string, error) {
+	imageInfo, err := c.imageInfoCache.getImageInfo(c.releaseInfo, c.architecture, pullSpec)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine image info for %s: %v", pullSpec, err)
+	}
+	out, err := c.releaseInfo.ReleaseInfo(imageInfo.GenerateDigestPullSpec())
+	if err != nil {
+		return nil, fmt.Errorf("could not get release info for %s: %v", pullSpec, err)
+	}
+	var info releaseInfoShort
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		return nil, fmt.Errorf("could not unmarshal release info for %s: %v", pullSpec, err)
+	}
+	if info.References == nil {
+		return nil, fmt.Errorf("release info for %s has no image references", pullSpec)
+	}
+	images := make(map[string]string)
+	for _, tag := range info.References.Spec.Tags {
+		if tag.From != nil && tag.From.Kind == "DockerImage" {
+			images[tag.Name] = tag.From.Name
+		}
+	}
+	return images, nil
+}
+
+// diffComponentImages returns the component images, sorted by name, that differ between the releases
+func diffComponentImages(from, to map[string]string) []componentImageDiff {
+	var diffs []componentImageDiff
+	for name, fromPull := range from {
+		if toPull := to[name]; toPull != fromPull {
+			diffs = append(diffs, componentImageDiff{Name: name, From: fromPull, To: toPull})
+		}
+	}
+	for name, toPull := range to {
+		if _, ok := from[name]; !ok {
+			diffs = append(diffs, componentImageDiff{Name: name, To: toPull})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs
+}
+
+// renderComponentImageDiffs writes the changed component images as a collapsible table.  Nothing is written if no
+// component image changed.
+func renderComponentImageDiffs(w io.Writer, diffs []componentImageDiff) error {
+	if len(diffs) == 0 {
+		return nil
+	}
+	return componentImageDiffsTemplate.Execute(w, diffs)
+}
+
+// imageDigest returns the digest of the pull spec, or the pull spec itself if it is not by digest
+func imageDigest(pullSpec string) string {
+	if _, digest, ok := strings.Cut(pullSpec, "@"); ok {
+		return digest
+	}
+	return pullSpec
+}
+
+// registryURL returns the url, on the registry, of the image manifest the pull spec refers to
+func registryURL(pullSpec string) string {
+	repository, digest, ok := strings.Cut(pullSpec, "@")
+	if !ok {
+		return "https://" + pullSpec
+	}
+	host, path, ok := strings.Cut(repository, "/")
+	if !ok {
+		return "https://" + pullSpec
+	}
+	if host == "quay.io" {
+		return fmt.Sprintf("https://quay.io/repository/%s/manifest/%s", path, digest)
+	}
+	return fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, path, digest)
+}
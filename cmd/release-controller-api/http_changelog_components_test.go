@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeComponentsReleaseInfo serves the release info of the release images, by digest, from the testdata fixtures
+type fakeComponentsReleaseInfo struct {
+	fakeImageInfoCountingReleaseInfo
+	releaseInfos map[string]string
+}
+
+func newFakeComponentsReleaseInfo(t *testing.T) *fakeComponentsReleaseInfo {
+	releaseInfos := make(map[string]string)
+	for digest, fixture := range map[string]string{
+		"sha256:0": "testdata/release-info-4.14.0.json",
+		"sha256:1": "testdata/release-info-4.14.1.json",
+	} {
+		data, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Fatalf("unable to read %s: %v", fixture, err)
+		}
+		releaseInfos["quay.io/openshift-release-dev/ocp-release@"+digest] = string(data)
+	}
+	return &fakeComponentsReleaseInfo{
+		fakeImageInfoCountingReleaseInfo: fakeImageInfoCountingReleaseInfo{imageInfos: map[string]int{}},
+		releaseInfos:                     releaseInfos,
+	}
+}
+
+func (r *fakeComponentsReleaseInfo) ReleaseInfo(image string) (string, error) {
+	if out, ok := r.releaseInfos[image]; ok {
+		return out, nil
+	}
+	return "", fmt.Errorf("no release info for %s", image)
+}
+
+func componentImagePullSpec(digit string) string {
+	return "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:" + strings.Repeat(digit, 64)
+}
+
+func TestGetComponentImageDiffs(t *testing.T) {
+	c := &Controller{architecture: "amd64", releaseInfo: newFakeComponentsReleaseInfo(t)}
+
+	ch := make(chan componentImageDiffResult, 1)
+	c.getComponentImageDiffs(ch, "quay.io/openshift-release-dev/ocp-release:4.14.0", "quay.io/openshift-release-dev/ocp-release:4.14.1")
+	result := <-ch
+	if result.err != nil {
+		t.Fatalf("unexpected error: %v", result.err)
+	}
+
+	expected := []componentImageDiff{
+		{Name: "cluster-version-operator", From: componentImagePullSpec("2"), To: componentImagePullSpec("5")},
+		{Name: "installer", From: componentImagePullSpec("3"), To: componentImagePullSpec("6")},
+		{Name: "machine-os-images", To: componentImagePullSpec("7")},
+		{Name: "ovirt-csi-driver", From: componentImagePullSpec("4")},
+	}
+	if !reflect.DeepEqual(result.diffs, expected) {
+		t.Errorf("expected component image diffs:\n%v\ngot:\n%v", expected, result.diffs)
+	}
+
+	c.getComponentImageDiffs(ch, "quay.io/openshift-release-dev/ocp-release:4.14.0", "quay.io/openshift-release-dev/ocp-release:4.14.2")
+	if result := <-ch; result.err == nil {
+		t.Errorf("expected an error for a release without release info, got: %v", result.diffs)
+	}
+}
+
+func TestRenderChangeLogComponentImages(t *testing.T) {
+	testCases := []struct {
+		name     string
+		toPull   string
+		expected []string
+		excluded []string
+	}{
+		{
+			name:   "ComponentImagesChanged",
+			toPull: "quay.io/openshift-release-dev/ocp-release:4.14.1",
+			expected: []string{
+				`<details id="component-images">`,
+				`<summary>Component images (4 changed)</summary>`,
+				`<tr><td>cluster-version-operator</td><td><a target="_blank" href="https://quay.io/repository/openshift-release-dev/ocp-v4.0-art-dev/manifest/sha256:` + strings.Repeat("2", 64) + `"><code>sha256:` + strings.Repeat("2", 64) + `</code></a></td><td><a target="_blank" href="https://quay.io/repository/openshift-release-dev/ocp-v4.0-art-dev/manifest/sha256:` + strings.Repeat("5", 64) + `"><code>sha256:` + strings.Repeat("5", 64) + `</code></a></td></tr>`,
+				`<tr><td>machine-os-images</td><td><em>added</em></td>`,
+				`<tr><td>ovirt-csi-driver</td><td><a target="_blank" href="https://quay.io/repository/openshift-release-dev/ocp-v4.0-art-dev/manifest/sha256:` + strings.Repeat("4", 64) + `"><code>sha256:` + strings.Repeat("4", 64) + `</code></a></td><td><em>removed</em></td></tr>`,
+				"Changes from quay.io/openshift-release-dev/ocp-release@sha256:0",
+			},
+			excluded: []string{
+				"<tr><td>cli</td>",
+			},
+		},
+		{
+			name:   "NoReleaseInfo",
+			toPull: "quay.io/openshift-release-dev/ocp-release:4.14.2",
+			expected: []string{
+				"Changes from quay.io/openshift-release-dev/ocp-release@sha256:0",
+			},
+			excluded: []string{
+				`<details id="component-images">`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Controller{architecture: "amd64", releaseInfo: newFakeComponentsReleaseInfo(t)}
+
+			w := httptest.NewRecorder()
+			c.renderChangeLog(w, "quay.io/openshift-release-dev/ocp-release:4.14.0", "4.14.0", tc.toPull, "4.14.1", "markdown")
+			body := w.Body.String()
+
+			for _, expected := range tc.expected {
+				if !strings.Contains(body, expected) {
+					t.Errorf("expected the changelog to contain %q, got:\n%s", expected, body)
+				}
+			}
+			for _, excluded := range tc.excluded {
+				if strings.Contains(body, excluded) {
+					t.Errorf("expected the changelog to not contain %q, got:\n%s", excluded, body)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistryURL(t *testing.T) {
+	testCases := []struct {
+		pullSpec string
+		expected string
+	}{
+		{
+			pullSpec: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:1111",
+			expected: "https://quay.io/repository/openshift-release-dev/ocp-v4.0-art-dev/manifest/sha256:1111",
+		},
+		{
+			pullSpec: "registry.ci.openshift.org/ocp/4.14-2023-10-01-000000@sha256:2222",
+			expected: "https://registry.ci.openshift.org/v2/ocp/4.14-2023-10-01-000000/manifests/sha256:2222",
+		},
+		{
+			pullSpec: "registry.ci.openshift.org/ocp/release:4.14.0",
+			expected: "https://registry.ci.openshift.org/ocp/release:4.14.0",
+		},
+	}
+	for _, tc := range testCases {
+		if actual := registryURL(tc.pullSpec); actual != tc.expected {
+			t.Errorf("expected the registry url of %s to be %s, got %s", tc.pullSpec, tc.expected, actual)
+		}
+	}
+}
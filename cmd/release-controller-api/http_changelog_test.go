@@ -0,0 +1,805 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	imagev1 "github.com/openshift/api/image/v1"
+	imagelisters "github.com/openshift/client-go/image/listers/image/v1"
+	releasecontroller "github.com/openshift/release-controller/pkg/release-controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestLoadChangelogHTMLTemplate(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.tmpl")
+	if err := os.WriteFile(valid, []byte(`{{ .FromTag }}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.tmpl")
+	if err := os.WriteFile(invalid, []byte(`{{ .FromTag `), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name        string
+		path        string
+		wantDefault bool
+		wantErr     bool
+	}{
+		{
+			name:        "NoPathUsesDefault",
+			path:        "",
+			wantDefault: true,
+		},
+		{
+			name: "ValidTemplate",
+			path: valid,
+		},
+		{
+			name:    "InvalidTemplate",
+			path:    invalid,
+			wantErr: true,
+		},
+		{
+			name:    "MissingTemplate",
+			path:    filepath.Join(dir, "missing.tmpl"),
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl, err := loadChangelogHTMLTemplate(tc.path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error: %t, got: %v", tc.wantErr, err)
+			}
+			if tc.wantErr {
+				return
+			}
+			if (tmpl == defaultChangelogHTMLTemplate) != tc.wantDefault {
+				t.Errorf("expected default template: %t", tc.wantDefault)
+			}
+		})
+	}
+}
+
+func TestRenderChangeLogHTMLPage(t *testing.T) {
+	customTemplate := filepath.Join(t.TempDir(), "changelog.tmpl")
+	if err := os.WriteFile(customTemplate, []byte(`<h1>{{ .FromTag }} to {{ .ToTag }}</h1>{{ .RenderedMarkdown }}<footer>{{ .Timestamp.Year }}</footer>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	custom, err := loadChangelogHTMLTemplate(customTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name        string
+		template    string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "DefaultTemplate",
+			contains: []string{
+				"<title>Change log for 4.14.1</title>",
+				"<h2>Changes from 4.14.0</h2>",
+				"Source code for this page located on",
+			},
+		},
+		{
+			name:     "CustomTemplate",
+			template: customTemplate,
+			contains: []string{
+				"<h1>4.14.0 to 4.14.1</h1>",
+				"<h2>Changes from 4.14.0</h2>",
+				"<footer>",
+			},
+			notContains: []string{
+				"<title>",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Controller{}
+			if len(tc.template) > 0 {
+				c.changelogHTMLTemplate = custom
+			}
+			w := httptest.NewRecorder()
+			c.renderChangeLogHTMLPage(w, "4.14.0", "4.14.1", "## Changes from 4.14.0\n")
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != "text/html;charset=UTF-8" {
+				t.Errorf("unexpected Content-Type: %s", contentType)
+			}
+			body := w.Body.String()
+			for _, s := range tc.contains {
+				if !strings.Contains(body, s) {
+					t.Errorf("expected body to contain %q, got: %s", s, body)
+				}
+			}
+			for _, s := range tc.notContains {
+				if strings.Contains(body, s) {
+					t.Errorf("expected body not to contain %q, got: %s", s, body)
+				}
+			}
+		})
+	}
+}
+
+func TestTransformChangeLogLinks(t *testing.T) {
+	input := `<a href="/releasetag/4.14.0">4.14.0</a> <a href="/release-controller/releasetag/4.14.1">4.14.1</a> <a href="https://github.com/openshift/origin/pull/1">#1</a> <a href="//example.com/x">x</a>`
+	testCases := []struct {
+		name     string
+		basePath string
+		expected string
+	}{
+		{
+			name:     "NoBasePath",
+			basePath: "",
+			expected: `<a target="_blank" href="/releasetag/4.14.0">4.14.0</a> <a target="_blank" href="/release-controller/releasetag/4.14.1">4.14.1</a> <a target="_blank" href="https://github.com/openshift/origin/pull/1">#1</a> <a target="_blank" href="//example.com/x">x</a>`,
+		},
+		{
+			name:     "BasePath",
+			basePath: "/release-controller",
+			expected: `<a target="_blank" href="/release-controller/releasetag/4.14.0">4.14.0</a> <a target="_blank" href="/release-controller/releasetag/4.14.1">4.14.1</a> <a target="_blank" href="https://github.com/openshift/origin/pull/1">#1</a> <a target="_blank" href="//example.com/x">x</a>`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Controller{basePath: tc.basePath}
+			if got := string(c.transformChangeLogLinks([]byte(input))); got != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, got)
+			}
+		})
+	}
+}
+
+// fakeArchitectureReleaseInfo generates a changelog, per architecture, unless the architecture is set to fail
+type fakeArchitectureReleaseInfo struct {
+	failures map[string]bool
+}
+
+var _ releasecontroller.ReleaseInfo = &fakeArchitectureReleaseInfo{}
+
+func (r *fakeArchitectureReleaseInfo) Bugs(from, to string) ([]releasecontroller.BugDetails, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (r *fakeArchitectureReleaseInfo) ReleaseInfo(image string) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (r *fakeArchitectureReleaseInfo) UpgradeInfo(image string) (releasecontroller.ReleaseUpgradeInfo, error) {
+	return releasecontroller.ReleaseUpgradeInfo{}, fmt.Errorf("not implemented")
+}
+
+func (r *fakeArchitectureReleaseInfo) IssuesInfo(changelog string) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (r *fakeArchitectureReleaseInfo) GetFeatureChildren(featuresList []string, validityPeriod time.Duration) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (r *fakeArchitectureReleaseInfo) ImageInfo(image, architecture string) (string, error) {
+	if r.failures[architecture] {
+		return "", fmt.Errorf("no %s image found in %s", architecture, image)
+	}
+	return fmt.Sprintf(`{"name":%q,"digest":"sha256:%s","config":{"architecture":%q}}`, image, architecture, architecture), nil
+}
+
+func (r *fakeArchitectureReleaseInfo) ChangeLog(from, to string, json bool) (string, error) {
+	return fmt.Sprintf("Changes for %s", strings.SplitN(to, "@sha256:", 2)[1]), nil
+}
+
+func TestStripCommitTrailers(t *testing.T) {
+	commitMessage := `* Fix the upgrade graph pruning [#1234](https://github.com/openshift/release-controller/pull/1234)
+
+  Prune the edges of deleted releases.
+
+  Signed-off-by: Jane Doe <jdoe@example.com>
+  Co-authored-by: John Roe <jroe@example.com>
+  Reviewed-by: Alex Poe <apoe@example.com>
+`
+
+	testCases := []struct {
+		name     string
+		trailers []string
+		expected string
+	}{
+		{
+			name:     "NoTrailers",
+			expected: commitMessage,
+		},
+		{
+			name:     "SignedOffBy",
+			trailers: []string{"Signed-off-by"},
+			expected: `* Fix the upgrade graph pruning [#1234](https://github.com/openshift/release-controller/pull/1234)
+
+  Prune the edges of deleted releases.
+
+  Co-authored-by: John Roe <jroe@example.com>
+  Reviewed-by: Alex Poe <apoe@example.com>
+`,
+		},
+		{
+			name:     "CaseInsensitiveKeys",
+			trailers: []string{"signed-off-by", " CO-AUTHORED-BY "},
+			expected: `* Fix the upgrade graph pruning [#1234](https://github.com/openshift/release-controller/pull/1234)
+
+  Prune the edges of deleted releases.
+
+  Reviewed-by: Alex Poe <apoe@example.com>
+`,
+		},
+		{
+			name:     "UnknownTrailer",
+			trailers: []string{"Acked-by"},
+			expected: commitMessage,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := stripCommitTrailers(commitMessage, newCommitTrailerSet(tc.trailers)); actual != tc.expected {
+				t.Errorf("unexpected changelog: %s", cmp.Diff(tc.expected, actual))
+			}
+		})
+	}
+}
+
+func TestRenderMultiArchChangeLog(t *testing.T) {
+	testCases := []struct {
+		name           string
+		failures       map[string]bool
+		expectedStatus int
+		expectedErrors []string
+	}{
+		{
+			name:           "AllArchitecturesSucceed",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "SomeArchitecturesFail",
+			failures:       map[string]bool{"s390x": true, "ppc64le": true},
+			expectedStatus: http.StatusMultiStatus,
+			expectedErrors: []string{"ppc64le", "s390x"},
+		},
+		{
+			name:           "AllArchitecturesFail",
+			failures:       map[string]bool{"amd64": true, "arm64": true, "s390x": true, "ppc64le": true},
+			expectedStatus: http.StatusInternalServerError,
+			expectedErrors: []string{"amd64", "arm64", "ppc64le", "s390x"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Controller{releaseInfo: &fakeArchitectureReleaseInfo{failures: tc.failures}}
+
+			w := httptest.NewRecorder()
+			c.renderChangeLog(w, "quay.io/openshift-release-dev/ocp-release:4.14.0-multi", "4.14.0", "quay.io/openshift-release-dev/ocp-release:4.14.1-multi", "4.14.1", "multiarch")
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+
+			body := w.Body.String()
+			if tc.expectedStatus == http.StatusOK {
+				for _, architecture := range multiArchChangeLogArchitectures {
+					if !strings.Contains(body, fmt.Sprintf("<h3>%s</h3>", architecture)) || !strings.Contains(body, fmt.Sprintf("Changes for %s", architecture)) {
+						t.Errorf("expected the %s changelog in:\n%s", architecture, body)
+					}
+				}
+				return
+			}
+
+			if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("unexpected Content-Type: %s", contentType)
+			}
+			var result MultiArchChangeLog
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("unable to decode response: %v", err)
+			}
+			var errors []string
+			for architecture := range result.Errors {
+				errors = append(errors, architecture)
+			}
+			sort.Strings(errors)
+			if !cmp.Equal(errors, tc.expectedErrors) {
+				t.Errorf("expected errors for %v, got %v", tc.expectedErrors, errors)
+			}
+			for _, architecture := range multiArchChangeLogArchitectures {
+				if included := strings.Contains(result.ChangeLog, fmt.Sprintf("### %s\n", architecture)); included == tc.failures[architecture] {
+					t.Errorf("expected the %s changelog to be included: %t, got:\n%s", architecture, !tc.failures[architecture], result.ChangeLog)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderJSONChangeLogError(t *testing.T) {
+	testCases := []struct {
+		name          string
+		failures      map[string]bool
+		expectedStage string
+	}{
+		{
+			name:          "ImageInfoFails",
+			failures:      map[string]bool{"amd64": true},
+			expectedStage: changeLogStageImageInfo,
+		},
+		{
+			// The fake changelog is markdown, rather than JSON, so it cannot be transformed
+			name:          "TransformFails",
+			expectedStage: changeLogStageTransform,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Controller{architecture: "amd64", releaseInfo: &fakeArchitectureReleaseInfo{failures: tc.failures}}
+
+			w := httptest.NewRecorder()
+			c.renderChangeLog(w, "quay.io/openshift-release-dev/ocp-release:4.14.0-x86_64", "4.14.0", "quay.io/openshift-release-dev/ocp-release:4.14.1-x86_64", "4.14.1", "json")
+
+			if w.Code != http.StatusInternalServerError {
+				t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("unexpected Content-Type: %s", contentType)
+			}
+			var result ChangeLogError
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("unable to decode response %q: %v", w.Body.String(), err)
+			}
+			if result.Stage != tc.expectedStage {
+				t.Errorf("expected stage %q, got %q", tc.expectedStage, result.Stage)
+			}
+			if len(result.Error) == 0 {
+				t.Errorf("expected an error message")
+			}
+		})
+	}
+}
+
+func TestNewChangeLogFormatSet(t *testing.T) {
+	testCases := []struct {
+		name        string
+		formats     []string
+		expected    []string
+		expectedErr bool
+	}{
+		{
+			name:     "DefaultsToAllFormats",
+			expected: []string{"html", "json", "markdown", "multiarch"},
+		},
+		{
+			name:     "Subset",
+			formats:  []string{" JSON", "markdown", ""},
+			expected: []string{"json", "markdown"},
+		},
+		{
+			name:        "UnknownFormat",
+			formats:     []string{"json", "asciidoc"},
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			formats, err := newChangeLogFormatSet(tc.formats)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("expected error: %t, got: %v", tc.expectedErr, err)
+			}
+			if err == nil && !cmp.Equal(formats.List(), tc.expected) {
+				t.Errorf("expected formats %v, got %v", tc.expected, formats.List())
+			}
+		})
+	}
+}
+
+func TestChangeLogAllowedFormats(t *testing.T) {
+	allowed, err := newChangeLogFormatSet([]string{"html", "json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := &Controller{changelogAllowedFormats: allowed}
+
+	testCases := []struct {
+		name       string
+		url        string
+		disallowed bool
+	}{
+		{
+			name: "Allowed",
+			url:  "/changelog?format=json",
+		},
+		{
+			name:       "Disallowed",
+			url:        "/changelog?format=markdown",
+			disallowed: true,
+		},
+		{
+			name:       "DisallowedDefault",
+			url:        "/changelog",
+			disallowed: true,
+		},
+		{
+			name:       "DisallowedComparison",
+			url:        "/dashboards/compare?from=4.14.0&to=4.14.1&format=multiarch",
+			disallowed: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c.userInterfaceHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.url, nil))
+			if disallowed := strings.Contains(w.Body.String(), "changelog format is not enabled"); disallowed != tc.disallowed {
+				t.Fatalf("expected disallowed: %t, got: %d %s", tc.disallowed, w.Code, w.Body.String())
+			}
+			if tc.disallowed && w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
+
+// fakeRHCOSReleaseInfo generates a changelog, that upgrades RHCOS between two releases of the legacy streams, for the
+// architecture of the image
+type fakeRHCOSReleaseInfo struct {
+	fakeArchitectureReleaseInfo
+}
+
+func (r *fakeRHCOSReleaseInfo) ChangeLog(from, to string, json bool) (string, error) {
+	return "## Changes from 4.10.0\n\n* Red Hat Enterprise Linux CoreOS upgraded from 410.84.202205191234-0 to 410.84.202206011234-0\n", nil
+}
+
+func TestGetChangeLogRHCOSLinks(t *testing.T) {
+	testCases := []struct {
+		architecture   string
+		expectedArch   string
+		expectedStream string
+	}{
+		{
+			architecture:   "amd64",
+			expectedArch:   "x86_64",
+			expectedStream: "releases/rhcos-4.10",
+		},
+		{
+			architecture:   "arm64",
+			expectedArch:   "aarch64",
+			expectedStream: "releases/rhcos-4.10-aarch64",
+		},
+		{
+			architecture:   "s390x",
+			expectedArch:   "s390x",
+			expectedStream: "releases/rhcos-4.10-s390x",
+		},
+		{
+			architecture:   "ppc64le",
+			expectedArch:   "ppc64le",
+			expectedStream: "releases/rhcos-4.10-ppc64le",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.architecture, func(t *testing.T) {
+			c := &Controller{releaseInfo: &fakeRHCOSReleaseInfo{}, architecture: tc.architecture}
+
+			ch := make(chan renderResult, 1)
+			c.getChangeLog(ch, "quay.io/openshift-release-dev/ocp-release:4.10.0", "4.10.0", "quay.io/openshift-release-dev/ocp-release:4.10.1", "4.10.1", "markdown")
+			result := <-ch
+			if result.err != nil {
+				t.Fatalf("unexpected error: %v", result.err)
+			}
+
+			expected := []string{
+				fmt.Sprintf("https://releases-rhcos-art.apps.ocp-virt.prod.psi.redhat.com/?arch=%s&release=410.84.202205191234-0&stream=%s#410.84.202205191234-0", tc.expectedArch, url.QueryEscape(tc.expectedStream)),
+				fmt.Sprintf("https://releases-rhcos-art.apps.ocp-virt.prod.psi.redhat.com/?arch=%s&release=410.84.202206011234-0&stream=%s#410.84.202206011234-0", tc.expectedArch, url.QueryEscape(tc.expectedStream)),
+				fmt.Sprintf("https://releases-rhcos-art.apps.ocp-virt.prod.psi.redhat.com/diff.html?arch=%s&first_release=410.84.202205191234-0&first_stream=%s&second_release=410.84.202206011234-0&second_stream=%s", tc.expectedArch, url.QueryEscape(tc.expectedStream), url.QueryEscape(tc.expectedStream)),
+			}
+			for _, link := range expected {
+				if !strings.Contains(result.out, link) {
+					t.Errorf("expected %s in:\n%s", link, result.out)
+				}
+			}
+		})
+	}
+}
+
+// fakeJSONChangeLogReleaseInfo generates the JSON changelog, after the delay, or fails with the error
+type fakeJSONChangeLogReleaseInfo struct {
+	fakeArchitectureReleaseInfo
+	delay time.Duration
+	err   error
+}
+
+func (r *fakeJSONChangeLogReleaseInfo) ChangeLog(from, to string, json bool) (string, error) {
+	time.Sleep(r.delay)
+	if r.err != nil {
+		return "", r.err
+	}
+	return `{"from":{"name":"4.14.0"},"to":{"name":"4.14.1"},"components":[],"updatedImages":[{"name":"cli","path":"openshift/oc","shortCommit":"abc1234","commit":"abc1234"}]}`, nil
+}
+
+// newChangeLogReleaseLister returns a lister of a stable release stream, that contains the tags
+func newChangeLogReleaseLister(t *testing.T, tags ...string) *releasecontroller.MultiImageStreamLister {
+	t.Helper()
+	imageStream := &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "release",
+			Namespace:   "ocp",
+			Annotations: map[string]string{releasecontroller.ReleaseAnnotationConfig: `{"name":"4-stable","as":"Stable"}`},
+		},
+		Status: imagev1.ImageStreamStatus{
+			PublicDockerImageRepository: "quay.io/openshift-release-dev/ocp-release",
+		},
+	}
+	for _, tag := range tags {
+		imageStream.Spec.Tags = append(imageStream.Spec.Tags, imagev1.TagReference{
+			Name: tag,
+			Annotations: map[string]string{
+				releasecontroller.ReleaseAnnotationSource: "ocp/release",
+				releasecontroller.ReleaseAnnotationName:   "4-stable",
+			},
+		})
+		imageStream.Status.Tags = append(imageStream.Status.Tags, imagev1.NamedTagEventList{Tag: tag})
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := indexer.Add(imageStream); err != nil {
+		t.Fatalf("unable to add the image stream: %v", err)
+	}
+	return &releasecontroller.MultiImageStreamLister{Listers: map[string]imagelisters.ImageStreamNamespaceLister{
+		"ocp": imagelisters.NewImageStreamLister(indexer).ImageStreams("ocp"),
+	}}
+}
+
+func TestHandleChangeLogJSON(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		allowedFormats []string
+		releaseInfo    *fakeJSONChangeLogReleaseInfo
+		expectedStatus int
+		expectedImages []string
+	}{
+		{
+			name:           "ChangeLog",
+			query:          "from=4.14.0&to=4.14.1",
+			releaseInfo:    &fakeJSONChangeLogReleaseInfo{},
+			expectedStatus: http.StatusOK,
+			expectedImages: []string{"cli"},
+		},
+		{
+			name:           "MissingFrom",
+			query:          "to=4.14.1",
+			releaseInfo:    &fakeJSONChangeLogReleaseInfo{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "UnknownTag",
+			query:          "from=4.14.0&to=4.15.0",
+			releaseInfo:    &fakeJSONChangeLogReleaseInfo{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "FormatNotEnabled",
+			query:          "from=4.14.0&to=4.14.1",
+			allowedFormats: []string{"html", "markdown"},
+			releaseInfo:    &fakeJSONChangeLogReleaseInfo{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Timeout",
+			query:          "from=4.14.0&to=4.14.1",
+			releaseInfo:    &fakeJSONChangeLogReleaseInfo{delay: time.Second},
+			expectedStatus: http.StatusGatewayTimeout,
+		},
+		{
+			name:           "ChangeLogError",
+			query:          "from=4.14.0&to=4.14.1",
+			releaseInfo:    &fakeJSONChangeLogReleaseInfo{err: fmt.Errorf("unable to clone repository")},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			allowedFormats := tc.allowedFormats
+			if allowedFormats == nil {
+				allowedFormats = []string{"html", "json", "markdown"}
+			}
+			c := &Controller{
+				releaseInfo:             tc.releaseInfo,
+				releaseLister:           newChangeLogReleaseLister(t, "4.14.0", "4.14.1"),
+				architecture:            "amd64",
+				changelogAllowedFormats: sets.NewString(allowedFormats...),
+				changelogSpinnerTimeout: 50 * time.Millisecond,
+				changelogRenderTimeout:  50 * time.Millisecond,
+			}
+
+			w := httptest.NewRecorder()
+			c.handleChangeLogJSON(w, httptest.NewRequest(http.MethodGet, "/api/v1/changelog?"+tc.query, nil))
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+			if tc.expectedStatus != http.StatusOK {
+				if strings.Contains(w.Body.String(), "<p") {
+					t.Errorf("unexpected HTML in the error: %s", w.Body.String())
+				}
+				return
+			}
+
+			if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("unexpected Content-Type: %s", contentType)
+			}
+			var changeLog releasecontroller.ChangeLog
+			if err := json.Unmarshal(w.Body.Bytes(), &changeLog); err != nil {
+				t.Fatalf("unable to decode response: %v\n%s", err, w.Body.String())
+			}
+			var images []string
+			for _, image := range changeLog.UpdatedImages {
+				images = append(images, image.Name)
+			}
+			if !cmp.Equal(images, tc.expectedImages) {
+				t.Errorf("expected images %v, got %v", tc.expectedImages, images)
+			}
+		})
+	}
+}
+
+func TestGetChangeLogRHCOSLinksMultiArchitecture(t *testing.T) {
+	c := &Controller{releaseInfo: &fakeRHCOSReleaseInfo{}, architecture: "multi"}
+
+	ch := make(chan renderResult, 1)
+	c.getChangeLog(ch, "quay.io/openshift-release-dev/ocp-release:4.10.0", "4.10.0", "quay.io/openshift-release-dev/ocp-release:4.10.1", "4.10.1", "markdown")
+	result := <-ch
+	if result.err != nil {
+		t.Fatalf("unexpected error: %v", result.err)
+	}
+
+	stream := url.QueryEscape("releases/rhcos-4.10")
+	expected := fmt.Sprintf("* Red Hat Enterprise Linux CoreOS upgraded from [410.84.202205191234-0](https://releases-rhcos-art.apps.ocp-virt.prod.psi.redhat.com/?arch=x86_64&release=410.84.202205191234-0&stream=%s#410.84.202205191234-0) to [410.84.202206011234-0](https://releases-rhcos-art.apps.ocp-virt.prod.psi.redhat.com/?arch=x86_64&release=410.84.202206011234-0&stream=%s#410.84.202206011234-0)\n", stream, stream)
+	if !strings.Contains(result.out, expected) {
+		t.Errorf("expected %s in:\n%s", expected, result.out)
+	}
+	for _, malformed := range []string{"diff.html", "rhcos-4.10-multi", "arch=multi"} {
+		if strings.Contains(result.out, malformed) {
+			t.Errorf("unexpected %s in:\n%s", malformed, result.out)
+		}
+	}
+}
+
+// fakeSlowChangeLogReleaseInfo generates the markdown changelog after the delay
+type fakeSlowChangeLogReleaseInfo struct {
+	fakeArchitectureReleaseInfo
+	delay time.Duration
+}
+
+func (r *fakeSlowChangeLogReleaseInfo) ChangeLog(from, to string, json bool) (string, error) {
+	time.Sleep(r.delay)
+	return "## Changes from 4.14.0\n\n* Slowly generated changelog\n", nil
+}
+
+// timedResponseRecorder records when each part of the response was written, relative to its creation
+type timedResponseRecorder struct {
+	*httptest.ResponseRecorder
+	start  time.Time
+	writes map[string]time.Duration
+}
+
+func (w *timedResponseRecorder) Write(data []byte) (int, error) {
+	w.writes[string(data)] = time.Since(w.start)
+	return w.ResponseRecorder.Write(data)
+}
+
+// writtenAfter returns when the first part of the response containing s was written
+func (w *timedResponseRecorder) writtenAfter(s string) (time.Duration, bool) {
+	for data, elapsed := range w.writes {
+		if strings.Contains(data, s) {
+			return elapsed, true
+		}
+	}
+	return 0, false
+}
+
+func TestRenderChangeLogTimeouts(t *testing.T) {
+	const (
+		loadingMessage = "Loading changelog, this may take a while"
+		errorMessage   = "Unable to show full changelog: the changelog is still loading"
+		changeLog      = "Slowly generated changelog"
+	)
+
+	testCases := []struct {
+		name           string
+		delay          time.Duration
+		spinnerTimeout time.Duration
+		renderTimeout  time.Duration
+		expectLoading  bool
+		expectError    bool
+	}{
+		{
+			name:           "RenderedBeforeSpinner",
+			spinnerTimeout: time.Second,
+			renderTimeout:  time.Second,
+		},
+		{
+			name:           "RenderedAfterSpinner",
+			delay:          200 * time.Millisecond,
+			spinnerTimeout: 50 * time.Millisecond,
+			renderTimeout:  5 * time.Second,
+			expectLoading:  true,
+		},
+		{
+			name:           "RenderTimeout",
+			delay:          5 * time.Second,
+			spinnerTimeout: 50 * time.Millisecond,
+			renderTimeout:  100 * time.Millisecond,
+			expectLoading:  true,
+			expectError:    true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Controller{
+				architecture:            "amd64",
+				releaseInfo:             &fakeSlowChangeLogReleaseInfo{delay: tc.delay},
+				changelogSpinnerTimeout: tc.spinnerTimeout,
+				changelogRenderTimeout:  tc.renderTimeout,
+			}
+
+			w := &timedResponseRecorder{ResponseRecorder: httptest.NewRecorder(), start: time.Now(), writes: map[string]time.Duration{}}
+			c.renderChangeLog(w, "quay.io/openshift-release-dev/ocp-release:4.14.0", "4.14.0", "quay.io/openshift-release-dev/ocp-release:4.14.1", "4.14.1", "markdown")
+			body := w.Body.String()
+
+			loadingAfter, loading := w.writtenAfter(loadingMessage)
+			if loading != tc.expectLoading {
+				t.Fatalf("expected loading message: %t, got:\n%s", tc.expectLoading, body)
+			}
+			if loading && (loadingAfter < tc.spinnerTimeout || (tc.delay > 0 && loadingAfter >= tc.delay)) {
+				t.Errorf("expected the loading message after %s, before %s, got it after %s", tc.spinnerTimeout, tc.delay, loadingAfter)
+			}
+
+			errorAfter, failed := w.writtenAfter(errorMessage)
+			if failed != tc.expectError {
+				t.Fatalf("expected error message: %t, got:\n%s", tc.expectError, body)
+			}
+			if failed && errorAfter < tc.spinnerTimeout+tc.renderTimeout {
+				t.Errorf("expected the error message after %s, got it after %s", tc.spinnerTimeout+tc.renderTimeout, errorAfter)
+			}
+
+			if rendered := strings.Contains(body, changeLog); rendered == tc.expectError {
+				t.Errorf("expected changelog: %t, got:\n%s", !tc.expectError, body)
+			}
+		})
+	}
+}
+
+func TestChangeLogTimeoutDefaults(t *testing.T) {
+	c := &Controller{}
+	if timeout := c.changeLogTimeout(); timeout != defaultChangeLogSpinnerTimeout+defaultChangeLogRenderTimeout {
+		t.Errorf("expected a default timeout of %s, got %s", defaultChangeLogSpinnerTimeout+defaultChangeLogRenderTimeout, timeout)
+	}
+	if timeout := c.changeLogComponentDiffTimeout(); timeout != defaultChangeLogComponentDiffTimeout {
+		t.Errorf("expected a default component diff timeout of %s, got %s", defaultChangeLogComponentDiffTimeout, timeout)
+	}
+	c = &Controller{changelogSpinnerTimeout: time.Second, changelogRenderTimeout: time.Minute, changelogComponentDiffTimeout: 3 * time.Second}
+	if timeout := c.changeLogTimeout(); timeout != time.Second+time.Minute {
+		t.Errorf("expected a timeout of %s, got %s", time.Second+time.Minute, timeout)
+	}
+	if timeout := c.changeLogComponentDiffTimeout(); timeout != 3*time.Second {
+		t.Errorf("expected a component diff timeout of %s, got %s", 3*time.Second, timeout)
+	}
+}
@@ -65,6 +65,17 @@ func (c *Controller) httpDashboardCompare(w http.ResponseWriter, req *http.Reque
 	toRelease := req.URL.Query().Get("to")
 	format := req.URL.Query().Get("format")
 
+	// The changelog is rendered as html, unless another format was requested
+	if requested := format; len(requested) > 0 || (len(fromRelease) > 0 && len(toRelease) > 0) {
+		if len(requested) == 0 {
+			requested = "html"
+		}
+		if !c.changeLogFormatAllowed(requested) {
+			http.Error(w, fmt.Sprintf("the %s changelog format is not enabled", requested), http.StatusBadRequest)
+			return
+		}
+	}
+
 	fromComparison := &Comparison{
 		Type:     From,
 		Tag:      nil,
@@ -116,6 +127,13 @@ func (c *Controller) httpDashboardCompare(w http.ResponseWriter, req *http.Reque
 		}
 	}
 
+	// The multiarch, and json, changelogs are rendered on a page of their own, so that the response can report
+	// (partial) failures
+	if (format == "multiarch" || format == "json") && fromComparison.Tag != nil && toComparison.Tag != nil {
+		c.renderChangeLog(w, fromComparison.PullSpec, fromComparison.Tag.Name, toComparison.PullSpec, toComparison.Tag.Name, format)
+		return
+	}
+
 	fmt.Fprintf(w, htmlPageStart, "Release Comparison Dashboard")
 	defer func() { fmt.Fprintln(w, htmlPageEnd) }()
 
@@ -141,7 +159,7 @@ func (c *Controller) httpDashboardCompare(w http.ResponseWriter, req *http.Reque
 		fmt.Fprint(w, `&nbsp;&nbsp;<label for="to">To release:&nbsp;</label>`)
 		fmt.Fprintf(w, `<select id="to" class="form-control" name="to"><option value="" disabled selected>Select</option>%s</select>&nbsp;&nbsp;`, generateSelectOptions(page.Tags, toComparison))
 		fmt.Fprint(w, `&nbsp;&nbsp;<label for="format">Format:&nbsp;</label>`)
-		fmt.Fprintf(w, `<select id="format" class="form-control" name="format">Select</option>%s</select>&nbsp;&nbsp;`, generateFormatOptions(format))
+		fmt.Fprintf(w, `<select id="format" class="form-control" name="format">Select</option>%s</select>&nbsp;&nbsp;`, c.generateFormatOptions(format))
 		fmt.Fprintf(w, `<input class="btn btn-link" type="submit" value="Compare">`)
 		fmt.Fprint(w, `</form></p>`)
 	}
@@ -176,9 +194,12 @@ func generateSelectOptions(tags []*v1.TagReference, comp *Comparison) string {
 	return strings.Join(options, "")
 }
 
-func generateFormatOptions(format string) string {
+func (c *Controller) generateFormatOptions(format string) string {
 	var options []string
-	for _, f := range []string{"html", "json"} {
+	for _, f := range []string{"html", "json", "multiarch"} {
+		if !c.changeLogFormatAllowed(f) {
+			continue
+		}
 		selected := ""
 		if format == f {
 			selected = "selected"
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasecontroller "github.com/openshift/release-controller/pkg/release-controller"
+	releasepayloadhelpers "github.com/openshift/release-controller/pkg/releasepayload/v1alpha1helpers"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
+)
+
+const (
+	// streamStatsRefreshInterval is how often the release stream statistics are recomputed in the background
+	streamStatsRefreshInterval = 5 * time.Minute
+
+	// streamStatsAcceptanceWindow is the window over which the acceptance rate of a release stream is computed
+	streamStatsAcceptanceWindow = 7 * 24 * time.Hour
+)
+
+// computeStreamStats returns the statistics of every release stream, sorted by stream name
+func computeStreamStats(releasePayloads []*v1alpha1.ReleasePayload, now time.Time) []releasecontroller.APIStreamStats {
+	type streamCounts struct {
+		releasecontroller.APIStreamStats
+		recentAccepted  int
+		recentCompleted int
+	}
+	streams := make(map[string]*streamCounts)
+	for _, releasePayload := range releasePayloads {
+		name := releasepayloadhelpers.ReleasePayloadStream(releasePayload)
+		counts, ok := streams[name]
+		if !ok {
+			counts = &streamCounts{APIStreamStats: releasecontroller.APIStreamStats{Stream: name}}
+			streams[name] = counts
+		}
+
+		completed := true
+		switch {
+		case meta.IsStatusConditionTrue(releasePayload.Status.Conditions, v1alpha1.ConditionPayloadAccepted):
+			counts.Accepted++
+		case meta.IsStatusConditionTrue(releasePayload.Status.Conditions, v1alpha1.ConditionPayloadRejected):
+			counts.Rejected++
+		case meta.IsStatusConditionTrue(releasePayload.Status.Conditions, v1alpha1.ConditionPayloadFailed):
+			counts.Failed++
+		default:
+			counts.Pending++
+			completed = false
+		}
+
+		if completed && now.Sub(releasePayload.CreationTimestamp.Time) <= streamStatsAcceptanceWindow {
+			counts.recentCompleted++
+			if meta.IsStatusConditionTrue(releasePayload.Status.Conditions, v1alpha1.ConditionPayloadAccepted) {
+				counts.recentAccepted++
+			}
+		}
+	}
+
+	stats := make([]releasecontroller.APIStreamStats, 0, len(streams))
+	for _, counts := range streams {
+		if counts.recentCompleted > 0 {
+			counts.AcceptanceRate7d = float64(counts.recentAccepted) / float64(counts.recentCompleted)
+		}
+		stats = append(stats, counts.APIStreamStats)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Stream < stats[j].Stream
+	})
+	return stats
+}
+
+// refreshStreamStats recomputes the cached release stream statistics from the ReleasePayloads
+func (c *Controller) refreshStreamStats() error {
+	releasePayloads, err := c.releasePayloadLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("unable to list releasepayloads: %w", err)
+	}
+	stats := computeStreamStats(releasePayloads, time.Now())
+
+	c.streamStatsLock.Lock()
+	defer c.streamStatsLock.Unlock()
+	c.streamStats = stats
+	return nil
+}
+
+// syncStreamStats keeps the cached release stream statistics up to date until stopCh is closed
+func (c *Controller) syncStreamStats(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(streamStatsRefreshInterval)
+	defer ticker.Stop()
+	for {
+		if err := c.refreshStreamStats(); err != nil {
+			klog.Errorf("Unable to refresh the release stream statistics: %v", err)
+		}
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Controller) apiStreamStats(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	defer func() { klog.V(4).Infof("rendered in %s", time.Now().Sub(start)) }()
+
+	c.streamStatsLock.RLock()
+	stats := c.streamStats
+	c.streamStatsLock.RUnlock()
+
+	// The statistics have not been computed in the background yet
+	if stats == nil {
+		if err := c.refreshStreamStats(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		c.streamStatsLock.RLock()
+		stats = c.streamStats
+		c.streamStatsLock.RUnlock()
+	}
+
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+	fmt.Fprintln(w)
+}
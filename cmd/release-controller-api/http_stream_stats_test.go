@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadlister "github.com/openshift/release-controller/pkg/client/listers/release/v1alpha1"
+	releasecontroller "github.com/openshift/release-controller/pkg/release-controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newStreamStatsReleasePayload(name, imageStreamName string, created time.Time, conditions ...string) *v1alpha1.ReleasePayload {
+	releasePayload := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "ocp",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: v1alpha1.ReleasePayloadSpec{
+			PayloadCoordinates: v1alpha1.PayloadCoordinates{
+				Namespace:       "ocp",
+				ImagestreamName: imageStreamName,
+			},
+		},
+	}
+	for _, condition := range conditions {
+		releasePayload.Status.Conditions = append(releasePayload.Status.Conditions, metav1.Condition{Type: condition, Status: metav1.ConditionTrue})
+	}
+	return releasePayload
+}
+
+func TestComputeStreamStats(t *testing.T) {
+	now := time.Date(2022, 2, 20, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-24 * time.Hour)
+	old := now.Add(-10 * 24 * time.Hour)
+
+	testCases := []struct {
+		name            string
+		releasePayloads []*v1alpha1.ReleasePayload
+		expected        []releasecontroller.APIStreamStats
+	}{
+		{
+			name:     "NoReleasePayloads",
+			expected: []releasecontroller.APIStreamStats{},
+		},
+		{
+			name: "MultipleStreams",
+			releasePayloads: []*v1alpha1.ReleasePayload{
+				newStreamStatsReleasePayload("4.11.0-0.nightly-2022-02-19-091559", "release", recent, v1alpha1.ConditionPayloadAccepted),
+				newStreamStatsReleasePayload("4.11.0-0.nightly-2022-02-19-101559", "release", recent, v1alpha1.ConditionPayloadAccepted),
+				newStreamStatsReleasePayload("4.11.0-0.nightly-2022-02-19-111559", "release", recent, v1alpha1.ConditionPayloadRejected),
+				newStreamStatsReleasePayload("4.11.0-0.nightly-2022-02-19-121559", "release", recent, v1alpha1.ConditionPayloadFailed),
+				newStreamStatsReleasePayload("4.11.0-0.nightly-2022-02-19-131559", "release", recent),
+				// Outside of the acceptance window
+				newStreamStatsReleasePayload("4.11.0-0.nightly-2022-02-10-091559", "release", old, v1alpha1.ConditionPayloadRejected),
+				newStreamStatsReleasePayload("4.11.0-0.ci-2022-02-19-091559", "release", old, v1alpha1.ConditionPayloadAccepted),
+				newStreamStatsReleasePayload("4.11.1", "4-stable", recent, v1alpha1.ConditionPayloadAccepted),
+			},
+			expected: []releasecontroller.APIStreamStats{
+				{
+					Stream:           "4-stable",
+					Accepted:         1,
+					AcceptanceRate7d: 1,
+				},
+				{
+					Stream:   "4.11.0-0.ci",
+					Accepted: 1,
+				},
+				{
+					Stream:           "4.11.0-0.nightly",
+					Accepted:         2,
+					Rejected:         2,
+					Failed:           1,
+					Pending:          1,
+					AcceptanceRate7d: 0.5,
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if stats := computeStreamStats(tc.releasePayloads, now); !cmp.Equal(stats, tc.expected) {
+				t.Errorf("%s: %s", tc.name, cmp.Diff(tc.expected, stats))
+			}
+		})
+	}
+}
+
+func TestAPIStreamStats(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := indexer.Add(newStreamStatsReleasePayload("4.11.0-0.nightly-2022-02-19-091559", "release", time.Now(), v1alpha1.ConditionPayloadAccepted)); err != nil {
+		t.Fatalf("unable to populate the cache: %v", err)
+	}
+	c := &Controller{releasePayloadLister: releasepayloadlister.NewReleasePayloadLister(indexer)}
+
+	w := httptest.NewRecorder()
+	c.userInterfaceHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/streams/stats", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("unexpected Content-Type: %s", contentType)
+	}
+	var stats []releasecontroller.APIStreamStats
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("unable to decode response: %v", err)
+	}
+	expected := []releasecontroller.APIStreamStats{{Stream: "4.11.0-0.nightly", Accepted: 1, AcceptanceRate7d: 1}}
+	if !cmp.Equal(stats, expected) {
+		t.Errorf("%s", cmp.Diff(expected, stats))
+	}
+
+	// The cached statistics are served until they are refreshed
+	if err := indexer.Add(newStreamStatsReleasePayload("4.11.0-0.nightly-2022-02-19-101559", "release", time.Now(), v1alpha1.ConditionPayloadRejected)); err != nil {
+		t.Fatalf("unable to populate the cache: %v", err)
+	}
+	w = httptest.NewRecorder()
+	c.userInterfaceHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/streams/stats", nil))
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("unable to decode response: %v", err)
+	}
+	if !cmp.Equal(stats, expected) {
+		t.Errorf("expected the cached statistics: %s", cmp.Diff(expected, stats))
+	}
+
+	if err := c.refreshStreamStats(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w = httptest.NewRecorder()
+	c.userInterfaceHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/streams/stats", nil))
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("unable to decode response: %v", err)
+	}
+	expected = []releasecontroller.APIStreamStats{{Stream: "4.11.0-0.nightly", Accepted: 1, Rejected: 1, AcceptanceRate7d: 0.5}}
+	if !cmp.Equal(stats, expected) {
+		t.Errorf("expected the refreshed statistics: %s", cmp.Diff(expected, stats))
+	}
+}
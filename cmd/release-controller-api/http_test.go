@@ -1,6 +1,8 @@
 package main
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/blang/semver"
@@ -86,3 +88,38 @@ func TestNextMinor(t *testing.T) {
 		})
 	}
 }
+
+func TestHttpRobotsTxt(t *testing.T) {
+	testCases := []struct {
+		name               string
+		changelogRobotsTxt bool
+		expected           string
+	}{
+		{
+			name:               "Enabled",
+			changelogRobotsTxt: true,
+			expected:           "User-agent: *\nDisallow: /changelog\nDisallow: /api/v1/\n",
+		},
+		{
+			name:               "Disabled",
+			changelogRobotsTxt: false,
+			expected:           "",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Controller{changelogRobotsTxt: tc.changelogRobotsTxt}
+			w := httptest.NewRecorder()
+			c.userInterfaceHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/robots.txt", nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != "text/plain" {
+				t.Errorf("unexpected Content-Type: %s", contentType)
+			}
+			if body := w.Body.String(); body != tc.expected {
+				t.Errorf("expected body %q, got %q", tc.expected, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+	"sync"
+
+	releasecontroller "github.com/openshift/release-controller/pkg/release-controller"
+)
+
+// imageInfoCacheSize is the maximum number of image infos that are kept in the imageInfoCache
+const imageInfoCacheSize = 1000
+
+// imageInfoCacheKey identifies the image info of an architecture of a release image.  The imageID is the digest that
+// a pull spec, by tag, resolved to, so that the entry is no longer used once the tag is moved to another image.
+type imageInfoCacheKey struct {
+	architecture string
+	pullSpec     string
+	imageID      string
+}
+
+// imageInfoCache keeps the image infos of the release images, so that the registry is not queried again for every
+// changelog that compares the same releases.  Images pulled by digest never change, so the entries never expire, they
+// are only evicted, oldest first, once the cache is full.  Images pulled by tag are only cached if resolveImageID
+// resolves the tag to the digest of its image, since the tag may be moved to another image.
+type imageInfoCache struct {
+	entries sync.Map
+
+	// resolveImageID returns the digest of the image that a pull spec, by tag, points at, or an empty string if it is
+	// unknown
+	resolveImageID func(pullSpec string) string
+
+	// lock serializes the additions, so that the order of the keys matches the entries
+	lock sync.Mutex
+	// order holds the keys of the entries, oldest first
+	order []imageInfoCacheKey
+}
+
+func newImageInfoCache(resolveImageID func(pullSpec string) string) *imageInfoCache {
+	return &imageInfoCache{resolveImageID: resolveImageID}
+}
+
+// getImageInfo returns the image info, of the architecture, of the release image.  The image info is looked up, and
+// added to the cache, unless the release image, pulled by digest or by a tag that resolves to a digest, is already
+// cached.
+func (c *imageInfoCache) getImageInfo(releaseInfo releasecontroller.ReleaseInfo, architecture, pullSpec string) (*releasecontroller.ImageInfoConfig, error) {
+	if c == nil {
+		return releasecontroller.GetImageInfo(releaseInfo, architecture, pullSpec)
+	}
+	key := imageInfoCacheKey{architecture: architecture, pullSpec: pullSpec}
+	if !strings.Contains(pullSpec, "@sha256:") {
+		if c.resolveImageID != nil {
+			key.imageID = c.resolveImageID(pullSpec)
+		}
+		if len(key.imageID) == 0 {
+			return releasecontroller.GetImageInfo(releaseInfo, architecture, pullSpec)
+		}
+	}
+	if imageInfo, ok := c.entries.Load(key); ok {
+		return imageInfo.(*releasecontroller.ImageInfoConfig), nil
+	}
+	imageInfo, err := releasecontroller.GetImageInfo(releaseInfo, architecture, pullSpec)
+	if err != nil {
+		return nil, err
+	}
+	c.add(key, imageInfo)
+	return imageInfo, nil
+}
+
+func (c *imageInfoCache) add(key imageInfoCacheKey, imageInfo *releasecontroller.ImageInfoConfig) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, loaded := c.entries.LoadOrStore(key, imageInfo); loaded {
+		return
+	}
+	c.order = append(c.order, key)
+	for len(c.order) > imageInfoCacheSize {
+		c.entries.Delete(c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// resolveImageID returns the digest of the image that a public pull spec, by tag, of a release tag points at, according
+// to its release image stream.  An empty string is returned if the pull spec is not that of a release tag, or if the
+// image of the tag has not been imported yet.
+func (c *Controller) resolveImageID(pullSpec string) string {
+	i := strings.LastIndex(pullSpec, ":")
+	if i < 0 || strings.ContainsAny(pullSpec[i:], "/@") {
+		return ""
+	}
+	repository, tag := pullSpec[:i], pullSpec[i+1:]
+	tags, ok := c.findReleaseStreamTags(false, tag)
+	if !ok {
+		return ""
+	}
+	target := tags[tag].Release.Target
+	if target.Status.PublicDockerImageRepository != repository {
+		return ""
+	}
+	return releasecontroller.FindImageIDForTag(target, tag)
+}
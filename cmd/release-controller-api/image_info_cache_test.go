@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	"k8s.io/utils/pointer"
+)
+
+// fakeImageInfoCountingReleaseInfo counts the image info lookups of every image
+type fakeImageInfoCountingReleaseInfo struct {
+	fakeArchitectureReleaseInfo
+	imageInfos map[string]int
+}
+
+func (r *fakeImageInfoCountingReleaseInfo) ImageInfo(image, architecture string) (string, error) {
+	r.imageInfos[image]++
+	return fmt.Sprintf(`{"name":%q,"digest":"sha256:%s","config":{"architecture":%q}}`, image, image[len(image)-1:], architecture), nil
+}
+
+func (r *fakeImageInfoCountingReleaseInfo) ChangeLog(from, to string, json bool) (string, error) {
+	return fmt.Sprintf("Changes from %s to %s", from, to), nil
+}
+
+func TestImageInfoCache(t *testing.T) {
+	testCases := []struct {
+		name               string
+		fromPull           string
+		toPull             string
+		cache              *imageInfoCache
+		expectedImageInfos map[string]int
+	}{
+		{
+			name:     "ByDigest",
+			fromPull: "quay.io/openshift-release-dev/ocp-release@sha256:a",
+			toPull:   "quay.io/openshift-release-dev/ocp-release@sha256:b",
+			cache:    newImageInfoCache(nil),
+			expectedImageInfos: map[string]int{
+				"quay.io/openshift-release-dev/ocp-release@sha256:a": 1,
+				"quay.io/openshift-release-dev/ocp-release@sha256:b": 1,
+			},
+		},
+		{
+			name:     "ByTag",
+			fromPull: "quay.io/openshift-release-dev/ocp-release:4.14.0",
+			toPull:   "quay.io/openshift-release-dev/ocp-release:4.14.1",
+			cache: newImageInfoCache(func(pullSpec string) string {
+				return "sha256:" + pullSpec[len(pullSpec)-1:]
+			}),
+			expectedImageInfos: map[string]int{
+				"quay.io/openshift-release-dev/ocp-release:4.14.0": 1,
+				"quay.io/openshift-release-dev/ocp-release:4.14.1": 1,
+			},
+		},
+		{
+			name:     "ByUnresolvedTag",
+			fromPull: "quay.io/openshift-release-dev/ocp-release:4.14.0",
+			toPull:   "quay.io/openshift-release-dev/ocp-release:4.14.1",
+			cache: newImageInfoCache(func(pullSpec string) string {
+				return ""
+			}),
+			expectedImageInfos: map[string]int{
+				"quay.io/openshift-release-dev/ocp-release:4.14.0": 2,
+				"quay.io/openshift-release-dev/ocp-release:4.14.1": 2,
+			},
+		},
+		{
+			name:     "Disabled",
+			fromPull: "quay.io/openshift-release-dev/ocp-release@sha256:a",
+			toPull:   "quay.io/openshift-release-dev/ocp-release@sha256:b",
+			expectedImageInfos: map[string]int{
+				"quay.io/openshift-release-dev/ocp-release@sha256:a": 2,
+				"quay.io/openshift-release-dev/ocp-release@sha256:b": 2,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			releaseInfo := &fakeImageInfoCountingReleaseInfo{imageInfos: map[string]int{}}
+			c := &Controller{releaseInfo: releaseInfo, architecture: "amd64", imageInfoCache: tc.cache}
+
+			ch := make(chan renderResult, 1)
+			for i := 0; i < 2; i++ {
+				c.getChangeLog(ch, tc.fromPull, "4.14.0", tc.toPull, "4.14.1", "markdown")
+				if result := <-ch; result.err != nil {
+					t.Fatalf("unexpected error: %v", result.err)
+				}
+			}
+
+			if len(releaseInfo.imageInfos) != len(tc.expectedImageInfos) {
+				t.Fatalf("expected image infos of %v, got %v", tc.expectedImageInfos, releaseInfo.imageInfos)
+			}
+			for image, expected := range tc.expectedImageInfos {
+				if releaseInfo.imageInfos[image] != expected {
+					t.Errorf("expected %d image info lookups of %s, got %d", expected, image, releaseInfo.imageInfos[image])
+				}
+			}
+		})
+	}
+}
+
+func TestImageInfoCacheEviction(t *testing.T) {
+	releaseInfo := &fakeImageInfoCountingReleaseInfo{imageInfos: map[string]int{}}
+	cache := newImageInfoCache(nil)
+
+	pullSpec := func(i int) string {
+		return fmt.Sprintf("quay.io/openshift-release-dev/ocp-release@sha256:%d", i)
+	}
+	for i := 0; i <= imageInfoCacheSize; i++ {
+		if _, err := cache.getImageInfo(releaseInfo, "amd64", pullSpec(i)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(cache.order) != imageInfoCacheSize {
+		t.Errorf("expected %d cached image infos, got %d", imageInfoCacheSize, len(cache.order))
+	}
+
+	// The oldest image info was evicted, the newest one is still cached
+	for _, i := range []int{0, imageInfoCacheSize} {
+		if _, err := cache.getImageInfo(releaseInfo, "amd64", pullSpec(i)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if lookups := releaseInfo.imageInfos[pullSpec(0)]; lookups != 2 {
+		t.Errorf("expected the evicted image info to be looked up again, got %d lookups", lookups)
+	}
+	if lookups := releaseInfo.imageInfos[pullSpec(imageInfoCacheSize)]; lookups != 1 {
+		t.Errorf("expected the cached image info to be reused, got %d lookups", lookups)
+	}
+}
+
+func TestImageInfoCacheTagMoved(t *testing.T) {
+	releaseInfo := &fakeImageInfoCountingReleaseInfo{imageInfos: map[string]int{}}
+	imageID := "sha256:a"
+	cache := newImageInfoCache(func(pullSpec string) string {
+		return imageID
+	})
+
+	pullSpec := "quay.io/openshift-release-dev/ocp-release:4.14.0"
+	for _, id := range []string{"sha256:a", "sha256:a", "sha256:b"} {
+		imageID = id
+		if _, err := cache.getImageInfo(releaseInfo, "amd64", pullSpec); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// The image info is looked up again once the tag was moved to another image
+	if lookups := releaseInfo.imageInfos[pullSpec]; lookups != 2 {
+		t.Errorf("expected 2 image info lookups of %s, got %d", pullSpec, lookups)
+	}
+}
+
+func TestResolveImageID(t *testing.T) {
+	c := &Controller{releaseLister: newChangeLogReleaseLister(t, "4.14.0", "4.14.1")}
+	imageStream, err := c.releaseLister.ImageStreams("ocp").Get("release")
+	if err != nil {
+		t.Fatalf("unable to get the image stream: %v", err)
+	}
+	// 4.14.0 has been imported, 4.14.1 has not
+	imageStream.Spec.Tags[0].Generation = pointer.Int64(1)
+	imageStream.Status.Tags[0].Items = []imagev1.TagEvent{{Image: "sha256:a", Generation: 1}}
+
+	testCases := []struct {
+		name     string
+		pullSpec string
+		expected string
+	}{
+		{
+			name:     "Imported",
+			pullSpec: "quay.io/openshift-release-dev/ocp-release:4.14.0",
+			expected: "sha256:a",
+		},
+		{
+			name:     "NotImported",
+			pullSpec: "quay.io/openshift-release-dev/ocp-release:4.14.1",
+		},
+		{
+			name:     "OtherRepository",
+			pullSpec: "registry.ci.openshift.org/ocp/release:4.14.0",
+		},
+		{
+			name:     "UnknownTag",
+			pullSpec: "quay.io/openshift-release-dev/ocp-release:4.15.0",
+		},
+		{
+			name:     "NoTag",
+			pullSpec: "localhost:5000/ocp-release",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if imageID := c.resolveImageID(tc.pullSpec); imageID != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, imageID)
+			}
+		})
+	}
+}
@@ -63,6 +63,22 @@ type options struct {
 
 	ARTSuffix string
 
+	ChangelogHTMLTemplate string
+
+	BasePath string
+
+	ChangelogRobotsTxt bool
+
+	StripCommitTrailers []string
+
+	ChangelogAllowedFormats []string
+
+	ChangelogCacheTTL time.Duration
+
+	ChangelogSpinnerTimeout       time.Duration
+	ChangelogRenderTimeout        time.Duration
+	ChangelogComponentDiffTimeout time.Duration
+
 	jira       flagutil.JiraOptions
 	enableJira bool
 }
@@ -81,6 +97,12 @@ func main() {
 	opt := &options{
 		ListenAddr:          ":8080",
 		ToolsImageStreamTag: ":tests",
+		ChangelogRobotsTxt:  true,
+		ChangelogCacheTTL:   defaultChangeLogCacheTTL,
+
+		ChangelogSpinnerTimeout:       defaultChangeLogSpinnerTimeout,
+		ChangelogRenderTimeout:        defaultChangeLogRenderTimeout,
+		ChangelogComponentDiffTimeout: defaultChangeLogComponentDiffTimeout,
 	}
 	cmd := &cobra.Command{
 		Run: func(cmd *cobra.Command, arguments []string) {
@@ -114,6 +136,16 @@ func main() {
 
 	flagset.StringVar(&opt.ARTSuffix, "art-suffix", "", "Suffix for ART imagstreams (eg. `-art-latest`)")
 
+	flagset.StringVar(&opt.BasePath, "base-path", opt.BasePath, "The path prefix, stripped by a reverse proxy, that the UI is served under (e.g. `/release-controller`). Prepended to all generated internal links.")
+	flagset.BoolVar(&opt.ChangelogRobotsTxt, "changelog-robots-txt", opt.ChangelogRobotsTxt, "Serve a robots.txt that disallows crawling of the changelog and api endpoints. If false, an empty robots.txt is served.")
+	flagset.StringVar(&opt.ChangelogHTMLTemplate, "changelog-html-template", opt.ChangelogHTMLTemplate, "The path to a text/template file used to render the html changelog page. Falls back to the built-in template if unset.")
+	flagset.StringSliceVar(&opt.ChangelogAllowedFormats, "changelog-allowed-formats", opt.ChangelogAllowedFormats, "A comma-separated list of the formats (html, json, markdown, multiarch) that the changelog may be requested in. Defaults to all formats.")
+	flagset.DurationVar(&opt.ChangelogCacheTTL, "changelog-cache-ttl", opt.ChangelogCacheTTL, "How long a generated changelog is reused before it is generated again. Set to 0 to disable the changelog cache.")
+	flagset.DurationVar(&opt.ChangelogSpinnerTimeout, "changelog-spinner-timeout", opt.ChangelogSpinnerTimeout, "How long a changelog has to be generated before a loading message is shown.")
+	flagset.DurationVar(&opt.ChangelogRenderTimeout, "changelog-render-timeout", opt.ChangelogRenderTimeout, "How long, after the loading message is shown, a changelog has to be generated before an error is shown instead.  The /api/v1/changelog endpoint, and multiarch changelogs, wait for the sum of --changelog-spinner-timeout and --changelog-render-timeout.")
+	flagset.DurationVar(&opt.ChangelogComponentDiffTimeout, "changelog-component-diff-timeout", opt.ChangelogComponentDiffTimeout, "How long, after a changelog has been generated, the component images that changed are waited for before the changelog is shown without them.")
+	flagset.StringSliceVar(&opt.StripCommitTrailers, "strip-commit-trailers", opt.StripCommitTrailers, "A comma-separated list of commit message trailer keys (e.g. `Signed-off-by,Co-authored-by`) to remove from the changelog.")
+
 	flagset.AddGoFlag(original.Lookup("v"))
 	flagset.BoolVar(&opt.enableJira, "enable-jira", opt.enableJira, "Enable Jira issue fetching")
 
@@ -146,10 +178,31 @@ func (o *options) Run() error {
 	if len(o.ProwNamespace) == 0 {
 		o.ProwNamespace = o.JobNamespace
 	}
+	if o.ChangelogSpinnerTimeout <= 0 {
+		return fmt.Errorf("--changelog-spinner-timeout must be greater than 0")
+	}
+	if o.ChangelogRenderTimeout <= 0 {
+		return fmt.Errorf("--changelog-render-timeout must be greater than 0")
+	}
+	if o.ChangelogComponentDiffTimeout <= 0 {
+		return fmt.Errorf("--changelog-component-diff-timeout must be greater than 0")
+	}
+	if len(o.BasePath) > 0 && !strings.HasPrefix(o.BasePath, "/") {
+		return fmt.Errorf("--base-path must begin with a '/'")
+	}
+	basePath := strings.TrimSuffix(o.BasePath, "/")
 	var architecture = "amd64"
 	if len(o.ReleaseArchitecture) > 0 {
 		architecture = o.ReleaseArchitecture
 	}
+	changelogHTMLTemplate, err := loadChangelogHTMLTemplate(o.ChangelogHTMLTemplate)
+	if err != nil {
+		return err
+	}
+	changelogAllowedFormats, err := newChangeLogFormatSet(o.ChangelogAllowedFormats)
+	if err != nil {
+		return fmt.Errorf("--changelog-allowed-formats: %w", err)
+	}
 
 	inClusterCfg, err := loadClusterConfig()
 	if err != nil {
@@ -238,6 +291,15 @@ func (o *options) Run() error {
 		o.ARTSuffix,
 		releaseNamespace,
 		releasePayloadInformer.Lister(),
+		changelogHTMLTemplate,
+		basePath,
+		o.ChangelogRobotsTxt,
+		o.StripCommitTrailers,
+		changelogAllowedFormats,
+		o.ChangelogCacheTTL,
+		o.ChangelogSpinnerTimeout,
+		o.ChangelogRenderTimeout,
+		o.ChangelogComponentDiffTimeout,
 	)
 
 	var hasSynced []cache.InformerSynced
@@ -298,6 +360,8 @@ func (o *options) Run() error {
 	klog.Infof("Waiting for caches to sync")
 	cache.WaitForCacheSync(stopCh, hasSynced...)
 
+	go c.syncStreamStats(stopCh)
+
 	// read the graph
 	go releasecontroller.SyncGraphToSecret(graph, false, releasesClient.CoreV1().Secrets(releaseNamespace), releaseNamespace, "release-upgrade-graph", stopCh)
 
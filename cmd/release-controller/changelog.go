@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	imagereference "github.com/openshift/library-go/pkg/image/reference"
+	releasecontroller "github.com/openshift/release-controller/pkg/release-controller"
+	"github.com/openshift/release-controller/pkg/rhcos"
+	"github.com/russross/blackfriday"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// changeLogFormats are the formats that the changelog sub-command can write
+var changeLogFormats = sets.NewString("json", "text", "html")
+
+type changeLogOptions struct {
+	From         string
+	To           string
+	Format       string
+	Architecture string
+
+	Kubeconfig string
+	Namespace  string
+
+	releaseInfo releasecontroller.ReleaseInfo
+	out         io.Writer
+}
+
+// newChangeLogCommand returns the changelog sub-command, which generates the changelog between two release images
+// using the git cache of a running release-controller, without the need for the release-controller-api
+func newChangeLogCommand() *cobra.Command {
+	opt := &changeLogOptions{
+		Format:       "text",
+		Architecture: "amd64",
+		out:          os.Stdout,
+	}
+	cmd := &cobra.Command{
+		Use:   "changelog",
+		Short: "Write the changelog between two release images to stdout",
+		RunE: func(cmd *cobra.Command, arguments []string) error {
+			if err := opt.Validate(); err != nil {
+				return err
+			}
+			if err := opt.Complete(); err != nil {
+				return err
+			}
+			return opt.Run()
+		},
+	}
+	flagset := cmd.Flags()
+	flagset.StringVar(&opt.From, "from", opt.From, "The pull spec of the release image to generate the changelog from.")
+	flagset.StringVar(&opt.To, "to", opt.To, "The pull spec of the release image to generate the changelog to.")
+	flagset.StringVar(&opt.Format, "format", opt.Format, fmt.Sprintf("The format of the changelog. Valid options are: %s.", strings.Join(changeLogFormats.List(), ", ")))
+	flagset.StringVar(&opt.Architecture, "release-architecture", opt.Architecture, "The architecture of the release images.")
+	flagset.StringVar(&opt.Kubeconfig, "tools-kubeconfig", opt.Kubeconfig, "The kubeconfig of the cluster running the release-controller tools. Falls back to the default client configuration if unset.")
+	flagset.StringVar(&opt.Namespace, "job-namespace", opt.Namespace, "The namespace, of the release-controller, that holds the git cache.")
+	return cmd
+}
+
+func (o *changeLogOptions) Validate() error {
+	if len(o.From) == 0 {
+		return fmt.Errorf("--from must be set to a release image pull spec")
+	}
+	if len(o.To) == 0 {
+		return fmt.Errorf("--to must be set to a release image pull spec")
+	}
+	if !changeLogFormats.Has(o.Format) {
+		return fmt.Errorf("unrecognized --format %q, must be one of: %s", o.Format, strings.Join(changeLogFormats.List(), ", "))
+	}
+	return nil
+}
+
+// Complete creates the ReleaseInfo that executes in the git cache of the release-controller
+func (o *changeLogOptions) Complete() error {
+	if len(o.Namespace) == 0 {
+		return fmt.Errorf("no job namespace set, use --job-namespace")
+	}
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if len(o.Kubeconfig) > 0 {
+		loadingRules = &clientcmd.ClientConfigLoadingRules{ExplicitPath: o.Kubeconfig}
+	}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("could not load client configuration: %v", err)
+	}
+	client, err := clientset.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("unable to create tools client: %v", err)
+	}
+	o.releaseInfo = releasecontroller.NewExecReleaseInfo(client, config, o.Namespace, "", nil, nil)
+	return nil
+}
+
+func (o *changeLogOptions) Run() error {
+	fromImage, err := releasecontroller.GetImageInfo(o.releaseInfo, o.Architecture, o.From)
+	if err != nil {
+		return err
+	}
+	toImage, err := releasecontroller.GetImageInfo(o.releaseInfo, o.Architecture, o.To)
+	if err != nil {
+		return err
+	}
+
+	isJson := o.Format == "json"
+	out, err := o.releaseInfo.ChangeLog(fromImage.GenerateDigestPullSpec(), toImage.GenerateDigestPullSpec(), isJson)
+	if err != nil {
+		return err
+	}
+
+	// There is an inconsistency with what is returned from ReleaseInfo (amd64) and what
+	// needs to be passed into the RHCOS diff engine (x86_64).
+	var architecture, archExtension string
+	switch toImage.Config.Architecture {
+	case "amd64":
+		architecture = "x86_64"
+	case "arm64":
+		architecture = "aarch64"
+		archExtension = fmt.Sprintf("-%s", architecture)
+	case "multi":
+		architecture = toImage.Config.Architecture
+	default:
+		architecture = toImage.Config.Architecture
+		archExtension = fmt.Sprintf("-%s", architecture)
+	}
+
+	if isJson {
+		out, err = rhcos.TransformJsonOutput(out, architecture, archExtension)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.out, out)
+		return nil
+	}
+
+	out, err = rhcos.TransformMarkDownOutput(out, releaseTagName(o.From), releaseTagName(o.To), "", architecture, archExtension)
+	if err != nil {
+		return err
+	}
+	if o.Format == "html" {
+		o.out.Write(blackfriday.Run([]byte(out)))
+		return nil
+	}
+	fmt.Fprintln(o.out, out)
+	return nil
+}
+
+// releaseTagName returns the tag, of the pull spec, that the changelog refers to the release by
+func releaseTagName(pullSpec string) string {
+	if ref, err := imagereference.Parse(pullSpec); err == nil && len(ref.Tag) > 0 {
+		return ref.Tag
+	}
+	return pullSpec
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	releasecontroller "github.com/openshift/release-controller/pkg/release-controller"
+)
+
+// fakeChangeLogReleaseInfo returns a canned changelog for any pair of release images
+type fakeChangeLogReleaseInfo struct {
+	changeLogs []string
+}
+
+var _ releasecontroller.ReleaseInfo = &fakeChangeLogReleaseInfo{}
+
+func (r *fakeChangeLogReleaseInfo) Bugs(from, to string) ([]releasecontroller.BugDetails, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (r *fakeChangeLogReleaseInfo) ReleaseInfo(image string) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (r *fakeChangeLogReleaseInfo) UpgradeInfo(image string) (releasecontroller.ReleaseUpgradeInfo, error) {
+	return releasecontroller.ReleaseUpgradeInfo{}, fmt.Errorf("not implemented")
+}
+
+func (r *fakeChangeLogReleaseInfo) IssuesInfo(changelog string) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (r *fakeChangeLogReleaseInfo) GetFeatureChildren(featuresList []string, validityPeriod time.Duration) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (r *fakeChangeLogReleaseInfo) ImageInfo(image, architecture string) (string, error) {
+	return fmt.Sprintf(`{"name":%q,"digest":"sha256:0123456789abcdef","config":{"architecture":%q}}`, image, architecture), nil
+}
+
+func (r *fakeChangeLogReleaseInfo) ChangeLog(from, to string, isJson bool) (string, error) {
+	r.changeLogs = append(r.changeLogs, fmt.Sprintf("%s..%s", from, to))
+	if isJson {
+		return `{"from":{"name":"4.14.0"},"to":{"name":"4.14.1"},"components":[],"updatedImages":[]}`, nil
+	}
+	return "## Changes from 4.14.0\n\n* Fixed the **installer**\n", nil
+}
+
+func TestChangeLogCommand(t *testing.T) {
+	testCases := []struct {
+		name     string
+		format   string
+		validate func(t *testing.T, out string)
+	}{
+		{
+			name:   "Text",
+			format: "text",
+			validate: func(t *testing.T, out string) {
+				if !strings.Contains(out, "* Fixed the **installer**") {
+					t.Errorf("expected the markdown changelog, got:\n%s", out)
+				}
+			},
+		},
+		{
+			name:   "HTML",
+			format: "html",
+			validate: func(t *testing.T, out string) {
+				if !strings.Contains(out, "<strong>installer</strong>") {
+					t.Errorf("expected the html changelog, got:\n%s", out)
+				}
+			},
+		},
+		{
+			name:   "JSON",
+			format: "json",
+			validate: func(t *testing.T, out string) {
+				var changeLog releasecontroller.ChangeLog
+				if err := json.Unmarshal([]byte(out), &changeLog); err != nil {
+					t.Fatalf("expected the json changelog, got: %v\n%s", err, out)
+				}
+				if changeLog.From.Name != "4.14.0" || changeLog.To.Name != "4.14.1" {
+					t.Errorf("unexpected changelog: %#v", changeLog)
+				}
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			releaseInfo := &fakeChangeLogReleaseInfo{}
+			out := &bytes.Buffer{}
+			o := &changeLogOptions{
+				From:         "quay.io/openshift-release-dev/ocp-release:4.14.0-x86_64",
+				To:           "quay.io/openshift-release-dev/ocp-release:4.14.1-x86_64",
+				Format:       tc.format,
+				Architecture: "amd64",
+				releaseInfo:  releaseInfo,
+				out:          out,
+			}
+			if err := o.Validate(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := o.Run(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			// The changelog is generated from the digests of the release images
+			expected := "quay.io/openshift-release-dev/ocp-release@sha256:0123456789abcdef..quay.io/openshift-release-dev/ocp-release@sha256:0123456789abcdef"
+			if len(releaseInfo.changeLogs) != 1 || releaseInfo.changeLogs[0] != expected {
+				t.Errorf("expected a single changelog for %s, got %v", expected, releaseInfo.changeLogs)
+			}
+			tc.validate(t, out.String())
+		})
+	}
+}
+
+func TestChangeLogOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		options     changeLogOptions
+		expectedErr bool
+	}{
+		{
+			name:    "Valid",
+			options: changeLogOptions{From: "quay.io/a:1", To: "quay.io/a:2", Format: "html"},
+		},
+		{
+			name:        "MissingFrom",
+			options:     changeLogOptions{To: "quay.io/a:2", Format: "html"},
+			expectedErr: true,
+		},
+		{
+			name:        "MissingTo",
+			options:     changeLogOptions{From: "quay.io/a:1", Format: "html"},
+			expectedErr: true,
+		},
+		{
+			name:        "UnknownFormat",
+			options:     changeLogOptions{From: "quay.io/a:1", To: "quay.io/a:2", Format: "rst"},
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.options.Validate(); (err != nil) != tc.expectedErr {
+				t.Errorf("expected error: %t, got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
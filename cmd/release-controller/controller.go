@@ -145,6 +145,17 @@ type Controller struct {
 
 	releasePayloadClient releasepayloadclient.ReleasePayloadsGetter
 	releasePayloadLister *releasecontroller.MultiReleasePayloadLister
+
+	// defaultJobActiveDeadlineSeconds is the activeDeadlineSeconds of the jobs created for a release, unless the
+	// respective ReleasePayload specifies its own
+	defaultJobActiveDeadlineSeconds int64
+
+	// jobServiceAccount is the service account that the jobs created for a release run as
+	jobServiceAccount string
+
+	// manifestToolImage is the image of the job that assembles the release images, of the architectures of a
+	// release, into the manifest list of the release
+	manifestToolImage string
 }
 
 // NewController instantiates a Controller to manage release objects.
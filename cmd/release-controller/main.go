@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/informers"
@@ -108,6 +109,13 @@ type options struct {
 	ConfirmPruneGraph bool
 
 	ProcessLegacyResults bool
+
+	DefaultJobActiveDeadlineSeconds int64
+
+	JobServiceAccount         string
+	AllowedJobServiceAccounts []string
+
+	ManifestToolImage string
 }
 
 // Add metrics for jira verifier errors
@@ -139,6 +147,12 @@ func main() {
 		Registry: "registry.ci.openshift.org",
 
 		PrintPrunedGraph: releasecontroller.PruneGraphPrintSecret,
+
+		DefaultJobActiveDeadlineSeconds: 3600,
+
+		JobServiceAccount: "builder",
+
+		ManifestToolImage: "docker.io/mplatform/manifest-tool:v2.1.6",
 	}
 	cmd := &cobra.Command{
 		Run: func(cmd *cobra.Command, arguments []string) {
@@ -212,6 +226,13 @@ func main() {
 
 	flagset.BoolVar(&opt.ProcessLegacyResults, "process-legacy-results", opt.ProcessLegacyResults, "enable the migration of imagestream based results to ReleasePayloads")
 
+	flagset.Int64Var(&opt.DefaultJobActiveDeadlineSeconds, "default-job-active-deadline-seconds", opt.DefaultJobActiveDeadlineSeconds, "The activeDeadlineSeconds of the jobs created for a release, unless the ReleasePayload specifies spec.payloadCreationConfig.activeDeadlineSeconds.")
+
+	flagset.StringVar(&opt.JobServiceAccount, "job-service-account", opt.JobServiceAccount, "The service account that the jobs created for a release run as.")
+	flagset.StringSliceVar(&opt.AllowedJobServiceAccounts, "allowed-job-service-accounts", opt.AllowedJobServiceAccounts, "The service accounts that --job-service-account may be set to.  Any service account is allowed if unset.")
+
+	flagset.StringVar(&opt.ManifestToolImage, "manifest-tool-image", opt.ManifestToolImage, "The manifest-tool image that assembles the release images, of the architectures of a ReleasePayload that lists its spec.architectures, into the manifest list of the release.")
+
 	goFlagSet := flag.NewFlagSet("prowflags", flag.ContinueOnError)
 	opt.github.AddFlags(goFlagSet)
 	opt.jira.AddFlags(goFlagSet)
@@ -223,6 +244,8 @@ func main() {
 		klog.Warningf("failed to set up kubeconfig watches: %v", err)
 	}
 
+	cmd.AddCommand(newChangeLogCommand())
+
 	if err := cmd.Execute(); err != nil {
 		klog.Exitf("error: %v", err)
 	}
@@ -250,6 +273,12 @@ func (o *options) Run() error {
 	if sets.NewString(o.ReleaseNamespaces...).HasAny(o.PublishNamespaces...) {
 		return fmt.Errorf("--release-namespace and --publish-namespace may not overlap")
 	}
+	if o.DefaultJobActiveDeadlineSeconds <= 0 {
+		return fmt.Errorf("--default-job-active-deadline-seconds must be greater than 0")
+	}
+	if err := validateJobServiceAccount(o.JobServiceAccount, o.AllowedJobServiceAccounts); err != nil {
+		return err
+	}
 	var architecture = "amd64"
 	if len(o.ReleaseArchitecture) > 0 {
 		architecture = o.ReleaseArchitecture
@@ -448,6 +477,10 @@ func (o *options) Run() error {
 		c.cliImageForAudit = o.CLIImageForAudit
 	}
 
+	c.defaultJobActiveDeadlineSeconds = o.DefaultJobActiveDeadlineSeconds
+	c.jobServiceAccount = o.JobServiceAccount
+	c.manifestToolImage = o.ManifestToolImage
+
 	if len(o.SigningKeyring) > 0 {
 		signer, err := signer.NewFromKeyring(o.SigningKeyring)
 		if err != nil {
@@ -590,6 +623,21 @@ func (o *options) Run() error {
 	}
 }
 
+// validateJobServiceAccount returns an error if the service account, of the jobs created for a release, is not a valid
+// name or is not one of the allowed service accounts.  Any valid name is allowed if no service accounts are.
+func validateJobServiceAccount(serviceAccount string, allowed []string) error {
+	if len(serviceAccount) == 0 {
+		return fmt.Errorf("--job-service-account must be set")
+	}
+	if errs := validation.IsDNS1123Subdomain(serviceAccount); len(errs) > 0 {
+		return fmt.Errorf("--job-service-account %q is not a valid service account name: %s", serviceAccount, strings.Join(errs, ", "))
+	}
+	if len(allowed) > 0 && !sets.NewString(allowed...).Has(serviceAccount) {
+		return fmt.Errorf("--job-service-account %q must be one of --allowed-job-service-accounts: %s", serviceAccount, strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
 func (o *options) prowJobClient(cfg *rest.Config) (dynamic.NamespaceableResourceInterface, error) {
 	if o.ProwJobKubeconfig != "" {
 		var err error
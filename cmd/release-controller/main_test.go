@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestValidateJobServiceAccount(t *testing.T) {
+	testCases := []struct {
+		name           string
+		serviceAccount string
+		allowed        []string
+		expectedErr    bool
+	}{
+		{
+			name:           "AnyAllowed",
+			serviceAccount: "builder",
+		},
+		{
+			name:           "Allowed",
+			serviceAccount: "release-creator",
+			allowed:        []string{"builder", "release-creator"},
+		},
+		{
+			name:           "NotAllowed",
+			serviceAccount: "default",
+			allowed:        []string{"builder", "release-creator"},
+			expectedErr:    true,
+		},
+		{
+			name:        "Empty",
+			expectedErr: true,
+		},
+		{
+			name:           "InvalidName",
+			serviceAccount: "Release_Creator",
+			expectedErr:    true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateJobServiceAccount(tc.serviceAccount, tc.allowed)
+			if (err != nil) != tc.expectedErr {
+				t.Errorf("expected error: %t, got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
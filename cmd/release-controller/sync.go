@@ -370,7 +370,7 @@ func (c *Controller) syncPending(release *releasecontroller.Release, pendingTags
 				if err := c.markReleaseReady(release, nil, tag.Name); err != nil {
 					return err
 				}
-				if tags := releasecontroller.SortedRawReleaseTags(release, releasecontroller.ReleasePhaseReady); len(tags) > 0 {
+				if tags := releasecontroller.SortedRawReleaseTags(release, releasecontroller.ReleasePhaseReady); len(tags) > 0 && !c.changelogGenerationDisabled(release, tag.Name) {
 					go func() {
 						fromPullSpec := releasecontroller.FindPublicImagePullSpec(release.Target, tags[0].Name)
 						if len(fromPullSpec) == 0 {
@@ -424,18 +424,18 @@ func (c *Controller) syncPending(release *releasecontroller.Release, pendingTags
 			return fmt.Errorf("mirror hash for %q does not match, release cannot be created", tag.Name)
 		}
 
-		job, err := c.ensureReleaseJob(release, tag.Name, mirror)
-		if err != nil || job == nil {
+		jobs, err := c.ensureReleaseJobs(release, tag.Name, mirror)
+		if err != nil || len(jobs) == 0 {
 			return err
 		}
-		success, complete := jobIsComplete(job)
+		success, complete, failed := releaseJobsComplete(jobs)
 		klog.V(4).Infof("Release creation for %s success: %v, complete: %v", tag.Name, success, complete)
 		switch {
 		case !complete:
 			return nil
 		case !success:
 			// try to get the last termination message
-			log, _, _ := ensureJobTerminationMessageRetrieved(c.podClient, job, "status.phase=Failed", "build", false)
+			log, _, _ := ensureJobTerminationMessageRetrieved(c.podClient, failed, "status.phase=Failed", "build", false)
 			if err := c.transitionReleasePhaseFailure(release, []string{releasecontroller.ReleasePhasePending}, releasecontroller.ReleasePhaseFailed, withLog(reasonAndMessage("CreateReleaseFailed", "Could not create the release image"), log), tag.Name); err != nil {
 				return err
 			}
@@ -443,7 +443,7 @@ func (c *Controller) syncPending(release *releasecontroller.Release, pendingTags
 			if err := c.markReleaseReady(release, nil, tag.Name); err != nil {
 				return err
 			}
-			if tags := releasecontroller.SortedRawReleaseTags(release, releasecontroller.ReleasePhaseReady); len(tags) > 0 {
+			if tags := releasecontroller.SortedRawReleaseTags(release, releasecontroller.ReleasePhaseReady); len(tags) > 0 && !c.changelogGenerationDisabled(release, tag.Name) {
 				go func() {
 					fromImage, err := releasecontroller.GetImageInfo(c.releaseInfo, c.architecture, tags[0].Name)
 					if err != nil {
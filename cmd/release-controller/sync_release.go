@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
 	releasecontroller "github.com/openshift/release-controller/pkg/release-controller"
+	releasepayloadhelpers "github.com/openshift/release-controller/pkg/releasepayload/v1alpha1helpers"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -19,24 +22,74 @@ import (
 	imagev1 "github.com/openshift/api/image/v1"
 )
 
+// ensureReleaseJobs ensures the release creation jobs of the release tag.  If the ReleasePayload, of the release tag,
+// lists its spec.architectures, a release creation job is created for each of them, followed by the job that
+// assembles their release images into the manifest list of the release tag, otherwise a single release creation job
+// is created.  No jobs are returned while any of them is being recreated.
+func (c *Controller) ensureReleaseJobs(release *releasecontroller.Release, name string, mirror *imagev1.ImageStream) ([]*batchv1.Job, error) {
+	architectures, err := c.releaseArchitectures(release, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(architectures) == 0 {
+		job, err := c.ensureReleaseJob(release, name, mirror)
+		if err != nil || job == nil {
+			return nil, err
+		}
+		return []*batchv1.Job{job}, nil
+	}
+	var jobs []*batchv1.Job
+	for _, architecture := range architectures {
+		job, err := c.ensureArchitectureReleaseJob(release, name, mirror, architecture)
+		if err != nil || job == nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	// Nothing is pushed to the release tag until the release images, of all the architectures, have been created
+	if succeeded, _, _ := releaseJobsComplete(jobs); !succeeded {
+		return jobs, nil
+	}
+	job, err := c.ensureManifestListJob(release, name, mirror, architectures)
+	if err != nil || job == nil {
+		return nil, err
+	}
+	return append(jobs, job), nil
+}
+
 func (c *Controller) ensureReleaseJob(release *releasecontroller.Release, name string, mirror *imagev1.ImageStream) (*batchv1.Job, error) {
-	return c.ensureJob(name, nil, func() (*batchv1.Job, error) {
-		toImage := fmt.Sprintf("%s:%s", release.Target.Status.PublicDockerImageRepository, name)
+	return c.ensureReleaseCreationJob(release, name, name, mirror, "")
+}
+
+// ensureArchitectureReleaseJob ensures the release creation job that creates the release, of the release tag, for the
+// architecture.  The job, and the release image it pushes, are named after the release tag and the architecture
+// (i.e. 4.11.0-0.nightly-2022-02-09-091559-arm64).
+func (c *Controller) ensureArchitectureReleaseJob(release *releasecontroller.Release, name string, mirror *imagev1.ImageStream, architecture string) (*batchv1.Job, error) {
+	return c.ensureReleaseCreationJob(release, name, releasepayloadhelpers.ReleaseCreationJobNameForArchitecture(name, architecture), mirror, architecture)
+}
+
+func (c *Controller) ensureReleaseCreationJob(release *releasecontroller.Release, name, jobName string, mirror *imagev1.ImageStream, architecture string) (*batchv1.Job, error) {
+	return c.ensureJob(jobName, nil, func() (*batchv1.Job, error) {
+		toImage := fmt.Sprintf("%s:%s", release.Target.Status.PublicDockerImageRepository, jobName)
 		cliImage := fmt.Sprintf("%s:cli", mirror.Status.DockerImageRepository)
 		if len(release.Config.OverrideCLIImage) > 0 {
 			cliImage = release.Config.OverrideCLIImage
 		}
 
-		job, prefix := newReleaseJobBase(name, cliImage, release.Config.PullSecretName)
+		job, prefix := newReleaseJobBase(jobName, cliImage, release.Config.PullSecretName)
 
-		job.Spec.Template.Spec.Containers[0].Command = []string{
-			"/bin/bash", "-c",
-			prefix + `
+		command := `
 			oc adm release new "--name=$1" "--from-image-stream=$2" "--namespace=$3" "--to-image=$4" "--reference-mode=$5"
-			`,
-			"",
-			name, mirror.Name, mirror.Namespace, toImage, release.Config.ReferenceMode,
+			`
+		args := []string{name, mirror.Name, mirror.Namespace, toImage, release.Config.ReferenceMode}
+		if len(architecture) > 0 {
+			command = `
+			oc adm release new "--name=$1" "--from-image-stream=$2" "--namespace=$3" "--to-image=$4" "--reference-mode=$5" "--filter-by-os=linux/$6"
+			`
+			args = append(args, architecture)
+			job.Annotations[releasecontroller.ReleaseAnnotationArchitecture] = architecture
 		}
+		job.Spec.Template.Spec.Containers[0].Command = append([]string{"/bin/bash", "-c", prefix + command, ""}, args...)
 
 		job.Annotations[releasecontroller.ReleaseAnnotationSource] = mirror.Annotations[releasecontroller.ReleaseAnnotationSource]
 		job.Annotations[releasecontroller.ReleaseAnnotationTarget] = mirror.Annotations[releasecontroller.ReleaseAnnotationTarget]
@@ -48,6 +101,55 @@ func (c *Controller) ensureReleaseJob(release *releasecontroller.Release, name s
 	})
 }
 
+// ensureManifestListJob ensures the job that pushes the manifest list, of the release images of the architectures, to
+// the release tag.  The job is named after the release tag, like the release creation job of a single architecture
+// release, so that it is found at the coordinates of the release creation job of the ReleasePayload.
+func (c *Controller) ensureManifestListJob(release *releasecontroller.Release, name string, mirror *imagev1.ImageStream, architectures []string) (*batchv1.Job, error) {
+	return c.ensureJob(name, nil, func() (*batchv1.Job, error) {
+		repository := release.Target.Status.PublicDockerImageRepository
+		cliImage := fmt.Sprintf("%s:cli", mirror.Status.DockerImageRepository)
+		if len(release.Config.OverrideCLIImage) > 0 {
+			cliImage = release.Config.OverrideCLIImage
+		}
+
+		job, prefix := newReleaseJobBase(name, cliImage, release.Config.PullSecretName)
+
+		// The registry credentials are written, by oc, to the home directory shared with manifest-tool
+		login := job.Spec.Template.Spec.Containers[0]
+		login.Name = "login"
+		login.Command = []string{"/bin/bash", "-c", prefix}
+		login.VolumeMounts = append(login.VolumeMounts, corev1.VolumeMount{Name: "home", MountPath: "/tmp"})
+		job.Spec.Template.Spec.InitContainers = []corev1.Container{login}
+
+		var platforms []string
+		for _, architecture := range architectures {
+			platforms = append(platforms, fmt.Sprintf("linux/%s", architecture))
+		}
+		build := &job.Spec.Template.Spec.Containers[0]
+		build.Image = c.manifestToolImage
+		build.Args = []string{
+			"--docker-cfg=/tmp/.docker",
+			"push", "from-args",
+			fmt.Sprintf("--platforms=%s", strings.Join(platforms, ",")),
+			fmt.Sprintf("--template=%s:%s", repository, releasepayloadhelpers.ReleaseCreationJobNameForArchitecture(name, "ARCH")),
+			fmt.Sprintf("--target=%s:%s", repository, name),
+		}
+		build.VolumeMounts = []corev1.VolumeMount{{Name: "home", MountPath: "/tmp"}}
+		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name:         "home",
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+
+		job.Annotations[releasecontroller.ReleaseAnnotationSource] = mirror.Annotations[releasecontroller.ReleaseAnnotationSource]
+		job.Annotations[releasecontroller.ReleaseAnnotationTarget] = mirror.Annotations[releasecontroller.ReleaseAnnotationTarget]
+		job.Annotations[releasecontroller.ReleaseAnnotationGeneration] = strconv.FormatInt(release.Target.Generation, 10)
+		job.Annotations[releasecontroller.ReleaseAnnotationReleaseTag] = mirror.Annotations[releasecontroller.ReleaseAnnotationReleaseTag]
+
+		klog.V(2).Infof("Running manifest list job %s/%s for %s", c.jobNamespace, job.Name, name)
+		return job, nil
+	})
+}
+
 func (c *Controller) ensureRewriteJob(release *releasecontroller.Release, name string, mirror *imagev1.ImageStream, metadataJSON string) (*batchv1.Job, error) {
 	ref := releasecontroller.FindTagReference(release.Source, name)
 	generation := *ref.Generation
@@ -187,6 +289,17 @@ func (c *Controller) ensureJob(name string, preconditions map[string]string, cre
 		return nil, err
 	}
 
+	if payload := c.jobReleasePayload(job); payload != nil && payload.Spec.JobTemplate != nil {
+		job = mergeJobTemplate(job, payload.Spec.JobTemplate)
+	}
+	if job.Spec.ActiveDeadlineSeconds == nil {
+		job.Spec.ActiveDeadlineSeconds = c.jobActiveDeadlineSeconds(job)
+	}
+	if len(c.jobServiceAccount) > 0 {
+		job.Spec.Template.Spec.ServiceAccountName = c.jobServiceAccount
+	}
+	job.Name = name
+
 	for k, v := range preconditions {
 		if job.Annotations[k] != v {
 			return nil, fmt.Errorf("job %s doesn't match provided preconditions, programmer error: %v", job.Name, preconditions)
@@ -244,6 +357,172 @@ func (c *Controller) ensureRewriteJobImageRetrieved(release *releasecontroller.R
 	return nil
 }
 
+// jobActiveDeadlineSeconds returns the activeDeadlineSeconds for a job created for a release.  The
+// spec.payloadCreationConfig.activeDeadlineSeconds, of the release's ReleasePayload, takes precedence over the
+// --default-job-active-deadline-seconds.
+func (c *Controller) jobActiveDeadlineSeconds(job *batchv1.Job) *int64 {
+	if payload := c.jobReleasePayload(job); payload != nil && payload.Spec.PayloadCreationConfig.ActiveDeadlineSeconds != nil {
+		activeDeadlineSeconds := *payload.Spec.PayloadCreationConfig.ActiveDeadlineSeconds
+		return &activeDeadlineSeconds
+	}
+	if c.defaultJobActiveDeadlineSeconds > 0 {
+		activeDeadlineSeconds := c.defaultJobActiveDeadlineSeconds
+		return &activeDeadlineSeconds
+	}
+	return nil
+}
+
+// jobReleasePayload returns the ReleasePayload of the release that the job is created for, or nil if there is none
+func (c *Controller) jobReleasePayload(job *batchv1.Job) *v1alpha1.ReleasePayload {
+	if c.releasePayloadLister == nil {
+		return nil
+	}
+	parts := strings.Split(job.Annotations[releasecontroller.ReleaseAnnotationTarget], "/")
+	if len(parts) != 2 {
+		return nil
+	}
+	lister := c.releasePayloadLister.ReleasePayloads(parts[0])
+	if lister == nil {
+		return nil
+	}
+	payload, err := lister.Get(job.Annotations[releasecontroller.ReleaseAnnotationReleaseTag])
+	if err != nil {
+		return nil
+	}
+	return payload
+}
+
+// releaseJobMetadataPrefix is the prefix of the labels, and annotations, of the jobs of a release that a
+// ReleaseJobTemplate can not override
+const releaseJobMetadataPrefix = "release.openshift.io/"
+
+// mergeJobTemplate returns a copy of the job with the fields of the template applied.  Only the fields of the
+// ReleaseJobTemplate can be overridden, and the release.openshift.io labels, and annotations, that the job is tracked
+// by, are kept.
+func mergeJobTemplate(job *batchv1.Job, template *v1alpha1.ReleaseJobTemplate) *batchv1.Job {
+	if template == nil {
+		return job
+	}
+	merged := job.DeepCopy()
+	for key, value := range template.Labels {
+		if strings.HasPrefix(key, releaseJobMetadataPrefix) {
+			continue
+		}
+		if merged.Labels == nil {
+			merged.Labels = make(map[string]string)
+		}
+		merged.Labels[key] = value
+	}
+	for key, value := range template.Annotations {
+		if strings.HasPrefix(key, releaseJobMetadataPrefix) {
+			continue
+		}
+		if merged.Annotations == nil {
+			merged.Annotations = make(map[string]string)
+		}
+		merged.Annotations[key] = value
+	}
+	if template.ActiveDeadlineSeconds != nil {
+		activeDeadlineSeconds := *template.ActiveDeadlineSeconds
+		merged.Spec.ActiveDeadlineSeconds = &activeDeadlineSeconds
+	}
+	if template.BackoffLimit != nil {
+		backoffLimit := *template.BackoffLimit
+		merged.Spec.BackoffLimit = &backoffLimit
+	}
+	podSpec := &merged.Spec.Template.Spec
+	for key, value := range template.NodeSelector {
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = make(map[string]string)
+		}
+		podSpec.NodeSelector[key] = value
+	}
+	for _, toleration := range template.Tolerations {
+		podSpec.Tolerations = append(podSpec.Tolerations, *toleration.DeepCopy())
+	}
+	for _, containerTemplate := range template.Containers {
+		found := false
+		for i := range podSpec.Containers {
+			if podSpec.Containers[i].Name != containerTemplate.Name {
+				continue
+			}
+			mergeResources(&podSpec.Containers[i].Resources, containerTemplate.Resources)
+			found = true
+		}
+		if !found {
+			klog.V(4).Infof("Ignoring the job template of container %s, which is not part of job %s", containerTemplate.Name, job.Name)
+		}
+	}
+	return merged
+}
+
+// mergeResources sets the requests and limits, of the template, on the resources
+func mergeResources(resources *corev1.ResourceRequirements, template corev1.ResourceRequirements) {
+	for name, quantity := range template.Requests {
+		if resources.Requests == nil {
+			resources.Requests = make(corev1.ResourceList)
+		}
+		resources.Requests[name] = quantity.DeepCopy()
+	}
+	for name, quantity := range template.Limits {
+		if resources.Limits == nil {
+			resources.Limits = make(corev1.ResourceList)
+		}
+		resources.Limits[name] = quantity.DeepCopy()
+	}
+}
+
+// changelogGenerationDisabled returns true if the ReleasePayload, of the release tag, has
+// spec.disableChangelogGeneration set, in which case the changelog of the release is not pre-generated
+func (c *Controller) changelogGenerationDisabled(release *releasecontroller.Release, tagName string) bool {
+	if c.releasePayloadLister == nil {
+		return false
+	}
+	lister := c.releasePayloadLister.ReleasePayloads(release.Target.Namespace)
+	if lister == nil {
+		return false
+	}
+	payload, err := lister.Get(tagName)
+	if err != nil || !payload.Spec.DisableChangelogGeneration {
+		return false
+	}
+	klog.V(4).Infof("Changelog generation is disabled for release %s/%s", release.Target.Namespace, tagName)
+	return true
+}
+
+// releaseArchitectures returns the spec.architectures of the ReleasePayload, of the release tag.  The ReleasePayload is
+// created along with the release tag, so an error is returned, for the release to be retried, until it has been
+// observed rather than creating the release for a single architecture.
+func (c *Controller) releaseArchitectures(release *releasecontroller.Release, tagName string) ([]string, error) {
+	if c.releasePayloadLister == nil {
+		return nil, nil
+	}
+	lister := c.releasePayloadLister.ReleasePayloads(release.Target.Namespace)
+	if lister == nil {
+		return nil, nil
+	}
+	payload, err := lister.Get(tagName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine the architectures of release %s/%s: %w", release.Target.Namespace, tagName, err)
+	}
+	return payload.Spec.Architectures, nil
+}
+
+// releaseJobsComplete returns whether all the release creation jobs have completed, and whether all of them succeeded.
+// The first job to fail is returned, so that its termination message can be retrieved.
+func releaseJobsComplete(jobs []*batchv1.Job) (succeeded bool, complete bool, failed *batchv1.Job) {
+	complete = true
+	for _, job := range jobs {
+		jobSucceeded, jobComplete := jobIsComplete(job)
+		if jobComplete && !jobSucceeded {
+			// A failed job fails the release, without waiting for the jobs of the other architectures
+			return false, true, job
+		}
+		complete = complete && jobComplete
+	}
+	return complete, complete, nil
+}
+
 func findJobContainerStatus(podClient kv1core.PodsGetter, job *batchv1.Job, fieldSelector string, containerName string) ([]*corev1.ContainerStatus, error) {
 	pods, err := podClient.Pods(job.Namespace).List(context.TODO(), metav1.ListOptions{
 		FieldSelector: fieldSelector,
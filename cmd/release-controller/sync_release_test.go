@@ -0,0 +1,621 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadlisters "github.com/openshift/release-controller/pkg/client/listers/release/v1alpha1"
+	releasecontroller "github.com/openshift/release-controller/pkg/release-controller"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func int64p(i int64) *int64 {
+	return &i
+}
+
+func TestEnsureJobActiveDeadlineSeconds(t *testing.T) {
+	testCases := []struct {
+		name             string
+		defaultDeadline  int64
+		payloadDeadline  *int64
+		jobDeadline      *int64
+		noReleasePayload bool
+		expectedDeadline *int64
+	}{
+		{
+			name:             "Default",
+			defaultDeadline:  3600,
+			expectedDeadline: int64p(3600),
+		},
+		{
+			name:             "ReleasePayloadOverride",
+			defaultDeadline:  3600,
+			payloadDeadline:  int64p(7200),
+			expectedDeadline: int64p(7200),
+		},
+		{
+			name:             "ReleasePayloadNotFound",
+			defaultDeadline:  3600,
+			noReleasePayload: true,
+			expectedDeadline: int64p(3600),
+		},
+		{
+			name:             "JobDeadlineSet",
+			defaultDeadline:  3600,
+			payloadDeadline:  int64p(7200),
+			jobDeadline:      int64p(60),
+			expectedDeadline: int64p(60),
+		},
+		{
+			name: "NoDefault",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			releasePayloadIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if !tc.noReleasePayload {
+				if err := releasePayloadIndexer.Add(&v1alpha1.ReleasePayload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "4.14.0-0.nightly-2023-06-01-000000",
+						Namespace: "ocp",
+					},
+					Spec: v1alpha1.ReleasePayloadSpec{
+						PayloadCreationConfig: v1alpha1.PayloadCreationConfig{
+							ActiveDeadlineSeconds: tc.payloadDeadline,
+						},
+					},
+				}); err != nil {
+					t.Fatalf("unable to add ReleasePayload: %v", err)
+				}
+			}
+
+			kubeClient := fake.NewSimpleClientset()
+			kubeFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+
+			c := &Controller{
+				jobNamespace: "ci-release",
+				jobClient:    kubeClient.BatchV1(),
+				jobLister:    kubeFactory.Batch().V1().Jobs().Lister(),
+				releasePayloadLister: &releasecontroller.MultiReleasePayloadLister{
+					Listers: map[string]releasepayloadlisters.ReleasePayloadNamespaceLister{
+						"ocp": releasepayloadlisters.NewReleasePayloadLister(releasePayloadIndexer).ReleasePayloads("ocp"),
+					},
+				},
+				defaultJobActiveDeadlineSeconds: tc.defaultDeadline,
+			}
+
+			job, err := c.ensureJob("4.14.0-0.nightly-2023-06-01-000000", nil, func() (*batchv1.Job, error) {
+				job, _ := newReleaseJobBase("4.14.0-0.nightly-2023-06-01-000000", "registry.ci.openshift.org/ocp/4.14:cli", "")
+				job.Annotations[releasecontroller.ReleaseAnnotationTarget] = "ocp/release"
+				job.Annotations[releasecontroller.ReleaseAnnotationReleaseTag] = "4.14.0-0.nightly-2023-06-01-000000"
+				job.Spec.ActiveDeadlineSeconds = tc.jobDeadline
+				return job, nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			created, err := kubeClient.BatchV1().Jobs("ci-release").Get(context.TODO(), job.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("unable to get job: %v", err)
+			}
+			switch {
+			case tc.expectedDeadline == nil && created.Spec.ActiveDeadlineSeconds != nil:
+				t.Errorf("expected no activeDeadlineSeconds, got %d", *created.Spec.ActiveDeadlineSeconds)
+			case tc.expectedDeadline != nil && created.Spec.ActiveDeadlineSeconds == nil:
+				t.Errorf("expected activeDeadlineSeconds %d, got none", *tc.expectedDeadline)
+			case tc.expectedDeadline != nil && *created.Spec.ActiveDeadlineSeconds != *tc.expectedDeadline:
+				t.Errorf("expected activeDeadlineSeconds %d, got %d", *tc.expectedDeadline, *created.Spec.ActiveDeadlineSeconds)
+			}
+		})
+	}
+}
+
+func TestEnsureJobServiceAccount(t *testing.T) {
+	testCases := []struct {
+		name              string
+		jobServiceAccount string
+		expected          string
+	}{
+		{
+			name:     "Unset",
+			expected: "builder",
+		},
+		{
+			name:              "Set",
+			jobServiceAccount: "release-creator",
+			expected:          "release-creator",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			kubeFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+
+			c := &Controller{
+				jobNamespace:      "ci-release",
+				jobClient:         kubeClient.BatchV1(),
+				jobLister:         kubeFactory.Batch().V1().Jobs().Lister(),
+				jobServiceAccount: tc.jobServiceAccount,
+			}
+
+			job, err := c.ensureJob("4.14.0-0.nightly-2023-06-01-000000", nil, func() (*batchv1.Job, error) {
+				job, _ := newReleaseJobBase("4.14.0-0.nightly-2023-06-01-000000", "registry.ci.openshift.org/ocp/4.14:cli", "")
+				return job, nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			created, err := kubeClient.BatchV1().Jobs("ci-release").Get(context.TODO(), job.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("unable to get job: %v", err)
+			}
+			if created.Spec.Template.Spec.ServiceAccountName != tc.expected {
+				t.Errorf("expected service account %q, got %q", tc.expected, created.Spec.Template.Spec.ServiceAccountName)
+			}
+		})
+	}
+}
+
+func TestChangelogGenerationDisabled(t *testing.T) {
+	testCases := []struct {
+		name             string
+		noLister         bool
+		noReleasePayload bool
+		disabled         bool
+		expected         bool
+	}{
+		{
+			name:     "Disabled",
+			disabled: true,
+			expected: true,
+		},
+		{
+			name: "Enabled",
+		},
+		{
+			name:             "ReleasePayloadNotFound",
+			noReleasePayload: true,
+		},
+		{
+			name:     "NoReleasePayloadLister",
+			noLister: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			releasePayloadIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if !tc.noReleasePayload {
+				if err := releasePayloadIndexer.Add(&v1alpha1.ReleasePayload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "4.14.0-0.nightly-2023-06-01-000000",
+						Namespace: "ocp",
+					},
+					Spec: v1alpha1.ReleasePayloadSpec{
+						DisableChangelogGeneration: tc.disabled,
+					},
+				}); err != nil {
+					t.Fatalf("unable to add ReleasePayload: %v", err)
+				}
+			}
+
+			c := &Controller{}
+			if !tc.noLister {
+				c.releasePayloadLister = &releasecontroller.MultiReleasePayloadLister{
+					Listers: map[string]releasepayloadlisters.ReleasePayloadNamespaceLister{
+						"ocp": releasepayloadlisters.NewReleasePayloadLister(releasePayloadIndexer).ReleasePayloads("ocp"),
+					},
+				}
+			}
+			release := &releasecontroller.Release{
+				Target: &imagev1.ImageStream{ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "ocp"}},
+			}
+
+			if disabled := c.changelogGenerationDisabled(release, "4.14.0-0.nightly-2023-06-01-000000"); disabled != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, disabled)
+			}
+		})
+	}
+}
+
+func newTestReleaseJob() *batchv1.Job {
+	job, _ := newReleaseJobBase("4.14.0-0.nightly-2023-06-01-000000", "registry.ci.openshift.org/ocp/4.14:cli", "")
+	job.Annotations[releasecontroller.ReleaseAnnotationTarget] = "ocp/release"
+	job.Annotations[releasecontroller.ReleaseAnnotationReleaseTag] = "4.14.0-0.nightly-2023-06-01-000000"
+	job.Spec.Template.Spec.Containers[0].Command = []string{"/bin/bash", "-c", "oc adm release new"}
+	return job
+}
+
+func TestMergeJobTemplate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template *v1alpha1.ReleaseJobTemplate
+		expected func(job *batchv1.Job)
+	}{
+		{
+			name:     "NoTemplate",
+			expected: func(job *batchv1.Job) {},
+		},
+		{
+			name: "PartialTemplate",
+			template: &v1alpha1.ReleaseJobTemplate{
+				Labels:       map[string]string{"team": "art"},
+				BackoffLimit: releasecontroller.Int32p(6),
+				NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"},
+				Containers: []v1alpha1.ReleaseJobContainerTemplate{
+					{
+						Name: "build",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+						},
+					},
+				},
+			},
+			expected: func(job *batchv1.Job) {
+				job.Labels = map[string]string{"team": "art"}
+				job.Spec.BackoffLimit = releasecontroller.Int32p(6)
+				job.Spec.Template.Spec.NodeSelector = map[string]string{"kubernetes.io/arch": "arm64"}
+				job.Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")}
+			},
+		},
+		{
+			name: "FullTemplate",
+			template: &v1alpha1.ReleaseJobTemplate{
+				Labels:                map[string]string{"team": "art"},
+				Annotations:           map[string]string{"owner": "art"},
+				ActiveDeadlineSeconds: int64p(600),
+				BackoffLimit:          releasecontroller.Int32p(0),
+				NodeSelector:          map[string]string{"kubernetes.io/arch": "arm64"},
+				Tolerations: []corev1.Toleration{
+					{Key: "node-role.kubernetes.io/builder", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+				},
+				Containers: []v1alpha1.ReleaseJobContainerTemplate{
+					{
+						Name: "build",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("2Gi")},
+							Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+						},
+					},
+					{
+						Name: "sidecar",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+						},
+					},
+				},
+			},
+			expected: func(job *batchv1.Job) {
+				job.Labels = map[string]string{"team": "art"}
+				job.Annotations["owner"] = "art"
+				job.Spec.ActiveDeadlineSeconds = int64p(600)
+				job.Spec.BackoffLimit = releasecontroller.Int32p(0)
+				job.Spec.Template.Spec.NodeSelector = map[string]string{"kubernetes.io/arch": "arm64"}
+				job.Spec.Template.Spec.Tolerations = []corev1.Toleration{
+					{Key: "node-role.kubernetes.io/builder", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+				}
+				// The template of a container that is not part of the job is ignored, rather than adding the container
+				job.Spec.Template.Spec.Containers[0].Resources = corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("2Gi")},
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+				}
+			},
+		},
+		{
+			name: "ReleaseAnnotationsKept",
+			template: &v1alpha1.ReleaseJobTemplate{
+				Annotations: map[string]string{
+					releasecontroller.ReleaseAnnotationReleaseTag: "4.14.0-0.nightly-2023-06-02-000000",
+					"release.openshift.io/untracked":              "true",
+					"owner":                                       "art",
+				},
+			},
+			expected: func(job *batchv1.Job) {
+				job.Annotations["owner"] = "art"
+			},
+		},
+		{
+			name: "ReleaseLabelsKept",
+			template: &v1alpha1.ReleaseJobTemplate{
+				Labels: map[string]string{
+					"release.openshift.io/payload": "4.14.0-0.nightly-2023-06-02-000000",
+					"team":                         "art",
+				},
+			},
+			expected: func(job *batchv1.Job) {
+				job.Labels = map[string]string{"team": "art"}
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := newTestReleaseJob()
+			expected := job.DeepCopy()
+			tc.expected(expected)
+
+			merged := mergeJobTemplate(job, tc.template)
+			if diff := cmp.Diff(expected, merged); diff != "" {
+				t.Errorf("unexpected job (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEnsureJobTemplate(t *testing.T) {
+	releasePayloadIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := releasePayloadIndexer.Add(&v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.14.0-0.nightly-2023-06-01-000000",
+			Namespace: "ocp",
+		},
+		Spec: v1alpha1.ReleasePayloadSpec{
+			JobTemplate: &v1alpha1.ReleaseJobTemplate{
+				ActiveDeadlineSeconds: int64p(600),
+				Containers: []v1alpha1.ReleaseJobContainerTemplate{
+					{
+						Name: "build",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+						},
+					},
+				},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("unable to add ReleasePayload: %v", err)
+	}
+
+	kubeClient := fake.NewSimpleClientset()
+	kubeFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+	c := &Controller{
+		jobNamespace: "ci-release",
+		jobClient:    kubeClient.BatchV1(),
+		jobLister:    kubeFactory.Batch().V1().Jobs().Lister(),
+		releasePayloadLister: &releasecontroller.MultiReleasePayloadLister{
+			Listers: map[string]releasepayloadlisters.ReleasePayloadNamespaceLister{
+				"ocp": releasepayloadlisters.NewReleasePayloadLister(releasePayloadIndexer).ReleasePayloads("ocp"),
+			},
+		},
+		defaultJobActiveDeadlineSeconds: 3600,
+		jobServiceAccount:               "builder",
+	}
+
+	job, err := c.ensureJob("4.14.0-0.nightly-2023-06-01-000000", nil, func() (*batchv1.Job, error) {
+		return newTestReleaseJob(), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	created, err := kubeClient.BatchV1().Jobs("ci-release").Get(context.TODO(), job.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get job: %v", err)
+	}
+	if created.Spec.ActiveDeadlineSeconds == nil || *created.Spec.ActiveDeadlineSeconds != 600 {
+		t.Errorf("expected the activeDeadlineSeconds of the job template, got %v", created.Spec.ActiveDeadlineSeconds)
+	}
+	if created.Spec.Template.Spec.ServiceAccountName != "builder" {
+		t.Errorf("expected the service account of the release-controller, got %q", created.Spec.Template.Spec.ServiceAccountName)
+	}
+	if created.Name != "4.14.0-0.nightly-2023-06-01-000000" {
+		t.Errorf("expected the name of the job to be kept, got %q", created.Name)
+	}
+	if memory := created.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceMemory]; memory.Cmp(resource.MustParse("2Gi")) != 0 {
+		t.Errorf("expected the memory request of the job template, got %s", memory.String())
+	}
+	if created.Spec.Template.Spec.Containers[0].Image != "registry.ci.openshift.org/ocp/4.14:cli" {
+		t.Errorf("expected the image set by the release-controller to be kept, got %q", created.Spec.Template.Spec.Containers[0].Image)
+	}
+}
+
+func TestEnsureReleaseJobs(t *testing.T) {
+	testCases := []struct {
+		name          string
+		architectures []string
+		completed     []string
+		notObserved   bool
+		expected      map[string]string
+		expectedErr   bool
+	}{
+		{
+			name: "SingleArchitecture",
+			expected: map[string]string{
+				"4.14.0-0.nightly-2023-06-01-000000": "",
+			},
+		},
+		{
+			name:          "MultipleArchitectures",
+			architectures: []string{"amd64", "arm64"},
+			expected: map[string]string{
+				"4.14.0-0.nightly-2023-06-01-000000-amd64": "amd64",
+				"4.14.0-0.nightly-2023-06-01-000000-arm64": "arm64",
+			},
+		},
+		{
+			name:          "MultipleArchitecturesPartiallyCreated",
+			architectures: []string{"amd64", "arm64"},
+			completed:     []string{"4.14.0-0.nightly-2023-06-01-000000-amd64"},
+			expected: map[string]string{
+				"4.14.0-0.nightly-2023-06-01-000000-amd64": "amd64",
+				"4.14.0-0.nightly-2023-06-01-000000-arm64": "arm64",
+			},
+		},
+		{
+			name:          "MultipleArchitecturesCreated",
+			architectures: []string{"amd64", "arm64"},
+			completed:     []string{"4.14.0-0.nightly-2023-06-01-000000-amd64", "4.14.0-0.nightly-2023-06-01-000000-arm64"},
+			expected: map[string]string{
+				"4.14.0-0.nightly-2023-06-01-000000-amd64": "amd64",
+				"4.14.0-0.nightly-2023-06-01-000000-arm64": "arm64",
+				"4.14.0-0.nightly-2023-06-01-000000":       "",
+			},
+		},
+		{
+			name:          "ReleasePayloadNotObserved",
+			architectures: []string{"amd64", "arm64"},
+			notObserved:   true,
+			expectedErr:   true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			releasePayloadIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if !tc.notObserved {
+				if err := releasePayloadIndexer.Add(&v1alpha1.ReleasePayload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "4.14.0-0.nightly-2023-06-01-000000",
+						Namespace: "ocp",
+					},
+					Spec: v1alpha1.ReleasePayloadSpec{
+						Architectures: tc.architectures,
+					},
+				}); err != nil {
+					t.Fatalf("unable to add ReleasePayload: %v", err)
+				}
+			}
+
+			kubeClient := fake.NewSimpleClientset()
+			kubeFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+			for _, name := range tc.completed {
+				if err := kubeFactory.Batch().V1().Jobs().Informer().GetIndexer().Add(&batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: "ci-release",
+						Annotations: map[string]string{
+							releasecontroller.ReleaseAnnotationArchitecture: strings.TrimPrefix(name, "4.14.0-0.nightly-2023-06-01-000000-"),
+							releasecontroller.ReleaseAnnotationReleaseTag:   "4.14.0-0.nightly-2023-06-01-000000",
+						},
+					},
+					Status: batchv1.JobStatus{CompletionTime: &metav1.Time{}},
+				}); err != nil {
+					t.Fatalf("unable to add job: %v", err)
+				}
+			}
+			c := &Controller{
+				manifestToolImage: "docker.io/mplatform/manifest-tool:v2.1.6",
+				jobNamespace:      "ci-release",
+				jobClient:    kubeClient.BatchV1(),
+				jobLister:    kubeFactory.Batch().V1().Jobs().Lister(),
+				releasePayloadLister: &releasecontroller.MultiReleasePayloadLister{
+					Listers: map[string]releasepayloadlisters.ReleasePayloadNamespaceLister{
+						"ocp": releasepayloadlisters.NewReleasePayloadLister(releasePayloadIndexer).ReleasePayloads("ocp"),
+					},
+				},
+			}
+			release := &releasecontroller.Release{
+				Target: &imagev1.ImageStream{
+					ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "ocp"},
+					Status:     imagev1.ImageStreamStatus{PublicDockerImageRepository: "registry.ci.openshift.org/ocp/release"},
+				},
+				Config: &releasecontroller.ReleaseConfig{ReferenceMode: "public"},
+			}
+			mirror := &imagev1.ImageStream{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.14-art-latest-2023-06-01-000000",
+					Namespace: "ocp",
+					Annotations: map[string]string{
+						releasecontroller.ReleaseAnnotationTarget:     "ocp/release",
+						releasecontroller.ReleaseAnnotationReleaseTag: "4.14.0-0.nightly-2023-06-01-000000",
+					},
+				},
+				Status: imagev1.ImageStreamStatus{DockerImageRepository: "image-registry.openshift-image-registry.svc:5000/ocp/4.14-art-latest-2023-06-01-000000"},
+			}
+
+			jobs, err := c.ensureReleaseJobs(release, "4.14.0-0.nightly-2023-06-01-000000", mirror)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("expected error: %t, got: %v", tc.expectedErr, err)
+			}
+
+			created := map[string]string{}
+			for _, job := range jobs {
+				created[job.Name] = job.Annotations[releasecontroller.ReleaseAnnotationArchitecture]
+				if job.Annotations[releasecontroller.ReleaseAnnotationReleaseTag] != "4.14.0-0.nightly-2023-06-01-000000" {
+					t.Errorf("%s: expected the release tag annotation, got %q", job.Name, job.Annotations[releasecontroller.ReleaseAnnotationReleaseTag])
+				}
+				if len(tc.architectures) > 0 && job.Name == "4.14.0-0.nightly-2023-06-01-000000" {
+					expectedArgs := []string{
+						"--docker-cfg=/tmp/.docker",
+						"push", "from-args",
+						"--platforms=linux/amd64,linux/arm64",
+						"--template=registry.ci.openshift.org/ocp/release:4.14.0-0.nightly-2023-06-01-000000-ARCH",
+						"--target=registry.ci.openshift.org/ocp/release:4.14.0-0.nightly-2023-06-01-000000",
+					}
+					if diff := cmp.Diff(expectedArgs, job.Spec.Template.Spec.Containers[0].Args); diff != "" {
+						t.Errorf("%s: unexpected manifest list arguments (-want +got):\n%s", job.Name, diff)
+					}
+					continue
+				}
+				if sets.NewString(tc.completed...).Has(job.Name) {
+					continue
+				}
+				command := job.Spec.Template.Spec.Containers[0].Command
+				if toImage := "registry.ci.openshift.org/ocp/release:" + job.Name; command[7] != toImage {
+					t.Errorf("%s: expected the release image to be pushed to %q, got %q", job.Name, toImage, command[7])
+				}
+				if architecture := job.Annotations[releasecontroller.ReleaseAnnotationArchitecture]; len(architecture) > 0 && command[len(command)-1] != architecture {
+					t.Errorf("%s: expected the release to be filtered by %q, got %v", job.Name, architecture, command)
+				}
+			}
+			if diff := cmp.Diff(tc.expected, created, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("unexpected jobs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReleaseJobsComplete(t *testing.T) {
+	running := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "running"}}
+	succeeded := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "succeeded"}, Status: batchv1.JobStatus{CompletionTime: &metav1.Time{}}}
+	failed := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "failed"},
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}},
+		},
+	}
+
+	testCases := []struct {
+		name              string
+		jobs              []*batchv1.Job
+		expectedSucceeded bool
+		expectedComplete  bool
+		expectedFailed    *batchv1.Job
+	}{
+		{
+			name: "Running",
+			jobs: []*batchv1.Job{succeeded, running},
+		},
+		{
+			name:              "AllSucceeded",
+			jobs:              []*batchv1.Job{succeeded, succeeded},
+			expectedSucceeded: true,
+			expectedComplete:  true,
+		},
+		{
+			name:             "OneFailed",
+			jobs:             []*batchv1.Job{running, failed},
+			expectedComplete: true,
+			expectedFailed:   failed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			succeeded, complete, failed := releaseJobsComplete(tc.jobs)
+			if succeeded != tc.expectedSucceeded || complete != tc.expectedComplete || failed != tc.expectedFailed {
+				t.Errorf("expected succeeded=%t, complete=%t, failed=%v, got succeeded=%t, complete=%t, failed=%v", tc.expectedSucceeded, tc.expectedComplete, tc.expectedFailed, succeeded, complete, failed)
+			}
+		})
+	}
+}
@@ -24,5 +24,7 @@ func NewReleasePayloadControllerCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(releasepayloadcontroller.NewReleasePayloadControllerCommand("start"))
+	cmd.AddCommand(releasepayloadcontroller.NewGenerateDashboardCommand("generate-dashboard"))
+	cmd.AddCommand(releasepayloadcontroller.NewGenerateRBACCommand("generate-rbac"))
 	return cmd
 }
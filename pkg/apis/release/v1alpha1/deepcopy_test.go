@@ -0,0 +1,103 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newDeepCopyReleasePayload returns a ReleasePayload with the specified number of blocking, and informing, jobs that
+// each have a single completed JobRunResult
+func newDeepCopyReleasePayload(jobs int) *ReleasePayload {
+	now := metav1.NewTime(time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC))
+	newJobStatuses := func(prefix string) []JobStatus {
+		var statuses []JobStatus
+		for i := 0; i < jobs; i++ {
+			statuses = append(statuses, JobStatus{
+				CIConfigurationName:    fmt.Sprintf("%s-%d", prefix, i),
+				CIConfigurationJobName: fmt.Sprintf("periodic-ci-openshift-release-master-nightly-4.11-%s-%d", prefix, i),
+				AggregateState:         JobStateSuccess,
+				JobRunResults: []JobRunResult{
+					{
+						Coordinates: JobRunCoordinates{
+							Name:      fmt.Sprintf("%s-%d", prefix, i),
+							Namespace: "ci",
+						},
+						StartTime:      now,
+						CompletionTime: &now,
+						State:          JobRunStateSuccess,
+					},
+				},
+			})
+		}
+		return statuses
+	}
+	return &ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+		Status: ReleasePayloadStatus{
+			Conditions: []metav1.Condition{
+				{Type: ConditionPayloadCreated, Status: metav1.ConditionTrue, LastTransitionTime: now},
+				{Type: ConditionPayloadAccepted, Status: metav1.ConditionTrue, LastTransitionTime: now},
+				{Type: ConditionPayloadRejected, Status: metav1.ConditionFalse, LastTransitionTime: now},
+			},
+			ReleaseCreationJobResult: ReleaseCreationJobResult{
+				Status:    ReleaseCreationJobSuccess,
+				StartTime: &now,
+			},
+			BlockingJobResults:  newJobStatuses("blocking"),
+			InformingJobResults: newJobStatuses("informing"),
+		},
+	}
+}
+
+// deepCopySink keeps the copies, made by the allocation test and benchmark, on the heap like those of the controllers
+var deepCopySink *ReleasePayload
+
+func TestReleasePayloadDeepCopyAllocations(t *testing.T) {
+	const jobs = 20
+	releasePayload := newDeepCopyReleasePayload(jobs)
+
+	// The ReleasePayload, the StartTime of its ReleaseCreationJobResult and the backing arrays of Conditions,
+	// BlockingJobResults and InformingJobResults, plus the backing array of JobRunResults, and its CompletionTime, for
+	// every job
+	expected := float64(1 + 1 + 3 + 2*2*jobs)
+	if allocs := testing.AllocsPerRun(100, func() { deepCopySink = releasePayload.DeepCopy() }); allocs != expected {
+		t.Errorf("Expected %v allocations, got %v", expected, allocs)
+	}
+}
+
+func TestReleasePayloadDeepCopyIsIndependent(t *testing.T) {
+	releasePayload := newDeepCopyReleasePayload(2)
+	out := releasePayload.DeepCopy()
+
+	out.Status.Conditions[0].Status = metav1.ConditionFalse
+	out.Status.BlockingJobResults[0].JobRunResults[0].State = JobRunStateFailure
+	out.Status.InformingJobResults[1].JobRunResults[0].CompletionTime.Time = time.Time{}
+
+	if releasePayload.Status.Conditions[0].Status != metav1.ConditionTrue {
+		t.Errorf("Expected the Conditions to be copied")
+	}
+	if releasePayload.Status.BlockingJobResults[0].JobRunResults[0].State != JobRunStateSuccess {
+		t.Errorf("Expected the BlockingJobResults to be copied")
+	}
+	if releasePayload.Status.InformingJobResults[1].JobRunResults[0].CompletionTime.IsZero() {
+		t.Errorf("Expected the InformingJobResults to be copied")
+	}
+}
+
+func BenchmarkReleasePayloadDeepCopy(b *testing.B) {
+	for _, jobs := range []int{0, 10, 100} {
+		releasePayload := newDeepCopyReleasePayload(jobs)
+		b.Run(fmt.Sprintf("Jobs%d", jobs), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				deepCopySink = releasePayload.DeepCopy()
+			}
+		})
+	}
+}
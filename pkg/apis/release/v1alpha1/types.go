@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -117,6 +118,7 @@ type ReleasePayload struct {
 }
 
 // ReleasePayloadSpec has the information to represent a ReleasePayload
+// +kubebuilder:validation:XValidation:rule="!has(self.disableChangelogGeneration) || !self.disableChangelogGeneration || (has(self.payloadCoordinates) && has(self.payloadCoordinates.imagestreamTagName) && self.payloadCoordinates.imagestreamTagName.matches('-[0-9]{4}-[0-9]{2}-[0-9]{2}-[0-9]{6}$'))",message="DisableChangelogGeneration is only allowed for the releases of non-stable release streams"
 type ReleasePayloadSpec struct {
 	// PayloadCoordinates the coordinates of the imagestreamtag that this ReleasePayload was created from
 	PayloadCoordinates PayloadCoordinates `json:"payloadCoordinates,omitempty"`
@@ -126,6 +128,79 @@ type ReleasePayloadSpec struct {
 	PayloadOverride ReleasePayloadOverride `json:"payloadOverride,omitempty"`
 	// PayloadVerificationConfig the configuration that will be used to verify this ReleasePayload
 	PayloadVerificationConfig PayloadVerificationConfig `json:"payloadVerificationConfig,omitempty"`
+	// PauseReconciliation, when true, stops all the release-payload-controllers from modifying the ReleasePayload
+	// +optional
+	PauseReconciliation bool `json:"pauseReconciliation,omitempty"`
+	// Suspended, when true, suspends the release creation jobs of the ReleasePayload, so that they do not start any pods
+	// until it is set back to false.  The release creation jobs are still failed, once they have been incomplete for
+	// longer than the --reject-unknown-status-duration of the release-payload-controller.
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+	// DisableChangelogGeneration, when true, stops the release-controller from pre-generating the changelog of the
+	// release.  Only allowed for the timestamped releases of non-stable release streams (i.e. 4.11.0-0.nightly-2022-02-09-091559).
+	// +optional
+	DisableChangelogGeneration bool `json:"disableChangelogGeneration,omitempty"`
+	// Architectures the architectures (i.e. amd64 and arm64) that the release is created for, with one release creation
+	// job per architecture, followed by a job that assembles their release images into the manifest list of the release.
+	// If empty, a single release creation job creates the release for the architecture of the release imagestream.
+	// +optional
+	// +listType=set
+	Architectures []string `json:"architectures,omitempty"`
+	// JobTemplate, when set, is merged into the jobs that the release-controller creates for the release.  Its fields
+	// take precedence over the ones set by the release-controller.
+	// +optional
+	JobTemplate *ReleaseJobTemplate `json:"jobTemplate,omitempty"`
+}
+
+// ReleaseJobTemplate holds the fields, of the batchv1.Jobs that the release-controller creates for a release, that
+// a ReleasePayload may override.  The image, command, service account and name of the jobs are always set by the
+// release-controller.
+type ReleaseJobTemplate struct {
+	// Labels are added to the labels of the jobs.  The release.openshift.io labels, that are kept in sync with the
+	// ReleasePayload, can not be overridden.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to the annotations of the jobs.  The release.openshift.io annotations, that the jobs are
+	// tracked by, can not be overridden.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// ActiveDeadlineSeconds the duration, in seconds, that the jobs may be active before they are terminated
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// BackoffLimit the number of retries before the jobs are marked as failed
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// NodeSelector is added to the node selector of the pods of the jobs
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are added to the tolerations of the pods of the jobs
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Containers overrides the compute resources of the containers of the jobs, matched by name
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Containers []ReleaseJobContainerTemplate `json:"containers,omitempty"`
+}
+
+// ReleaseJobContainerTemplate holds the fields, of a container of the release-controller's batchv1.Jobs, that a
+// ReleasePayload may override
+type ReleaseJobContainerTemplate struct {
+	// Name of the container, i.e. "build"
+	Name string `json:"name"`
+
+	// Resources are merged into the compute resources of the container, with the requests and limits of the template
+	// taking precedence
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // PayloadCoordinates houses the information pointing to the location of the imagesteamtag that this ReleasePayload
@@ -161,6 +236,24 @@ type PayloadCreationConfig struct {
 
 	// ProwCoordinates houses the configuration for Prow
 	ProwCoordinates ProwCoordinates `json:"prowCoordinates,omitempty"`
+
+	// ActiveDeadlineSeconds the duration, in seconds, that the release creation batchv1.Job may be active before
+	// it is terminated.  Takes precedence over the release-controller's default.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// SigningConfig houses the configuration used to sign the release image, once it has been created.  The release
+	// image is not signed if unset.
+	// +optional
+	SigningConfig *SigningConfig `json:"signingConfig,omitempty"`
+}
+
+// SigningConfig houses the information necessary to GPG sign the release image
+type SigningConfig struct {
+	// GPGKeyRef selects the key, of a Secret in the namespace of the ReleasePayload, that holds the GPG private key
+	// used to sign the digest of the release image.  The key may be ASCII armored.
+	GPGKeyRef corev1.SecretKeySelector `json:"gpgKeyRef"`
 }
 
 // ReleaseCreationCoordinates houses the information pointing to the location of the release creation job
@@ -259,7 +352,7 @@ type CIConfiguration struct {
 // ReleasePayloadStatus the status of all the promotion test jobs
 type ReleasePayloadStatus struct {
 	// Conditions communicates the state of the ReleasePayload.
-	// Supported conditions include PayloadCreated, PayloadFailed, PayloadAccepted, and PayloadRejected.
+	// Supported conditions include PayloadCreated, PayloadFailed, PayloadAccepted, PayloadRejected, and Ready.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
 	// ReleaseCreationJobResult stores the coordinates and status of the release creation job that is
@@ -269,6 +362,21 @@ type ReleasePayloadStatus struct {
 	// the release-controller will then begin the validation process.
 	ReleaseCreationJobResult ReleaseCreationJobResult `json:"releaseCreationJobResult,omitempty"`
 
+	// ArchitectureResults stores the coordinates and status of the release creation job of each of the Architectures
+	// in the ReleasePayloadSpec.  The ReleaseCreationJobResult is then the worst result of all the architectures.
+	// +optional
+	ArchitectureResults map[string]ReleaseCreationJobResult `json:"architectureResults,omitempty"`
+
+	// ReleaseDigest is the digest of the release image that was pushed by the release creation job
+	ReleaseDigest string `json:"releaseDigest,omitempty"`
+
+	// AcceptedBy is the user that manually approved the ReleasePayload, via the
+	// release.openshift.io/approved-by annotation
+	AcceptedBy string `json:"acceptedBy,omitempty"`
+
+	// AcceptedAt is the time that the ReleasePayload was manually approved
+	AcceptedAt *metav1.Time `json:"acceptedAt,omitempty"`
+
 	// BlockingJobResults stores the results of all blocking jobs
 	BlockingJobResults []JobStatus `json:"blockingJobResults,omitempty"`
 
@@ -277,6 +385,41 @@ type ReleasePayloadStatus struct {
 
 	// UpgradeJobResults stores the results of generated upgrade jobs
 	UpgradeJobResults []JobStatus `json:"upgradeJobResults,omitempty"`
+
+	// KnownBugCount is the number of open bugs, in the configured bug tracker, targeting the release.  It is counted
+	// when the ReleasePayload is Accepted, and is unset until then.
+	// +optional
+	KnownBugCount *int32 `json:"knownBugCount,omitempty"`
+
+	// SBOMConfigMapRef references the ConfigMap, in the namespace of the ReleasePayload, holding the Software Bill of
+	// Materials of every component image of the release.  It is generated when the ReleasePayload is Accepted, and is
+	// unset until then.
+	// +optional
+	SBOMConfigMapRef corev1.LocalObjectReference `json:"sbomConfigMapRef,omitempty"`
+
+	// SBOMFailureMessage records why the Software Bill of Materials of the release could not be stored.  Once it is
+	// set, the SBOM is not generated again.
+	// +optional
+	SBOMFailureMessage string `json:"sbomFailureMessage,omitempty"`
+
+	// StatusHistory stores the most recent transitions, oldest first, of the ReleaseCreationJobResult.  Only the last
+	// StatusHistoryLimit transitions are kept.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	StatusHistory []ReleasePayloadStatusSnapshot `json:"statusHistory,omitempty"`
+}
+
+// StatusHistoryLimit is the maximum number of snapshots kept in the StatusHistory of a ReleasePayload
+const StatusHistoryLimit = 10
+
+// ReleasePayloadStatusSnapshot records a transition of the ReleaseCreationJobResult of a ReleasePayload
+type ReleasePayloadStatusSnapshot struct {
+	// Status is the status of the release creation job after the transition
+	Status ReleaseCreationJobStatus `json:"status"`
+	// Message is the message of the release creation job after the transition, without the logs of a failed job
+	Message string `json:"message,omitempty"`
+	// TransitionTime is the time that the transition was observed
+	TransitionTime metav1.Time `json:"transitionTime"`
 }
 
 // These are valid condition types for ReleasePayloadStatus.
@@ -298,6 +441,10 @@ const (
 	// ConditionPayloadRejected is true if the ReleasePayload has failed one or more of its verification criteria
 	// The release-controller will take no more action in this phase.
 	ConditionPayloadRejected string = "PayloadRejected"
+
+	// ConditionPayloadReady is true once the release creation job has succeeded, and false while it is pending or if it
+	// has failed.  It allows the ReleasePayload to be waited on with standard tooling (i.e. kubectl wait --for=condition=Ready).
+	ConditionPayloadReady string = "Ready"
 )
 
 // ReleaseCreationJobResult houses the information about the Release creation batch/v1 Job.  The release
@@ -311,10 +458,19 @@ type ReleaseCreationJobResult struct {
 	Status ReleaseCreationJobStatus `json:"status,omitempty"`
 	// Message is a human-readable message indicating details about the result of the release creation job
 	Message string `json:"message,omitempty"`
+	// StartTime is the time that the release creation job started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Attempts is the number of times that a pod, of the release creation job, has failed and been retried
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
 }
 
 // ReleaseCreationJobCoordinates houses the information necessary to locate the job execution
 type ReleaseCreationJobCoordinates struct {
+	// Name is the name of the batch/v1 Job, which must be a valid DNS subdomain
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:XValidation:rule="self.matches('^[a-z0-9]([-a-z0-9]*[a-z0-9])?([.][a-z0-9]([-a-z0-9]*[a-z0-9])?)*$')",message="Name must be a valid DNS subdomain"
 	Name      string `json:"name,omitempty"`
 	Namespace string `json:"namespace,omitempty"`
 }
@@ -324,6 +480,8 @@ type ReleaseCreationJobStatus string
 const (
 	// ReleaseCreationJobUnknown means the job's current state is not known
 	ReleaseCreationJobUnknown ReleaseCreationJobStatus = "Unknown"
+	// ReleaseCreationJobPending means the job is running but has not completed its execution
+	ReleaseCreationJobPending ReleaseCreationJobStatus = "Pending"
 	// ReleaseCreationJobSuccess means the job has completed its execution successfully
 	ReleaseCreationJobSuccess ReleaseCreationJobStatus = "Success"
 	// ReleaseCreationJobFailed means the job has failed its execution
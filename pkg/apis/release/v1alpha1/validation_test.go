@@ -0,0 +1,290 @@
+package v1alpha1
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	"sigs.k8s.io/yaml"
+)
+
+// loadReleasePayloadSchema returns the schema, of the ReleasePayload CRD, that the API server validates against
+func loadReleasePayloadSchema(t *testing.T) *apiextensions.JSONSchemaProps {
+	data, err := os.ReadFile("../../../../artifacts/release.openshift.io_releasepayloads.yaml")
+	if err != nil {
+		t.Fatalf("unable to read ReleasePayload CRD: %v", err)
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := yaml.Unmarshal(data, crd); err != nil {
+		t.Fatalf("unable to decode ReleasePayload CRD: %v", err)
+	}
+	for _, version := range crd.Spec.Versions {
+		if version.Name != SchemeGroupVersion.Version {
+			continue
+		}
+		schema := &apiextensions.JSONSchemaProps{}
+		if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(version.Schema.OpenAPIV3Schema, schema, nil); err != nil {
+			t.Fatalf("unable to convert ReleasePayload schema: %v", err)
+		}
+		return schema
+	}
+	t.Fatalf("ReleasePayload CRD does not serve %s", SchemeGroupVersion.Version)
+	return nil
+}
+
+// newReleasePayloadValidator returns a function that validates a ReleasePayload, on creation, against both the
+// OpenAPI schema and the CEL rules of the ReleasePayload CRD
+func newReleasePayloadValidator(t *testing.T) func(releasePayload map[string]interface{}) field.ErrorList {
+	schema := loadReleasePayloadSchema(t)
+	schemaValidator, _, err := validation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: schema})
+	if err != nil {
+		t.Fatalf("unable to create schema validator: %v", err)
+	}
+	structural, err := structuralschema.NewStructural(schema)
+	if err != nil {
+		t.Fatalf("unable to create structural schema: %v", err)
+	}
+	celValidator := cel.NewValidator(structural, true, celconfig.PerCallLimit)
+
+	return func(releasePayload map[string]interface{}) field.ErrorList {
+		errs := validation.ValidateCustomResource(nil, releasePayload, schemaValidator)
+		celErrs, _ := celValidator.Validate(context.TODO(), field.NewPath("root"), structural, releasePayload, nil, celconfig.RuntimeCELCostBudget)
+		return append(errs, celErrs...)
+	}
+}
+
+func TestReleaseCreationJobCoordinatesNameValidation(t *testing.T) {
+	validate := newReleasePayloadValidator(t)
+
+	testCases := []struct {
+		name        string
+		jobName     string
+		expectValid bool
+	}{
+		{
+			name:        "Valid",
+			jobName:     "4.11.0-0.nightly-2022-02-09-091559",
+			expectValid: true,
+		},
+		{
+			name:        "MaximumLength",
+			jobName:     strings.Repeat("a", 253),
+			expectValid: true,
+		},
+		{
+			name:    "TooLong",
+			jobName: strings.Repeat("a", 254),
+		},
+		{
+			name:    "UpperCase",
+			jobName: "4.11.0-0.Nightly-2022-02-09-091559",
+		},
+		{
+			name:    "IllegalCharacters",
+			jobName: "4.11.0-0.nightly_2022-02-09/091559",
+		},
+		{
+			name:    "LeadingDash",
+			jobName: "-4.11.0-0.nightly-2022-02-09-091559",
+		},
+		{
+			name:    "EmptySegment",
+			jobName: "4..11.0-0.nightly-2022-02-09-091559",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			releasePayload := map[string]interface{}{
+				"apiVersion": SchemeGroupVersion.String(),
+				"kind":       "ReleasePayload",
+				"metadata": map[string]interface{}{
+					"name":      "4.11.0-0.nightly-2022-02-09-091559",
+					"namespace": "ocp",
+				},
+				"status": map[string]interface{}{
+					"releaseCreationJobResult": map[string]interface{}{
+						"coordinates": map[string]interface{}{
+							"name":      testCase.jobName,
+							"namespace": "ci-release",
+						},
+					},
+				},
+			}
+
+			errs := validate(releasePayload)
+			if valid := len(errs) == 0; valid != testCase.expectValid {
+				t.Errorf("%s: Expected valid: %t, got errors: %v", testCase.name, testCase.expectValid, errs)
+			}
+		})
+	}
+}
+
+func TestDisableChangelogGenerationValidation(t *testing.T) {
+	validate := newReleasePayloadValidator(t)
+
+	testCases := []struct {
+		name                       string
+		imagestreamTagName         string
+		disableChangelogGeneration bool
+		expectValid                bool
+	}{
+		{
+			name:                       "NightlyRelease",
+			imagestreamTagName:         "4.11.0-0.nightly-2022-02-09-091559",
+			disableChangelogGeneration: true,
+			expectValid:                true,
+		},
+		{
+			name:                       "CIRelease",
+			imagestreamTagName:         "4.11.0-0.ci-2022-02-09-091559",
+			disableChangelogGeneration: true,
+			expectValid:                true,
+		},
+		{
+			name:                       "StableRelease",
+			imagestreamTagName:         "4.11.22",
+			disableChangelogGeneration: true,
+		},
+		{
+			name:                       "CandidateRelease",
+			imagestreamTagName:         "4.12.0-rc.0",
+			disableChangelogGeneration: true,
+		},
+		{
+			name:                       "MissingCoordinates",
+			disableChangelogGeneration: true,
+		},
+		{
+			name:               "StableReleaseWithChangelogGeneration",
+			imagestreamTagName: "4.11.22",
+			expectValid:        true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			spec := map[string]interface{}{
+				"disableChangelogGeneration": testCase.disableChangelogGeneration,
+			}
+			if len(testCase.imagestreamTagName) > 0 {
+				spec["payloadCoordinates"] = map[string]interface{}{
+					"namespace":          "ocp",
+					"imagestreamName":    "release",
+					"imagestreamTagName": testCase.imagestreamTagName,
+				}
+			}
+			releasePayload := map[string]interface{}{
+				"apiVersion": SchemeGroupVersion.String(),
+				"kind":       "ReleasePayload",
+				"metadata": map[string]interface{}{
+					"name":      "4.11.0-0.nightly-2022-02-09-091559",
+					"namespace": "ocp",
+				},
+				"spec": spec,
+			}
+
+			errs := validate(releasePayload)
+			if valid := len(errs) == 0; valid != testCase.expectValid {
+				t.Errorf("%s: Expected valid: %t, got errors: %v", testCase.name, testCase.expectValid, errs)
+			}
+		})
+	}
+}
+
+func TestJobTemplateValidation(t *testing.T) {
+	validate := newReleasePayloadValidator(t)
+
+	testCases := []struct {
+		name        string
+		jobTemplate map[string]interface{}
+		expectValid bool
+	}{
+		{
+			name: "Valid",
+			jobTemplate: map[string]interface{}{
+				"labels":                map[string]interface{}{"team": "art"},
+				"annotations":           map[string]interface{}{"owner": "art"},
+				"activeDeadlineSeconds": int64(7200),
+				"backoffLimit":          int64(0),
+				"nodeSelector":          map[string]interface{}{"kubernetes.io/arch": "arm64"},
+				"tolerations": []interface{}{
+					map[string]interface{}{"key": "node-role.kubernetes.io/builder", "operator": "Exists", "effect": "NoSchedule"},
+				},
+				"containers": []interface{}{
+					map[string]interface{}{
+						"name": "build",
+						"resources": map[string]interface{}{
+							"requests": map[string]interface{}{"cpu": "500m", "memory": "2Gi"},
+							"limits":   map[string]interface{}{"memory": int64(4294967296)},
+						},
+					},
+				},
+			},
+			expectValid: true,
+		},
+		{
+			name:        "ZeroActiveDeadlineSeconds",
+			jobTemplate: map[string]interface{}{"activeDeadlineSeconds": int64(0)},
+		},
+		{
+			name:        "NegativeBackoffLimit",
+			jobTemplate: map[string]interface{}{"backoffLimit": int64(-1)},
+		},
+		{
+			name: "UnnamedContainer",
+			jobTemplate: map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{
+						"resources": map[string]interface{}{
+							"requests": map[string]interface{}{"memory": "2Gi"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "InvalidQuantity",
+			jobTemplate: map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{
+						"name": "build",
+						"resources": map[string]interface{}{
+							"requests": map[string]interface{}{"memory": "two gigabytes"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "InvalidLabels",
+			jobTemplate: map[string]interface{}{"labels": []interface{}{"team=art"}},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			releasePayload := map[string]interface{}{
+				"apiVersion": SchemeGroupVersion.String(),
+				"kind":       "ReleasePayload",
+				"metadata": map[string]interface{}{
+					"name":      "4.11.0-0.nightly-2022-02-09-091559",
+					"namespace": "ocp",
+				},
+				"spec": map[string]interface{}{
+					"jobTemplate": testCase.jobTemplate,
+				},
+			}
+
+			errs := validate(releasePayload)
+			if valid := len(errs) == 0; valid != testCase.expectValid {
+				t.Errorf("%s: Expected valid: %t, got errors: %v", testCase.name, testCase.expectValid, errs)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -108,6 +109,16 @@ func (in *PayloadCreationConfig) DeepCopyInto(out *PayloadCreationConfig) {
 	*out = *in
 	out.ReleaseCreationCoordinates = in.ReleaseCreationCoordinates
 	out.ProwCoordinates = in.ProwCoordinates
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SigningConfig != nil {
+		in, out := &in.SigningConfig, &out.SigningConfig
+		*out = new(SigningConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -204,6 +215,10 @@ func (in *ReleaseCreationJobCoordinates) DeepCopy() *ReleaseCreationJobCoordinat
 func (in *ReleaseCreationJobResult) DeepCopyInto(out *ReleaseCreationJobResult) {
 	*out = *in
 	out.Coordinates = in.Coordinates
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -217,6 +232,84 @@ func (in *ReleaseCreationJobResult) DeepCopy() *ReleaseCreationJobResult {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseJobContainerTemplate) DeepCopyInto(out *ReleaseJobContainerTemplate) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseJobContainerTemplate.
+func (in *ReleaseJobContainerTemplate) DeepCopy() *ReleaseJobContainerTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseJobContainerTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseJobTemplate) DeepCopyInto(out *ReleaseJobTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]ReleaseJobContainerTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseJobTemplate.
+func (in *ReleaseJobTemplate) DeepCopy() *ReleaseJobTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseJobTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReleasePayload) DeepCopyInto(out *ReleasePayload) {
 	*out = *in
@@ -298,9 +391,19 @@ func (in *ReleasePayloadOverride) DeepCopy() *ReleasePayloadOverride {
 func (in *ReleasePayloadSpec) DeepCopyInto(out *ReleasePayloadSpec) {
 	*out = *in
 	out.PayloadCoordinates = in.PayloadCoordinates
-	out.PayloadCreationConfig = in.PayloadCreationConfig
+	in.PayloadCreationConfig.DeepCopyInto(&out.PayloadCreationConfig)
 	out.PayloadOverride = in.PayloadOverride
 	in.PayloadVerificationConfig.DeepCopyInto(&out.PayloadVerificationConfig)
+	if in.Architectures != nil {
+		in, out := &in.Architectures, &out.Architectures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.JobTemplate != nil {
+		in, out := &in.JobTemplate, &out.JobTemplate
+		*out = new(ReleaseJobTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -324,7 +427,18 @@ func (in *ReleasePayloadStatus) DeepCopyInto(out *ReleasePayloadStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.ReleaseCreationJobResult = in.ReleaseCreationJobResult
+	in.ReleaseCreationJobResult.DeepCopyInto(&out.ReleaseCreationJobResult)
+	if in.ArchitectureResults != nil {
+		in, out := &in.ArchitectureResults, &out.ArchitectureResults
+		*out = make(map[string]ReleaseCreationJobResult, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.AcceptedAt != nil {
+		in, out := &in.AcceptedAt, &out.AcceptedAt
+		*out = (*in).DeepCopy()
+	}
 	if in.BlockingJobResults != nil {
 		in, out := &in.BlockingJobResults, &out.BlockingJobResults
 		*out = make([]JobStatus, len(*in))
@@ -346,6 +460,19 @@ func (in *ReleasePayloadStatus) DeepCopyInto(out *ReleasePayloadStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.KnownBugCount != nil {
+		in, out := &in.KnownBugCount, &out.KnownBugCount
+		*out = new(int32)
+		**out = **in
+	}
+	out.SBOMConfigMapRef = in.SBOMConfigMapRef
+	if in.StatusHistory != nil {
+		in, out := &in.StatusHistory, &out.StatusHistory
+		*out = make([]ReleasePayloadStatusSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -358,3 +485,37 @@ func (in *ReleasePayloadStatus) DeepCopy() *ReleasePayloadStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleasePayloadStatusSnapshot) DeepCopyInto(out *ReleasePayloadStatusSnapshot) {
+	*out = *in
+	in.TransitionTime.DeepCopyInto(&out.TransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleasePayloadStatusSnapshot.
+func (in *ReleasePayloadStatusSnapshot) DeepCopy() *ReleasePayloadStatusSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleasePayloadStatusSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SigningConfig) DeepCopyInto(out *SigningConfig) {
+	*out = *in
+	in.GPGKeyRef.DeepCopyInto(&out.GPGKeyRef)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningConfig.
+func (in *SigningConfig) DeepCopy() *SigningConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SigningConfig)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,210 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
+	releasepayloadhelpers "github.com/openshift/release-controller/pkg/releasepayload/v1alpha1helpers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// ReleasePayloadApprovedByAnnotation is set, to the name of a user, to manually approve a ReleasePayload
+	ReleasePayloadApprovedByAnnotation = "release.openshift.io/approved-by"
+
+	// approvedUsersConfigMapKey is the key, of the approved users ConfigMap, that lists the users, one per line, that
+	// are allowed to approve ReleasePayloads
+	approvedUsersConfigMapKey = "users"
+)
+
+// ApprovalController is responsible for recording the manual approval of ReleasePayloads.  A ReleasePayload is
+// approved by setting the release.openshift.io/approved-by annotation to the name of a user listed in the approved
+// users ConfigMap.
+// The ApprovalController reads the following pieces of information:
+//   - .metadata.annotations["release.openshift.io/approved-by"]
+//
+// and writes the following information:
+//   - .status.acceptedBy
+//   - .status.acceptedAt
+type ApprovalController struct {
+	*ReleasePayloadController
+
+	configMapLister    corev1listers.ConfigMapLister
+	configMapNamespace string
+	configMapName      string
+
+	clock clock.PassiveClock
+}
+
+func NewApprovalController(
+	releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer,
+	releasePayloadClient releasepayloadclient.ReleaseV1alpha1Interface,
+	configMapInformer corev1informers.ConfigMapInformer,
+	configMapNamespace, configMapName string,
+	eventRecorder events.Recorder,
+) (*ApprovalController, error) {
+	c := &ApprovalController{
+		ReleasePayloadController: NewReleasePayloadController("Approval Controller",
+			releasePayloadInformer,
+			releasePayloadClient,
+			eventRecorder.WithComponentSuffix("approval-controller"),
+			workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ApprovalController")),
+		configMapLister:    configMapInformer.Lister(),
+		configMapNamespace: configMapNamespace,
+		configMapName:      configMapName,
+		clock:              clock.RealClock{},
+	}
+
+	c.syncFn = c.sync
+	c.cachesToSync = append(c.cachesToSync, configMapInformer.Informer().HasSynced)
+
+	releasePayloadFilter := func(obj interface{}) bool {
+		if releasePayload, ok := obj.(*v1alpha1.ReleasePayload); ok {
+			_, ok := releasePayload.Annotations[ReleasePayloadApprovedByAnnotation]
+			return ok
+		}
+		return false
+	}
+
+	releasePayloadInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: releasePayloadFilter,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.Enqueue,
+			UpdateFunc: func(old, new interface{}) { c.Enqueue(new) },
+			DeleteFunc: c.Enqueue,
+		},
+	})
+
+	configMapFilter := func(obj interface{}) bool {
+		if configMap, ok := obj.(*corev1.ConfigMap); ok {
+			return configMap.Namespace == c.configMapNamespace && configMap.Name == c.configMapName
+		}
+		return false
+	}
+
+	// A change to the approved users may approve payloads that were previously annotated by an unknown user
+	configMapInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: configMapFilter,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.enqueueAnnotatedReleasePayloads,
+			UpdateFunc: func(old, new interface{}) { c.enqueueAnnotatedReleasePayloads(new) },
+		},
+	})
+
+	return c, nil
+}
+
+func (c *ApprovalController) enqueueAnnotatedReleasePayloads(obj interface{}) {
+	releasePayloads, err := c.releasePayloadLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("unable to list releasepayloads: %w", err))
+		return
+	}
+	for _, releasePayload := range releasePayloads {
+		if _, ok := releasePayload.Annotations[ReleasePayloadApprovedByAnnotation]; ok && len(releasePayload.Status.AcceptedBy) == 0 {
+			c.Enqueue(releasePayload)
+		}
+	}
+}
+
+func (c *ApprovalController) sync(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting ApprovalController sync")
+	defer logger.V(4).Info("ApprovalController sync done")
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	// Get the ReleasePayload resource with this namespace/name
+	originalReleasePayload, err := c.releasePayloadLister.ReleasePayloads(namespace).Get(name)
+	// The ReleasePayload resource may no longer exist, in which case we stop processing.
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(originalReleasePayload) {
+		return nil
+	}
+
+	// Approvals are only ever recorded once
+	user := strings.TrimSpace(originalReleasePayload.Annotations[ReleasePayloadApprovedByAnnotation])
+	if len(user) == 0 || len(originalReleasePayload.Status.AcceptedBy) > 0 {
+		return nil
+	}
+
+	approvedUsers, err := c.approvedUsers()
+	if err != nil {
+		return err
+	}
+	if !approvedUsers.Has(user) {
+		klog.Warningf("%s: ignoring approval of ReleasePayload %s/%s by %q: user is not listed in %s/%s", c.name, namespace, name, user, c.configMapNamespace, c.configMapName)
+		return nil
+	}
+
+	releasePayload := originalReleasePayload.DeepCopy()
+	now := metav1.NewTime(c.clock.Now())
+	releasePayload.Status.AcceptedBy = user
+	releasePayload.Status.AcceptedAt = &now
+	releasepayloadhelpers.CanonicalizeReleasePayloadStatus(releasePayload)
+
+	if reflect.DeepEqual(originalReleasePayload, releasePayload) {
+		return nil
+	}
+
+	klog.V(4).Infof("Syncing approval for ReleasePayload: %s/%s", releasePayload.Namespace, releasePayload.Name)
+	_, err = c.releasePayloadClient.ReleasePayloads(releasePayload.Namespace).UpdateStatus(ctx, releasePayload, metav1.UpdateOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	c.eventRecorder.Eventf("ReleasePayloadApproved", "ReleasePayload %s/%s was approved by %s", releasePayload.Namespace, releasePayload.Name, user)
+	return nil
+}
+
+// approvedUsers returns the users, listed in the approved users ConfigMap, that are allowed to approve ReleasePayloads.
+// If the ConfigMap does not exist, then nobody is allowed to approve ReleasePayloads.
+func (c *ApprovalController) approvedUsers() (sets.String, error) {
+	configMap, err := c.configMapLister.ConfigMaps(c.configMapNamespace).Get(c.configMapName)
+	if errors.IsNotFound(err) {
+		klog.Warningf("%s: approved users configmap %s/%s does not exist", c.name, c.configMapNamespace, c.configMapName)
+		return sets.NewString(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	users := sets.NewString()
+	for _, line := range strings.Split(configMap.Data[approvedUsersConfigMapKey], "\n") {
+		if user := strings.TrimSpace(line); len(user) > 0 {
+			users.Insert(user)
+		}
+	}
+	return users, nil
+}
@@ -0,0 +1,136 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	fake2 "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestApprovalSync(t *testing.T) {
+	now := time.Date(2022, 2, 9, 10, 0, 0, 0, time.UTC)
+	acceptedAt := metav1.NewTime(now)
+	previouslyAcceptedAt := metav1.NewTime(now.Add(-time.Hour))
+
+	approvedUsers := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "approved-users",
+			Namespace: "ci",
+		},
+		Data: map[string]string{
+			approvedUsersConfigMapKey: "alice\n bob \n\n",
+		},
+	}
+
+	newReleasePayload := func(approvedBy string, status v1alpha1.ReleasePayloadStatus) *v1alpha1.ReleasePayload {
+		releasePayload := &v1alpha1.ReleasePayload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "4.11.0-0.nightly-2022-02-09-091559",
+				Namespace: "ocp",
+			},
+			Status: status,
+		}
+		if len(approvedBy) > 0 {
+			releasePayload.Annotations = map[string]string{ReleasePayloadApprovedByAnnotation: approvedBy}
+		}
+		return releasePayload
+	}
+
+	testCases := []struct {
+		name           string
+		input          *v1alpha1.ReleasePayload
+		kubeObjects    []runtime.Object
+		expected       *v1alpha1.ReleasePayload
+		expectedEvents []string
+	}{
+		{
+			name:        "NotApproved",
+			input:       newReleasePayload("", v1alpha1.ReleasePayloadStatus{}),
+			kubeObjects: []runtime.Object{approvedUsers},
+			expected:    newReleasePayload("", v1alpha1.ReleasePayloadStatus{}),
+		},
+		{
+			name:           "ApprovedByApprovedUser",
+			input:          newReleasePayload("bob", v1alpha1.ReleasePayloadStatus{}),
+			kubeObjects:    []runtime.Object{approvedUsers},
+			expected:       newReleasePayload("bob", v1alpha1.ReleasePayloadStatus{AcceptedBy: "bob", AcceptedAt: &acceptedAt}),
+			expectedEvents: []string{"ReleasePayloadApproved"},
+		},
+		{
+			name:        "ApprovedByUnknownUser",
+			input:       newReleasePayload("mallory", v1alpha1.ReleasePayloadStatus{}),
+			kubeObjects: []runtime.Object{approvedUsers},
+			expected:    newReleasePayload("mallory", v1alpha1.ReleasePayloadStatus{}),
+		},
+		{
+			name:     "ApprovedUsersConfigMapMissing",
+			input:    newReleasePayload("alice", v1alpha1.ReleasePayloadStatus{}),
+			expected: newReleasePayload("alice", v1alpha1.ReleasePayloadStatus{}),
+		},
+		{
+			name:        "AlreadyApproved",
+			input:       newReleasePayload("bob", v1alpha1.ReleasePayloadStatus{AcceptedBy: "alice", AcceptedAt: &previouslyAcceptedAt}),
+			kubeObjects: []runtime.Object{approvedUsers},
+			expected:    newReleasePayload("bob", v1alpha1.ReleasePayloadStatus{AcceptedBy: "alice", AcceptedAt: &previouslyAcceptedAt}),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			releasePayloadClient := fake.NewSimpleClientset(testCase.input)
+			releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+
+			kubeClient := fake2.NewSimpleClientset(testCase.kubeObjects...)
+			kubeFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, controllerDefaultResyncDuration, informers.WithNamespace("ci"))
+
+			recorder := events.NewInMemoryRecorder("approval-controller-test")
+
+			c, err := NewApprovalController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), kubeFactory.Core().V1().ConfigMaps(), "ci", "approved-users", recorder)
+			if err != nil {
+				t.Fatalf("%s: unable to create controller: %v", testCase.name, err)
+			}
+			defer c.queue.ShutDown()
+			c.clock = clocktesting.NewFakePassiveClock(now)
+
+			releasePayloadInformerFactory.Start(context.Background().Done())
+			kubeFactory.Start(context.Background().Done())
+
+			if !cache.WaitForNamedCacheSync("ApprovalController", context.Background().Done(), c.cachesToSync...) {
+				t.Fatalf("%s: error waiting for caches to sync", testCase.name)
+			}
+
+			if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", testCase.input.Namespace, testCase.input.Name)); err != nil {
+				t.Errorf("%s: unexpected err: %v", testCase.name, err)
+			}
+
+			// Performing a live lookup instead of having to wait for the cache to sink (again)...
+			output, err := c.releasePayloadClient.ReleasePayloads(testCase.input.Namespace).Get(context.TODO(), testCase.input.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			if !cmp.Equal(output, testCase.expected) {
+				t.Errorf("%s: Expected %v, got %v", testCase.name, testCase.expected, output)
+			}
+
+			var reasons []string
+			for _, event := range recorder.Events() {
+				reasons = append(reasons, event.Reason)
+			}
+			if !cmp.Equal(reasons, testCase.expectedEvents) {
+				t.Errorf("%s: Expected events %v, got %v", testCase.name, testCase.expectedEvents, reasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package release_payload_controller
+
+import (
+	"encoding/json"
+
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ReleasePayloadBlockPromotionAnnotation is set, to the reason why, to prevent a ReleasePayload from ever being
+	// promoted (i.e. because it contains a known regression).  The ReleasePayload is Rejected, regardless of the
+	// results of its jobs or of any PayloadOverride, for as long as the annotation is present.
+	ReleasePayloadBlockPromotionAnnotation = "release.openshift.io/block-promotion"
+
+	// ReleasePayloadPromotionBlockedReason programmatic identifier indicating that the ReleasePayload was Rejected
+	// because of the release.openshift.io/block-promotion annotation
+	ReleasePayloadPromotionBlockedReason string = "ReleasePayloadPromotionBlocked"
+)
+
+// promotionBlocked returns the reason, from the release.openshift.io/block-promotion annotation, that the
+// ReleasePayload must not be promoted, and whether the annotation is present
+func promotionBlocked(payload *v1alpha1.ReleasePayload) (string, bool) {
+	reason, ok := payload.Annotations[ReleasePayloadBlockPromotionAnnotation]
+	return reason, ok
+}
+
+// blockPromotionSetBy returns the field manager that last set the release.openshift.io/block-promotion annotation,
+// and when, as recorded in the managedFields of the ReleasePayload.  An empty manager, and a nil time, are returned
+// if the managedFields do not record the annotation.
+func blockPromotionSetBy(payload *v1alpha1.ReleasePayload) (string, *metav1.Time) {
+	var manager string
+	var at *metav1.Time
+	for _, entry := range payload.ManagedFields {
+		if entry.FieldsV1 == nil || !managesAnnotation(entry.FieldsV1.Raw, ReleasePayloadBlockPromotionAnnotation) {
+			continue
+		}
+		if at == nil || (entry.Time != nil && at.Before(entry.Time)) {
+			manager, at = entry.Manager, entry.Time
+		}
+	}
+	return manager, at
+}
+
+// managesAnnotation returns true if the fields, of a managedFields entry, include the annotation
+func managesAnnotation(fields []byte, annotation string) bool {
+	var set struct {
+		Metadata struct {
+			Annotations map[string]json.RawMessage `json:"f:annotations"`
+		} `json:"f:metadata"`
+	}
+	if err := json.Unmarshal(fields, &set); err != nil {
+		return false
+	}
+	_, ok := set.Metadata.Annotations["f:"+annotation]
+	return ok
+}
@@ -0,0 +1,117 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// BugCountController is responsible for recording the number of known bugs, of an Accepted ReleasePayload, from the
+// configured BugTracker.  The bugs are counted once, when the ReleasePayload is Accepted, so that restarts of the
+// controller do not query the BugTracker for every ReleasePayload again.
+// The BugCountController reads the following pieces of information:
+//   - .metadata.name
+//   - .status.conditions.PayloadAccepted
+//   - .status.knownBugCount
+//
+// and writes the following information:
+//   - .status.knownBugCount
+type BugCountController struct {
+	*ReleasePayloadController
+
+	bugTracker BugTracker
+}
+
+func NewBugCountController(
+	releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer,
+	releasePayloadClient releasepayloadclient.ReleaseV1alpha1Interface,
+	eventRecorder events.Recorder,
+	bugTracker BugTracker,
+) (*BugCountController, error) {
+	c := &BugCountController{
+		ReleasePayloadController: NewReleasePayloadController("Bug Count Controller",
+			releasePayloadInformer,
+			releasePayloadClient,
+			eventRecorder.WithComponentSuffix("bug-count-controller"),
+			workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "BugCountController")),
+		bugTracker: bugTracker,
+	}
+
+	c.syncFn = c.sync
+
+	releasePayloadInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			releasePayload, ok := obj.(*v1alpha1.ReleasePayload)
+			return ok && needsBugCount(releasePayload)
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.Enqueue,
+			UpdateFunc: func(old, new interface{}) { c.Enqueue(new) },
+		},
+	})
+
+	return c, nil
+}
+
+// needsBugCount returns true if the ReleasePayload is Accepted but its known bugs have not been counted yet
+func needsBugCount(releasePayload *v1alpha1.ReleasePayload) bool {
+	return v1helpers.IsConditionTrue(releasePayload.Status.Conditions, v1alpha1.ConditionPayloadAccepted) && releasePayload.Status.KnownBugCount == nil
+}
+
+func (c *BugCountController) sync(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting BugCountController sync")
+	defer logger.V(4).Info("BugCountController sync done")
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	// Get the ReleasePayload resource with this namespace/name
+	originalReleasePayload, err := c.releasePayloadLister.ReleasePayloads(namespace).Get(name)
+	// The ReleasePayload resource may no longer exist, in which case we stop processing.
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(originalReleasePayload) {
+		return nil
+	}
+
+	if !needsBugCount(originalReleasePayload) {
+		return nil
+	}
+
+	count, err := c.bugTracker.KnownBugCount(ctx, originalReleasePayload.Name)
+	if err != nil {
+		return fmt.Errorf("unable to count the known bugs of ReleasePayload %s/%s: %w", originalReleasePayload.Namespace, originalReleasePayload.Name, err)
+	}
+
+	releasePayload := originalReleasePayload.DeepCopy()
+	releasePayload.Status.KnownBugCount = &count
+
+	klog.V(4).Infof("Syncing known bug count for ReleasePayload: %s/%s", releasePayload.Namespace, releasePayload.Name)
+	_, err = c.releasePayloadClient.ReleasePayloads(releasePayload.Namespace).UpdateStatus(ctx, releasePayload, metav1.UpdateOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
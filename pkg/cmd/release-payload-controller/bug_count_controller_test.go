@@ -0,0 +1,129 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+)
+
+// fakeBugTracker returns the count, or the err, and records the releases that it was queried for
+type fakeBugTracker struct {
+	count   int32
+	err     error
+	queries []string
+}
+
+func (f *fakeBugTracker) KnownBugCount(ctx context.Context, release string) (int32, error) {
+	f.queries = append(f.queries, release)
+	return f.count, f.err
+}
+
+func TestBugCountSync(t *testing.T) {
+	accepted := []metav1.Condition{
+		{
+			Type:   v1alpha1.ConditionPayloadAccepted,
+			Status: metav1.ConditionTrue,
+			Reason: ReleasePayloadAcceptedReason,
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		status          v1alpha1.ReleasePayloadStatus
+		bugTracker      *fakeBugTracker
+		expected        *int32
+		expectedQueries []string
+		expectedErr     bool
+	}{
+		{
+			name:       "NotAccepted",
+			bugTracker: &fakeBugTracker{count: 3},
+		},
+		{
+			name: "Accepted",
+			status: v1alpha1.ReleasePayloadStatus{
+				Conditions: accepted,
+			},
+			bugTracker:      &fakeBugTracker{count: 3},
+			expected:        pointer.Int32(3),
+			expectedQueries: []string{"4.11.0-0.nightly-2022-02-09-091559"},
+		},
+		{
+			name: "AcceptedWithoutKnownBugs",
+			status: v1alpha1.ReleasePayloadStatus{
+				Conditions: accepted,
+			},
+			bugTracker:      &fakeBugTracker{},
+			expected:        pointer.Int32(0),
+			expectedQueries: []string{"4.11.0-0.nightly-2022-02-09-091559"},
+		},
+		{
+			name: "AlreadyCounted",
+			status: v1alpha1.ReleasePayloadStatus{
+				Conditions:    accepted,
+				KnownBugCount: pointer.Int32(1),
+			},
+			bugTracker: &fakeBugTracker{count: 3},
+			expected:   pointer.Int32(1),
+		},
+		{
+			name: "BugTrackerError",
+			status: v1alpha1.ReleasePayloadStatus{
+				Conditions: accepted,
+			},
+			bugTracker:      &fakeBugTracker{err: fmt.Errorf("service unavailable")},
+			expectedQueries: []string{"4.11.0-0.nightly-2022-02-09-091559"},
+			expectedErr:     true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			input := &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+				},
+				Status: testCase.status,
+			}
+
+			releasePayloadClient := fake.NewSimpleClientset(input)
+			releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+
+			c, err := NewBugCountController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), events.NewInMemoryRecorder("bug-count-controller-test"), testCase.bugTracker)
+			if err != nil {
+				t.Fatalf("%s: unable to create controller: %v", testCase.name, err)
+			}
+			defer c.queue.ShutDown()
+
+			releasePayloadInformerFactory.Start(context.Background().Done())
+
+			if !cache.WaitForNamedCacheSync("BugCountController", context.Background().Done(), c.cachesToSync...) {
+				t.Fatalf("%s: error waiting for caches to sync", testCase.name)
+			}
+
+			if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); (err != nil) != testCase.expectedErr {
+				t.Fatalf("%s: expected error: %t, got: %v", testCase.name, testCase.expectedErr, err)
+			}
+
+			output, err := c.releasePayloadClient.ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("%s: unable to get ReleasePayload: %v", testCase.name, err)
+			}
+			if !cmp.Equal(output.Status.KnownBugCount, testCase.expected) {
+				t.Errorf("%s: Expected known bug count %v, got %v", testCase.name, testCase.expected, output.Status.KnownBugCount)
+			}
+			if !cmp.Equal(testCase.bugTracker.queries, testCase.expectedQueries) {
+				t.Errorf("%s: Expected queries %v, got %v", testCase.name, testCase.expectedQueries, testCase.bugTracker.queries)
+			}
+		})
+	}
+}
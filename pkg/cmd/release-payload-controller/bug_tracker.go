@@ -0,0 +1,124 @@
+package release_payload_controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// BugTrackerTypeBugzilla queries the REST API of a Bugzilla instance
+	BugTrackerTypeBugzilla = "bugzilla"
+
+	// BugTrackerTypeJira queries the REST API of a Jira instance
+	BugTrackerTypeJira = "jira"
+
+	bugTrackerRequestTimeout = 30 * time.Second
+)
+
+var bugTrackerTypes = []string{BugTrackerTypeBugzilla, BugTrackerTypeJira}
+
+// BugTracker counts the known bugs of a release
+type BugTracker interface {
+	// KnownBugCount returns the number of open bugs whose target release is the specified release
+	KnownBugCount(ctx context.Context, release string) (int32, error)
+}
+
+// newBugTracker returns the BugTracker, of the specified type, that queries the REST API served at the url
+func newBugTracker(bugTrackerType, bugTrackerURL string) (BugTracker, error) {
+	base, err := url.Parse(bugTrackerURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url %q: %w", bugTrackerURL, err)
+	}
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, fmt.Errorf("invalid url %q: the scheme must be http or https", bugTrackerURL)
+	}
+	client := &http.Client{Timeout: bugTrackerRequestTimeout}
+	switch bugTrackerType {
+	case BugTrackerTypeBugzilla:
+		return &bugzillaBugTracker{base: base, client: client}, nil
+	case BugTrackerTypeJira:
+		return &jiraBugTracker{base: base, client: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown bug tracker type %q, must be one of: %s", bugTrackerType, strings.Join(bugTrackerTypes, ", "))
+	}
+}
+
+// bugzillaBugTracker counts the unresolved bugs whose target_release is the release
+type bugzillaBugTracker struct {
+	base   *url.URL
+	client *http.Client
+}
+
+func (b *bugzillaBugTracker) KnownBugCount(ctx context.Context, release string) (int32, error) {
+	query := url.Values{}
+	query.Set("target_release", release)
+	query.Set("resolution", "---")
+	query.Set("count_only", "1")
+
+	var response struct {
+		BugCount int32 `json:"bug_count"`
+	}
+	if err := getJSON(ctx, b.client, endpointURL(b.base, "rest", "bug"), query, &response); err != nil {
+		return 0, err
+	}
+	return response.BugCount, nil
+}
+
+// jiraBugTracker counts the unresolved issues whose "Target Version" is the release
+type jiraBugTracker struct {
+	base   *url.URL
+	client *http.Client
+}
+
+func (j *jiraBugTracker) KnownBugCount(ctx context.Context, release string) (int32, error) {
+	query := url.Values{}
+	query.Set("jql", fmt.Sprintf(`"Target Version" = %q AND resolution = Unresolved`, release))
+	// Only the total is needed, not the issues themselves
+	query.Set("maxResults", "0")
+
+	var response struct {
+		Total int32 `json:"total"`
+	}
+	if err := getJSON(ctx, j.client, endpointURL(j.base, "rest", "api", "2", "search"), query, &response); err != nil {
+		return 0, err
+	}
+	return response.Total, nil
+}
+
+// endpointURL returns a copy of the base url with the elements joined to its path
+func endpointURL(base *url.URL, elem ...string) *url.URL {
+	endpoint := *base
+	endpoint.Path = path.Join(append([]string{"/", base.Path}, elem...)...)
+	endpoint.RawPath = ""
+	return &endpoint
+}
+
+// getJSON decodes the JSON response, of a GET request to the endpoint with the query, into the response
+func getJSON(ctx context.Context, client *http.Client, endpoint *url.URL, query url.Values, response interface{}) error {
+	endpoint.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to query %s: %w", endpoint.Redacted(), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d from %s: %s", resp.StatusCode, endpoint.Redacted(), strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("unable to decode the response from %s: %w", endpoint.Redacted(), err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestBugTrackerKnownBugCount(t *testing.T) {
+	const release = "4.11.0-0.nightly-2022-02-09-091559"
+
+	testCases := []struct {
+		name           string
+		bugTrackerType string
+		path           string
+		expectedQuery  url.Values
+		response       string
+		statusCode     int
+		expected       int32
+		expectedErr    bool
+	}{
+		{
+			name:           "Bugzilla",
+			bugTrackerType: BugTrackerTypeBugzilla,
+			path:           "/rest/bug",
+			expectedQuery: url.Values{
+				"target_release": []string{release},
+				"resolution":     []string{"---"},
+				"count_only":     []string{"1"},
+			},
+			response:   `{"bug_count":3}`,
+			statusCode: http.StatusOK,
+			expected:   3,
+		},
+		{
+			name:           "Jira",
+			bugTrackerType: BugTrackerTypeJira,
+			path:           "/rest/api/2/search",
+			expectedQuery: url.Values{
+				"jql":        []string{fmt.Sprintf(`"Target Version" = %q AND resolution = Unresolved`, release)},
+				"maxResults": []string{"0"},
+			},
+			response:   `{"startAt":0,"maxResults":0,"total":7,"issues":[]}`,
+			statusCode: http.StatusOK,
+			expected:   7,
+		},
+		{
+			name:           "ServerError",
+			bugTrackerType: BugTrackerTypeJira,
+			path:           "/rest/api/2/search",
+			response:       `{"errorMessages":["internal error"]}`,
+			statusCode:     http.StatusInternalServerError,
+			expectedErr:    true,
+		},
+		{
+			name:           "MalformedResponse",
+			bugTrackerType: BugTrackerTypeBugzilla,
+			path:           "/rest/bug",
+			response:       `<html></html>`,
+			statusCode:     http.StatusOK,
+			expectedErr:    true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != testCase.path {
+					t.Errorf("%s: expected path %q, got %q", testCase.name, testCase.path, r.URL.Path)
+				}
+				if testCase.expectedQuery != nil && r.URL.Query().Encode() != testCase.expectedQuery.Encode() {
+					t.Errorf("%s: expected query %q, got %q", testCase.name, testCase.expectedQuery.Encode(), r.URL.Query().Encode())
+				}
+				w.WriteHeader(testCase.statusCode)
+				_, _ = w.Write([]byte(testCase.response))
+			}))
+			defer server.Close()
+
+			bugTracker, err := newBugTracker(testCase.bugTrackerType, server.URL)
+			if err != nil {
+				t.Fatalf("%s: unable to create bug tracker: %v", testCase.name, err)
+			}
+			count, err := bugTracker.KnownBugCount(context.TODO(), release)
+			if (err != nil) != testCase.expectedErr {
+				t.Fatalf("%s: expected error: %t, got: %v", testCase.name, testCase.expectedErr, err)
+			}
+			if count != testCase.expected {
+				t.Errorf("%s: expected %d known bugs, got %d", testCase.name, testCase.expected, count)
+			}
+		})
+	}
+}
+
+func TestNewBugTracker(t *testing.T) {
+	testCases := []struct {
+		name           string
+		bugTrackerType string
+		bugTrackerURL  string
+		expectedErr    bool
+	}{
+		{
+			name:           "Bugzilla",
+			bugTrackerType: BugTrackerTypeBugzilla,
+			bugTrackerURL:  "https://bugzilla.redhat.com",
+		},
+		{
+			name:           "Jira",
+			bugTrackerType: BugTrackerTypeJira,
+			bugTrackerURL:  "https://issues.redhat.com",
+		},
+		{
+			name:           "UnknownType",
+			bugTrackerType: "github",
+			bugTrackerURL:  "https://github.com",
+			expectedErr:    true,
+		},
+		{
+			name:           "NoScheme",
+			bugTrackerType: BugTrackerTypeJira,
+			bugTrackerURL:  "issues.redhat.com",
+			expectedErr:    true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, err := newBugTracker(testCase.bugTrackerType, testCase.bugTrackerURL); (err != nil) != testCase.expectedErr {
+				t.Errorf("%s: expected error: %t, got: %v", testCase.name, testCase.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package release_payload_controller
+
+import (
+	"testing"
+	"time"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	imagefake "github.com/openshift/client-go/image/clientset/versioned/fake"
+	imageinformers "github.com/openshift/client-go/image/informers/externalversions"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	batchv1informers "k8s.io/client-go/informers/batch/v1"
+	fake2 "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+)
+
+// ReleasePayloadControllerTestBuilder wires a ReleaseCreationStatusController up to fake clients, and their informers,
+// so that tests only have to declare the objects that exist in the cluster.
+type ReleasePayloadControllerTestBuilder struct {
+	t *testing.T
+
+	releasePayloads []runtime.Object
+	kubeObjects     []runtime.Object
+	imageStreams    []runtime.Object
+	jobsNamespaces  []string
+
+	queue                       workqueue.RateLimitingInterface
+	clock                       clock.PassiveClock
+	rejectUnknownStatusDuration time.Duration
+	jobLogTailBytes             int
+	workers                     int
+	dryRun                      bool
+}
+
+func newReleasePayloadControllerTestBuilder(t *testing.T) *ReleasePayloadControllerTestBuilder {
+	return &ReleasePayloadControllerTestBuilder{t: t, workers: defaultReleaseCreationStatusWorkers}
+}
+
+// WithReleasePayload adds the ReleasePayload to the fake release clientset
+func (b *ReleasePayloadControllerTestBuilder) WithReleasePayload(p *v1alpha1.ReleasePayload) *ReleasePayloadControllerTestBuilder {
+	b.releasePayloads = append(b.releasePayloads, p)
+	return b
+}
+
+// WithBatchJob adds the Job to the fake kubernetes clientset
+func (b *ReleasePayloadControllerTestBuilder) WithBatchJob(j *batchv1.Job) *ReleasePayloadControllerTestBuilder {
+	b.kubeObjects = append(b.kubeObjects, j)
+	return b
+}
+
+// WithKubeObject adds any other object, i.e. a ConfigMap, to the fake kubernetes clientset
+func (b *ReleasePayloadControllerTestBuilder) WithKubeObject(obj runtime.Object) *ReleasePayloadControllerTestBuilder {
+	b.kubeObjects = append(b.kubeObjects, obj)
+	return b
+}
+
+// WithImageStream adds the ImageStream, that the release images are pushed to, to the fake image clientset
+func (b *ReleasePayloadControllerTestBuilder) WithImageStream(is *imagev1.ImageStream) *ReleasePayloadControllerTestBuilder {
+	b.imageStreams = append(b.imageStreams, is)
+	return b
+}
+
+// WithJobsNamespace restricts the watched release creation jobs to the namespace.  May be called multiple times.
+// Jobs in all namespaces are watched if never called.
+func (b *ReleasePayloadControllerTestBuilder) WithJobsNamespace(ns string) *ReleasePayloadControllerTestBuilder {
+	b.jobsNamespaces = append(b.jobsNamespaces, ns)
+	return b
+}
+
+// WithQueue replaces the workqueue of the controller
+func (b *ReleasePayloadControllerTestBuilder) WithQueue(queue workqueue.RateLimitingInterface) *ReleasePayloadControllerTestBuilder {
+	b.queue = queue
+	return b
+}
+
+// WithClock replaces the clock used to determine how long a release creation job has been Unknown
+func (b *ReleasePayloadControllerTestBuilder) WithClock(clock clock.PassiveClock) *ReleasePayloadControllerTestBuilder {
+	b.clock = clock
+	return b
+}
+
+// WithRejectUnknownStatusDuration sets how long a release creation job can be Unknown before it is considered Failed
+func (b *ReleasePayloadControllerTestBuilder) WithRejectUnknownStatusDuration(duration time.Duration) *ReleasePayloadControllerTestBuilder {
+	b.rejectUnknownStatusDuration = duration
+	return b
+}
+
+// WithJobLogTailBytes sets how many bytes, of the logs of a failed release creation job, are appended to its message
+func (b *ReleasePayloadControllerTestBuilder) WithJobLogTailBytes(bytes int) *ReleasePayloadControllerTestBuilder {
+	b.jobLogTailBytes = bytes
+	return b
+}
+
+// WithWorkers sets the number of workers started by Run
+func (b *ReleasePayloadControllerTestBuilder) WithWorkers(workers int) *ReleasePayloadControllerTestBuilder {
+	b.workers = workers
+	return b
+}
+
+// WithDryRun makes the controller send its writes as dry runs
+func (b *ReleasePayloadControllerTestBuilder) WithDryRun() *ReleasePayloadControllerTestBuilder {
+	b.dryRun = true
+	return b
+}
+
+// Build creates the controller, starts its informers and waits for their caches to sync.  The informers are stopped,
+// and the queue shut down, when the test completes.
+func (b *ReleasePayloadControllerTestBuilder) Build() *ReleaseCreationStatusController {
+	c, _ := b.BuildWithImageClient()
+	return c
+}
+
+// BuildWithImageClient is Build that also returns the fake image clientset, so that tests can push release images to
+// the ImageStreams
+func (b *ReleasePayloadControllerTestBuilder) BuildWithImageClient() (*ReleaseCreationStatusController, *imagefake.Clientset) {
+	b.t.Helper()
+
+	kubeClient := fake2.NewSimpleClientset(b.kubeObjects...)
+	kubeFactories := newJobInformerFactories(kubeClient, b.jobsNamespaces, nil)
+	batchJobInformers := make(map[string]batchv1informers.JobInformer)
+	for namespace, factory := range kubeFactories {
+		batchJobInformers[namespace] = factory.Batch().V1().Jobs()
+	}
+
+	releasePayloadClient := fake.NewSimpleClientset(b.releasePayloads...)
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+
+	imageClient := imagefake.NewSimpleClientset(b.imageStreams...)
+	imageStreamInformerFactory := imageinformers.NewSharedInformerFactory(imageClient, controllerDefaultResyncDuration)
+
+	c, err := NewReleaseCreationStatusController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), batchJobInformers, kubeClient.BatchV1(), imageStreamInformerFactory.Image().V1().ImageStreams(), kubeClient.CoreV1(), events.NewInMemoryRecorder("release-creation-status-controller-test"), b.rejectUnknownStatusDuration, b.jobLogTailBytes, b.workers, b.dryRun)
+	if err != nil {
+		b.t.Fatalf("unable to create controller: %v", err)
+	}
+	if b.queue != nil {
+		c.queue.ShutDown()
+		c.queue = b.queue
+	}
+	if b.clock != nil {
+		c.clock = b.clock
+	}
+
+	stopCh := make(chan struct{})
+	b.t.Cleanup(func() {
+		close(stopCh)
+		c.queue.ShutDown()
+	})
+
+	releasePayloadInformerFactory.Start(stopCh)
+	imageStreamInformerFactory.Start(stopCh)
+	for _, factory := range kubeFactories {
+		factory.Start(stopCh)
+	}
+
+	if !cache.WaitForNamedCacheSync("ReleaseCreationStatusController", stopCh, c.cachesToSync...) {
+		b.t.Fatalf("error waiting for caches to sync")
+	}
+
+	return c, imageClient
+}
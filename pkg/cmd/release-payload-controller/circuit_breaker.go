@@ -0,0 +1,169 @@
+package release_payload_controller
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// defaultCircuitBreakerFailureThreshold is the number of consecutive failed calls that opens the circuit
+	defaultCircuitBreakerFailureThreshold = 10
+
+	// defaultCircuitBreakerFailureWindow is how recent the consecutive failed calls must be to open the circuit
+	defaultCircuitBreakerFailureWindow = time.Minute
+
+	// defaultCircuitBreakerOpenDuration is how long the circuit stays open before a call is let through again
+	defaultCircuitBreakerOpenDuration = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned, instead of making the call, while the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// circuitState is the state of a circuitBreaker.  The values match the ones reported by the
+// release_payload_controller_circuit_breaker_state metric.
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitHalfOpen
+	circuitOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitClosed:
+		return "closed"
+	case circuitHalfOpen:
+		return "half-open"
+	case circuitOpen:
+		return "open"
+	}
+	return "unknown"
+}
+
+var circuitBreakerState = metrics.NewGaugeVec(
+	&metrics.GaugeOpts{
+		Name:           "release_payload_controller_circuit_breaker_state",
+		Help:           "The state of the circuit breaker of a release-payload-controller: 0 (closed), 1 (half-open) or 2 (open)",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"name"},
+)
+
+func init() {
+	legacyregistry.MustRegister(circuitBreakerState)
+}
+
+// circuitBreaker stops calls to the API server, while it is unavailable, so that every sync does not fail, and log,
+// the same error.  The circuit opens after failureThreshold consecutive calls fail within failureWindow.  Once
+// openDuration has passed, the circuit is half-open and the next call decides whether it closes or opens again.
+type circuitBreaker struct {
+	name string
+
+	failureThreshold int
+	failureWindow    time.Duration
+	openDuration     time.Duration
+
+	lock         sync.Mutex
+	state        circuitState
+	failures     int
+	firstFailure time.Time
+	openedAt     time.Time
+
+	clock clock.PassiveClock
+}
+
+func newCircuitBreaker(name string) *circuitBreaker {
+	cb := &circuitBreaker{
+		name:             name,
+		failureThreshold: defaultCircuitBreakerFailureThreshold,
+		failureWindow:    defaultCircuitBreakerFailureWindow,
+		openDuration:     defaultCircuitBreakerOpenDuration,
+		clock:            clock.RealClock{},
+	}
+	circuitBreakerState.WithLabelValues(name).Set(float64(circuitClosed))
+	return cb
+}
+
+// retryAfter returns how long, until the circuit is half-open, calls will be rejected.  Zero is returned when calls
+// are allowed.
+func (cb *circuitBreaker) retryAfter() time.Duration {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+	if cb.state != circuitOpen {
+		return 0
+	}
+	remaining := cb.openDuration - cb.clock.Since(cb.openedAt)
+	if remaining <= 0 {
+		cb.setState(circuitHalfOpen)
+		return 0
+	}
+	return remaining
+}
+
+// execute calls fn, unless the circuit is open, and records whether the API server was available
+func (cb *circuitBreaker) execute(fn func() error) error {
+	if cb.retryAfter() > 0 {
+		return ErrCircuitOpen
+	}
+
+	err := fn()
+
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+	if !isServerUnavailable(err) {
+		cb.failures = 0
+		if cb.state != circuitClosed {
+			cb.setState(circuitClosed)
+		}
+		return err
+	}
+
+	now := cb.clock.Now()
+	if cb.state == circuitHalfOpen {
+		cb.open(now)
+		return err
+	}
+	if cb.failures == 0 || now.Sub(cb.firstFailure) > cb.failureWindow {
+		cb.failures, cb.firstFailure = 0, now
+	}
+	cb.failures++
+	if cb.state == circuitClosed && cb.failures >= cb.failureThreshold {
+		cb.open(now)
+	}
+	return err
+}
+
+func (cb *circuitBreaker) open(now time.Time) {
+	cb.failures = 0
+	cb.openedAt = now
+	cb.setState(circuitOpen)
+}
+
+// setState must be called with the lock held
+func (cb *circuitBreaker) setState(state circuitState) {
+	klog.Infof("%s: circuit breaker state changed from %s to %s", cb.name, cb.state, state)
+	cb.state = state
+	circuitBreakerState.WithLabelValues(cb.name).Set(float64(state))
+}
+
+// isServerUnavailable returns true if the error indicates that the API server could not handle the call.  Errors
+// returned by a responsive API server, like NotFound or Conflict, do not count towards opening the circuit.
+func isServerUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var status k8serrors.APIStatus
+	if !errors.As(err, &status) {
+		return true
+	}
+	code := status.Status().Code
+	return code >= 500 || code == 429
+}
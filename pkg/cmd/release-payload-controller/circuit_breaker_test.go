@@ -0,0 +1,123 @@
+package release_payload_controller
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/component-base/metrics/testutil"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	unavailable := k8serrors.NewServiceUnavailable("unavailable")
+	notFound := k8serrors.NewNotFound(schema.GroupResource{Group: "release.openshift.io", Resource: "releasepayloads"}, "4.11.0-0.nightly-2022-02-09-091559")
+
+	// step is a call made, through the circuit breaker, after advancing the clock
+	type step struct {
+		advance       time.Duration
+		err           error
+		calls         int
+		expectedErr   error
+		expectedState circuitState
+	}
+	failures := func(count int, advance time.Duration, err error, expectedState circuitState) []step {
+		var steps []step
+		for i := 0; i < count; i++ {
+			steps = append(steps, step{advance: advance, err: err, calls: 1, expectedErr: err, expectedState: expectedState})
+		}
+		return steps
+	}
+
+	testCases := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name:  "BelowThreshold",
+			steps: failures(9, time.Second, unavailable, circuitClosed),
+		},
+		{
+			name: "OpensAtThreshold",
+			steps: append(failures(9, time.Second, unavailable, circuitClosed),
+				step{advance: time.Second, err: unavailable, calls: 1, expectedErr: unavailable, expectedState: circuitOpen},
+				step{advance: time.Second, err: nil, calls: 0, expectedErr: ErrCircuitOpen, expectedState: circuitOpen},
+			),
+		},
+		{
+			name: "FailuresOutsideWindowDoNotOpen",
+			steps: append(failures(9, time.Second, unavailable, circuitClosed),
+				failures(9, time.Minute, unavailable, circuitClosed)...,
+			),
+		},
+		{
+			name: "SuccessResetsFailures",
+			steps: append(append(failures(9, time.Second, unavailable, circuitClosed),
+				step{advance: time.Second, err: nil, calls: 1, expectedErr: nil, expectedState: circuitClosed}),
+				failures(9, time.Second, unavailable, circuitClosed)...,
+			),
+		},
+		{
+			name: "ResponsiveServerErrorsDoNotOpen",
+			steps: append(failures(9, time.Second, unavailable, circuitClosed),
+				failures(10, time.Second, notFound, circuitClosed)...,
+			),
+		},
+		{
+			name: "ClosesOnSuccessAfterOpenDuration",
+			steps: append(failures(9, time.Second, unavailable, circuitClosed),
+				step{advance: time.Second, err: unavailable, calls: 1, expectedErr: unavailable, expectedState: circuitOpen},
+				step{advance: 30 * time.Second, err: nil, calls: 1, expectedErr: nil, expectedState: circuitClosed},
+			),
+		},
+		{
+			name: "ReopensOnFailureAfterOpenDuration",
+			steps: append(failures(9, time.Second, unavailable, circuitClosed),
+				step{advance: time.Second, err: unavailable, calls: 1, expectedErr: unavailable, expectedState: circuitOpen},
+				step{advance: 30 * time.Second, err: unavailable, calls: 1, expectedErr: unavailable, expectedState: circuitOpen},
+				step{advance: 29 * time.Second, err: nil, calls: 0, expectedErr: ErrCircuitOpen, expectedState: circuitOpen},
+			),
+		},
+		{
+			name: "ConnectionErrorsOpen",
+			steps: append(failures(9, time.Second, errors.New("connection refused"), circuitClosed),
+				step{advance: time.Second, err: errors.New("connection refused"), calls: 1, expectedState: circuitOpen},
+			),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeClock := clocktesting.NewFakePassiveClock(time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC))
+			cb := newCircuitBreaker(fmt.Sprintf("TestCircuitBreaker/%s", testCase.name))
+			cb.clock = fakeClock
+
+			for i, step := range testCase.steps {
+				fakeClock.SetTime(fakeClock.Now().Add(step.advance))
+				calls := 0
+				err := cb.execute(func() error {
+					calls++
+					return step.err
+				})
+				if step.expectedErr != nil && !errors.Is(err, step.expectedErr) {
+					t.Errorf("%s: step %d: Expected error %v, got: %v", testCase.name, i, step.expectedErr, err)
+				}
+				if calls != step.calls {
+					t.Errorf("%s: step %d: Expected %d calls, got: %d", testCase.name, i, step.calls, calls)
+				}
+				if cb.state != step.expectedState {
+					t.Errorf("%s: step %d: Expected state %s, got: %s", testCase.name, i, step.expectedState, cb.state)
+				}
+				value, err := testutil.GetGaugeMetricValue(circuitBreakerState.WithLabelValues(cb.name))
+				if err != nil {
+					t.Fatalf("%s: step %d: unable to read metric: %v", testCase.name, i, err)
+				}
+				if value != float64(step.expectedState) {
+					t.Errorf("%s: step %d: Expected metric %v, got: %v", testCase.name, i, float64(step.expectedState), value)
+				}
+			}
+		})
+	}
+}
@@ -4,22 +4,94 @@ import (
 	"context"
 	"fmt"
 	imageclientset "github.com/openshift/client-go/image/clientset/versioned"
-	imageinformers "github.com/openshift/client-go/image/informers/externalversions"
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned"
 	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
 	"github.com/openshift/release-controller/pkg/version"
+	"github.com/openshift/release-controller/pkg/webhook"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"io"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/informers"
+	batchv1informers "k8s.io/client-go/informers/batch/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 	prowjobclientset "k8s.io/test-infra/prow/client/clientset/versioned"
-	prowjobinformers "k8s.io/test-infra/prow/client/informers/externalversions"
+	"net"
+	"strings"
+	"time"
 )
 
 type Options struct {
 	controllerContext *controllercmd.ControllerContext
+
+	DebugListenAddr string
+
+	ControllerManagerBindAddress string
+
+	WebhookPort    int
+	WebhookCertDir string
+
+	BlockingJobRequeueInterval time.Duration
+
+	JobNamespaces []string
+
+	WatchNamespaces []string
+
+	// ProwJobNamespaces are the namespaces that are watched for ProwJobs.  All namespaces are watched if empty.
+	ProwJobNamespaces []string
+
+	WatchTimeout time.Duration
+
+	EnableCompression bool
+
+	RejectUnknownStatusDuration time.Duration
+
+	JobLogTailBytes int
+
+	ReleaseCreationStatusWorkers int
+
+	QueueDepthAlertThreshold int
+
+	ReleasePayloadClientQPS   float32
+	ReleasePayloadClientBurst int
+
+	ApprovedUsersConfigMap string
+
+	WatchErrorStrategy          string
+	WatchErrorFailFastThreshold int
+
+	MaxInformerCacheSize int
+
+	MaxPayloadsPerStream int
+
+	SyncOnStartup bool
+
+	GarbageCollectTerminalPayloadsAfter time.Duration
+
+	ManageOperatorCondition bool
+
+	EventDedupWindow time.Duration
+
+	BugTrackerType string
+	BugTrackerURL  string
+
+	GenerateSBOM bool
+
+	LogFormat   string
+	ZapLogLevel string
+
+	ReleaseCreationStatusDryRun bool
+
+	ManageGatekeeperPolicy bool
+
+	FederatedClusters     []string
+	FederatedClientConfig string
+	FederatedClientType   string
 }
 
 func NewReleasePayloadControllerCommand(name string) *cobra.Command {
@@ -51,14 +123,157 @@ func NewReleasePayloadControllerCommand(name string) *cobra.Command {
 }
 
 func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&o.BlockingJobRequeueInterval, "blocking-job-requeue-interval", defaultBlockingJobRequeueInterval, "How long to batch the updates of a release payload while all of its blocking jobs are still running.")
+	fs.DurationVar(&o.WatchTimeout, "watch-timeout", defaultWatchTimeout, "The timeout of the list and watch calls made by the informers.  Forces stale watches, i.e. after an API server restart, to be re-established.")
+	fs.BoolVar(&o.EnableCompression, "enable-compression", o.EnableCompression, "Request gzip-compressed responses, including the watch streams of the informers, from the API server.")
+	fs.DurationVar(&o.RejectUnknownStatusDuration, "reject-unknown-status-duration", defaultRejectUnknownStatusDuration, "How long the release creation job of a release payload can be in the Unknown, or Pending, state before the release payload is failed.  Disabled if 0.")
+	fs.IntVar(&o.JobLogTailBytes, "job-log-tail-bytes", defaultJobLogTailBytes, "How many bytes, from the end of the logs of the most recent failed pod, are appended to the message of a failed release creation job.  Disabled if 0.")
+	fs.IntVar(&o.ReleaseCreationStatusWorkers, "release-creation-status-workers", defaultReleaseCreationStatusWorkers, "The number of release payloads whose release creation job status is reconciled concurrently.")
+	fs.IntVar(&o.QueueDepthAlertThreshold, "queue-depth-alert-threshold", defaultQueueDepthAlertThreshold, fmt.Sprintf("The number of release payloads, waiting in the work queue of the release creation status controller, above which %s reports the controller as unhealthy.", queueHealthPath))
+	fs.Float32Var(&o.ReleasePayloadClientQPS, "release-payload-client-qps", defaultReleasePayloadClientQPS, "The maximum queries per second, to the API server, of the release payload client.")
+	fs.IntVar(&o.ReleasePayloadClientBurst, "release-payload-client-burst", defaultReleasePayloadClientBurst, "The maximum burst of queries, to the API server, of the release payload client.")
+	fs.StringVar(&o.WatchErrorStrategy, "watch-error-strategy", WatchErrorStrategyBackoff, fmt.Sprintf("How the informers handle failed list and watch calls.  One of: %s.", strings.Join(watchErrorStrategies, ", ")))
+	fs.IntVar(&o.WatchErrorFailFastThreshold, "watch-error-fail-fast-threshold", defaultWatchErrorFailFastThreshold, "The number of consecutive failed list and watch calls, of an informer, after which the fail-fast watch error strategy exits.")
+	fs.IntVar(&o.MaxInformerCacheSize, "max-informer-cache-size", defaultMaxInformerCacheSize, "The number of release payloads, in the informer cache, above which the controllers, other than the ones removing release payloads, stop processing until release payloads are garbage collected.  Disabled if 0.")
+	fs.IntVar(&o.MaxPayloadsPerStream, "max-payloads-per-stream", defaultMaxPayloadsPerStream, "The number of release payloads, of a release stream, that may be in progress at once.  Newer release payloads are failed once the limit is reached.  Disabled if 0.")
+	fs.BoolVar(&o.SyncOnStartup, "sync-on-startup", true, "Reconcile every existing release payload once, after the informer caches have synced, before processing events.")
+	fs.DurationVar(&o.GarbageCollectTerminalPayloadsAfter, "garbage-collect-terminal-payloads-after", o.GarbageCollectTerminalPayloadsAfter, "How long after their creation that Accepted, Rejected and Failed release payloads are deleted (e.g. 72h).  Disabled if 0.")
+	fs.BoolVar(&o.ManageOperatorCondition, "manage-operator-condition", o.ManageOperatorCondition, fmt.Sprintf("Report whether the release-controller is Upgradeable, based on the release payloads accepted in the past day, on the %s OperatorCondition.  Requires the release-payload-controller to be installed by OLM.", OperatorConditionName))
+	fs.DurationVar(&o.EventDedupWindow, "event-dedup-window", defaultEventDedupWindow, "How long a Warning event suppresses the identical, i.e. same controller, reason and message, Warning events that follow it.  Disabled if 0.")
+	fs.StringSliceVar(&o.JobNamespaces, "job-namespace", o.JobNamespaces, "A namespace to watch for release creation jobs.  May be specified multiple times.  Watches all namespaces if unset.")
+	fs.StringSliceVar(&o.WatchNamespaces, "watch-namespaces", o.WatchNamespaces, "A comma-separated list of the namespaces to watch for release payloads, and their imagestreams.  When set, --job-namespace and --prowjob-namespace must be set too, and only namespace scoped access, i.e. the Roles generated by generate-rbac, is required.  Watches all namespaces if unset.")
+	fs.StringSliceVar(&o.ProwJobNamespaces, "prowjob-namespace", o.ProwJobNamespaces, "A namespace to watch for ProwJobs.  May be specified multiple times.  Watches all namespaces if unset.")
+	fs.StringVar(&o.ApprovedUsersConfigMap, "approved-users-configmap", o.ApprovedUsersConfigMap, "The namespace/name of the ConfigMap listing the users allowed to approve release payloads, via the release.openshift.io/approved-by annotation.  Approvals are ignored if unset.")
+	fs.StringVar(&o.BugTrackerType, "bug-tracker-type", BugTrackerTypeJira, fmt.Sprintf("The type of the bug tracker served at --bug-tracker-url.  One of: %s.", strings.Join(bugTrackerTypes, ", ")))
+	fs.StringVar(&o.BugTrackerURL, "bug-tracker-url", o.BugTrackerURL, "The url of the bug tracker to count the open bugs, targeting an accepted release payload, from (e.g. https://issues.redhat.com).  Disabled if empty.")
+	fs.BoolVar(&o.GenerateSBOM, "generate-sbom", o.GenerateSBOM, "Generate the Software Bill of Materials, of every component image, of accepted release payloads.  Requires oc and syft on the PATH.")
+	fs.StringSliceVar(&o.FederatedClusters, "federated-cluster", o.FederatedClusters, fmt.Sprintf("A member cluster to serve the release payloads of, read-only, on --debug-listen, through the federation API server of --federated-client-config.  May be specified multiple times.  The release payloads are labeled with %s.  No controllers are run: the jobs, imagestreams and prowjobs of the release payloads live on the member clusters.", FederatedClusterNameLabel))
+	fs.StringVar(&o.FederatedClientConfig, "federated-client-config", o.FederatedClientConfig, "The path to the kubeconfig of the federation API server that the --federated-cluster are reached through.")
+	fs.StringVar(&o.FederatedClientType, "federated-client-type", FederatedClientTypeKarmada, fmt.Sprintf("The type of the federation API server of --federated-client-config.  One of: %s.", strings.Join(federatedClientTypes, ", ")))
+	fs.BoolVar(&o.ManageGatekeeperPolicy, "manage-gatekeeper-policy", o.ManageGatekeeperPolicy, fmt.Sprintf("Create the %s ConstraintTemplate, and constraint, so that the OPA Gatekeeper audit reports the release payloads that break the naming convention of their release creation jobs.  The admission webhook still enforces it.  Requires Gatekeeper to be installed.", ReleasePayloadNamingPolicyKind))
+	fs.BoolVar(&o.ReleaseCreationStatusDryRun, "release-creation-status-dry-run", o.ReleaseCreationStatusDryRun, "Compute, and log, the release creation job status of release payloads without writing it.  The status updates, and job label patches, of the release creation status controller are sent to the API server as dry runs.  The other controllers write as usual.")
+	fs.StringVar(&o.LogFormat, "log-format", LogFormatText, fmt.Sprintf("The format of the logs.  One of: %s.  The json format logs through zap, with the controller, namespace and name of the release payload on every record.", strings.Join(logFormats, ", ")))
+	fs.StringVar(&o.ZapLogLevel, "zap-log-level", "info", fmt.Sprintf("The minimum level of the records logged in the json format.  One of: %s.  Verbose records, i.e. -v above 0, are logged at the debug level.", strings.Join(zapLogLevels, ", ")))
+	fs.StringVar(&o.ControllerManagerBindAddress, "controller-manager-bind-address", defaultHealthProbeBindAddress, fmt.Sprintf("The address to serve the health probes (i.e. %s, %s and %s) on.  Disabled if empty.", healthzPath, queueHealthPath, readyzPath))
+	fs.StringVar(&o.DebugListenAddr, "debug-listen", o.DebugListenAddr, "The address to serve debug information (i.e. /debug/active-syncs, /api/v1/releasePayloads/{namespace}/{name}/diff and the /ws/releasePayloads/{namespace}/{name}/status WebSocket) on.  Disabled if empty.")
+	fs.IntVar(&o.WebhookPort, "webhook-port", o.WebhookPort, "The port to serve the ReleasePayload validating admission webhook on.  Disabled if 0.")
+	fs.StringVar(&o.WebhookCertDir, "webhook-cert-dir", o.WebhookCertDir, "The directory containing the serving certificate (tls.crt and tls.key) of the admission webhook.")
 }
 
 func (o *Options) Validate(ctx context.Context) error {
+	if o.BlockingJobRequeueInterval <= 0 {
+		return fmt.Errorf("--blocking-job-requeue-interval must be greater than 0")
+	}
+	if o.WatchTimeout < time.Second {
+		return fmt.Errorf("--watch-timeout must be at least 1s")
+	}
+	if o.RejectUnknownStatusDuration < 0 {
+		return fmt.Errorf("--reject-unknown-status-duration must not be negative")
+	}
+	if o.JobLogTailBytes < 0 {
+		return fmt.Errorf("--job-log-tail-bytes must not be negative")
+	}
+	if o.ReleaseCreationStatusWorkers < 1 {
+		return fmt.Errorf("--release-creation-status-workers must be at least 1")
+	}
+	if o.QueueDepthAlertThreshold < 0 {
+		return fmt.Errorf("--queue-depth-alert-threshold must not be negative")
+	}
+	if o.ReleasePayloadClientQPS <= 0 {
+		return fmt.Errorf("--release-payload-client-qps must be greater than 0")
+	}
+	if o.ReleasePayloadClientBurst < 1 {
+		return fmt.Errorf("--release-payload-client-burst must be at least 1")
+	}
+	if _, err := newWatchErrorHandler("", o.WatchErrorStrategy, o.WatchErrorFailFastThreshold); err != nil {
+		return fmt.Errorf("--watch-error-strategy: %w", err)
+	}
+	if o.LogFormat != LogFormatText && o.LogFormat != LogFormatJSON {
+		return fmt.Errorf("--log-format must be one of: %s", strings.Join(logFormats, ", "))
+	}
+	if _, err := newZapLogger(io.Discard, o.ZapLogLevel); err != nil {
+		return fmt.Errorf("--zap-log-level: %w", err)
+	}
+	if o.WatchErrorFailFastThreshold < 1 {
+		return fmt.Errorf("--watch-error-fail-fast-threshold must be at least 1")
+	}
+	if o.GarbageCollectTerminalPayloadsAfter < 0 {
+		return fmt.Errorf("--garbage-collect-terminal-payloads-after must not be negative")
+	}
+	if o.EventDedupWindow < 0 {
+		return fmt.Errorf("--event-dedup-window must not be negative")
+	}
+	if o.MaxInformerCacheSize < 0 {
+		return fmt.Errorf("--max-informer-cache-size must not be negative")
+	}
+	if o.MaxPayloadsPerStream < 0 {
+		return fmt.Errorf("--max-payloads-per-stream must not be negative")
+	}
+	if len(o.ControllerManagerBindAddress) > 0 {
+		if _, _, err := net.SplitHostPort(o.ControllerManagerBindAddress); err != nil {
+			return fmt.Errorf("--controller-manager-bind-address must be of the form host:port: %w", err)
+		}
+	}
+	if o.WebhookPort < 0 || o.WebhookPort > 65535 {
+		return fmt.Errorf("--webhook-port must be between 0 and 65535")
+	}
+	if o.WebhookPort > 0 && len(o.WebhookCertDir) == 0 {
+		return fmt.Errorf("--webhook-cert-dir must be set when --webhook-port is set")
+	}
+	if len(o.BugTrackerURL) > 0 {
+		if _, err := newBugTracker(o.BugTrackerType, o.BugTrackerURL); err != nil {
+			return fmt.Errorf("--bug-tracker-url: %w", err)
+		}
+	}
+	if o.GenerateSBOM {
+		if _, err := newExecSBOMGenerator(); err != nil {
+			return fmt.Errorf("--generate-sbom: %w", err)
+		}
+	}
+	if len(o.FederatedClusters) > 0 {
+		if err := validateFederatedClusters(o.FederatedClusters); err != nil {
+			return fmt.Errorf("--federated-cluster: %w", err)
+		}
+		if len(o.FederatedClientConfig) == 0 {
+			return fmt.Errorf("--federated-client-config must be set when --federated-cluster is set")
+		}
+		if o.FederatedClientType != FederatedClientTypeKarmada && o.FederatedClientType != FederatedClientTypeClusterpedia {
+			return fmt.Errorf("--federated-client-type must be one of: %s", strings.Join(federatedClientTypes, ", "))
+		}
+		if len(o.DebugListenAddr) == 0 {
+			return fmt.Errorf("--debug-listen must be set when --federated-cluster is set")
+		}
+	}
+	if len(o.ApprovedUsersConfigMap) > 0 {
+		if namespace, name, err := cache.SplitMetaNamespaceKey(o.ApprovedUsersConfigMap); err != nil || len(namespace) == 0 || len(name) == 0 {
+			return fmt.Errorf("--approved-users-configmap must be of the form namespace/name")
+		}
+	}
+	if len(o.WatchNamespaces) > 0 && len(o.FederatedClusters) == 0 {
+		if len(o.JobNamespaces) == 0 {
+			return fmt.Errorf("--job-namespace must be set when --watch-namespaces is set")
+		}
+		if len(o.ProwJobNamespaces) == 0 {
+			return fmt.Errorf("--prowjob-namespace must be set when --watch-namespaces is set")
+		}
+	}
 	return nil
 }
 
 func (o *Options) Run(ctx context.Context) error {
+	if err := configureLogging(o.LogFormat, o.ZapLogLevel); err != nil {
+		return err
+	}
+
+	eventRecorder := o.controllerContext.EventRecorder
+	if o.EventDedupWindow > 0 {
+		eventRecorder = newDedupEventRecorder(eventRecorder, o.EventDedupWindow)
+	}
+
 	inClusterConfig := o.controllerContext.KubeConfig
+	if o.EnableCompression {
+		inClusterConfig = withCompression(inClusterConfig)
+	}
 
 	kubeClient, err := kubernetes.NewForConfig(inClusterConfig)
 	if err != nil {
@@ -66,17 +281,52 @@ func (o *Options) Run(ctx context.Context) error {
 	}
 
 	// Batch Job Informers
-	kubeFactory := informers.NewSharedInformerFactory(kubeClient, controllerDefaultResyncDuration)
-	batchJobInformer := kubeFactory.Batch().V1().Jobs()
+	tweakListOptions := watchTimeoutTweakListOptions(o.WatchTimeout)
+
+	kubeFactories := newJobInformerFactories(kubeClient, o.JobNamespaces, tweakListOptions)
+	batchJobInformers := make(map[string]batchv1informers.JobInformer)
+	for namespace, factory := range kubeFactories {
+		batchJobInformers[namespace] = factory.Batch().V1().Jobs()
+	}
 
 	// ReleasePayload Informers
-	releasePayloadClient, err := releasepayloadclient.NewForConfig(inClusterConfig)
+	var releasePayloadClient releasepayloadclient.Interface
+	releasePayloadClient, err = releasepayloadclient.NewForConfig(withClientRateLimits(inClusterConfig, o.ReleasePayloadClientQPS, o.ReleasePayloadClientBurst))
 	if err != nil {
 		klog.Fatalf("Error building releasePayload clientset: %s", err.Error())
 	}
 
-	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
-	releasePayloadInformer := releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads()
+	if len(o.FederatedClusters) > 0 {
+		federatedConfig, err := clientcmd.BuildConfigFromFlags("", o.FederatedClientConfig)
+		if err != nil {
+			return fmt.Errorf("unable to load --federated-client-config: %w", err)
+		}
+		federatedClient, err := NewFederatedReleasePayloadClientForConfig(withClientRateLimits(federatedConfig, o.ReleasePayloadClientQPS, o.ReleasePayloadClientBurst), o.FederatedClientType, o.FederatedClusters)
+		if err != nil {
+			return err
+		}
+		releasePayloadClient = &federatedClientset{Interface: releasePayloadClient, federated: federatedClient}
+	}
+
+	if len(o.WatchNamespaces) > 0 && len(o.FederatedClusters) == 0 {
+		approvedUsersNamespace, _, _ := cache.SplitMetaNamespaceKey(o.ApprovedUsersConfigMap)
+		scope := namespacedRBACScope{
+			WatchNamespaces:        o.WatchNamespaces,
+			JobNamespaces:          o.JobNamespaces,
+			ProwJobNamespaces:      o.ProwJobNamespaces,
+			ApprovedUsersNamespace: approvedUsersNamespace,
+		}
+		if err := verifyNamespacedAccess(ctx, kubeClient.AuthorizationV1(), scope); err != nil {
+			return fmt.Errorf("the release-payload-controller is not permitted to manage the resources of the watched namespaces: %w", err)
+		}
+	}
+
+	releasePayloadFactories := newReleasePayloadInformerFactories(releasePayloadClient, o.WatchNamespaces, tweakListOptions)
+	releasePayloadInformer := newReleasePayloadInformer(releasePayloadFactories)
+
+	if len(o.FederatedClusters) > 0 {
+		return o.runFederatedView(ctx, releasePayloadFactories, releasePayloadInformer)
+	}
 
 	// ProwJob Informers
 	prowJobClient, err := prowjobclientset.NewForConfig(inClusterConfig)
@@ -84,8 +334,8 @@ func (o *Options) Run(ctx context.Context) error {
 		klog.Fatalf("Error building prowjob clientset: %s", err.Error())
 	}
 
-	prowJobInformerFactory := prowjobinformers.NewSharedInformerFactory(prowJobClient, controllerDefaultResyncDuration)
-	prowJobInformer := prowJobInformerFactory.Prow().V1().ProwJobs()
+	prowJobFactories := newProwJobInformerFactories(prowJobClient, o.ProwJobNamespaces, tweakListOptions)
+	prowJobInformer := newProwJobInformer(prowJobFactories)
 
 	// ImageStream Informers
 	imageStreamClient, err := imageclientset.NewForConfig(inClusterConfig)
@@ -93,79 +343,363 @@ func (o *Options) Run(ctx context.Context) error {
 		klog.Fatalf("Error building imagestream clientset: %s", err.Error())
 	}
 
-	imageStreamInformerFactory := imageinformers.NewSharedInformerFactory(imageStreamClient, controllerDefaultResyncDuration)
-	imageStreamInformer := imageStreamInformerFactory.Image().V1().ImageStreams()
+	imageStreamFactories := newImageStreamInformerFactories(imageStreamClient, o.WatchNamespaces, tweakListOptions)
+	imageStreamInformer := newImageStreamInformer(imageStreamFactories)
 
 	// Payload Verification Controller
-	payloadVerificationController, err := NewPayloadVerificationController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), o.controllerContext.EventRecorder)
+	payloadVerificationController, err := NewPayloadVerificationController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), eventRecorder)
 	if err != nil {
 		return err
 	}
 
 	// Release Creation Status Controller
-	releaseCreationStatusController, err := NewReleaseCreationStatusController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), batchJobInformer, o.controllerContext.EventRecorder)
+	releaseCreationStatusController, err := NewReleaseCreationStatusController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), batchJobInformers, kubeClient.BatchV1(), imageStreamInformer, kubeClient.CoreV1(), eventRecorder, o.RejectUnknownStatusDuration, o.JobLogTailBytes, o.ReleaseCreationStatusWorkers, o.ReleaseCreationStatusDryRun)
 	if err != nil {
 		return err
 	}
 
 	// Release Creation Jobs Controller
-	releaseCreationJobsController, err := NewReleaseCreationJobController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), o.controllerContext.EventRecorder)
+	releaseCreationJobsController, err := NewReleaseCreationJobController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), eventRecorder)
+	if err != nil {
+		return err
+	}
+
+	// Release Creation Job Suspension Controller
+	releaseCreationJobSuspensionController, err := NewReleaseCreationJobSuspensionController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), batchJobInformers, kubeClient.BatchV1(), eventRecorder)
 	if err != nil {
 		return err
 	}
 
 	// Payload Creation Controller
-	payloadCreationController, err := NewPayloadCreationController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), o.controllerContext.EventRecorder)
+	payloadCreationController, err := NewPayloadCreationController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), eventRecorder)
 	if err != nil {
 		return err
 	}
 
 	// Payload Accepted Controller
-	payloadAcceptedController, err := NewPayloadAcceptedController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), o.controllerContext.EventRecorder)
+	payloadAcceptedController, err := NewPayloadAcceptedController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), eventRecorder)
 	if err != nil {
 		return err
 	}
 
 	// Payload Rejected Controller
-	payloadRejectedController, err := NewPayloadRejectedController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), o.controllerContext.EventRecorder)
+	payloadRejectedController, err := NewPayloadRejectedController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), eventRecorder)
 	if err != nil {
 		return err
 	}
 
 	// Aggregated State Controller
-	aggregateStateController, err := NewJobStateController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), o.controllerContext.EventRecorder)
+	aggregateStateController, err := NewJobStateController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), eventRecorder, o.BlockingJobRequeueInterval)
 	if err != nil {
 		return err
 	}
 
 	// ProwJob Controller
-	pjController, err := NewProwJobStatusController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), prowJobInformer, o.controllerContext.EventRecorder)
+	pjController, err := NewProwJobStatusController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), prowJobInformer, eventRecorder)
 	if err != nil {
 		return err
 	}
 
 	// ProwJob Controller
-	legacyResultsController, err := NewLegacyJobStatusController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), imageStreamInformer, o.controllerContext.EventRecorder)
+	legacyResultsController, err := NewLegacyJobStatusController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), imageStreamInformer, eventRecorder)
 	if err != nil {
 		return err
 	}
 
+	// Label Propagation Controller
+	labelPropagationController, err := NewLabelPropagationController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), imageStreamInformer, eventRecorder)
+	if err != nil {
+		return err
+	}
+
+	// Signing Controller
+	signingController, err := NewSigningController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), imageStreamInformer, kubeClient.CoreV1(), kubeClient.CoreV1(), eventRecorder)
+	if err != nil {
+		return err
+	}
+
+	// Release Payload Deletion Controller
+	releasePayloadDeletionController, err := NewReleasePayloadDeletionController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), batchJobInformers, kubeClient.BatchV1(), eventRecorder)
+	if err != nil {
+		return err
+	}
+
+	// Latest Tag Controller
+	latestTagController, err := NewLatestTagController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), imageStreamInformer, imageStreamClient.ImageV1(), eventRecorder)
+	if err != nil {
+		return err
+	}
+
+	// Release Payload Aggregator Controller
+	releasePayloadAggregatorController, err := NewReleasePayloadAggregatorController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), kubeClient.CoreV1(), eventRecorder)
+	if err != nil {
+		return err
+	}
+
+	// Approval Controller
+	var approvalController *ApprovalController
+	var configMapInformerFactory informers.SharedInformerFactory
+	if len(o.ApprovedUsersConfigMap) > 0 {
+		namespace, name, _ := cache.SplitMetaNamespaceKey(o.ApprovedUsersConfigMap)
+		configMapInformerFactory = informers.NewSharedInformerFactoryWithOptions(kubeClient, controllerDefaultResyncDuration, informers.WithNamespace(namespace), informers.WithTweakListOptions(tweakListOptions))
+		approvalController, err = NewApprovalController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), configMapInformerFactory.Core().V1().ConfigMaps(), namespace, name, eventRecorder)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Garbage Collection Controller
+	var garbageCollectionController *GarbageCollectionController
+	if o.GarbageCollectTerminalPayloadsAfter > 0 {
+		garbageCollectionController, err = NewGarbageCollectionController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), eventRecorder, o.GarbageCollectTerminalPayloadsAfter)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Stream Quota Controller
+	var streamQuotaController *StreamQuotaController
+	if o.MaxPayloadsPerStream > 0 {
+		streamQuotaController, err = NewStreamQuotaController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), eventRecorder, o.MaxPayloadsPerStream)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Bug Count Controller
+	var bugCountController *BugCountController
+	if len(o.BugTrackerURL) > 0 {
+		bugTracker, err := newBugTracker(o.BugTrackerType, o.BugTrackerURL)
+		if err != nil {
+			return err
+		}
+		bugCountController, err = NewBugCountController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), eventRecorder, bugTracker)
+		if err != nil {
+			return err
+		}
+	}
+
+	// SBOM Controller
+	var sbomController *SBOMController
+	if o.GenerateSBOM {
+		sbomGenerator, err := newExecSBOMGenerator()
+		if err != nil {
+			return err
+		}
+		sbomController, err = NewSBOMController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), imageStreamInformer, kubeClient.CoreV1(), eventRecorder, sbomGenerator)
+		if err != nil {
+			return err
+		}
+	}
+
+	// PrometheusRule and OLM Status Controllers
+	var prometheusRuleController *PrometheusRuleController
+	var olmStatusController *OLMStatusController
+	if len(o.controllerContext.OperatorNamespace) > 0 {
+		dynamicClient, err := dynamic.NewForConfig(inClusterConfig)
+		if err != nil {
+			return fmt.Errorf("can't build dynamic client: %w", err)
+		}
+		prometheusRuleController = NewPrometheusRuleController(dynamicClient, o.controllerContext.OperatorNamespace, eventRecorder)
+		if o.ManageOperatorCondition {
+			olmStatusController = NewOLMStatusController(releasePayloadInformer, dynamicClient, o.controllerContext.OperatorNamespace, eventRecorder)
+		}
+	} else {
+		klog.Warningf("Unable to determine the namespace of the release-payload-controller, the %s PrometheusRule will not be managed", PrometheusRuleName)
+		if o.ManageOperatorCondition {
+			klog.Warningf("Unable to determine the namespace of the release-payload-controller, the %s OperatorCondition will not be managed", OperatorConditionName)
+		}
+	}
+
+	// Gatekeeper Policy Controller
+	var gatekeeperPolicyController *GatekeeperPolicyController
+	if o.ManageGatekeeperPolicy {
+		dynamicClient, err := dynamic.NewForConfig(inClusterConfig)
+		if err != nil {
+			return fmt.Errorf("can't build dynamic client: %w", err)
+		}
+		gatekeeperPolicyController = NewGatekeeperPolicyController(dynamicClient, eventRecorder)
+	}
+
+	// Keep the recent statuses of every ReleasePayload, and push their changes to the watchers, for the debug server
+	var statusDiffCache *statusDiffCache
+	var statusBroadcaster *statusBroadcaster
+	if len(o.DebugListenAddr) > 0 {
+		statusDiffCache = newStatusDiffCache(releasePayloadInformer, defaultStatusDiffCacheSize)
+		statusBroadcaster = newStatusBroadcaster(releasePayloadInformer)
+	}
+
+	// Handle the watch errors of every informer
+	watchedInformers := make(map[string]cache.SharedIndexInformer)
+	for namespace, factory := range prowJobFactories {
+		watchedInformers[fmt.Sprintf("ProwJobs(%s)", namespace)] = factory.Prow().V1().ProwJobs().Informer()
+	}
+	for namespace, factory := range imageStreamFactories {
+		watchedInformers[fmt.Sprintf("ImageStreams(%s)", namespace)] = factory.Image().V1().ImageStreams().Informer()
+	}
+	for namespace, factory := range releasePayloadFactories {
+		watchedInformers[fmt.Sprintf("ReleasePayloads(%s)", namespace)] = factory.Release().V1alpha1().ReleasePayloads().Informer()
+	}
+	for namespace, batchJobInformer := range batchJobInformers {
+		watchedInformers[fmt.Sprintf("Jobs(%s)", namespace)] = batchJobInformer.Informer()
+	}
+	if configMapInformerFactory != nil {
+		watchedInformers["ConfigMaps"] = configMapInformerFactory.Core().V1().ConfigMaps().Informer()
+	}
+	for name, informer := range watchedInformers {
+		handler, err := newWatchErrorHandler(name, o.WatchErrorStrategy, o.WatchErrorFailFastThreshold)
+		if err != nil {
+			return err
+		}
+		if err := informer.SetWatchErrorHandler(handler); err != nil {
+			return fmt.Errorf("unable to set the watch error handler of the %s informer: %w", name, err)
+		}
+	}
+
 	// Start the informers
-	kubeFactory.Start(ctx.Done())
-	releasePayloadInformerFactory.Start(ctx.Done())
-	prowJobInformerFactory.Start(ctx.Done())
-	imageStreamInformerFactory.Start(ctx.Done())
+	for _, factory := range kubeFactories {
+		factory.Start(ctx.Done())
+	}
+	for _, factory := range releasePayloadFactories {
+		factory.Start(ctx.Done())
+	}
+	for _, factory := range prowJobFactories {
+		factory.Start(ctx.Done())
+	}
+	for _, factory := range imageStreamFactories {
+		factory.Start(ctx.Done())
+	}
+	if configMapInformerFactory != nil {
+		configMapInformerFactory.Start(ctx.Done())
+	}
+
+	controllers := []*ReleasePayloadController{
+		payloadVerificationController.ReleasePayloadController,
+		releaseCreationStatusController.ReleasePayloadController,
+		releaseCreationJobsController.ReleasePayloadController,
+		releaseCreationJobSuspensionController.ReleasePayloadController,
+		payloadCreationController.ReleasePayloadController,
+		payloadAcceptedController.ReleasePayloadController,
+		payloadRejectedController.ReleasePayloadController,
+		pjController.ReleasePayloadController,
+		aggregateStateController.ReleasePayloadController,
+		legacyResultsController.ReleasePayloadController,
+		labelPropagationController.ReleasePayloadController,
+		signingController.ReleasePayloadController,
+		releasePayloadDeletionController.ReleasePayloadController,
+		latestTagController.ReleasePayloadController,
+		releasePayloadAggregatorController.ReleasePayloadController,
+	}
+	if approvalController != nil {
+		controllers = append(controllers, approvalController.ReleasePayloadController)
+	}
+	if garbageCollectionController != nil {
+		controllers = append(controllers, garbageCollectionController.ReleasePayloadController)
+	}
+	if streamQuotaController != nil {
+		controllers = append(controllers, streamQuotaController.ReleasePayloadController)
+	}
+	if bugCountController != nil {
+		controllers = append(controllers, bugCountController.ReleasePayloadController)
+	}
+	if sbomController != nil {
+		controllers = append(controllers, sbomController.ReleasePayloadController)
+	}
+	for _, controller := range controllers {
+		controller.maxCacheSize = o.MaxInformerCacheSize
+		controller.syncOnStartup = o.SyncOnStartup
+	}
+
+	if len(o.DebugListenAddr) > 0 {
+		serveDebug(o.DebugListenAddr, statusDiffCache, statusBroadcaster, controllers...)
+	}
+
+	// The work queue probe is only served by the health probe server.  The controller manager server requires
+	// authorization for every path, other than /healthz, /readyz and /livez, so the kubelet would be refused access to
+	// it there.
+	if len(o.ControllerManagerBindAddress) > 0 {
+		if _, err := serveHealthProbes(ctx, o.ControllerManagerBindAddress, o.QueueDepthAlertThreshold, releaseCreationStatusController.ReleasePayloadController, controllers...); err != nil {
+			return err
+		}
+	}
+
+	if o.WebhookPort > 0 {
+		server := webhook.NewServer(o.WebhookPort, o.WebhookCertDir, kubeClient.CoreV1())
+		go func() {
+			if err := server.Start(ctx); err != nil {
+				klog.Errorf("Admission webhook server failed: %v", err)
+			}
+		}()
+	}
 
 	// Run the Controllers
 	go payloadVerificationController.RunWorkers(ctx, 10)
-	go releaseCreationStatusController.RunWorkers(ctx, 10)
+	go releaseCreationStatusController.Run(ctx)
 	go releaseCreationJobsController.RunWorkers(ctx, 10)
+	go releaseCreationJobSuspensionController.RunWorkers(ctx, 10)
 	go payloadCreationController.RunWorkers(ctx, 10)
 	go payloadAcceptedController.RunWorkers(ctx, 10)
 	go payloadRejectedController.RunWorkers(ctx, 10)
 	go pjController.RunWorkers(ctx, 10)
 	go aggregateStateController.RunWorkers(ctx, 10)
 	go legacyResultsController.RunWorkers(ctx, 10)
+	go labelPropagationController.RunWorkers(ctx, 10)
+	go signingController.RunWorkers(ctx, 10)
+	go releasePayloadDeletionController.RunWorkers(ctx, 10)
+	go latestTagController.RunWorkers(ctx, 10)
+	// The summaries are written per namespace, after a debounce, so a single worker keeps up
+	go releasePayloadAggregatorController.RunWorkers(ctx, 1)
+	if approvalController != nil {
+		go approvalController.RunWorkers(ctx, 10)
+	}
+	if garbageCollectionController != nil {
+		go garbageCollectionController.RunWorkers(ctx, 10)
+	}
+	if streamQuotaController != nil {
+		go streamQuotaController.RunWorkers(ctx, 10)
+	}
+	if bugCountController != nil {
+		go bugCountController.RunWorkers(ctx, 10)
+	}
+	if sbomController != nil {
+		// Generating an SBOM pulls every component image of the release, so only one is generated at a time
+		go sbomController.RunWorkers(ctx, 1)
+	}
+	if prometheusRuleController != nil {
+		go prometheusRuleController.Run(ctx)
+	}
+	if olmStatusController != nil {
+		go olmStatusController.Run(ctx)
+	}
+	if gatekeeperPolicyController != nil {
+		go gatekeeperPolicyController.Run(ctx)
+	}
+
+	<-ctx.Done()
+
+	return nil
+}
+
+// runFederatedView serves the release payloads of the --federated-cluster, read-only, on --debug-listen.  None of the
+// controllers are run, because the jobs, imagestreams and prowjobs that they reconcile the release payloads from live
+// on the member clusters.
+func (o *Options) runFederatedView(ctx context.Context, releasePayloadFactories map[string]releasepayloadinformers.SharedInformerFactory, releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer) error {
+	statusDiffCache := newStatusDiffCache(releasePayloadInformer, defaultStatusDiffCacheSize)
+	statusBroadcaster := newStatusBroadcaster(releasePayloadInformer)
+
+	for namespace, factory := range releasePayloadFactories {
+		name := fmt.Sprintf("ReleasePayloads(%s)", namespace)
+		handler, err := newWatchErrorHandler(name, o.WatchErrorStrategy, o.WatchErrorFailFastThreshold)
+		if err != nil {
+			return err
+		}
+		if err := factory.Release().V1alpha1().ReleasePayloads().Informer().SetWatchErrorHandler(handler); err != nil {
+			return fmt.Errorf("unable to set the watch error handler of the %s informer: %w", name, err)
+		}
+		factory.Start(ctx.Done())
+	}
+
+	serveDebug(o.DebugListenAddr, statusDiffCache, statusBroadcaster)
 
 	<-ctx.Done()
 
@@ -0,0 +1,79 @@
+package release_payload_controller
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+)
+
+// withCompression returns a copy of the config whose clients request gzip-compressed responses.  The informers use
+// these clients for their list and watch calls, so that watch streams with many ReleasePayloads use less bandwidth.
+func withCompression(config *rest.Config) *rest.Config {
+	config = rest.CopyConfig(config)
+	config.DisableCompression = false
+	config.Wrap(newGzipRoundTripperWrapper())
+	return config
+}
+
+func newGzipRoundTripperWrapper() transport.WrapperFunc {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &gzipRoundTripper{delegate: rt}
+	}
+}
+
+// gzipRoundTripper sets the Accept-Encoding header of every request to gzip, and decompresses the responses that
+// the server compressed.  Setting the header explicitly stops net/http from decompressing the responses, so it is
+// done here instead.
+type gzipRoundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *gzipRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(req.Header.Get("Accept-Encoding")) == 0 {
+		req = utilnet.CloneRequest(req)
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	resp, err := rt.delegate.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		return resp, nil
+	}
+	resp.Body = &gzipReadCloser{body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+func (rt *gzipRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return rt.delegate
+}
+
+// gzipReadCloser decompresses the body of a response.  The gzip header is read on the first Read, rather than when
+// the response is received, so that a watch does not block until its first event is sent.
+type gzipReadCloser struct {
+	body   io.ReadCloser
+	reader *gzip.Reader
+}
+
+func (g *gzipReadCloser) Read(p []byte) (int, error) {
+	if g.reader == nil {
+		reader, err := gzip.NewReader(g.body)
+		if err != nil {
+			return 0, err
+		}
+		g.reader = reader
+	}
+	return g.reader.Read(p)
+}
+
+func (g *gzipReadCloser) Close() error {
+	return g.body.Close()
+}
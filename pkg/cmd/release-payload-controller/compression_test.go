@@ -0,0 +1,80 @@
+package release_payload_controller
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestWithCompression(t *testing.T) {
+	testCases := []struct {
+		name     string
+		compress bool
+	}{
+		{
+			name:     "CompressedResponse",
+			compress: true,
+		},
+		{
+			name:     "UncompressedResponse",
+			compress: false,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var lock sync.Mutex
+			var acceptEncoding []string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				lock.Lock()
+				acceptEncoding = append(acceptEncoding, r.Header.Get("Accept-Encoding"))
+				lock.Unlock()
+
+				list := &v1alpha1.ReleasePayloadList{
+					TypeMeta: metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "ReleasePayloadList"},
+					Items: []v1alpha1.ReleasePayload{
+						{ObjectMeta: metav1.ObjectMeta{Name: "4.11.0-0.nightly-2022-02-09-091559", Namespace: "ocp"}},
+					},
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if !testCase.compress {
+					_ = json.NewEncoder(w).Encode(list)
+					return
+				}
+				w.Header().Set("Content-Encoding", "gzip")
+				gz := gzip.NewWriter(w)
+				defer gz.Close()
+				_ = json.NewEncoder(gz).Encode(list)
+			}))
+			defer server.Close()
+
+			client, err := releasepayloadclient.NewForConfig(withCompression(&rest.Config{Host: server.URL}))
+			if err != nil {
+				t.Fatalf("%s: unable to create client: %v", testCase.name, err)
+			}
+
+			releasePayloads, err := client.ReleaseV1alpha1().ReleasePayloads("ocp").List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			if len(releasePayloads.Items) != 1 || releasePayloads.Items[0].Name != "4.11.0-0.nightly-2022-02-09-091559" {
+				t.Errorf("%s: Unexpected ReleasePayloads: %v", testCase.name, releasePayloads.Items)
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			if len(acceptEncoding) != 1 || acceptEncoding[0] != "gzip" {
+				t.Errorf("%s: Expected the Accept-Encoding header to be gzip, got: %v", testCase.name, acceptEncoding)
+			}
+		})
+	}
+}
@@ -1,7 +1,53 @@
 package release_payload_controller
 
-import "time"
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
 
 const (
 	controllerDefaultResyncDuration = 24 * time.Hour
+
+	defaultBlockingJobRequeueInterval = 2 * time.Minute
+
+	defaultWatchTimeout = 5 * time.Minute
+
+	defaultRejectUnknownStatusDuration = 2 * time.Hour
+
+	defaultWatchErrorFailFastThreshold = 5
+
+	defaultMaxInformerCacheSize = 10000
+
+	defaultMaxPayloadsPerStream = 10
+
+	defaultJobLogTailBytes = 4096
+
+	defaultReleasePayloadClientQPS = 20
+
+	defaultReleasePayloadClientBurst = 40
+
+	defaultReleaseCreationStatusWorkers = 5
+
+	defaultQueueDepthAlertThreshold = 500
 )
+
+// watchTimeoutTweakListOptions returns a function, for the informer factories, that sets the timeout of every list
+// and watch call to the specified duration
+func watchTimeoutTweakListOptions(timeout time.Duration) func(*metav1.ListOptions) {
+	return func(options *metav1.ListOptions) {
+		timeoutSeconds := int64(timeout.Seconds())
+		options.TimeoutSeconds = &timeoutSeconds
+	}
+}
+
+// withClientRateLimits returns a copy of the config whose clients are rate limited, client-side, to the specified QPS
+// and burst instead of the client-go defaults (5 and 10), which throttle the controllers when many ReleasePayloads are
+// reconciled at once
+func withClientRateLimits(config *rest.Config, qps float32, burst int) *rest.Config {
+	config = rest.CopyConfig(config)
+	config.QPS = qps
+	config.Burst = burst
+	return config
+}
@@ -0,0 +1,27 @@
+package release_payload_controller
+
+import (
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestWithClientRateLimits(t *testing.T) {
+	original := &rest.Config{Host: "https://api.ci.openshift.org:6443", QPS: 5, Burst: 10}
+
+	config := withClientRateLimits(original, defaultReleasePayloadClientQPS, defaultReleasePayloadClientBurst)
+	if config.QPS != defaultReleasePayloadClientQPS {
+		t.Errorf("Expected QPS %v, got %v", float32(defaultReleasePayloadClientQPS), config.QPS)
+	}
+	if config.Burst != defaultReleasePayloadClientBurst {
+		t.Errorf("Expected burst %d, got %d", defaultReleasePayloadClientBurst, config.Burst)
+	}
+	if config.Host != original.Host {
+		t.Errorf("Expected host %q, got %q", original.Host, config.Host)
+	}
+
+	// The original config, shared with the other clients, is left alone
+	if original.QPS != 5 || original.Burst != 10 {
+		t.Errorf("Expected the original config to be unchanged, got QPS %v and burst %d", original.QPS, original.Burst)
+	}
+}
@@ -4,14 +4,19 @@ import (
 	"context"
 	"fmt"
 	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
 	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
 	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
 	releasepayloadlister "github.com/openshift/release-controller/pkg/client/listers/release/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -25,6 +30,18 @@ type ReleasePayloadController struct {
 	releasePayloadLister releasepayloadlister.ReleasePayloadLister
 	releasePayloadClient releasepayloadclient.ReleaseV1alpha1Interface
 
+	// releasePayloadStore is the informer cache of ReleasePayloads, which may hold at most maxCacheSize items before
+	// processing stops.  Unlimited if maxCacheSize is 0.
+	releasePayloadStore cache.Store
+	maxCacheSize        int
+
+	// shrinksCache is set by the controllers that remove ReleasePayloads.  They are the only way for the informer cache
+	// to shrink, so they keep processing when it holds more than maxCacheSize ReleasePayloads.
+	shrinksCache bool
+
+	// syncOnStartup enqueues every ReleasePayload in the informer cache once, before the workers are started
+	syncOnStartup bool
+
 	eventRecorder events.Recorder
 
 	cachesToSync []cache.InformerSynced
@@ -32,6 +49,25 @@ type ReleasePayloadController struct {
 	queue workqueue.RateLimitingInterface
 
 	syncFn func(ctx context.Context, key string) error
+
+	// activeKeys tracks the keys that are currently being synced, mapped to the time their sync started
+	activeKeys sync.Map
+
+	// reportsPause is only set on the ReleaseCreationStatusController, so that the pause of a ReleasePayload is reported
+	// by a single event rather than one per controller
+	reportsPause bool
+
+	// pausedReleasePayloads tracks the keys of the ReleasePayloads whose reconciliation is paused, mapped to their UID,
+	// so that a single event is emitted when the pause is first observed
+	pausedReleasePayloads sync.Map
+}
+
+// ActiveSync describes a sync operation that is currently in progress
+type ActiveSync struct {
+	Controller string        `json:"controller"`
+	Key        string        `json:"key"`
+	StartTime  time.Time     `json:"startTime"`
+	Elapsed    time.Duration `json:"elapsed"`
 }
 
 func NewReleasePayloadController(
@@ -44,6 +80,7 @@ func NewReleasePayloadController(
 		name:                 name,
 		releasePayloadLister: releasePayloadInformer.Lister(),
 		releasePayloadClient: releasePayloadClient,
+		releasePayloadStore:  releasePayloadInformer.Informer().GetStore(),
 		eventRecorder:        eventRecorder,
 		queue:                queue,
 	}
@@ -76,6 +113,10 @@ func (c *ReleasePayloadController) RunWorkers(ctx context.Context, workers int)
 		return
 	}
 
+	if c.syncOnStartup {
+		c.enqueueAll()
+	}
+
 	for i := 0; i < workers; i++ {
 		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
 	}
@@ -83,6 +124,20 @@ func (c *ReleasePayloadController) RunWorkers(ctx context.Context, workers int)
 	<-ctx.Done()
 }
 
+// enqueueAll adds every ReleasePayload in the informer cache to the queue, so that ReleasePayloads that changed while
+// the controller was not running are reconciled without waiting for their next event
+func (c *ReleasePayloadController) enqueueAll() {
+	releasePayloads, err := c.releasePayloadLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("%s: unable to list releasepayloads: %w", c.name, err))
+		return
+	}
+	klog.Infof("%s: enqueueing %d ReleasePayloads on startup", c.name, len(releasePayloads))
+	for _, releasePayload := range releasePayloads {
+		c.Enqueue(releasePayload)
+	}
+}
+
 func (c *ReleasePayloadController) runWorker(ctx context.Context) {
 	for c.processNextItem(ctx) {
 	}
@@ -99,15 +154,97 @@ func (c *ReleasePayloadController) processNextItem(ctx context.Context) bool {
 	}
 	defer c.queue.Done(key)
 
+	// Stop processing, until the ReleasePayloads have been garbage collected, rather than risk being OOM killed
+	if c.cacheSizeExceeded() {
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	// Every record logged, by the sync, through the logger of the context identifies the ReleasePayload and the
+	// controller
+	namespace, name, _ := cache.SplitMetaNamespaceKey(key.(string))
+	logger := klog.LoggerWithValues(klog.FromContext(ctx), logFieldController, c.name, logFieldNamespace, namespace, logFieldName, name)
+	ctx = klog.NewContext(ctx, logger)
+
+	c.activeKeys.Store(key, time.Now())
+	defer c.activeKeys.Delete(key)
 	err := c.syncFn(ctx, key.(string))
 
+	c.forgetDeletedReleasePayload(key.(string))
+
 	if err == nil {
 		c.queue.Forget(key)
 		return true
 	}
 
-	utilruntime.HandleError(fmt.Errorf("%v failed with : %w", key, err))
+	logger.Error(err, "Sync failed")
 	c.queue.AddRateLimited(key)
 
 	return true
 }
+
+// cacheSizeExceeded returns true, and logs a warning, if the informer cache holds more than maxCacheSize ReleasePayloads
+func (c *ReleasePayloadController) cacheSizeExceeded() bool {
+	if c.shrinksCache || c.maxCacheSize <= 0 || c.releasePayloadStore == nil {
+		return false
+	}
+	size := len(c.releasePayloadStore.ListKeys())
+	if size <= c.maxCacheSize {
+		return false
+	}
+	klog.Warningf("%s: the informer cache holds %d ReleasePayloads, more than the maximum of %d, processing is paused until ReleasePayloads are garbage collected", c.name, size, c.maxCacheSize)
+	return true
+}
+
+// ActiveSyncs returns the keys currently being synced, sorted by their start time
+func (c *ReleasePayloadController) ActiveSyncs() []ActiveSync {
+	now := time.Now()
+	var syncs []ActiveSync
+	c.activeKeys.Range(func(key, value interface{}) bool {
+		startTime := value.(time.Time)
+		syncs = append(syncs, ActiveSync{
+			Controller: c.name,
+			Key:        key.(string),
+			StartTime:  startTime,
+			Elapsed:    now.Sub(startTime),
+		})
+		return true
+	})
+	sort.Slice(syncs, func(i, j int) bool {
+		return syncs[i].StartTime.Before(syncs[j].StartTime)
+	})
+	return syncs
+}
+
+// reconciliationPaused returns true if the ReleasePayload has .spec.pauseReconciliation set.  If the controller
+// reportsPause, a Warning event is emitted the first time the pause is observed.
+func (c *ReleasePayloadController) reconciliationPaused(releasePayload *v1alpha1.ReleasePayload) bool {
+	key := fmt.Sprintf("%s/%s", releasePayload.Namespace, releasePayload.Name)
+	if !releasePayload.Spec.PauseReconciliation {
+		c.pausedReleasePayloads.Delete(key)
+		return false
+	}
+	klog.Infof("%s: reconciliation of ReleasePayload %s/%s is paused", c.name, releasePayload.Namespace, releasePayload.Name)
+	if !c.reportsPause {
+		return true
+	}
+	if uid, alreadyPaused := c.pausedReleasePayloads.Load(key); !alreadyPaused || uid != releasePayload.UID {
+		c.pausedReleasePayloads.Store(key, releasePayload.UID)
+		c.eventRecorder.Warningf("ReconciliationPaused", "Reconciliation of ReleasePayload %s/%s is paused", releasePayload.Namespace, releasePayload.Name)
+	}
+	return true
+}
+
+// forgetDeletedReleasePayload stops tracking the pause of a ReleasePayload once it is no longer found
+func (c *ReleasePayloadController) forgetDeletedReleasePayload(key string) {
+	if _, paused := c.pausedReleasePayloads.Load(key); !paused {
+		return
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return
+	}
+	if _, err := c.releasePayloadLister.ReleasePayloads(namespace).Get(name); errors.IsNotFound(err) {
+		c.pausedReleasePayloads.Delete(key)
+	}
+}
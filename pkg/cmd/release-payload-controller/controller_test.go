@@ -0,0 +1,553 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	imagefake "github.com/openshift/client-go/image/clientset/versioned/fake"
+	imageinformers "github.com/openshift/client-go/image/informers/externalversions"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	batchv1informers "k8s.io/client-go/informers/batch/v1"
+	fake2 "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	prowfake "k8s.io/test-infra/prow/client/clientset/versioned/fake"
+	prowjobinformers "k8s.io/test-infra/prow/client/informers/externalversions"
+)
+
+func TestReconciliationPaused(t *testing.T) {
+	releasePayload := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+			UID:       "paused-release-payload",
+		},
+		Spec: v1alpha1.ReleasePayloadSpec{
+			PayloadCoordinates: v1alpha1.PayloadCoordinates{
+				Namespace:          "ocp",
+				ImagestreamName:    "release",
+				ImagestreamTagName: "4.11.0-0.nightly-2022-02-09-091559",
+			},
+			PayloadCreationConfig: v1alpha1.PayloadCreationConfig{
+				ReleaseCreationCoordinates: v1alpha1.ReleaseCreationCoordinates{
+					Namespace:              "ci-release",
+					ReleaseCreationJobName: "4.11.0-0.nightly-2022-02-09-091559",
+				},
+				ProwCoordinates: v1alpha1.ProwCoordinates{
+					Namespace: "ci",
+				},
+			},
+			PauseReconciliation: true,
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			BlockingJobResults: []v1alpha1.JobStatus{
+				{
+					CIConfigurationName:    "aws-serial",
+					CIConfigurationJobName: "periodic-ci-openshift-release-master-nightly-4.11-e2e-aws-serial",
+					JobRunResults: []v1alpha1.JobRunResult{
+						{
+							State: v1alpha1.JobRunStateFailure,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		dataSource   v1alpha1.PayloadVerificationDataSource
+		reportsPause bool
+		newSyncFn    func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error)
+	}{
+		{
+			name: "ApprovalController",
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				c, err := NewApprovalController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), kubeFactory.Core().V1().ConfigMaps(), "ci", "approved-users", recorder)
+				if err != nil {
+					return nil, err
+				}
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name: "BugCountController",
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				c, err := NewBugCountController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), recorder, &fakeBugTracker{})
+				if err != nil {
+					return nil, err
+				}
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name: "GarbageCollectionController",
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				c, err := NewGarbageCollectionController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), recorder, time.Nanosecond)
+				if err != nil {
+					return nil, err
+				}
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name: "JobStateController",
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				c, err := NewJobStateController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), recorder, time.Minute)
+				if err != nil {
+					return nil, err
+				}
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name: "LabelPropagationController",
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				imageStreamInformerFactory := imageinformers.NewSharedInformerFactory(imagefake.NewSimpleClientset(), controllerDefaultResyncDuration)
+				c, err := NewLabelPropagationController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), imageStreamInformerFactory.Image().V1().ImageStreams(), recorder)
+				if err != nil {
+					return nil, err
+				}
+				imageStreamInformerFactory.Start(context.Background().Done())
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name: "LatestTagController",
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				imageStreamClient := imagefake.NewSimpleClientset()
+				imageStreamInformerFactory := imageinformers.NewSharedInformerFactory(imageStreamClient, controllerDefaultResyncDuration)
+				c, err := NewLatestTagController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), imageStreamInformerFactory.Image().V1().ImageStreams(), imageStreamClient.ImageV1(), recorder)
+				if err != nil {
+					return nil, err
+				}
+				imageStreamInformerFactory.Start(context.Background().Done())
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name:       "LegacyJobStatusController",
+			dataSource: v1alpha1.PayloadVerificationDataSourceImageStream,
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				imageStreamInformerFactory := imageinformers.NewSharedInformerFactory(imagefake.NewSimpleClientset(), controllerDefaultResyncDuration)
+				c, err := NewLegacyJobStatusController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), imageStreamInformerFactory.Image().V1().ImageStreams(), recorder)
+				if err != nil {
+					return nil, err
+				}
+				imageStreamInformerFactory.Start(context.Background().Done())
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name: "PayloadAcceptedController",
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				c, err := NewPayloadAcceptedController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), recorder)
+				if err != nil {
+					return nil, err
+				}
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name: "PayloadCreationController",
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				c, err := NewPayloadCreationController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), recorder)
+				if err != nil {
+					return nil, err
+				}
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name: "PayloadRejectedController",
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				c, err := NewPayloadRejectedController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), recorder)
+				if err != nil {
+					return nil, err
+				}
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name: "PayloadVerificationController",
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				c, err := NewPayloadVerificationController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), recorder)
+				if err != nil {
+					return nil, err
+				}
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name:       "ProwJobStatusController",
+			dataSource: v1alpha1.PayloadVerificationDataSourceBuildFarm,
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				prowJobInformerFactory := prowjobinformers.NewSharedInformerFactory(prowfake.NewSimpleClientset(), controllerDefaultResyncDuration)
+				c, err := NewProwJobStatusController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), prowJobInformerFactory.Prow().V1().ProwJobs(), recorder)
+				if err != nil {
+					return nil, err
+				}
+				prowJobInformerFactory.Start(context.Background().Done())
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name: "ReleaseCreationJobController",
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				c, err := NewReleaseCreationJobController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), recorder)
+				if err != nil {
+					return nil, err
+				}
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name:         "ReleaseCreationStatusController",
+			reportsPause: true,
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				imageStreamInformerFactory := imageinformers.NewSharedInformerFactory(imagefake.NewSimpleClientset(), controllerDefaultResyncDuration)
+				c, err := NewReleaseCreationStatusController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), map[string]batchv1informers.JobInformer{"": kubeFactory.Batch().V1().Jobs()}, kubeClient.BatchV1(), imageStreamInformerFactory.Image().V1().ImageStreams(), kubeClient.CoreV1(), recorder, 0, 0, defaultReleaseCreationStatusWorkers, false)
+				if err != nil {
+					return nil, err
+				}
+				imageStreamInformerFactory.Start(context.Background().Done())
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name: "ReleasePayloadDeletionController",
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				c, err := NewReleasePayloadDeletionController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), map[string]batchv1informers.JobInformer{"": kubeFactory.Batch().V1().Jobs()}, kubeClient.BatchV1(), recorder)
+				if err != nil {
+					return nil, err
+				}
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name: "SigningController",
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				imageStreamInformerFactory := imageinformers.NewSharedInformerFactory(imagefake.NewSimpleClientset(), controllerDefaultResyncDuration)
+				c, err := NewSigningController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), imageStreamInformerFactory.Image().V1().ImageStreams(), kubeClient.CoreV1(), kubeClient.CoreV1(), recorder)
+				if err != nil {
+					return nil, err
+				}
+				imageStreamInformerFactory.Start(context.Background().Done())
+				return c.ReleasePayloadController, nil
+			},
+		},
+		{
+			name: "StreamQuotaController",
+			newSyncFn: func(releasePayloadInformerFactory releasepayloadinformers.SharedInformerFactory, releasePayloadClient *fake.Clientset, kubeClient *fake2.Clientset, kubeFactory informers.SharedInformerFactory, recorder events.Recorder) (*ReleasePayloadController, error) {
+				c, err := NewStreamQuotaController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), recorder, 0)
+				if err != nil {
+					return nil, err
+				}
+				return c.ReleasePayloadController, nil
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			input := releasePayload.DeepCopy()
+			input.Spec.PayloadVerificationConfig.PayloadVerificationDataSource = testCase.dataSource
+
+			releasePayloadClient := fake.NewSimpleClientset(input)
+			releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+
+			kubeClient := fake2.NewSimpleClientset()
+			kubeFactory := informers.NewSharedInformerFactory(kubeClient, controllerDefaultResyncDuration)
+
+			recorder := events.NewInMemoryRecorder("release-payload-controller-test")
+
+			c, err := testCase.newSyncFn(releasePayloadInformerFactory, releasePayloadClient, kubeClient, kubeFactory, recorder)
+			if err != nil {
+				t.Fatalf("%s: unable to create controller: %v", testCase.name, err)
+			}
+
+			releasePayloadInformerFactory.Start(context.Background().Done())
+			kubeFactory.Start(context.Background().Done())
+
+			if !cache.WaitForNamedCacheSync(testCase.name, context.Background().Done(), c.cachesToSync...) {
+				t.Fatalf("%s: error waiting for caches to sync", testCase.name)
+			}
+
+			releasePayloadClient.ClearActions()
+			kubeClient.ClearActions()
+
+			// Sync twice, to verify that the pause is only reported once, and only by the ReleaseCreationStatusController
+			for i := 0; i < 2; i++ {
+				if err := c.syncFn(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+					t.Errorf("%s: unexpected err: %v", testCase.name, err)
+				}
+			}
+
+			for _, action := range releasePayloadClient.Actions() {
+				if action.GetVerb() != "list" && action.GetVerb() != "watch" {
+					t.Errorf("%s: unexpected action on paused ReleasePayload: %v", testCase.name, action)
+				}
+			}
+			for _, action := range kubeClient.Actions() {
+				if action.GetVerb() != "list" && action.GetVerb() != "watch" {
+					t.Errorf("%s: unexpected action while ReleasePayload is paused: %v", testCase.name, action)
+				}
+			}
+
+			var warnings int
+			for _, event := range recorder.Events() {
+				if event.Type == corev1.EventTypeWarning && event.Reason == "ReconciliationPaused" {
+					warnings++
+				}
+			}
+			expected := 0
+			if testCase.reportsPause {
+				expected = 1
+			}
+			if warnings != expected {
+				t.Errorf("%s: Expected %d ReconciliationPaused events, got %d", testCase.name, expected, warnings)
+			}
+		})
+	}
+}
+
+func TestProcessNextItemForgetsDeletedPausedReleasePayload(t *testing.T) {
+	releasePayload := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+			UID:       "paused-release-payload",
+		},
+		Spec: v1alpha1.ReleasePayloadSpec{
+			PauseReconciliation: true,
+		},
+	}
+	key := fmt.Sprintf("%s/%s", releasePayload.Namespace, releasePayload.Name)
+
+	releasePayloadClient := fake.NewSimpleClientset()
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+	releasePayloadInformer := releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads()
+
+	c := NewReleasePayloadController("Test Controller",
+		releasePayloadInformer,
+		releasePayloadClient.ReleaseV1alpha1(),
+		events.NewInMemoryRecorder("release-payload-controller-test"),
+		workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "TestController"))
+	c.syncFn = func(ctx context.Context, key string) error {
+		return nil
+	}
+	c.reportsPause = true
+
+	if !c.reconciliationPaused(releasePayload) {
+		t.Fatalf("Expected the reconciliation to be paused")
+	}
+	if _, paused := c.pausedReleasePayloads.Load(key); !paused {
+		t.Fatalf("Expected the pause of %s to be tracked", key)
+	}
+
+	// The ReleasePayload is not in the informer cache, as if it had been deleted
+	c.queue.Add(key)
+	c.processNextItem(context.TODO())
+
+	if _, paused := c.pausedReleasePayloads.Load(key); paused {
+		t.Errorf("Expected the pause of the deleted %s to be forgotten", key)
+	}
+}
+
+func TestProcessNextItemMaxCacheSize(t *testing.T) {
+	testCases := []struct {
+		name            string
+		cacheSize       int
+		maxCacheSize    int
+		shrinksCache    bool
+		expectedSynced  bool
+		expectedRequeue bool
+	}{
+		{
+			name:           "Unlimited",
+			cacheSize:      5,
+			expectedSynced: true,
+		},
+		{
+			name:           "BelowLimit",
+			cacheSize:      3,
+			maxCacheSize:   3,
+			expectedSynced: true,
+		},
+		{
+			name:            "AboveLimit",
+			cacheSize:       5,
+			maxCacheSize:    3,
+			expectedRequeue: true,
+		},
+		{
+			name:           "AboveLimitShrinksCache",
+			cacheSize:      5,
+			maxCacheSize:   3,
+			shrinksCache:   true,
+			expectedSynced: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+			for i := 0; i < testCase.cacheSize; i++ {
+				if err := store.Add(&v1alpha1.ReleasePayload{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("4.11.0-0.nightly-2022-02-09-09155%d", i), Namespace: "ocp"}}); err != nil {
+					t.Fatalf("unable to populate the cache: %v", err)
+				}
+			}
+
+			queue := &countingRateLimitingQueue{RateLimitingInterface: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "MaxCacheSize")}
+			defer queue.ShutDown()
+
+			synced := false
+			c := &ReleasePayloadController{
+				name:                "Max Cache Size Controller",
+				releasePayloadStore: store,
+				maxCacheSize:        testCase.maxCacheSize,
+				shrinksCache:        testCase.shrinksCache,
+				queue:               queue,
+				syncFn: func(ctx context.Context, key string) error {
+					synced = true
+					return nil
+				},
+			}
+
+			c.queue.Add("ocp/4.11.0-0.nightly-2022-02-09-091559")
+			if !c.processNextItem(context.TODO()) {
+				t.Fatalf("%s: unexpected queue shutdown", testCase.name)
+			}
+			if synced != testCase.expectedSynced {
+				t.Errorf("%s: Expected synced: %t, got: %t", testCase.name, testCase.expectedSynced, synced)
+			}
+			if requeued := queue.rateLimited > 0; requeued != testCase.expectedRequeue {
+				t.Errorf("%s: Expected requeue: %t, got: %t", testCase.name, testCase.expectedRequeue, requeued)
+			}
+		})
+	}
+}
+
+func TestProcessNextItemActiveKeysPanic(t *testing.T) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ActiveKeysPanic")
+	defer queue.ShutDown()
+
+	c := &ReleasePayloadController{
+		name:  "Panicking Controller",
+		queue: queue,
+		syncFn: func(ctx context.Context, key string) error {
+			panic("sync failed")
+		},
+	}
+
+	c.queue.Add("ocp/4.11.0-0.nightly-2022-02-09-091559")
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("Expected the sync to panic")
+			}
+		}()
+		c.processNextItem(context.TODO())
+	}()
+
+	if syncs := c.ActiveSyncs(); len(syncs) != 0 {
+		t.Errorf("Expected no active syncs after a panic, got: %v", syncs)
+	}
+}
+
+// countingRateLimitingQueue counts the calls made to AddRateLimited
+type countingRateLimitingQueue struct {
+	workqueue.RateLimitingInterface
+
+	rateLimited int
+}
+
+func (q *countingRateLimitingQueue) AddRateLimited(item interface{}) {
+	q.rateLimited++
+	q.RateLimitingInterface.AddRateLimited(item)
+}
+
+func TestRunWorkersSyncOnStartup(t *testing.T) {
+	keys := []string{
+		"ocp/4.11.0-0.nightly-2022-02-09-091559",
+		"ocp/4.11.0-0.nightly-2022-02-09-101559",
+		"ocp/4.11.0-0.nightly-2022-02-09-111559",
+	}
+
+	testCases := []struct {
+		name          string
+		syncOnStartup bool
+		expected      sets.String
+	}{
+		{
+			name:          "SyncOnStartup",
+			syncOnStartup: true,
+			expected:      sets.NewString(keys...),
+		},
+		{
+			name:     "NoSyncOnStartup",
+			expected: sets.NewString(),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var objects []runtime.Object
+			for _, key := range keys {
+				namespace, name, _ := cache.SplitMetaNamespaceKey(key)
+				objects = append(objects, &v1alpha1.ReleasePayload{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}})
+			}
+			releasePayloadClient := fake.NewSimpleClientset(objects...)
+			releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+			releasePayloadInformer := releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads()
+
+			var lock sync.Mutex
+			synced := sets.NewString()
+			c := NewReleasePayloadController("Sync On Startup Controller",
+				releasePayloadInformer,
+				releasePayloadClient.ReleaseV1alpha1(),
+				events.NewInMemoryRecorder("sync-on-startup-controller-test"),
+				workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "SyncOnStartupController"))
+			c.syncOnStartup = testCase.syncOnStartup
+			c.syncFn = func(ctx context.Context, key string) error {
+				lock.Lock()
+				defer lock.Unlock()
+				synced.Insert(key)
+				return nil
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			releasePayloadInformerFactory.Start(ctx.Done())
+			go c.RunWorkers(ctx, 2)
+
+			if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				return synced.Equal(testCase.expected), nil
+			}); err != nil {
+				t.Fatalf("%s: the ReleasePayloads were not processed: %v", testCase.name, err)
+			}
+			// Nothing else must be processed once the startup sync has completed
+			if err := wait.Poll(10*time.Millisecond, 100*time.Millisecond, func() (bool, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				return !synced.Equal(testCase.expected), nil
+			}); err != wait.ErrWaitTimeout {
+				lock.Lock()
+				defer lock.Unlock()
+				t.Errorf("%s: Expected %v to be processed, got %v", testCase.name, testCase.expected.List(), synced.List())
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package release_payload_controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// DashboardUID is the UID of the Grafana dashboard of the release-payload-controller
+	DashboardUID = "release-payload-controller"
+
+	// workQueueDepthMetric and workQueueWorkDurationMetric are exported, for the queue of every controller, by the
+	// client-go workqueue metrics provider.  The queues are named after their controllers (i.e. PayloadCreationController).
+	workQueueDepthMetric        = "workqueue_depth"
+	workQueueWorkDurationMetric = "workqueue_work_duration_seconds"
+
+	// workQueueNameSelector matches the queues of all the release-payload-controllers
+	workQueueNameSelector = `name=~".+Controller"`
+
+	// syncLatencyObjective is the duration within which a sync is expected to complete.  It must match one of the
+	// bucket boundaries of the workqueue_work_duration_seconds histogram.
+	syncLatencyObjective = 10 * time.Second
+)
+
+// These types are the subset of the Grafana dashboard JSON model that the release-payload-controller dashboard uses.
+// The fields are declared in a fixed order, so that the same dashboard is always rendered to the same JSON.
+type grafanaDashboard struct {
+	UID           string            `json:"uid"`
+	Title         string            `json:"title"`
+	Tags          []string          `json:"tags"`
+	Timezone      string            `json:"timezone"`
+	SchemaVersion int               `json:"schemaVersion"`
+	Refresh       string            `json:"refresh"`
+	Time          grafanaTimeRange  `json:"time"`
+	Templating    grafanaTemplating `json:"templating"`
+	Panels        []grafanaPanel    `json:"panels"`
+}
+
+type grafanaTimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type grafanaTemplating struct {
+	List []grafanaVariable `json:"list"`
+}
+
+type grafanaVariable struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Query string `json:"query"`
+}
+
+type grafanaDatasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type grafanaGridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type grafanaTarget struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat"`
+}
+
+type grafanaFieldConfig struct {
+	Defaults grafanaFieldDefaults `json:"defaults"`
+}
+
+type grafanaFieldDefaults struct {
+	Unit string `json:"unit"`
+}
+
+type grafanaPanel struct {
+	ID          int                `json:"id"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Type        string             `json:"type"`
+	Datasource  grafanaDatasource  `json:"datasource"`
+	GridPos     grafanaGridPos     `json:"gridPos"`
+	FieldConfig grafanaFieldConfig `json:"fieldConfig"`
+	Targets     []grafanaTarget    `json:"targets"`
+}
+
+// newDashboard returns the Grafana dashboard of the release-payload-controller, with panels for the depth of the
+// queues, the latency of the syncs, the rate of syncs that exceed the syncLatencyObjective and the distribution of the
+// ReleasePayloads across their conditions.
+func newDashboard() grafanaDashboard {
+	datasource := grafanaDatasource{Type: "prometheus", UID: "${datasource}"}
+	syncLatencyObjectiveBucket := fmt.Sprintf("%g", syncLatencyObjective.Seconds())
+
+	phases := []string{
+		v1alpha1.ConditionPayloadCreated,
+		v1alpha1.ConditionPayloadAccepted,
+		v1alpha1.ConditionPayloadRejected,
+		v1alpha1.ConditionPayloadFailed,
+	}
+
+	return grafanaDashboard{
+		UID:           DashboardUID,
+		Title:         "Release Payload Controller",
+		Tags:          []string{"release-controller", "release-payload-controller"},
+		Timezone:      "utc",
+		SchemaVersion: 36,
+		Refresh:       "1m",
+		Time:          grafanaTimeRange{From: "now-6h", To: "now"},
+		Templating: grafanaTemplating{
+			List: []grafanaVariable{
+				{Name: "datasource", Label: "Data source", Type: "datasource", Query: "prometheus"},
+			},
+		},
+		Panels: []grafanaPanel{
+			{
+				ID:          1,
+				Title:       "Queue depth",
+				Description: "The number of ReleasePayloads waiting to be synced, by controller",
+				Type:        "timeseries",
+				Datasource:  datasource,
+				GridPos:     grafanaGridPos{H: 8, W: 12, X: 0, Y: 0},
+				FieldConfig: grafanaFieldConfig{Defaults: grafanaFieldDefaults{Unit: "short"}},
+				Targets: []grafanaTarget{
+					{
+						RefID:        "A",
+						Expr:         fmt.Sprintf("sum by (name) (%s{%s})", workQueueDepthMetric, workQueueNameSelector),
+						LegendFormat: "{{name}}",
+					},
+				},
+			},
+			{
+				ID:          2,
+				Title:       "Sync latency",
+				Description: "The 50th, 90th and 99th percentile of the time taken to sync a ReleasePayload",
+				Type:        "timeseries",
+				Datasource:  datasource,
+				GridPos:     grafanaGridPos{H: 8, W: 12, X: 12, Y: 0},
+				FieldConfig: grafanaFieldConfig{Defaults: grafanaFieldDefaults{Unit: "s"}},
+				Targets: []grafanaTarget{
+					{
+						RefID:        "A",
+						Expr:         fmt.Sprintf("histogram_quantile(0.5, sum by (le) (rate(%s_bucket{%s}[5m])))", workQueueWorkDurationMetric, workQueueNameSelector),
+						LegendFormat: "p50",
+					},
+					{
+						RefID:        "B",
+						Expr:         fmt.Sprintf("histogram_quantile(0.9, sum by (le) (rate(%s_bucket{%s}[5m])))", workQueueWorkDurationMetric, workQueueNameSelector),
+						LegendFormat: "p90",
+					},
+					{
+						RefID:        "C",
+						Expr:         fmt.Sprintf("histogram_quantile(0.99, sum by (le) (rate(%s_bucket{%s}[5m])))", workQueueWorkDurationMetric, workQueueNameSelector),
+						LegendFormat: "p99",
+					},
+				},
+			},
+			{
+				ID:          3,
+				Title:       "SLO violation rate",
+				Description: fmt.Sprintf("The fraction of syncs, by controller, that took longer than %s", syncLatencyObjective),
+				Type:        "timeseries",
+				Datasource:  datasource,
+				GridPos:     grafanaGridPos{H: 8, W: 12, X: 0, Y: 8},
+				FieldConfig: grafanaFieldConfig{Defaults: grafanaFieldDefaults{Unit: "percentunit"}},
+				Targets: []grafanaTarget{
+					{
+						RefID: "A",
+						Expr: fmt.Sprintf(`1 - (sum by (name) (rate(%s_bucket{%s,le="%s"}[5m])) / sum by (name) (rate(%s_count{%s}[5m])))`,
+							workQueueWorkDurationMetric, workQueueNameSelector, syncLatencyObjectiveBucket, workQueueWorkDurationMetric, workQueueNameSelector),
+						LegendFormat: "{{name}}",
+					},
+				},
+			},
+			{
+				ID:          4,
+				Title:       "Phase distribution",
+				Description: "The number of ReleasePayloads, by condition, whose condition is True",
+				Type:        "piechart",
+				Datasource:  datasource,
+				GridPos:     grafanaGridPos{H: 8, W: 12, X: 12, Y: 8},
+				FieldConfig: grafanaFieldConfig{Defaults: grafanaFieldDefaults{Unit: "short"}},
+				Targets: []grafanaTarget{
+					{
+						RefID:        "A",
+						Expr:         fmt.Sprintf(`sum by (condition) (%s{condition=~"%s"} == 1)`, releasePayloadConditionMetric, strings.Join(phases, "|")),
+						LegendFormat: "{{condition}}",
+					},
+				},
+			},
+		},
+	}
+}
+
+// writeDashboard writes the Grafana dashboard, of the release-payload-controller, as indented JSON
+func writeDashboard(out io.Writer) error {
+	data, err := json.MarshalIndent(newDashboard(), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%s\n", data)
+	return err
+}
+
+type generateDashboardOptions struct {
+	Output string
+}
+
+func (o *generateDashboardOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Output, "output", o.Output, "The file to write the Grafana dashboard to.  Written to stdout if unset.")
+}
+
+func (o *generateDashboardOptions) Run() error {
+	if len(o.Output) == 0 {
+		return writeDashboard(os.Stdout)
+	}
+	f, err := os.Create(o.Output)
+	if err != nil {
+		return err
+	}
+	if err := writeDashboard(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// NewGenerateDashboardCommand returns the command that generates the Grafana dashboard of the
+// release-payload-controller
+func NewGenerateDashboardCommand(name string) *cobra.Command {
+	o := &generateDashboardOptions{}
+
+	cmd := &cobra.Command{
+		Use:   name,
+		Short: "Generate the Grafana dashboard of the Release Payload Controller",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
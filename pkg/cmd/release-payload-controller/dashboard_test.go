@@ -0,0 +1,37 @@
+package release_payload_controller
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDashboardArtifact(t *testing.T) {
+	data, err := os.ReadFile("testdata/grafana-dashboard.json")
+	if err != nil {
+		t.Fatalf("unable to read Grafana dashboard: %v", err)
+	}
+
+	expected := &bytes.Buffer{}
+	if err := writeDashboard(expected); err != nil {
+		t.Fatalf("unable to generate Grafana dashboard: %v", err)
+	}
+	if !bytes.Equal(data, expected.Bytes()) {
+		t.Errorf("Grafana dashboard is out of date, run `make update-dashboard`: %s", cmp.Diff(expected.String(), string(data)))
+	}
+}
+
+func TestDashboardDeterministic(t *testing.T) {
+	first, second := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := writeDashboard(first); err != nil {
+		t.Fatalf("unable to generate Grafana dashboard: %v", err)
+	}
+	if err := writeDashboard(second); err != nil {
+		t.Fatalf("unable to generate Grafana dashboard: %v", err)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Errorf("Grafana dashboard is not deterministic: %s", cmp.Diff(first.String(), second.String()))
+	}
+}
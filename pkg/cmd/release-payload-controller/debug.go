@@ -0,0 +1,89 @@
+package release_payload_controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// activeSyncsHandler serves the sync operations, of all the specified controllers, that are currently in progress
+func activeSyncsHandler(controllers ...*ReleasePayloadController) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		syncs := []ActiveSync{}
+		for _, c := range controllers {
+			syncs = append(syncs, c.ActiveSyncs()...)
+		}
+		sort.SliceStable(syncs, func(i, j int) bool {
+			return syncs[i].StartTime.Before(syncs[j].StartTime)
+		})
+		data, err := json.MarshalIndent(syncs, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(data); err != nil {
+			klog.Errorf("Unable to write active syncs response: %v", err)
+		}
+	}
+}
+
+// statusDiffPathPrefix is the prefix of the /api/v1/releasePayloads/{namespace}/{name}/diff endpoint
+const statusDiffPathPrefix = "/api/v1/releasePayloads/"
+
+// statusDiffHandler serves the JSON Patch between the last two statuses, of a ReleasePayload, in the statusDiffCache
+func statusDiffHandler(cache *statusDiffCache) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		parts := strings.Split(strings.TrimPrefix(req.URL.Path, statusDiffPathPrefix), "/")
+		if len(parts) != 3 || len(parts[0]) == 0 || len(parts[1]) == 0 || parts[2] != "diff" {
+			http.NotFound(w, req)
+			return
+		}
+		key := fmt.Sprintf("%s/%s", parts[0], parts[1])
+		entries := cache.last(key, 2)
+		if len(entries) < 2 {
+			http.Error(w, fmt.Sprintf("fewer than two statuses of ReleasePayload %s have been observed", key), http.StatusNotFound)
+			return
+		}
+		patch, err := diffStatuses(entries[0].Status, entries[1].Status)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data, err := json.MarshalIndent(patch, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(data); err != nil {
+			klog.Errorf("Unable to write status diff response: %v", err)
+		}
+	}
+}
+
+// serveDebug starts an http server, on the specified address, for diagnosing the running controllers
+func serveDebug(addr string, cache *statusDiffCache, broadcaster *statusBroadcaster, controllers ...*ReleasePayloadController) {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/active-syncs", activeSyncsHandler(controllers...))
+	mux.Handle(statusDiffPathPrefix, statusDiffHandler(cache))
+	mux.Handle(statusWatchPathPrefix, statusWatchHandler(broadcaster, statusWatchHeartbeatInterval))
+	go func() {
+		klog.Infof("Listening on %s for debug requests", addr)
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			klog.Fatalf("Unable to start debug server: %v", err)
+		}
+	}()
+}
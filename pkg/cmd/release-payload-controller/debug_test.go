@@ -0,0 +1,173 @@
+package release_payload_controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestActiveSyncsHandler(t *testing.T) {
+	keys := []string{
+		"ocp/4.11.0-0.nightly-2022-02-09-091559",
+		"ocp/4.11.0-0.nightly-2022-02-09-101559",
+		"ocp/4.11.0-0.nightly-2022-02-09-111559",
+		"ocp/4.11.0-0.nightly-2022-02-09-121559",
+		"ocp/4.11.0-0.nightly-2022-02-09-131559",
+	}
+
+	started := make(chan string, len(keys))
+	release := make(chan struct{})
+
+	c := &ReleasePayloadController{
+		name:  "Slow Controller",
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "SlowController"),
+	}
+	c.syncFn = func(ctx context.Context, key string) error {
+		started <- key
+		<-release
+		return nil
+	}
+	defer c.queue.ShutDown()
+
+	for _, key := range keys {
+		c.queue.Add(key)
+	}
+
+	var wg sync.WaitGroup
+	for range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.processNextItem(context.TODO())
+		}()
+	}
+	for range keys {
+		<-started
+	}
+
+	handler := activeSyncsHandler(c)
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/debug/active-syncs", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("unexpected Content-Type: %s", contentType)
+	}
+	var syncs []ActiveSync
+	if err := json.Unmarshal(w.Body.Bytes(), &syncs); err != nil {
+		t.Fatalf("unable to decode response: %v", err)
+	}
+	got := sets.NewString()
+	for _, s := range syncs {
+		if s.Controller != c.name {
+			t.Errorf("expected controller %q, got %q", c.name, s.Controller)
+		}
+		if s.StartTime.IsZero() {
+			t.Errorf("expected start time to be set for key %q", s.Key)
+		}
+		got.Insert(s.Key)
+	}
+	if !cmp.Equal(got.List(), sets.NewString(keys...).List()) {
+		t.Errorf("%s: Expected %v, got %v", "active syncs", sets.NewString(keys...).List(), got.List())
+	}
+
+	close(release)
+	wg.Wait()
+
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/debug/active-syncs", nil))
+	if err := json.Unmarshal(w.Body.Bytes(), &syncs); err != nil {
+		t.Fatalf("unable to decode response: %v", err)
+	}
+	if len(syncs) != 0 {
+		t.Errorf("expected no active syncs, got: %v", syncs)
+	}
+}
+
+func TestStatusDiffHandler(t *testing.T) {
+	h := &statusDiffCache{size: defaultStatusDiffCacheSize, entries: make(map[string][]statusDiffCacheEntry)}
+	newReleasePayload := func(name string, status v1alpha1.ReleaseCreationJobStatus) *v1alpha1.ReleasePayload {
+		return &v1alpha1.ReleasePayload{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ocp"},
+			Status: v1alpha1.ReleasePayloadStatus{
+				ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{Status: status},
+			},
+		}
+	}
+	h.record(newReleasePayload("4.11.0-0.nightly-2022-02-09-091559", v1alpha1.ReleaseCreationJobUnknown))
+	h.record(newReleasePayload("4.11.0-0.nightly-2022-02-09-091559", v1alpha1.ReleaseCreationJobFailed))
+	h.record(newReleasePayload("4.11.0-0.nightly-2022-02-09-091559", v1alpha1.ReleaseCreationJobSuccess))
+	h.record(newReleasePayload("4.11.0-0.nightly-2022-02-09-101559", v1alpha1.ReleaseCreationJobUnknown))
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		expectedCode int
+		expected     []jsonPatchOperation
+	}{
+		{
+			name:         "LastTwoStatuses",
+			method:       http.MethodGet,
+			path:         "/api/v1/releasePayloads/ocp/4.11.0-0.nightly-2022-02-09-091559/diff",
+			expectedCode: http.StatusOK,
+			expected: []jsonPatchOperation{
+				{Op: "replace", Path: "/releaseCreationJobResult/status", Value: string(v1alpha1.ReleaseCreationJobSuccess)},
+			},
+		},
+		{
+			name:         "SingleStatus",
+			method:       http.MethodGet,
+			path:         "/api/v1/releasePayloads/ocp/4.11.0-0.nightly-2022-02-09-101559/diff",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "UnknownReleasePayload",
+			method:       http.MethodGet,
+			path:         "/api/v1/releasePayloads/ocp/4.11.0-0.nightly-2022-02-09-111559/diff",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "InvalidPath",
+			method:       http.MethodGet,
+			path:         "/api/v1/releasePayloads/ocp/4.11.0-0.nightly-2022-02-09-091559",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "MethodNotAllowed",
+			method:       http.MethodPost,
+			path:         "/api/v1/releasePayloads/ocp/4.11.0-0.nightly-2022-02-09-091559/diff",
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			statusDiffHandler(h)(w, httptest.NewRequest(testCase.method, testCase.path, nil))
+			if w.Code != testCase.expectedCode {
+				t.Fatalf("%s: expected status %d, got %d", testCase.name, testCase.expectedCode, w.Code)
+			}
+			if testCase.expectedCode != http.StatusOK {
+				return
+			}
+			var patch []jsonPatchOperation
+			if err := json.Unmarshal(w.Body.Bytes(), &patch); err != nil {
+				t.Fatalf("%s: unable to decode response: %v", testCase.name, err)
+			}
+			if !cmp.Equal(patch, testCase.expected) {
+				t.Errorf("%s: %s", testCase.name, cmp.Diff(testCase.expected, patch))
+			}
+		})
+	}
+}
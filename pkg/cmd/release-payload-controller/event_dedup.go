@@ -0,0 +1,95 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// defaultEventDedupWindow is how long a Warning event suppresses the identical Warning events that follow it
+	defaultEventDedupWindow = 5 * time.Minute
+
+	// eventDedupCacheSize is the number of distinct Warning events remembered.  The least recently emitted ones are
+	// forgotten first, which, at worst, lets one of their duplicates through.
+	eventDedupCacheSize = 1000
+)
+
+// dedupEventRecorder is an events.Recorder that drops a Warning event if an identical one, i.e. with the same
+// component, reason and message, was emitted within the dedup window.  The component identifies the controller and
+// the message the ReleasePayload, so a sync that keeps failing with the same error emits a single Warning per window.
+// Normal events are always emitted.
+type dedupEventRecorder struct {
+	events.Recorder
+
+	state *eventDedupState
+}
+
+// eventDedupState is shared by a dedupEventRecorder and every recorder derived from it
+type eventDedupState struct {
+	lock   sync.Mutex
+	seen   *cache.LRUExpireCache
+	window time.Duration
+}
+
+// eventDedupKey identifies a Warning event
+type eventDedupKey struct {
+	component string
+	reason    string
+	message   string
+}
+
+func newDedupEventRecorder(recorder events.Recorder, window time.Duration) events.Recorder {
+	return newDedupEventRecorderWithClock(recorder, window, clock.RealClock{})
+}
+
+func newDedupEventRecorderWithClock(recorder events.Recorder, window time.Duration, clock clock.PassiveClock) events.Recorder {
+	return &dedupEventRecorder{
+		Recorder: recorder,
+		state: &eventDedupState{
+			seen:   cache.NewLRUExpireCacheWithClock(eventDedupCacheSize, clock),
+			window: window,
+		},
+	}
+}
+
+// firstSeen returns true, and starts the dedup window, if the event was not emitted within the current window
+func (s *eventDedupState) firstSeen(key eventDedupKey) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.seen.Get(key); ok {
+		return false
+	}
+	s.seen.Add(key, struct{}{}, s.window)
+	return true
+}
+
+func (r *dedupEventRecorder) Warning(reason, message string) {
+	if !r.state.firstSeen(eventDedupKey{component: r.ComponentName(), reason: reason, message: message}) {
+		klog.V(4).Infof("Dropping duplicate %s event of %s: %s", reason, r.ComponentName(), message)
+		return
+	}
+	r.Recorder.Warning(reason, message)
+}
+
+func (r *dedupEventRecorder) Warningf(reason, messageFmt string, args ...interface{}) {
+	r.Warning(reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *dedupEventRecorder) ForComponent(componentName string) events.Recorder {
+	return &dedupEventRecorder{Recorder: r.Recorder.ForComponent(componentName), state: r.state}
+}
+
+func (r *dedupEventRecorder) WithComponentSuffix(componentNameSuffix string) events.Recorder {
+	return &dedupEventRecorder{Recorder: r.Recorder.WithComponentSuffix(componentNameSuffix), state: r.state}
+}
+
+func (r *dedupEventRecorder) WithContext(ctx context.Context) events.Recorder {
+	return &dedupEventRecorder{Recorder: r.Recorder.WithContext(ctx), state: r.state}
+}
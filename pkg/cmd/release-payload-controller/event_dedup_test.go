@@ -0,0 +1,130 @@
+package release_payload_controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	corev1 "k8s.io/api/core/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+// recordedEvent is the part of an event, recorded by the in memory recorder, that the dedup tests compare
+type recordedEvent struct {
+	eventType string
+	reason    string
+	message   string
+}
+
+func recordedEvents(recorder events.InMemoryRecorder) []recordedEvent {
+	var recorded []recordedEvent
+	for _, event := range recorder.Events() {
+		recorded = append(recorded, recordedEvent{eventType: event.Type, reason: event.Reason, message: event.Message})
+	}
+	return recorded
+}
+
+func TestDedupEventRecorder(t *testing.T) {
+	const window = 5 * time.Minute
+
+	testCases := []struct {
+		name     string
+		record   func(recorder events.Recorder, clock *clocktesting.FakeClock)
+		expected []recordedEvent
+	}{
+		{
+			name: "DuplicateWarningsWithinWindow",
+			record: func(recorder events.Recorder, clock *clocktesting.FakeClock) {
+				recorder.Warningf("ReleaseCreationJobFailed", "ReleasePayload %s/%s failed", "ocp", "4.11.0-0.nightly-2022-02-09-091559")
+				clock.Step(time.Minute)
+				recorder.Warningf("ReleaseCreationJobFailed", "ReleasePayload %s/%s failed", "ocp", "4.11.0-0.nightly-2022-02-09-091559")
+				clock.Step(3 * time.Minute)
+				recorder.Warning("ReleaseCreationJobFailed", "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed")
+			},
+			expected: []recordedEvent{
+				{eventType: corev1.EventTypeWarning, reason: "ReleaseCreationJobFailed", message: "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed"},
+			},
+		},
+		{
+			name: "DuplicateWarningAfterWindow",
+			record: func(recorder events.Recorder, clock *clocktesting.FakeClock) {
+				recorder.Warning("ReleaseCreationJobFailed", "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed")
+				clock.Step(window + time.Second)
+				recorder.Warning("ReleaseCreationJobFailed", "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed")
+			},
+			expected: []recordedEvent{
+				{eventType: corev1.EventTypeWarning, reason: "ReleaseCreationJobFailed", message: "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed"},
+				{eventType: corev1.EventTypeWarning, reason: "ReleaseCreationJobFailed", message: "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed"},
+			},
+		},
+		{
+			name: "DifferentReasonsAndMessages",
+			record: func(recorder events.Recorder, clock *clocktesting.FakeClock) {
+				recorder.Warning("ReleaseCreationJobFailed", "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed")
+				recorder.Warning("ReleaseCreationJobUnknown", "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed")
+				recorder.Warning("ReleaseCreationJobFailed", "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-101559 failed")
+			},
+			expected: []recordedEvent{
+				{eventType: corev1.EventTypeWarning, reason: "ReleaseCreationJobFailed", message: "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed"},
+				{eventType: corev1.EventTypeWarning, reason: "ReleaseCreationJobUnknown", message: "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed"},
+				{eventType: corev1.EventTypeWarning, reason: "ReleaseCreationJobFailed", message: "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-101559 failed"},
+			},
+		},
+		{
+			name: "DifferentComponents",
+			record: func(recorder events.Recorder, clock *clocktesting.FakeClock) {
+				recorder.ForComponent("signing-controller").Warning("ReleasePayloadFailed", "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed")
+				recorder.ForComponent("approval-controller").Warning("ReleasePayloadFailed", "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed")
+				recorder.ForComponent("signing-controller").Warning("ReleasePayloadFailed", "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed")
+			},
+			expected: []recordedEvent{
+				{eventType: corev1.EventTypeWarning, reason: "ReleasePayloadFailed", message: "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed"},
+				{eventType: corev1.EventTypeWarning, reason: "ReleasePayloadFailed", message: "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed"},
+			},
+		},
+		{
+			name: "NormalEventsAreNotDeduplicated",
+			record: func(recorder events.Recorder, clock *clocktesting.FakeClock) {
+				recorder.Event("ReleasePayloadSigned", "Signed ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559")
+				recorder.Eventf("ReleasePayloadSigned", "Signed ReleasePayload %s/%s", "ocp", "4.11.0-0.nightly-2022-02-09-091559")
+			},
+			expected: []recordedEvent{
+				{eventType: corev1.EventTypeNormal, reason: "ReleasePayloadSigned", message: "Signed ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559"},
+				{eventType: corev1.EventTypeNormal, reason: "ReleasePayloadSigned", message: "Signed ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			clock := clocktesting.NewFakeClock(time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC))
+			inMemoryRecorder := events.NewInMemoryRecorder("release-payload-controller")
+			recorder := newDedupEventRecorderWithClock(inMemoryRecorder, window, clock)
+
+			testCase.record(recorder, clock)
+
+			recorded := recordedEvents(inMemoryRecorder)
+			if len(recorded) != len(testCase.expected) {
+				t.Fatalf("%s: Expected %d events, got %d: %v", testCase.name, len(testCase.expected), len(recorded), recorded)
+			}
+			for i := range recorded {
+				if recorded[i] != testCase.expected[i] {
+					t.Errorf("%s: Expected event %d to be %v, got %v", testCase.name, i, testCase.expected[i], recorded[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDedupEventRecorderSharedByDerivedRecorders(t *testing.T) {
+	clock := clocktesting.NewFakeClock(time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC))
+	inMemoryRecorder := events.NewInMemoryRecorder("release-payload-controller")
+	recorder := newDedupEventRecorderWithClock(inMemoryRecorder, time.Minute, clock)
+
+	// Recorders derived, separately, for the same component share the events that were seen
+	recorder.ForComponent("signing-controller").Warning("ReleasePayloadFailed", "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed")
+	recorder.ForComponent("signing-controller").Warning("ReleasePayloadFailed", "ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 failed")
+
+	if count := len(inMemoryRecorder.Events()); count != 1 {
+		t.Errorf("Expected 1 event, got %d: %v", count, recordedEvents(inMemoryRecorder))
+	}
+}
@@ -0,0 +1,426 @@
+package release_payload_controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclientset "github.com/openshift/release-controller/pkg/client/clientset/versioned"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// FederatedClusterNameLabel is set, on every ReleasePayload returned by the FederatedReleasePayloadClient, to the
+	// name of the member cluster that the ReleasePayload lives on
+	FederatedClusterNameLabel = "cluster.x-k8s.io/cluster-name"
+
+	// FederatedClientTypeKarmada reaches the member clusters through the cluster proxy of the Karmada API server
+	FederatedClientTypeKarmada = "karmada"
+
+	// FederatedClientTypeClusterpedia reaches the member clusters through the resources API of Clusterpedia
+	FederatedClientTypeClusterpedia = "clusterpedia"
+)
+
+var federatedClientTypes = []string{FederatedClientTypeKarmada, FederatedClientTypeClusterpedia}
+
+// federatedMemberConfig returns the config, of the client of the member cluster, that reaches it through the API
+// server, of the specified type, that the config points at
+func federatedMemberConfig(config *rest.Config, clientType, cluster string) (*rest.Config, error) {
+	var prefix string
+	switch clientType {
+	case FederatedClientTypeKarmada:
+		prefix = path.Join("/apis/cluster.karmada.io/v1alpha1/clusters", cluster, "proxy")
+	case FederatedClientTypeClusterpedia:
+		prefix = path.Join("/apis/clusterpedia.io/v1beta1/resources/clusters", cluster)
+	default:
+		return nil, fmt.Errorf("unknown federated client type %q, must be one of %v", clientType, federatedClientTypes)
+	}
+	host, err := url.Parse(config.Host)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the host of the federated client config: %w", err)
+	}
+	host.Path = path.Join(host.Path, prefix)
+	memberConfig := rest.CopyConfig(config)
+	memberConfig.Host = host.String()
+	return memberConfig, nil
+}
+
+// NewFederatedReleasePayloadClientForConfig returns a FederatedReleasePayloadClient for the member clusters, reached
+// through the API server, of the specified type, that the config points at
+func NewFederatedReleasePayloadClientForConfig(config *rest.Config, clientType string, clusters []string) (*FederatedReleasePayloadClient, error) {
+	members := make(map[string]releasepayloadclient.ReleaseV1alpha1Interface)
+	for _, cluster := range clusters {
+		memberConfig, err := federatedMemberConfig(config, clientType, cluster)
+		if err != nil {
+			return nil, err
+		}
+		client, err := releasepayloadclient.NewForConfig(memberConfig)
+		if err != nil {
+			return nil, fmt.Errorf("unable to build the client of federated cluster %s: %w", cluster, err)
+		}
+		members[cluster] = client
+	}
+	return NewFederatedReleasePayloadClient(members), nil
+}
+
+// FederatedReleasePayloadClient is a read-only ReleaseV1alpha1Interface over the ReleasePayloads of several member
+// clusters.  Reads fan out to every member cluster and label each ReleasePayload with the FederatedClusterNameLabel.
+// Every write fails: the jobs, imagestreams and prowjobs, that the ReleasePayloads are reconciled from, live on the
+// member clusters, so they can not be managed from here.
+//
+// The names of the ReleasePayloads must be unique across the member clusters.  A ReleasePayload found on more than one
+// of them is reported as a conflict, rather than one of them being picked.
+//
+// The resource versions of the ReleasePayloads, and of the lists, are opaque: they hold the resource version of every
+// member cluster involved.  A watch resumed from the resource version of a single ReleasePayload restarts the watches
+// of the other member clusters from their most recent resource version, which replays their ReleasePayloads as Added.
+type FederatedReleasePayloadClient struct {
+	members map[string]releasepayloadclient.ReleaseV1alpha1Interface
+
+	// clusters are the names of the member clusters, sorted, so that reads are deterministic
+	clusters []string
+
+	// owners are the member clusters, by namespace/name, of the ReleasePayloads that have been listed, or watched, so
+	// that a watch can detect a ReleasePayload of the same name appearing on another member cluster
+	owners     map[string]string
+	ownersLock sync.Mutex
+}
+
+func NewFederatedReleasePayloadClient(members map[string]releasepayloadclient.ReleaseV1alpha1Interface) *FederatedReleasePayloadClient {
+	clusters := make([]string, 0, len(members))
+	for cluster := range members {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	return &FederatedReleasePayloadClient{members: members, clusters: clusters, owners: make(map[string]string)}
+}
+
+// RESTClient returns nil, there is no single REST client for all the member clusters
+func (c *FederatedReleasePayloadClient) RESTClient() rest.Interface {
+	return nil
+}
+
+func (c *FederatedReleasePayloadClient) ReleasePayloads(namespace string) releasepayloadclient.ReleasePayloadInterface {
+	return &federatedReleasePayloads{client: c, namespace: namespace}
+}
+
+// federatedClientset serves the ReleasePayloads of the FederatedReleasePayloadClient, i.e. to the informers, and
+// everything else from the wrapped clientset
+type federatedClientset struct {
+	releasepayloadclientset.Interface
+
+	federated *FederatedReleasePayloadClient
+}
+
+func (c *federatedClientset) ReleaseV1alpha1() releasepayloadclient.ReleaseV1alpha1Interface {
+	return c.federated
+}
+
+// encodeFederatedResourceVersion returns the opaque resource version holding the resource version of every cluster
+func encodeFederatedResourceVersion(versions map[string]string) string {
+	data, err := json.Marshal(versions)
+	if err != nil {
+		// A map of strings always marshals
+		panic(err)
+	}
+	return string(data)
+}
+
+// decodeFederatedResourceVersion returns the resource version, by cluster, held by the opaque resource version.  The
+// empty, and "0", resource versions have the same meaning on every cluster, so they are returned for all of them.
+func (c *FederatedReleasePayloadClient) decodeFederatedResourceVersion(resourceVersion string) (map[string]string, error) {
+	versions := make(map[string]string)
+	if len(resourceVersion) == 0 || resourceVersion == "0" {
+		for _, cluster := range c.clusters {
+			versions[cluster] = resourceVersion
+		}
+		return versions, nil
+	}
+	if err := json.Unmarshal([]byte(resourceVersion), &versions); err != nil {
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("invalid federated resource version %q: %v", resourceVersion, err))
+	}
+	return versions, nil
+}
+
+// fromMember labels the ReleasePayload, of the member cluster, with the cluster and makes its resource version opaque
+func fromMember(cluster string, releasePayload *v1alpha1.ReleasePayload) *v1alpha1.ReleasePayload {
+	if releasePayload.Labels == nil {
+		releasePayload.Labels = make(map[string]string)
+	}
+	releasePayload.Labels[FederatedClusterNameLabel] = cluster
+	if len(releasePayload.ResourceVersion) > 0 {
+		releasePayload.ResourceVersion = encodeFederatedResourceVersion(map[string]string{cluster: releasePayload.ResourceVersion})
+	}
+	return releasePayload
+}
+
+// claim records the member cluster as the owner of the ReleasePayload and returns an error if another member cluster
+// already owns a ReleasePayload of the same namespace and name
+func (c *FederatedReleasePayloadClient) claim(cluster, namespace, name string) error {
+	c.ownersLock.Lock()
+	defer c.ownersLock.Unlock()
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	if owner, ok := c.owners[key]; ok && owner != cluster {
+		return newFederatedConflict(namespace, name, []string{owner, cluster})
+	}
+	c.owners[key] = cluster
+	return nil
+}
+
+// release forgets the member cluster as the owner of the ReleasePayload
+func (c *FederatedReleasePayloadClient) release(cluster, namespace, name string) {
+	c.ownersLock.Lock()
+	defer c.ownersLock.Unlock()
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	if c.owners[key] == cluster {
+		delete(c.owners, key)
+	}
+}
+
+// newFederatedConflict returns the error of a ReleasePayload that exists on more than one member cluster
+func newFederatedConflict(namespace, name string, clusters []string) error {
+	sort.Strings(clusters)
+	return k8serrors.NewConflict(v1alpha1.Resource("releasepayloads"), name, fmt.Errorf("ReleasePayload %s/%s exists on the federated clusters %s", namespace, name, strings.Join(clusters, ", ")))
+}
+
+// newFederatedReadOnly returns the error of a write to the read-only FederatedReleasePayloadClient
+func newFederatedReadOnly(verb string) error {
+	return k8serrors.NewMethodNotSupported(v1alpha1.Resource("releasepayloads"), verb)
+}
+
+// federatedReleasePayloads implements ReleasePayloadInterface over the member clusters
+type federatedReleasePayloads struct {
+	client    *FederatedReleasePayloadClient
+	namespace string
+}
+
+func (c *federatedReleasePayloads) member(cluster string) releasepayloadclient.ReleasePayloadInterface {
+	return c.client.members[cluster].ReleasePayloads(c.namespace)
+}
+
+func (c *federatedReleasePayloads) Create(ctx context.Context, releasePayload *v1alpha1.ReleasePayload, opts metav1.CreateOptions) (*v1alpha1.ReleasePayload, error) {
+	return nil, newFederatedReadOnly("create")
+}
+
+func (c *federatedReleasePayloads) Update(ctx context.Context, releasePayload *v1alpha1.ReleasePayload, opts metav1.UpdateOptions) (*v1alpha1.ReleasePayload, error) {
+	return nil, newFederatedReadOnly("update")
+}
+
+func (c *federatedReleasePayloads) UpdateStatus(ctx context.Context, releasePayload *v1alpha1.ReleasePayload, opts metav1.UpdateOptions) (*v1alpha1.ReleasePayload, error) {
+	return nil, newFederatedReadOnly("update")
+}
+
+func (c *federatedReleasePayloads) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return newFederatedReadOnly("delete")
+}
+
+func (c *federatedReleasePayloads) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	return newFederatedReadOnly("deletecollection")
+}
+
+func (c *federatedReleasePayloads) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*v1alpha1.ReleasePayload, error) {
+	return nil, newFederatedReadOnly("patch")
+}
+
+// Get gets the ReleasePayload from every member cluster, concurrently
+func (c *federatedReleasePayloads) Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1alpha1.ReleasePayload, error) {
+	releasePayloads := make([]*v1alpha1.ReleasePayload, len(c.client.clusters))
+	errs := make([]error, len(c.client.clusters))
+	var wg sync.WaitGroup
+	for i, cluster := range c.client.clusters {
+		wg.Add(1)
+		go func(i int, cluster string) {
+			defer wg.Done()
+			releasePayloads[i], errs[i] = c.member(cluster).Get(ctx, name, opts)
+		}(i, cluster)
+	}
+	wg.Wait()
+
+	var found []string
+	var result *v1alpha1.ReleasePayload
+	for i, cluster := range c.client.clusters {
+		switch {
+		case k8serrors.IsNotFound(errs[i]):
+		case errs[i] != nil:
+			return nil, fmt.Errorf("unable to get ReleasePayload %s/%s from federated cluster %s: %w", c.namespace, name, cluster, errs[i])
+		default:
+			found = append(found, cluster)
+			result = fromMember(cluster, releasePayloads[i])
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, k8serrors.NewNotFound(v1alpha1.Resource("releasepayloads"), name)
+	case 1:
+		return result, nil
+	default:
+		return nil, newFederatedConflict(c.namespace, name, found)
+	}
+}
+
+// List returns the ReleasePayloads of every member cluster.  The lists are not paginated: the limit and continue
+// options are ignored.
+func (c *federatedReleasePayloads) List(ctx context.Context, opts metav1.ListOptions) (*v1alpha1.ReleasePayloadList, error) {
+	versions, err := c.client.decodeFederatedResourceVersion(opts.ResourceVersion)
+	if err != nil {
+		return nil, err
+	}
+	result := &v1alpha1.ReleasePayloadList{}
+	listVersions := make(map[string]string)
+	owners := make(map[string][]string)
+	for _, cluster := range c.client.clusters {
+		memberOpts := opts
+		memberOpts.ResourceVersion = versions[cluster]
+		memberOpts.Limit = 0
+		memberOpts.Continue = ""
+		list, err := c.member(cluster).List(ctx, memberOpts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list the ReleasePayloads of federated cluster %s: %w", cluster, err)
+		}
+		for i := range list.Items {
+			key := fmt.Sprintf("%s/%s", list.Items[i].Namespace, list.Items[i].Name)
+			owners[key] = append(owners[key], cluster)
+			result.Items = append(result.Items, *fromMember(cluster, &list.Items[i]))
+		}
+		listVersions[cluster] = list.ResourceVersion
+	}
+	for _, item := range result.Items {
+		if clusters := owners[fmt.Sprintf("%s/%s", item.Namespace, item.Name)]; len(clusters) > 1 {
+			return nil, newFederatedConflict(item.Namespace, item.Name, clusters)
+		}
+	}
+
+	// The list replaces everything that was known about the owners of the ReleasePayloads of the namespace
+	c.client.ownersLock.Lock()
+	for key := range c.client.owners {
+		if namespace, _, _ := strings.Cut(key, "/"); len(c.namespace) == 0 || namespace == c.namespace {
+			delete(c.client.owners, key)
+		}
+	}
+	for key, clusters := range owners {
+		c.client.owners[key] = clusters[0]
+	}
+	c.client.ownersLock.Unlock()
+
+	result.ResourceVersion = encodeFederatedResourceVersion(listVersions)
+	return result, nil
+}
+
+// Watch watches the ReleasePayloads of every member cluster.  The watch ends when the watch of any member cluster does.
+func (c *federatedReleasePayloads) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	versions, err := c.client.decodeFederatedResourceVersion(opts.ResourceVersion)
+	if err != nil {
+		return nil, err
+	}
+	w := &federatedWatcher{
+		client: c.client,
+		result: make(chan watch.Event),
+		stopCh: make(chan struct{}),
+	}
+	for _, cluster := range c.client.clusters {
+		memberOpts := opts
+		memberOpts.ResourceVersion = versions[cluster]
+		memberWatcher, err := c.member(cluster).Watch(ctx, memberOpts)
+		if err != nil {
+			w.Stop()
+			return nil, fmt.Errorf("unable to watch the ReleasePayloads of federated cluster %s: %w", cluster, err)
+		}
+		w.watchers = append(w.watchers, memberWatcher)
+	}
+	for i, cluster := range c.client.clusters {
+		w.wg.Add(1)
+		go w.forward(cluster, w.watchers[i])
+	}
+	go func() {
+		w.wg.Wait()
+		close(w.result)
+	}()
+	return w, nil
+}
+
+// federatedWatcher merges the watches of the member clusters
+type federatedWatcher struct {
+	client   *FederatedReleasePayloadClient
+	watchers []watch.Interface
+	result   chan watch.Event
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func (w *federatedWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+		for _, watcher := range w.watchers {
+			watcher.Stop()
+		}
+	})
+}
+
+func (w *federatedWatcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+// forward sends the events, of the watch of the member cluster, until either ends.  A ReleasePayload that appears on a
+// second member cluster ends the watch with an error, so that the informer relists and reports the conflict.
+func (w *federatedWatcher) forward(cluster string, watcher watch.Interface) {
+	defer w.wg.Done()
+	defer w.Stop()
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return
+			}
+			if releasePayload, ok := event.Object.(*v1alpha1.ReleasePayload); ok {
+				switch event.Type {
+				case watch.Added, watch.Modified:
+					if err := w.client.claim(cluster, releasePayload.Namespace, releasePayload.Name); err != nil {
+						var status k8serrors.APIStatus
+						if errors.As(err, &status) {
+							status := status.Status()
+							event = watch.Event{Type: watch.Error, Object: &status}
+						}
+						select {
+						case w.result <- event:
+						case <-w.stopCh:
+						}
+						return
+					}
+				case watch.Deleted:
+					w.client.release(cluster, releasePayload.Namespace, releasePayload.Name)
+				}
+				event.Object = fromMember(cluster, releasePayload.DeepCopy())
+			}
+			select {
+			case w.result <- event:
+			case <-w.stopCh:
+				return
+			}
+		}
+	}
+}
+
+// validateFederatedClusters returns an error if any of the cluster names can not be part of a URL path
+func validateFederatedClusters(clusters []string) error {
+	for _, cluster := range clusters {
+		if len(cluster) == 0 || strings.ContainsAny(cluster, "/?#") || cluster == "." || cluster == ".." {
+			return fmt.Errorf("invalid federated cluster name %q", cluster)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package release_payload_controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclientsetfake "github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+)
+
+func newFederatedTestReleasePayload(name, resourceVersion string, labels map[string]string) *v1alpha1.ReleasePayload {
+	return &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "ocp",
+			ResourceVersion: resourceVersion,
+			Labels:          labels,
+		},
+	}
+}
+
+// newFederatedTestClient returns a FederatedReleasePayloadClient over the east and west member clusters, backed by fake
+// clientsets holding the ReleasePayloads
+func newFederatedTestClient(east, west []*v1alpha1.ReleasePayload) (*FederatedReleasePayloadClient, map[string]releasepayloadclient.ReleaseV1alpha1Interface) {
+	members := make(map[string]releasepayloadclient.ReleaseV1alpha1Interface)
+	for cluster, releasePayloads := range map[string][]*v1alpha1.ReleasePayload{"east": east, "west": west} {
+		client := releasepayloadclientsetfake.NewSimpleClientset()
+		for _, releasePayload := range releasePayloads {
+			if err := client.Tracker().Add(releasePayload); err != nil {
+				panic(err)
+			}
+		}
+		members[cluster] = client.ReleaseV1alpha1()
+	}
+	return NewFederatedReleasePayloadClient(members), members
+}
+
+func TestFederatedReleasePayloadClientList(t *testing.T) {
+	client, _ := newFederatedTestClient(
+		[]*v1alpha1.ReleasePayload{newFederatedTestReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "10", nil)},
+		[]*v1alpha1.ReleasePayload{newFederatedTestReleasePayload("4.11.0-0.ci-2022-02-09-091559", "20", map[string]string{"release.openshift.io/imagestream": "release"})},
+	)
+
+	list, err := client.ReleasePayloads("ocp").List(context.TODO(), metav1.ListOptions{Limit: 500})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []v1alpha1.ReleasePayload{
+		*newFederatedTestReleasePayload("4.11.0-0.nightly-2022-02-09-091559", `{"east":"10"}`, map[string]string{FederatedClusterNameLabel: "east"}),
+		*newFederatedTestReleasePayload("4.11.0-0.ci-2022-02-09-091559", `{"west":"20"}`, map[string]string{FederatedClusterNameLabel: "west", "release.openshift.io/imagestream": "release"}),
+	}
+	if diff := cmp.Diff(expected, list.Items); diff != "" {
+		t.Errorf("unexpected ReleasePayloads (-want +got):\n%s", diff)
+	}
+	versions, err := client.decodeFederatedResourceVersion(list.ResourceVersion)
+	if err != nil {
+		t.Fatalf("unable to decode the resource version of the list: %v", err)
+	}
+	if _, ok := versions["east"]; !ok {
+		t.Errorf("expected the resource version of the list to hold the east cluster, got %q", list.ResourceVersion)
+	}
+	if _, ok := versions["west"]; !ok {
+		t.Errorf("expected the resource version of the list to hold the west cluster, got %q", list.ResourceVersion)
+	}
+
+	if _, err := client.ReleasePayloads("ocp").List(context.TODO(), metav1.ListOptions{ResourceVersion: "10"}); !k8serrors.IsBadRequest(err) {
+		t.Errorf("expected a bad request for a resource version of a single cluster, got: %v", err)
+	}
+}
+
+func TestFederatedReleasePayloadClientGet(t *testing.T) {
+	client, _ := newFederatedTestClient(
+		nil,
+		[]*v1alpha1.ReleasePayload{newFederatedTestReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "20", nil)},
+	)
+
+	releasePayload, err := client.ReleasePayloads("ocp").Get(context.TODO(), "4.11.0-0.nightly-2022-02-09-091559", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cluster := releasePayload.Labels[FederatedClusterNameLabel]; cluster != "west" {
+		t.Errorf("expected the ReleasePayload to be labeled with the west cluster, got %q", cluster)
+	}
+
+	if _, err := client.ReleasePayloads("ocp").Get(context.TODO(), "4.11.0-0.ci-2022-02-09-091559", metav1.GetOptions{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected not found, got: %v", err)
+	}
+}
+
+func TestFederatedReleasePayloadClientConflict(t *testing.T) {
+	client, _ := newFederatedTestClient(
+		[]*v1alpha1.ReleasePayload{newFederatedTestReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "10", nil)},
+		[]*v1alpha1.ReleasePayload{newFederatedTestReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "20", nil)},
+	)
+
+	if _, err := client.ReleasePayloads("ocp").Get(context.TODO(), "4.11.0-0.nightly-2022-02-09-091559", metav1.GetOptions{}); !k8serrors.IsConflict(err) {
+		t.Errorf("expected a conflict getting a ReleasePayload of both clusters, got: %v", err)
+	}
+	if _, err := client.ReleasePayloads("ocp").List(context.TODO(), metav1.ListOptions{}); !k8serrors.IsConflict(err) {
+		t.Errorf("expected a conflict listing a ReleasePayload of both clusters, got: %v", err)
+	}
+}
+
+func TestFederatedReleasePayloadClientReadOnly(t *testing.T) {
+	client, members := newFederatedTestClient(
+		[]*v1alpha1.ReleasePayload{newFederatedTestReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "10", nil)},
+		nil,
+	)
+
+	releasePayload := newFederatedTestReleasePayload("4.11.0-0.nightly-2022-02-09-091559", `{"east":"10"}`, map[string]string{FederatedClusterNameLabel: "east"})
+	releasePayload.Status.ReleaseDigest = "sha256:a1b2c3"
+	writes := map[string]func() error{
+		"Create": func() error {
+			_, err := client.ReleasePayloads("ocp").Create(context.TODO(), releasePayload, metav1.CreateOptions{})
+			return err
+		},
+		"Update": func() error {
+			_, err := client.ReleasePayloads("ocp").Update(context.TODO(), releasePayload, metav1.UpdateOptions{})
+			return err
+		},
+		"UpdateStatus": func() error {
+			_, err := client.ReleasePayloads("ocp").UpdateStatus(context.TODO(), releasePayload, metav1.UpdateOptions{})
+			return err
+		},
+		"Patch": func() error {
+			_, err := client.ReleasePayloads("ocp").Patch(context.TODO(), releasePayload.Name, types.MergePatchType, []byte(`{"status":{"releaseDigest":"sha256:a1b2c3"}}`), metav1.PatchOptions{}, "status")
+			return err
+		},
+		"Delete": func() error {
+			return client.ReleasePayloads("ocp").Delete(context.TODO(), releasePayload.Name, metav1.DeleteOptions{})
+		},
+		"DeleteCollection": func() error {
+			return client.ReleasePayloads("ocp").DeleteCollection(context.TODO(), metav1.DeleteOptions{}, metav1.ListOptions{})
+		},
+	}
+	for name, write := range writes {
+		if err := write(); !k8serrors.IsMethodNotSupported(err) {
+			t.Errorf("%s: expected the write to be rejected, got: %v", name, err)
+		}
+	}
+
+	east, err := members["east"].ReleasePayloads("ocp").Get(context.TODO(), releasePayload.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get the ReleasePayload of the east cluster: %v", err)
+	}
+	if east.ResourceVersion != "10" || len(east.Status.ReleaseDigest) > 0 {
+		t.Errorf("expected the ReleasePayload of the east cluster to be left alone, got: %v", east)
+	}
+}
+
+func TestFederatedReleasePayloadClientWatch(t *testing.T) {
+	client, members := newFederatedTestClient(nil, nil)
+
+	w, err := client.ReleasePayloads("ocp").Watch(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Stop()
+
+	for _, cluster := range []string{"east", "west"} {
+		if _, err := members[cluster].ReleasePayloads("ocp").Create(context.TODO(), newFederatedTestReleasePayload(cluster, "", nil), metav1.CreateOptions{}); err != nil {
+			t.Fatalf("unable to create ReleasePayload on the %s cluster: %v", cluster, err)
+		}
+		select {
+		case event := <-w.ResultChan():
+			releasePayload, ok := event.Object.(*v1alpha1.ReleasePayload)
+			if !ok || event.Type != watch.Added {
+				t.Fatalf("expected an Added ReleasePayload, got: %v", event)
+			}
+			if releasePayload.Name != cluster || releasePayload.Labels[FederatedClusterNameLabel] != cluster {
+				t.Errorf("expected ReleasePayload %s labeled with the %s cluster, got: %s %v", cluster, cluster, releasePayload.Name, releasePayload.Labels)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for the ReleasePayload of the %s cluster", cluster)
+		}
+	}
+
+	w.Stop()
+	select {
+	case _, ok := <-w.ResultChan():
+		if ok {
+			t.Errorf("expected the watch to be closed once stopped")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out waiting for the watch to close")
+	}
+}
+
+func TestFederatedReleasePayloadClientWatchConflict(t *testing.T) {
+	client, members := newFederatedTestClient(nil, nil)
+
+	w, err := client.ReleasePayloads("ocp").Watch(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Stop()
+
+	expected := []watch.EventType{watch.Added, watch.Error}
+	for i, cluster := range []string{"east", "west"} {
+		if _, err := members[cluster].ReleasePayloads("ocp").Create(context.TODO(), newFederatedTestReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "", nil), metav1.CreateOptions{}); err != nil {
+			t.Fatalf("unable to create ReleasePayload on the %s cluster: %v", cluster, err)
+		}
+		select {
+		case event := <-w.ResultChan():
+			if event.Type != expected[i] {
+				t.Fatalf("expected a %s event for the ReleasePayload of the %s cluster, got: %v", expected[i], cluster, event)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for the ReleasePayload of the %s cluster", cluster)
+		}
+	}
+}
+
+func TestFederatedMemberConfig(t *testing.T) {
+	testCases := []struct {
+		name       string
+		host       string
+		clientType string
+		expected   string
+		expectErr  bool
+	}{
+		{
+			name:       "Karmada",
+			host:       "https://karmada-apiserver.karmada-system.svc:5443",
+			clientType: FederatedClientTypeKarmada,
+			expected:   "https://karmada-apiserver.karmada-system.svc:5443/apis/cluster.karmada.io/v1alpha1/clusters/east/proxy",
+		},
+		{
+			name:       "ClusterpediaWithPath",
+			host:       "https://hub.example.com/clusterpedia",
+			clientType: FederatedClientTypeClusterpedia,
+			expected:   "https://hub.example.com/clusterpedia/apis/clusterpedia.io/v1beta1/resources/clusters/east",
+		},
+		{
+			name:       "UnknownType",
+			host:       "https://hub.example.com",
+			clientType: "kubefed",
+			expectErr:  true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			config, err := federatedMemberConfig(&rest.Config{Host: testCase.host}, testCase.clientType, "east")
+			if testCase.expectErr {
+				if err == nil {
+					t.Errorf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config.Host != testCase.expected {
+				t.Errorf("expected host %q, got %q", testCase.expected, config.Host)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+// GarbageCollectionController is responsible for deleting ReleasePayloads that have reached a terminal state
+// (Accepted, Rejected or Failed) once they are older than the configured duration.
+// The GarbageCollectionController reads the following pieces of information:
+//   - .metadata.creationTimestamp
+//   - .status.conditions.PayloadAccepted
+//   - .status.conditions.PayloadRejected
+//   - .status.conditions.PayloadFailed
+type GarbageCollectionController struct {
+	*ReleasePayloadController
+
+	// collectAfter is the age after which a terminal ReleasePayload is deleted
+	collectAfter time.Duration
+
+	clock clock.PassiveClock
+}
+
+func NewGarbageCollectionController(
+	releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer,
+	releasePayloadClient releasepayloadclient.ReleaseV1alpha1Interface,
+	eventRecorder events.Recorder,
+	collectAfter time.Duration,
+) (*GarbageCollectionController, error) {
+	c := &GarbageCollectionController{
+		ReleasePayloadController: NewReleasePayloadController("Garbage Collection Controller",
+			releasePayloadInformer,
+			releasePayloadClient,
+			eventRecorder.WithComponentSuffix("garbage-collection-controller"),
+			workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "GarbageCollectionController")),
+		collectAfter: collectAfter,
+		clock:        clock.RealClock{},
+	}
+
+	c.syncFn = c.sync
+	c.shrinksCache = true
+
+	releasePayloadInformer.Informer().AddEventHandler(&cache.ResourceEventHandlerFuncs{
+		AddFunc: c.Enqueue,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.Enqueue(newObj)
+		},
+	})
+
+	return c, nil
+}
+
+// isTerminal returns true if the ReleasePayload has been Accepted, Rejected or has Failed
+func isTerminal(releasePayload *v1alpha1.ReleasePayload) bool {
+	return v1helpers.IsConditionTrue(releasePayload.Status.Conditions, v1alpha1.ConditionPayloadAccepted) ||
+		v1helpers.IsConditionTrue(releasePayload.Status.Conditions, v1alpha1.ConditionPayloadRejected) ||
+		v1helpers.IsConditionTrue(releasePayload.Status.Conditions, v1alpha1.ConditionPayloadFailed)
+}
+
+func (c *GarbageCollectionController) sync(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting GarbageCollectionController sync")
+	defer logger.V(4).Info("GarbageCollectionController sync done")
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	// Get the ReleasePayload resource with this namespace/name
+	releasePayload, err := c.releasePayloadLister.ReleasePayloads(namespace).Get(name)
+	// The ReleasePayload resource may no longer exist, in which case we stop processing.
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(releasePayload) {
+		return nil
+	}
+
+	if !isTerminal(releasePayload) {
+		return nil
+	}
+
+	// Check back once the ReleasePayload is old enough to be collected
+	if age := c.clock.Since(releasePayload.CreationTimestamp.Time); age < c.collectAfter {
+		c.queue.AddAfter(key, c.collectAfter-age)
+		return nil
+	}
+
+	klog.V(4).Infof("Deleting terminal ReleasePayload %s/%s, created %s", releasePayload.Namespace, releasePayload.Name, releasePayload.CreationTimestamp)
+	err = c.releasePayloadClient.ReleasePayloads(releasePayload.Namespace).Delete(ctx, releasePayload.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &releasePayload.UID},
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c.eventRecorder.Eventf("ReleasePayloadGarbageCollected", "Deleted ReleasePayload %s/%s, which was created %s", releasePayload.Namespace, releasePayload.Name, releasePayload.CreationTimestamp)
+	return nil
+}
@@ -0,0 +1,124 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestGarbageCollectionSync(t *testing.T) {
+	now := time.Date(2022, 2, 12, 9, 15, 59, 0, time.UTC)
+	collectAfter := 72 * time.Hour
+
+	newReleasePayload := func(age time.Duration, conditions ...string) *v1alpha1.ReleasePayload {
+		releasePayload := &v1alpha1.ReleasePayload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "4.11.0-0.nightly-2022-02-09-091559",
+				Namespace:         "ocp",
+				UID:               "garbage-collected-release-payload",
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+		}
+		for _, condition := range conditions {
+			releasePayload.Status.Conditions = append(releasePayload.Status.Conditions, metav1.Condition{Type: condition, Status: metav1.ConditionTrue})
+		}
+		return releasePayload
+	}
+
+	testCases := []struct {
+		name            string
+		input           *v1alpha1.ReleasePayload
+		expectedDeleted bool
+		expectedRequeue time.Duration
+	}{
+		{
+			name:            "OldAcceptedReleasePayload",
+			input:           newReleasePayload(96*time.Hour, v1alpha1.ConditionPayloadAccepted),
+			expectedDeleted: true,
+		},
+		{
+			name:            "OldRejectedReleasePayload",
+			input:           newReleasePayload(96*time.Hour, v1alpha1.ConditionPayloadRejected),
+			expectedDeleted: true,
+		},
+		{
+			name:            "OldFailedReleasePayload",
+			input:           newReleasePayload(collectAfter, v1alpha1.ConditionPayloadFailed),
+			expectedDeleted: true,
+		},
+		{
+			name:            "RecentAcceptedReleasePayload",
+			input:           newReleasePayload(48*time.Hour, v1alpha1.ConditionPayloadAccepted),
+			expectedRequeue: 24 * time.Hour,
+		},
+		{
+			name:  "OldPendingReleasePayload",
+			input: newReleasePayload(96 * time.Hour),
+		},
+		{
+			name: "OldReleasePayloadWithFalseConditions",
+			input: func() *v1alpha1.ReleasePayload {
+				releasePayload := newReleasePayload(96 * time.Hour)
+				releasePayload.Status.Conditions = []metav1.Condition{
+					{Type: v1alpha1.ConditionPayloadAccepted, Status: metav1.ConditionFalse},
+					{Type: v1alpha1.ConditionPayloadRejected, Status: metav1.ConditionFalse},
+					{Type: v1alpha1.ConditionPayloadFailed, Status: metav1.ConditionFalse},
+				}
+				return releasePayload
+			}(),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			releasePayloadClient := fake.NewSimpleClientset(testCase.input)
+			releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+			releasePayloadInformer := releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads()
+
+			queue := &recordingQueue{
+				RateLimitingInterface: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "GarbageCollectionController"),
+				addAfter:              map[interface{}]time.Duration{},
+			}
+			defer queue.ShutDown()
+
+			c := &GarbageCollectionController{
+				ReleasePayloadController: NewReleasePayloadController("Garbage Collection Controller",
+					releasePayloadInformer,
+					releasePayloadClient.ReleaseV1alpha1(),
+					events.NewInMemoryRecorder("garbage-collection-controller-test"),
+					queue),
+				collectAfter: collectAfter,
+				clock:        clocktesting.NewFakePassiveClock(now),
+			}
+
+			releasePayloadInformerFactory.Start(context.Background().Done())
+
+			if !cache.WaitForNamedCacheSync("GarbageCollectionController", context.Background().Done(), c.cachesToSync...) {
+				t.Fatalf("%s: error waiting for caches to sync", testCase.name)
+			}
+
+			key := fmt.Sprintf("%s/%s", testCase.input.Namespace, testCase.input.Name)
+			if err := c.sync(context.TODO(), key); err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+
+			_, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads(testCase.input.Namespace).Get(context.TODO(), testCase.input.Name, metav1.GetOptions{})
+			if deleted := errors.IsNotFound(err); deleted != testCase.expectedDeleted {
+				t.Errorf("%s: Expected deleted: %t, got: %t (%v)", testCase.name, testCase.expectedDeleted, deleted, err)
+			}
+			if requeue := queue.addAfter[key]; requeue != testCase.expectedRequeue {
+				t.Errorf("%s: Expected requeue after %s, got %s", testCase.name, testCase.expectedRequeue, requeue)
+			}
+		})
+	}
+}
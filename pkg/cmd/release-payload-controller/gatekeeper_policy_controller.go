@@ -0,0 +1,188 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/webhook"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ReleasePayloadNamingPolicyKind is the kind of the Gatekeeper constraint, defined by the ConstraintTemplate, that
+	// enforces the naming convention of the release creation jobs of ReleasePayloads
+	ReleasePayloadNamingPolicyKind = "ReleasePayloadNamingPolicy"
+
+	// ReleasePayloadNamingPolicyName is the name of the ReleasePayloadNamingPolicy constraint
+	ReleasePayloadNamingPolicyName = "release-payload-naming-policy"
+)
+
+var (
+	constraintTemplateGVR = schema.GroupVersionResource{
+		Group:    "templates.gatekeeper.sh",
+		Version:  "v1",
+		Resource: "constrainttemplates",
+	}
+
+	// Gatekeeper names the resource, of the CRD it generates for a ConstraintTemplate, after the lowercase kind
+	releasePayloadNamingPolicyGVR = schema.GroupVersionResource{
+		Group:    "constraints.gatekeeper.sh",
+		Version:  "v1beta1",
+		Resource: strings.ToLower(ReleasePayloadNamingPolicyKind),
+	}
+)
+
+// releasePayloadNamingPolicyRego denies the ReleasePayloads whose release creation job name is not a label-safe
+// release tag, like the ReleasePayload admission webhook does
+var releasePayloadNamingPolicyRego = fmt.Sprintf(`package %s
+
+violation[{"msg": msg}] {
+  name := input.review.object.status.releaseCreationJobResult.coordinates.name
+  name != ""
+  not regex.match(%s, name)
+  msg := sprintf("release creation job name %%q is not a valid release tag", [name])
+}
+`, strings.ToLower(ReleasePayloadNamingPolicyKind), "`"+webhook.ReleaseTagPattern+"`")
+
+// GatekeeperPolicyController is responsible for ensuring that the ConstraintTemplate, defining the
+// ReleasePayloadNamingPolicy, and the ReleasePayloadNamingPolicy constraint, applying it to every ReleasePayload, exist
+// so that the OPA Gatekeeper audit reports the ReleasePayloads that break the naming convention of the release creation
+// jobs.  The coordinates are only ever written through the status subresource, which the admission webhook of
+// Gatekeeper does not match, so the convention is still enforced by the ReleasePayload admission webhook.
+type GatekeeperPolicyController struct {
+	name               string
+	constraintTemplate dynamic.NamespaceableResourceInterface
+	constraint         dynamic.NamespaceableResourceInterface
+	eventRecorder      events.Recorder
+}
+
+func NewGatekeeperPolicyController(dynamicClient dynamic.Interface, eventRecorder events.Recorder) *GatekeeperPolicyController {
+	return &GatekeeperPolicyController{
+		name:               "GatekeeperPolicyController",
+		constraintTemplate: dynamicClient.Resource(constraintTemplateGVR),
+		constraint:         dynamicClient.Resource(releasePayloadNamingPolicyGVR),
+		eventRecorder:      eventRecorder,
+	}
+}
+
+// Run reconciles the ConstraintTemplate and constraint until the context is cancelled
+func (c *GatekeeperPolicyController) Run(ctx context.Context) {
+	klog.Infof("Starting %s", c.name)
+	defer klog.Infof("Shutting down %s", c.name)
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.sync(ctx); err != nil {
+			klog.Errorf("%s: unable to reconcile the %s policy: %v", c.name, ReleasePayloadNamingPolicyKind, err)
+		}
+	}, controllerDefaultResyncDuration)
+}
+
+func (c *GatekeeperPolicyController) sync(ctx context.Context) error {
+	klog.V(4).Infof("Starting %s sync", c.name)
+	defer klog.V(4).Infof("%s sync done", c.name)
+
+	if err := c.ensure(ctx, c.constraintTemplate, newReleasePayloadNamingPolicyConstraintTemplate()); err != nil {
+		return err
+	}
+
+	// Gatekeeper creates the CRD, of the constraint, once it has processed the ConstraintTemplate
+	err := c.ensure(ctx, c.constraint, newReleasePayloadNamingPolicyConstraint())
+	if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		klog.V(4).Infof("%s: waiting for Gatekeeper to create the %s CRD", c.name, ReleasePayloadNamingPolicyKind)
+		return nil
+	}
+	return err
+}
+
+// ensure creates, or updates the spec of, the cluster scoped object
+func (c *GatekeeperPolicyController) ensure(ctx context.Context, client dynamic.NamespaceableResourceInterface, expected *unstructured.Unstructured) error {
+	current, err := client.Get(ctx, expected.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err := client.Create(ctx, expected, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		c.eventRecorder.Eventf("GatekeeperPolicyCreated", "Created %s %s", expected.GetKind(), expected.GetName())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(current.Object["spec"], expected.Object["spec"]) {
+		return nil
+	}
+
+	updated := current.DeepCopy()
+	updated.Object["spec"] = expected.Object["spec"]
+	if _, err := client.Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	c.eventRecorder.Eventf("GatekeeperPolicyUpdated", "Updated %s %s", expected.GetKind(), expected.GetName())
+	return nil
+}
+
+// newReleasePayloadNamingPolicyConstraintTemplate returns the ConstraintTemplate defining the
+// ReleasePayloadNamingPolicy constraint
+func newReleasePayloadNamingPolicyConstraintTemplate() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": constraintTemplateGVR.GroupVersion().String(),
+			"kind":       "ConstraintTemplate",
+			"metadata": map[string]interface{}{
+				// Gatekeeper requires the name of the ConstraintTemplate to be the lowercase kind of its constraint
+				"name": strings.ToLower(ReleasePayloadNamingPolicyKind),
+			},
+			"spec": map[string]interface{}{
+				"crd": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"names": map[string]interface{}{
+							"kind": ReleasePayloadNamingPolicyKind,
+						},
+					},
+				},
+				"targets": []interface{}{
+					map[string]interface{}{
+						"target": "admission.k8s.gatekeeper.sh",
+						"rego":   releasePayloadNamingPolicyRego,
+					},
+				},
+			},
+		},
+	}
+}
+
+// newReleasePayloadNamingPolicyConstraint returns the ReleasePayloadNamingPolicy constraint that applies the policy to
+// the ReleasePayloads of every namespace
+func newReleasePayloadNamingPolicyConstraint() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": releasePayloadNamingPolicyGVR.GroupVersion().String(),
+			"kind":       ReleasePayloadNamingPolicyKind,
+			"metadata": map[string]interface{}{
+				"name": ReleasePayloadNamingPolicyName,
+			},
+			"spec": map[string]interface{}{
+				"enforcementAction": "deny",
+				"match": map[string]interface{}{
+					"kinds": []interface{}{
+						map[string]interface{}{
+							"apiGroups": []interface{}{v1alpha1.SchemeGroupVersion.Group},
+							"kinds":     []interface{}{"ReleasePayload"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
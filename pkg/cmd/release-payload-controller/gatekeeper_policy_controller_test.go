@@ -0,0 +1,139 @@
+package release_payload_controller
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/library-go/pkg/operator/events"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestGatekeeperPolicyControllerSync(t *testing.T) {
+	outdated := newReleasePayloadNamingPolicyConstraintTemplate()
+	if err := unstructured.SetNestedField(outdated.Object, []interface{}{}, "spec", "targets"); err != nil {
+		t.Fatalf("unable to create outdated ConstraintTemplate: %v", err)
+	}
+
+	testCases := []struct {
+		name               string
+		template           *unstructured.Unstructured
+		constraint         *unstructured.Unstructured
+		constraintCRDFound bool
+		expectedActions    []string
+		expectConstraint   bool
+	}{
+		{
+			name:               "PolicyMissing",
+			constraintCRDFound: true,
+			expectedActions:    []string{"get constrainttemplates", "create constrainttemplates", "get releasepayloadnamingpolicy", "create releasepayloadnamingpolicy"},
+			expectConstraint:   true,
+		},
+		{
+			name:               "ConstraintTemplateOutdated",
+			template:           outdated,
+			constraint:         newReleasePayloadNamingPolicyConstraint(),
+			constraintCRDFound: true,
+			expectedActions:    []string{"get constrainttemplates", "update constrainttemplates", "get releasepayloadnamingpolicy"},
+			expectConstraint:   true,
+		},
+		{
+			name:               "PolicyUpToDate",
+			template:           newReleasePayloadNamingPolicyConstraintTemplate(),
+			constraint:         newReleasePayloadNamingPolicyConstraint(),
+			constraintCRDFound: true,
+			expectedActions:    []string{"get constrainttemplates", "get releasepayloadnamingpolicy"},
+			expectConstraint:   true,
+		},
+		{
+			name:            "ConstraintCRDMissing",
+			template:        newReleasePayloadNamingPolicyConstraintTemplate(),
+			expectedActions: []string{"get constrainttemplates", "get releasepayloadnamingpolicy", "create releasepayloadnamingpolicy"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme())
+			// The fake client would guess the plural resource of the constraint, so the objects are created through it
+			if testCase.template != nil {
+				if _, err := dynamicClient.Resource(constraintTemplateGVR).Create(context.TODO(), testCase.template, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("unable to create ConstraintTemplate: %v", err)
+				}
+			}
+			if testCase.constraint != nil {
+				if _, err := dynamicClient.Resource(releasePayloadNamingPolicyGVR).Create(context.TODO(), testCase.constraint, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("unable to create constraint: %v", err)
+				}
+			}
+			dynamicClient.ClearActions()
+			if !testCase.constraintCRDFound {
+				// Until Gatekeeper has created the CRD of the constraint, the API server does not serve its resource
+				dynamicClient.PrependReactor("*", releasePayloadNamingPolicyGVR.Resource, func(action clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, k8serrors.NewNotFound(schema.GroupResource{}, "")
+				})
+			}
+
+			c := NewGatekeeperPolicyController(dynamicClient, events.NewInMemoryRecorder("gatekeeper-policy-controller-test"))
+
+			if err := c.sync(context.TODO()); err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+
+			var actions []string
+			for _, action := range dynamicClient.Actions() {
+				actions = append(actions, action.GetVerb()+" "+action.GetResource().Resource)
+			}
+			if diff := cmp.Diff(testCase.expectedActions, actions); diff != "" {
+				t.Errorf("unexpected actions (-want +got):\n%s", diff)
+			}
+
+			template, err := dynamicClient.Resource(constraintTemplateGVR).Get(context.TODO(), "releasepayloadnamingpolicy", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if expected := newReleasePayloadNamingPolicyConstraintTemplate(); !cmp.Equal(template.Object["spec"], expected.Object["spec"]) {
+				t.Errorf("unexpected ConstraintTemplate spec: %s", cmp.Diff(expected.Object["spec"], template.Object["spec"]))
+			}
+			if !testCase.expectConstraint {
+				return
+			}
+			constraint, err := dynamicClient.Resource(releasePayloadNamingPolicyGVR).Get(context.TODO(), ReleasePayloadNamingPolicyName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if expected := newReleasePayloadNamingPolicyConstraint(); !cmp.Equal(constraint.Object["spec"], expected.Object["spec"]) {
+				t.Errorf("unexpected constraint spec: %s", cmp.Diff(expected.Object["spec"], constraint.Object["spec"]))
+			}
+		})
+	}
+}
+
+// TestReleasePayloadNamingPolicyRegoPattern verifies that the pattern, as quoted in the Rego policy, matches the same
+// release creation job names as the admission webhook.  Rego's regex.match uses the same RE2 syntax as Go.
+func TestReleasePayloadNamingPolicyRegoPattern(t *testing.T) {
+	matches := regexp.MustCompile("not regex\\.match\\(`([^`]+)`, name\\)").FindStringSubmatch(releasePayloadNamingPolicyRego)
+	if matches == nil {
+		t.Fatalf("unable to find the pattern in the Rego policy:\n%s", releasePayloadNamingPolicyRego)
+	}
+	pattern := regexp.MustCompile(matches[1])
+
+	for name, valid := range map[string]bool{
+		"4.11.0-0.nightly-2022-02-09-091559":   true,
+		"4.11.0-0.nightly-arm64-2022-02-09-01": true,
+		"4.11.0":                               true,
+		"-4.11.0":                              false,
+		"4.11.0/nightly":                       false,
+		"4.11.0-0.nightly-2022-02-09-091559-0123456789-0123456789-0123456789": false,
+	} {
+		if got := pattern.MatchString(name); got != valid {
+			t.Errorf("%s: expected the policy to find it valid=%t, got %t", name, valid, got)
+		}
+	}
+}
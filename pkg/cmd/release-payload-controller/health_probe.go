@@ -0,0 +1,85 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultHealthProbeBindAddress is the address, following the controller-runtime convention, that the health
+	// probes are served on
+	defaultHealthProbeBindAddress = "0.0.0.0:8081"
+
+	healthzPath = "/healthz"
+	readyzPath  = "/readyz"
+
+	healthProbeShutdownTimeout = 5 * time.Second
+)
+
+// readyzHandler responds with a 503 until the informer caches, of all the specified controllers, have synced
+func readyzHandler(controllers ...*ReleasePayloadController) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		for _, c := range controllers {
+			for _, hasSynced := range c.cachesToSync {
+				if !hasSynced() {
+					http.Error(w, fmt.Sprintf("the informer caches of the %s have not synced", c.name), http.StatusServiceUnavailable)
+					return
+				}
+			}
+		}
+		if _, err := fmt.Fprintln(w, "ok"); err != nil {
+			klog.Errorf("Unable to write readyz response: %v", err)
+		}
+	}
+}
+
+// healthzHandler responds with a 200 for as long as the process is serving requests
+func healthzHandler(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if _, err := fmt.Fprintln(w, "ok"); err != nil {
+		klog.Errorf("Unable to write healthz response: %v", err)
+	}
+}
+
+// serveHealthProbes starts an http server, bound to the specified address, serving the liveness (/healthz), work
+// queue (/healthz/queue) and readiness (/readyz) probes, until the context is done.  The address that the server is
+// bound to is returned, so that port 0 may be used to bind to any free port.
+func serveHealthProbes(ctx context.Context, addr string, queueDepthThreshold int, queueController *ReleasePayloadController, controllers ...*ReleasePayloadController) (net.Addr, error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(healthzPath, healthzHandler)
+	mux.Handle(queueHealthPath, queueDepthHandler(queueController, queueDepthThreshold))
+	mux.Handle(readyzPath, readyzHandler(controllers...))
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to bind the health probe server to %s: %w", addr, err)
+	}
+	server := &http.Server{Handler: mux}
+	go func() {
+		klog.Infof("Listening on %s for health probes", listener.Addr())
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			klog.Errorf("Health probe server failed: %v", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), healthProbeShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			klog.Errorf("Unable to shut down the health probe server: %v", err)
+		}
+	}()
+	return listener.Addr(), nil
+}
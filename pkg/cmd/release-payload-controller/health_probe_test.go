@@ -0,0 +1,66 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestServeHealthProbes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	synced := false
+	c := &ReleasePayloadController{
+		name:         "Release Creation Status Controller",
+		queue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ReleaseCreationStatusController"),
+		cachesToSync: []cache.InformerSynced{func() bool { return synced }},
+	}
+	defer c.queue.ShutDown()
+
+	addr, err := serveHealthProbes(ctx, "127.0.0.1:0", defaultQueueDepthAlertThreshold, c, c)
+	if err != nil {
+		t.Fatalf("unable to serve health probes: %v", err)
+	}
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		t.Fatalf("expected a TCP address, got: %v", addr)
+	}
+	if !tcpAddr.IP.Equal(net.ParseIP("127.0.0.1")) || tcpAddr.Port == 0 {
+		t.Fatalf("expected the server to be bound to 127.0.0.1 on a free port, got: %s", tcpAddr)
+	}
+
+	get := func(path string) int {
+		resp, err := http.Get(fmt.Sprintf("http://%s%s", addr, path))
+		if err != nil {
+			t.Fatalf("unable to get %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	for path, expected := range map[string]int{
+		healthzPath:     http.StatusOK,
+		queueHealthPath: http.StatusOK,
+		readyzPath:      http.StatusServiceUnavailable,
+	} {
+		if code := get(path); code != expected {
+			t.Errorf("expected %s to respond with %d before the caches synced, got: %d", path, expected, code)
+		}
+	}
+
+	synced = true
+	if code := get(readyzPath); code != http.StatusOK {
+		t.Errorf("expected %s to respond with %d once the caches synced, got: %d", readyzPath, http.StatusOK, code)
+	}
+
+	// The address is in use for as long as the server is running
+	if _, err := serveHealthProbes(ctx, addr.String(), defaultQueueDepthAlertThreshold, c, c); err == nil {
+		t.Errorf("expected an error binding to %s while it is in use", addr)
+	}
+}
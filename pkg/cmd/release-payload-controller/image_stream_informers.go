@@ -0,0 +1,54 @@
+package release_payload_controller
+
+import (
+	imageclientset "github.com/openshift/client-go/image/clientset/versioned"
+	imageinformers "github.com/openshift/client-go/image/informers/externalversions"
+	imagev1informer "github.com/openshift/client-go/image/informers/externalversions/image/v1"
+	imagev1lister "github.com/openshift/client-go/image/listers/image/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// newImageStreamInformerFactories returns a SharedInformerFactory for each of the specified namespaces.  If no
+// namespaces are specified, a single SharedInformerFactory, watching all namespaces, is returned.
+func newImageStreamInformerFactories(imageStreamClient imageclientset.Interface, namespaces []string, tweakListOptions func(*metav1.ListOptions)) map[string]imageinformers.SharedInformerFactory {
+	if len(namespaces) == 0 {
+		return map[string]imageinformers.SharedInformerFactory{
+			metav1.NamespaceAll: imageinformers.NewSharedInformerFactoryWithOptions(imageStreamClient, controllerDefaultResyncDuration, imageinformers.WithTweakListOptions(tweakListOptions)),
+		}
+	}
+	factories := make(map[string]imageinformers.SharedInformerFactory)
+	for _, namespace := range namespaces {
+		factories[namespace] = imageinformers.NewSharedInformerFactoryWithOptions(imageStreamClient, controllerDefaultResyncDuration, imageinformers.WithNamespace(namespace), imageinformers.WithTweakListOptions(tweakListOptions))
+	}
+	return factories
+}
+
+// newImageStreamInformer returns an ImageStreamInformer that spans the ImageStream informers of all the factories.
+// The informer of the factory is returned, as is, if there is only one.
+func newImageStreamInformer(factories map[string]imageinformers.SharedInformerFactory) imagev1informer.ImageStreamInformer {
+	informers := make(map[string]cache.SharedIndexInformer)
+	for namespace, factory := range factories {
+		if len(factories) == 1 {
+			return factory.Image().V1().ImageStreams()
+		}
+		informers[namespace] = factory.Image().V1().ImageStreams().Informer()
+	}
+	return &multiNamespaceImageStreamInformer{
+		informer: &multiNamespaceInformer{informers: informers},
+	}
+}
+
+// multiNamespaceImageStreamInformer implements imagev1informer.ImageStreamInformer on top of several namespace scoped
+// informers
+type multiNamespaceImageStreamInformer struct {
+	informer *multiNamespaceInformer
+}
+
+func (i *multiNamespaceImageStreamInformer) Informer() cache.SharedIndexInformer {
+	return i.informer
+}
+
+func (i *multiNamespaceImageStreamInformer) Lister() imagev1lister.ImageStreamLister {
+	return imagev1lister.NewImageStreamLister(i.informer.GetIndexer())
+}
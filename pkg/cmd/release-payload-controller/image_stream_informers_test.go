@@ -0,0 +1,70 @@
+package release_payload_controller
+
+import (
+	"context"
+	"testing"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	imagefake "github.com/openshift/client-go/image/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newNamespacedImageStream(namespace, name string) *imagev1.ImageStream {
+	return &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+}
+
+func TestMultiNamespaceImageStreamInformer(t *testing.T) {
+	imageStreamClient := imagefake.NewSimpleClientset(
+		newNamespacedImageStream("ocp", "release"),
+		newNamespacedImageStream("ocp-arm64", "release-arm64"),
+		newNamespacedImageStream("origin", "release"),
+	)
+
+	factories := newImageStreamInformerFactories(imageStreamClient, []string{"ocp", "ocp-arm64"}, nil)
+	if len(factories) != 2 {
+		t.Fatalf("expected 2 informer factories, got %d", len(factories))
+	}
+	informer := newImageStreamInformer(factories)
+	// Request the informers before the factories are started
+	informer.Informer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, factory := range factories {
+		factory.Start(ctx.Done())
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), informer.Informer().HasSynced) {
+		t.Fatalf("informers did not sync")
+	}
+
+	imageStreams, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		t.Fatalf("unable to list imagestreams: %v", err)
+	}
+	if len(imageStreams) != 2 {
+		t.Errorf("expected 2 imagestreams, got %d", len(imageStreams))
+	}
+	if _, err := informer.Lister().ImageStreams("ocp-arm64").Get("release-arm64"); err != nil {
+		t.Errorf("unable to get imagestream from a watched namespace: %v", err)
+	}
+	if _, err := informer.Lister().ImageStreams("origin").Get("release"); err == nil {
+		t.Errorf("expected no imagestreams in a namespace that is not watched")
+	}
+}
+
+func TestImageStreamInformerAllNamespaces(t *testing.T) {
+	factories := newImageStreamInformerFactories(imagefake.NewSimpleClientset(), nil, nil)
+	if _, ok := factories[metav1.NamespaceAll]; !ok || len(factories) != 1 {
+		t.Fatalf("expected a single informer factory for all namespaces, got %d", len(factories))
+	}
+	if _, ok := newImageStreamInformer(factories).(*multiNamespaceImageStreamInformer); ok {
+		t.Errorf("expected the informer of the factory to be used as is")
+	}
+}
@@ -0,0 +1,74 @@
+package release_payload_controller
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	batchv1informers "k8s.io/client-go/informers/batch/v1"
+	"k8s.io/client-go/kubernetes"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// newJobInformerFactories returns a SharedInformerFactory for each of the specified namespaces.  If no namespaces
+// are specified, a single SharedInformerFactory, watching all namespaces, is returned.
+func newJobInformerFactories(kubeClient kubernetes.Interface, namespaces []string, tweakListOptions func(*metav1.ListOptions)) map[string]informers.SharedInformerFactory {
+	if len(namespaces) == 0 {
+		return map[string]informers.SharedInformerFactory{
+			metav1.NamespaceAll: informers.NewSharedInformerFactoryWithOptions(kubeClient, controllerDefaultResyncDuration, informers.WithTweakListOptions(tweakListOptions)),
+		}
+	}
+	factories := make(map[string]informers.SharedInformerFactory)
+	for _, namespace := range namespaces {
+		factories[namespace] = informers.NewSharedInformerFactoryWithOptions(kubeClient, controllerDefaultResyncDuration, informers.WithNamespace(namespace), informers.WithTweakListOptions(tweakListOptions))
+	}
+	return factories
+}
+
+// multiNamespaceJobLister implements batchv1listers.JobLister on top of the listers of several namespace scoped
+// informers.  Lookups in a namespace that is not being watched behave as if the namespace was empty.
+type multiNamespaceJobLister struct {
+	listers map[string]batchv1listers.JobLister
+}
+
+func newMultiNamespaceJobLister(batchJobInformers map[string]batchv1informers.JobInformer) batchv1listers.JobLister {
+	l := &multiNamespaceJobLister{
+		listers: make(map[string]batchv1listers.JobLister),
+	}
+	for namespace, informer := range batchJobInformers {
+		l.listers[namespace] = informer.Lister()
+	}
+	return l
+}
+
+func (l *multiNamespaceJobLister) List(selector labels.Selector) ([]*batchv1.Job, error) {
+	var ret []*batchv1.Job
+	for _, lister := range l.listers {
+		jobs, err := lister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, jobs...)
+	}
+	return ret, nil
+}
+
+func (l *multiNamespaceJobLister) Jobs(namespace string) batchv1listers.JobNamespaceLister {
+	return l.listerFor(namespace).Jobs(namespace)
+}
+
+func (l *multiNamespaceJobLister) GetPodJobs(pod *corev1.Pod) ([]batchv1.Job, error) {
+	return l.listerFor(pod.Namespace).GetPodJobs(pod)
+}
+
+func (l *multiNamespaceJobLister) listerFor(namespace string) batchv1listers.JobLister {
+	if lister, ok := l.listers[namespace]; ok {
+		return lister
+	}
+	if lister, ok := l.listers[metav1.NamespaceAll]; ok {
+		return lister
+	}
+	return batchv1listers.NewJobLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}))
+}
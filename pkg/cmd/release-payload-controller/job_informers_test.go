@@ -0,0 +1,193 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	releasecontroller "github.com/openshift/release-controller/pkg/release-controller"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	batchv1informers "k8s.io/client-go/informers/batch/v1"
+	"k8s.io/client-go/kubernetes"
+	fake2 "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newReleaseCreationJob(namespace, name, target string) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				releasecontroller.ReleaseAnnotationReleaseTag: name,
+				releasecontroller.ReleaseAnnotationTarget:     target,
+			},
+		},
+	}
+}
+
+func TestMultiNamespaceJobLister(t *testing.T) {
+	kubeClient := fake2.NewSimpleClientset(
+		newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release"),
+		newReleaseCreationJob("ci-release-priv", "4.11.0-0.nightly-priv-2022-02-09-091559", "ocp-priv/release-priv"),
+		newReleaseCreationJob("ci-release-arm64", "4.11.0-0.nightly-arm64-2022-02-09-091559", "ocp-arm64/release-arm64"),
+	)
+
+	testCases := []struct {
+		name       string
+		namespaces []string
+		found      []string
+		notFound   []string
+	}{
+		{
+			name:       "AllNamespaces",
+			namespaces: nil,
+			found:      []string{"ci-release/4.11.0-0.nightly-2022-02-09-091559", "ci-release-priv/4.11.0-0.nightly-priv-2022-02-09-091559", "ci-release-arm64/4.11.0-0.nightly-arm64-2022-02-09-091559"},
+		},
+		{
+			name:       "MultipleNamespaces",
+			namespaces: []string{"ci-release", "ci-release-priv"},
+			found:      []string{"ci-release/4.11.0-0.nightly-2022-02-09-091559", "ci-release-priv/4.11.0-0.nightly-priv-2022-02-09-091559"},
+			notFound:   []string{"ci-release-arm64/4.11.0-0.nightly-arm64-2022-02-09-091559"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			factories := newJobInformerFactories(kubeClient, testCase.namespaces, nil)
+			batchJobInformers := make(map[string]batchv1informers.JobInformer)
+			var cachesToSync []cache.InformerSynced
+			for namespace, factory := range factories {
+				batchJobInformers[namespace] = factory.Batch().V1().Jobs()
+				cachesToSync = append(cachesToSync, batchJobInformers[namespace].Informer().HasSynced)
+			}
+			lister := newMultiNamespaceJobLister(batchJobInformers)
+			for _, factory := range factories {
+				factory.Start(context.Background().Done())
+			}
+			if !cache.WaitForNamedCacheSync("MultiNamespaceJobLister", context.Background().Done(), cachesToSync...) {
+				t.Fatalf("%s: error waiting for caches to sync", testCase.name)
+			}
+
+			for _, key := range testCase.found {
+				namespace, name, _ := cache.SplitMetaNamespaceKey(key)
+				if _, err := lister.Jobs(namespace).Get(name); err != nil {
+					t.Errorf("%s: expected to find job %s, got: %v", testCase.name, key, err)
+				}
+			}
+			for _, key := range testCase.notFound {
+				namespace, name, _ := cache.SplitMetaNamespaceKey(key)
+				if _, err := lister.Jobs(namespace).Get(name); !errors.IsNotFound(err) {
+					t.Errorf("%s: expected job %s to not be found, got: %v", testCase.name, key, err)
+				}
+			}
+
+			jobs, err := lister.List(labels.Everything())
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			if len(jobs) != len(testCase.found) {
+				t.Errorf("%s: Expected %d jobs, got %d", testCase.name, len(testCase.found), len(jobs))
+			}
+		})
+	}
+}
+
+func TestReleaseCreationStatusControllerMultipleNamespaces(t *testing.T) {
+	c := newReleasePayloadControllerTestBuilder(t).
+		WithBatchJob(newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")).
+		WithBatchJob(newReleaseCreationJob("ci-release-priv", "4.11.0-0.nightly-priv-2022-02-09-091559", "ocp-priv/release-priv")).
+		WithBatchJob(newReleaseCreationJob("ci-release-arm64", "4.11.0-0.nightly-arm64-2022-02-09-091559", "ocp-arm64/release-arm64")).
+		WithJobsNamespace("ci-release").
+		WithJobsNamespace("ci-release-priv").
+		Build()
+
+	expected := sets.NewString("ocp/4.11.0-0.nightly-2022-02-09-091559", "ocp-priv/4.11.0-0.nightly-priv-2022-02-09-091559")
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return c.queue.Len() >= expected.Len(), nil
+	}); err != nil {
+		t.Fatalf("Expected %d queued keys, got %d", expected.Len(), c.queue.Len())
+	}
+
+	queued := sets.NewString()
+	for c.queue.Len() > 0 {
+		key, _ := c.queue.Get()
+		queued.Insert(key.(string))
+		c.queue.Done(key)
+	}
+	if !cmp.Equal(queued.List(), expected.List()) {
+		t.Errorf("Expected %v, got %v", expected.List(), queued.List())
+	}
+}
+
+func TestWatchTimeoutTweakListOptions(t *testing.T) {
+	var lock sync.Mutex
+	timeouts := map[string]string{}
+	done := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		verb := "list"
+		if req.URL.Query().Get("watch") == "true" {
+			verb = "watch"
+		}
+		lock.Lock()
+		timeouts[verb] = req.URL.Query().Get("timeoutSeconds")
+		lock.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if verb == "watch" {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			select {
+			case <-req.Context().Done():
+			case <-done:
+			}
+			return
+		}
+		fmt.Fprint(w, `{"kind":"JobList","apiVersion":"batch/v1","metadata":{"resourceVersion":"1"},"items":[]}`)
+	}))
+	defer server.Close()
+	defer close(done)
+
+	kubeClient, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	factories := newJobInformerFactories(kubeClient, []string{"ci-release"}, watchTimeoutTweakListOptions(5*time.Minute))
+	for _, factory := range factories {
+		factory.Batch().V1().Jobs().Informer()
+		factory.Start(ctx.Done())
+	}
+
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		_, listed := timeouts["list"]
+		_, watched := timeouts["watch"]
+		return listed && watched, nil
+	}); err != nil {
+		t.Fatalf("timed out waiting for list and watch requests")
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	for _, verb := range []string{"list", "watch"} {
+		if timeouts[verb] != "300" {
+			t.Errorf("Expected %s timeoutSeconds to be %q, got %q", verb, "300", timeouts[verb])
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"reflect"
+	"time"
 
 	"github.com/openshift/library-go/pkg/operator/events"
 )
@@ -32,12 +33,17 @@ import (
 //   - .status.upgradeJobResults[] | .state
 type JobStateController struct {
 	*ReleasePayloadController
+
+	// blockingJobRequeueInterval is how long updates are batched, before being processed, while all the blocking
+	// jobs of a ReleasePayload are still running
+	blockingJobRequeueInterval time.Duration
 }
 
 func NewJobStateController(
 	releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer,
 	releasePayloadClient releasepayloadclient.ReleaseV1alpha1Interface,
 	eventRecorder events.Recorder,
+	blockingJobRequeueInterval time.Duration,
 ) (*JobStateController, error) {
 	c := &JobStateController{
 		ReleasePayloadController: NewReleasePayloadController("Job State Controller",
@@ -45,24 +51,60 @@ func NewJobStateController(
 			releasePayloadClient,
 			eventRecorder.WithComponentSuffix("job-state-controller"),
 			workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "JobStateController")),
+		blockingJobRequeueInterval: blockingJobRequeueInterval,
 	}
 
 	c.syncFn = c.sync
 
 	releasePayloadInformer.Informer().AddEventHandler(&cache.ResourceEventHandlerFuncs{
-		AddFunc: c.Enqueue,
-		UpdateFunc: func(oldObj, newObj interface{}) {
-			c.Enqueue(newObj)
-		},
+		AddFunc:    c.Enqueue,
+		UpdateFunc: c.enqueueUpdate,
 		DeleteFunc: c.Enqueue,
 	})
 
 	return c, nil
 }
 
+// enqueueUpdate skips the updates that do not change the results of the jobs, which are the only input of the
+// controller.  While all the blocking jobs are still running, the updates are batched for blockingJobRequeueInterval,
+// since their aggregate state cannot change until one of them completes.
+func (c *JobStateController) enqueueUpdate(oldObj, newObj interface{}) {
+	oldReleasePayload, ok := oldObj.(*v1alpha1.ReleasePayload)
+	if !ok {
+		return
+	}
+	newReleasePayload, ok := newObj.(*v1alpha1.ReleasePayload)
+	if !ok {
+		return
+	}
+	if !jobResultsChanged(oldReleasePayload, newReleasePayload) {
+		return
+	}
+	if !allBlockingJobsRunning(newReleasePayload) {
+		c.Enqueue(newReleasePayload)
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(newReleasePayload)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid queue key '%v': %v", newReleasePayload, err))
+		return
+	}
+	c.queue.AddAfter(key, c.blockingJobRequeueInterval)
+}
+
+// jobResultsChanged returns true if the results of the jobs, or the pause of the reconciliation, differ between the
+// two ReleasePayloads
+func jobResultsChanged(oldReleasePayload, newReleasePayload *v1alpha1.ReleasePayload) bool {
+	return oldReleasePayload.Spec.PauseReconciliation != newReleasePayload.Spec.PauseReconciliation ||
+		!reflect.DeepEqual(oldReleasePayload.Status.BlockingJobResults, newReleasePayload.Status.BlockingJobResults) ||
+		!reflect.DeepEqual(oldReleasePayload.Status.InformingJobResults, newReleasePayload.Status.InformingJobResults) ||
+		!reflect.DeepEqual(oldReleasePayload.Status.UpgradeJobResults, newReleasePayload.Status.UpgradeJobResults)
+}
+
 func (c *JobStateController) sync(ctx context.Context, key string) error {
-	klog.V(4).Infof("Starting JobStateController sync")
-	defer klog.V(4).Infof("JobStateController sync done")
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting JobStateController sync")
+	defer logger.V(4).Info("JobStateController sync done")
 
 	// Convert the namespace/name string into a distinct namespace and name
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
@@ -81,6 +123,11 @@ func (c *JobStateController) sync(ctx context.Context, key string) error {
 		return err
 	}
 
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(originalReleasePayload) {
+		return nil
+	}
+
 	// TODO: at larger scales, we need to figure out if we need to change a value before the deepcopy
 	releasePayload := originalReleasePayload.DeepCopy()
 
@@ -120,6 +167,19 @@ func (c *JobStateController) sync(ctx context.Context, key string) error {
 	return nil
 }
 
+// allBlockingJobsRunning returns true if the ReleasePayload has blocking jobs and all of them are still pending
+func allBlockingJobsRunning(releasePayload *v1alpha1.ReleasePayload) bool {
+	if len(releasePayload.Status.BlockingJobResults) == 0 {
+		return false
+	}
+	for _, job := range releasePayload.Status.BlockingJobResults {
+		if computeJobState(job) != v1alpha1.JobStatePending {
+			return false
+		}
+	}
+	return true
+}
+
 // computeJobState analyzes the specified job to determine which type of JobRunResults to process and returns
 // the corresponding JobState.
 func computeJobState(job v1alpha1.JobStatus) v1alpha1.JobState {
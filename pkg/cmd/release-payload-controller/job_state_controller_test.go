@@ -13,6 +13,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"testing"
+	"time"
 )
 
 func TestJobStateSync(t *testing.T) {
@@ -1001,3 +1002,149 @@ func TestProcessRetryJobResults(t *testing.T) {
 		})
 	}
 }
+
+// recordingQueue records the calls made to Add and AddAfter
+type recordingQueue struct {
+	workqueue.RateLimitingInterface
+
+	added    []interface{}
+	addAfter map[interface{}]time.Duration
+}
+
+func (q *recordingQueue) Add(item interface{}) {
+	q.added = append(q.added, item)
+}
+
+func (q *recordingQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.addAfter[item] = duration
+}
+
+func TestJobStateEnqueueUpdate(t *testing.T) {
+	pendingJob := func(name string) v1alpha1.JobStatus {
+		return v1alpha1.JobStatus{
+			CIConfigurationName:    name,
+			CIConfigurationJobName: fmt.Sprintf("periodic-ci-openshift-release-master-nightly-4.11-e2e-%s", name),
+			JobRunResults: []v1alpha1.JobRunResult{
+				{
+					Coordinates: v1alpha1.JobRunCoordinates{
+						Name:      fmt.Sprintf("4.11.0-0.nightly-2022-02-09-091559-%s", name),
+						Namespace: "ci",
+						Cluster:   "build04",
+					},
+					State: v1alpha1.JobRunStatePending,
+				},
+			},
+		}
+	}
+	successfulJob := pendingJob("aws")
+	successfulJob.JobRunResults[0].State = v1alpha1.JobRunStateSuccess
+	informingJob := pendingJob("metal")
+	informingJob.JobRunResults[0].State = v1alpha1.JobRunStateFailure
+
+	testCases := []struct {
+		name                    string
+		old                     v1alpha1.ReleasePayloadStatus
+		new                     v1alpha1.ReleasePayloadStatus
+		newPaused               bool
+		expectedEnqueued        bool
+		expectedEnqueuedDelayed bool
+	}{
+		{
+			name: "JobResultsUnchanged",
+			old: v1alpha1.ReleasePayloadStatus{
+				BlockingJobResults: []v1alpha1.JobStatus{successfulJob, pendingJob("gcp")},
+			},
+			new: v1alpha1.ReleasePayloadStatus{
+				BlockingJobResults: []v1alpha1.JobStatus{successfulJob, pendingJob("gcp")},
+				Conditions: []metav1.Condition{
+					{Type: "PayloadCreated", Status: metav1.ConditionTrue},
+				},
+			},
+		},
+		{
+			name: "ReconciliationPaused",
+			old: v1alpha1.ReleasePayloadStatus{
+				BlockingJobResults: []v1alpha1.JobStatus{successfulJob},
+			},
+			new: v1alpha1.ReleasePayloadStatus{
+				BlockingJobResults: []v1alpha1.JobStatus{successfulJob},
+			},
+			newPaused:        true,
+			expectedEnqueued: true,
+		},
+		{
+			name: "BlockingJobCompleted",
+			old: v1alpha1.ReleasePayloadStatus{
+				BlockingJobResults: []v1alpha1.JobStatus{pendingJob("aws"), pendingJob("gcp")},
+			},
+			new: v1alpha1.ReleasePayloadStatus{
+				BlockingJobResults: []v1alpha1.JobStatus{successfulJob, pendingJob("gcp")},
+			},
+			expectedEnqueued: true,
+		},
+		{
+			name: "InformingJobChangedWhileAllBlockingJobsRunning",
+			old: v1alpha1.ReleasePayloadStatus{
+				BlockingJobResults:  []v1alpha1.JobStatus{pendingJob("aws"), pendingJob("gcp")},
+				InformingJobResults: []v1alpha1.JobStatus{pendingJob("metal")},
+			},
+			new: v1alpha1.ReleasePayloadStatus{
+				BlockingJobResults:  []v1alpha1.JobStatus{pendingJob("aws"), pendingJob("gcp")},
+				InformingJobResults: []v1alpha1.JobStatus{informingJob},
+			},
+			expectedEnqueuedDelayed: true,
+		},
+		{
+			name: "NoBlockingJobs",
+			old: v1alpha1.ReleasePayloadStatus{
+				InformingJobResults: []v1alpha1.JobStatus{pendingJob("metal")},
+			},
+			new: v1alpha1.ReleasePayloadStatus{
+				InformingJobResults: []v1alpha1.JobStatus{informingJob},
+			},
+			expectedEnqueued: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			oldReleasePayload := &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+				},
+				Status: testCase.old,
+			}
+			newReleasePayload := oldReleasePayload.DeepCopy()
+			newReleasePayload.Spec.PauseReconciliation = testCase.newPaused
+			newReleasePayload.Status = testCase.new
+
+			queue := &recordingQueue{
+				RateLimitingInterface: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "JobStateController"),
+				addAfter:              map[interface{}]time.Duration{},
+			}
+			c := &JobStateController{
+				ReleasePayloadController: &ReleasePayloadController{
+					name:  "Job State Controller",
+					queue: queue,
+				},
+				blockingJobRequeueInterval: 2 * time.Minute,
+			}
+
+			c.enqueueUpdate(oldReleasePayload, newReleasePayload)
+
+			key := fmt.Sprintf("%s/%s", newReleasePayload.Namespace, newReleasePayload.Name)
+			enqueued := len(queue.added) == 1 && queue.added[0] == key
+			if enqueued != testCase.expectedEnqueued {
+				t.Errorf("%s: Expected enqueued %t, got %v", testCase.name, testCase.expectedEnqueued, queue.added)
+			}
+			duration, enqueuedDelayed := queue.addAfter[key]
+			if enqueuedDelayed != testCase.expectedEnqueuedDelayed {
+				t.Fatalf("%s: Expected enqueued after a delay %t, got %t", testCase.name, testCase.expectedEnqueuedDelayed, enqueuedDelayed)
+			}
+			if enqueuedDelayed && duration != c.blockingJobRequeueInterval {
+				t.Errorf("%s: Expected enqueued after %v, got %v", testCase.name, c.blockingJobRequeueInterval, duration)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package release_payload_controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	imagev1informer "github.com/openshift/client-go/image/informers/externalversions/image/v1"
+	imagev1lister "github.com/openshift/client-go/image/listers/image/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// LabelPropagationController is responsible for copying the release.openshift.io/* labels, of the ImageStream that
+// a ReleasePayload was created from, onto the ReleasePayload.  Labels are added or updated, but never removed.
+// The LabelPropagationController watches for changes to the following resources:
+//   - imagev1.ImageStreams
+//
+// and reads the following pieces of information:
+//   - .spec.payloadCoordinates.namespace
+//   - .spec.payloadCoordinates.imagestreamName
+//
+// and writes the following information:
+//   - .metadata.labels
+type LabelPropagationController struct {
+	*ReleasePayloadController
+
+	imageStreamLister imagev1lister.ImageStreamLister
+}
+
+func NewLabelPropagationController(
+	releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer,
+	releasePayloadClient releasepayloadclient.ReleaseV1alpha1Interface,
+	imageStreamInformer imagev1informer.ImageStreamInformer,
+	eventRecorder events.Recorder,
+) (*LabelPropagationController, error) {
+	c := &LabelPropagationController{
+		ReleasePayloadController: NewReleasePayloadController("Label Propagation Controller",
+			releasePayloadInformer,
+			releasePayloadClient,
+			eventRecorder.WithComponentSuffix("label-propagation-controller"),
+			workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "LabelPropagationController")),
+		imageStreamLister: imageStreamInformer.Lister(),
+	}
+
+	c.syncFn = c.sync
+	c.cachesToSync = append(c.cachesToSync, imageStreamInformer.Informer().HasSynced)
+
+	releasePayloadInformer.Informer().AddEventHandler(&cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.Enqueue,
+		UpdateFunc: func(old, new interface{}) { c.Enqueue(new) },
+	})
+
+	imageStreamInformer.Informer().AddEventHandler(&cache.ResourceEventHandlerFuncs{
+		AddFunc: c.lookupReleasePayloads,
+		UpdateFunc: func(old, new interface{}) {
+			oldImageStream, oldOK := old.(*imagev1.ImageStream)
+			newImageStream, newOK := new.(*imagev1.ImageStream)
+			// ImageStreams change every time a tag is imported, only the label changes are of interest
+			if oldOK && newOK && labels.Equals(oldImageStream.Labels, newImageStream.Labels) {
+				return
+			}
+			c.lookupReleasePayloads(new)
+		},
+	})
+
+	return c, nil
+}
+
+// lookupReleasePayloads enqueues every ReleasePayload that was created from the ImageStream
+func (c *LabelPropagationController) lookupReleasePayloads(obj interface{}) {
+	imageStream, ok := obj.(*imagev1.ImageStream)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("unable to cast obj: %v", obj))
+		return
+	}
+	releasePayloads, err := c.releasePayloadLister.ReleasePayloads(imageStream.Namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("unable to list releasepayloads in namespace %s: %w", imageStream.Namespace, err))
+		return
+	}
+	for _, releasePayload := range releasePayloads {
+		if releasePayload.Spec.PayloadCoordinates.ImagestreamName == imageStream.Name {
+			c.Enqueue(releasePayload)
+		}
+	}
+}
+
+func (c *LabelPropagationController) sync(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting LabelPropagationController sync")
+	defer logger.V(4).Info("LabelPropagationController sync done")
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	// Get the ReleasePayload resource with this namespace/name
+	releasePayload, err := c.releasePayloadLister.ReleasePayloads(namespace).Get(name)
+	// The ReleasePayload resource may no longer exist, in which case we stop processing.
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(releasePayload) {
+		return nil
+	}
+
+	if len(releasePayload.Spec.PayloadCoordinates.Namespace) == 0 || len(releasePayload.Spec.PayloadCoordinates.ImagestreamName) == 0 {
+		return nil
+	}
+	imageStream, err := c.imageStreamLister.ImageStreams(releasePayload.Spec.PayloadCoordinates.Namespace).Get(releasePayload.Spec.PayloadCoordinates.ImagestreamName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	propagated := computeLabelPropagationPatch(imageStream, releasePayload)
+	if len(propagated) == 0 {
+		return nil
+	}
+	// A merge patch only sets the propagated labels, leaving any label written concurrently alone
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": propagated,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.V(4).Infof("Propagating labels of imagestream %s/%s to ReleasePayload %s/%s", imageStream.Namespace, imageStream.Name, releasePayload.Namespace, releasePayload.Name)
+	_, err = c.releasePayloadClient.ReleasePayloads(releasePayload.Namespace).Patch(ctx, releasePayload.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// computeLabelPropagationPatch returns the release.openshift.io/* labels, of the ImageStream, that the ReleasePayload
+// is missing, or has a different value for
+func computeLabelPropagationPatch(imageStream *imagev1.ImageStream, releasePayload *v1alpha1.ReleasePayload) map[string]string {
+	var propagated map[string]string
+	for key, value := range imageStream.Labels {
+		if !strings.HasPrefix(key, releasePayloadLabelPrefix) {
+			continue
+		}
+		if current, ok := releasePayload.Labels[key]; ok && current == value {
+			continue
+		}
+		if propagated == nil {
+			propagated = make(map[string]string)
+		}
+		propagated[key] = value
+	}
+	return propagated
+}
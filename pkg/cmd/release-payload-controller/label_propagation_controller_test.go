@@ -0,0 +1,200 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	imagev1 "github.com/openshift/api/image/v1"
+	imagefake "github.com/openshift/client-go/image/clientset/versioned/fake"
+	imageinformers "github.com/openshift/client-go/image/informers/externalversions"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func newLabelPropagationReleasePayload(name, imageStreamName string, labels map[string]string) *v1alpha1.ReleasePayload {
+	return &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ocp",
+			Labels:    labels,
+		},
+		Spec: v1alpha1.ReleasePayloadSpec{
+			PayloadCoordinates: v1alpha1.PayloadCoordinates{
+				Namespace:          "ocp",
+				ImagestreamName:    imageStreamName,
+				ImagestreamTagName: name,
+			},
+		},
+	}
+}
+
+func TestComputeLabelPropagationPatch(t *testing.T) {
+	imageStream := &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "release",
+			Namespace: "ocp",
+			Labels: map[string]string{
+				"release.openshift.io/stream-type": "nightly",
+				"release.openshift.io/a/b~c":       "escaped",
+				"release.openshift.io/empty":       "",
+				"app":                              "release-controller",
+			},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		labels   map[string]string
+		expected map[string]string
+	}{
+		{
+			name: "NoLabels",
+			expected: map[string]string{
+				"release.openshift.io/a/b~c":       "escaped",
+				"release.openshift.io/empty":       "",
+				"release.openshift.io/stream-type": "nightly",
+			},
+		},
+		{
+			name: "OutdatedLabel",
+			labels: map[string]string{
+				"release.openshift.io/stream-type": "ci",
+				"release.openshift.io/a/b~c":       "escaped",
+				"release.openshift.io/empty":       "",
+			},
+			expected: map[string]string{
+				"release.openshift.io/stream-type": "nightly",
+			},
+		},
+		{
+			name: "UpToDate",
+			labels: map[string]string{
+				"release.openshift.io/stream-type": "nightly",
+				"release.openshift.io/a/b~c":       "escaped",
+				"release.openshift.io/empty":       "",
+				"release.openshift.io/other":       "kept",
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			releasePayload := newLabelPropagationReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "release", testCase.labels)
+			if propagated := computeLabelPropagationPatch(imageStream, releasePayload); !cmp.Equal(propagated, testCase.expected) {
+				t.Errorf("%s: %s", testCase.name, cmp.Diff(testCase.expected, propagated))
+			}
+		})
+	}
+}
+
+func TestLabelPropagationSync(t *testing.T) {
+	imageStreams := []runtime.Object{
+		&imagev1.ImageStream{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "release",
+				Namespace: "ocp",
+				Labels: map[string]string{
+					"release.openshift.io/stream-type": "nightly",
+					"app":                              "release-controller",
+				},
+			},
+		},
+		&imagev1.ImageStream{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "release-ci",
+				Namespace: "ocp",
+				Labels: map[string]string{
+					"release.openshift.io/stream-type": "ci",
+				},
+			},
+		},
+	}
+
+	releasePayloads := []runtime.Object{
+		newLabelPropagationReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "release", nil),
+		newLabelPropagationReleasePayload("4.11.0-0.nightly-2022-02-10-091559", "release", map[string]string{
+			"release.openshift.io/stream-type": "ci",
+			"release.openshift.io/other":       "kept",
+		}),
+		newLabelPropagationReleasePayload("4.11.0-0.ci-2022-02-09-091559", "release-ci", nil),
+		newLabelPropagationReleasePayload("4.11.0-0.okd-2022-02-09-091559", "release-okd", nil),
+	}
+
+	expected := map[string]map[string]string{
+		"4.11.0-0.nightly-2022-02-09-091559": {
+			"release.openshift.io/stream-type": "nightly",
+		},
+		"4.11.0-0.nightly-2022-02-10-091559": {
+			"release.openshift.io/stream-type": "nightly",
+			"release.openshift.io/other":       "kept",
+		},
+		"4.11.0-0.ci-2022-02-09-091559": {
+			"release.openshift.io/stream-type": "ci",
+			"release.openshift.io/other":       "concurrent",
+		},
+		// The ImageStream does not exist
+		"4.11.0-0.okd-2022-02-09-091559": nil,
+	}
+
+	releasePayloadClient := fake.NewSimpleClientset(releasePayloads...)
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+	imageStreamInformerFactory := imageinformers.NewSharedInformerFactory(imagefake.NewSimpleClientset(imageStreams...), controllerDefaultResyncDuration)
+
+	c, err := NewLabelPropagationController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), imageStreamInformerFactory.Image().V1().ImageStreams(), events.NewInMemoryRecorder("label-propagation-controller-test"))
+	if err != nil {
+		t.Fatalf("unable to create controller: %v", err)
+	}
+	defer c.queue.ShutDown()
+
+	releasePayloadInformerFactory.Start(context.Background().Done())
+	imageStreamInformerFactory.Start(context.Background().Done())
+
+	if !cache.WaitForNamedCacheSync("LabelPropagationController", context.Background().Done(), c.cachesToSync...) {
+		t.Fatalf("error waiting for caches to sync")
+	}
+
+	// Every ReleasePayload of the ImageStream is queued when the ImageStream is observed
+	c.queue.ShutDown()
+	c.queue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "LabelPropagationController")
+	c.lookupReleasePayloads(imageStreams[0])
+	queued := sets.NewString()
+	for c.queue.Len() > 0 {
+		key, _ := c.queue.Get()
+		queued.Insert(key.(string))
+		c.queue.Done(key)
+	}
+	if expectedKeys := []string{"ocp/4.11.0-0.nightly-2022-02-09-091559", "ocp/4.11.0-0.nightly-2022-02-10-091559"}; !cmp.Equal(queued.List(), expectedKeys) {
+		t.Errorf("Expected %v to be queued, got %v", expectedKeys, queued.List())
+	}
+
+	// A label written after the ReleasePayload was cached is not overwritten by the propagated labels
+	current, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads("ocp").Get(context.TODO(), "4.11.0-0.ci-2022-02-09-091559", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	current.Labels = map[string]string{"release.openshift.io/other": "concurrent"}
+	if _, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads("ocp").Update(context.TODO(), current, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	for name, expectedLabels := range expected {
+		if err := c.sync(context.TODO(), fmt.Sprintf("ocp/%s", name)); err != nil {
+			t.Errorf("%s: unexpected err: %v", name, err)
+		}
+		output, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads("ocp").Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("%s: unexpected err: %v", name, err)
+		}
+		if !cmp.Equal(output.Labels, expectedLabels) {
+			t.Errorf("%s: Expected labels %v, got %v", name, expectedLabels, output.Labels)
+		}
+	}
+}
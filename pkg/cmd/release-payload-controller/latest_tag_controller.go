@@ -0,0 +1,303 @@
+package release_payload_controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	imagev1client "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
+	imagev1informer "github.com/openshift/client-go/image/informers/externalversions/image/v1"
+	imagev1lister "github.com/openshift/client-go/image/listers/image/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
+	releasepayloadhelpers "github.com/openshift/release-controller/pkg/releasepayload/v1alpha1helpers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// LatestTag is the tag, of the release ImageStream, that points to the image of the newest Accepted ReleasePayload
+const LatestTag = "latest"
+
+// releasePayloadStreamIndex is the name of the index, of the ReleasePayload informer, that groups the ReleasePayloads
+// by namespace and release stream
+const releasePayloadStreamIndex = "releasePayloadStream"
+
+// releasePayloadStreamIndexFunc indexes the ReleasePayload by its namespace and release stream
+func releasePayloadStreamIndexFunc(obj interface{}) ([]string, error) {
+	releasePayload, ok := obj.(*v1alpha1.ReleasePayload)
+	if !ok {
+		return nil, nil
+	}
+	return []string{releasePayloadStreamKey(releasePayload)}, nil
+}
+
+func releasePayloadStreamKey(releasePayload *v1alpha1.ReleasePayload) string {
+	return fmt.Sprintf("%s/%s", releasePayload.Namespace, releasepayloadhelpers.ReleasePayloadStream(releasePayload))
+}
+
+// LatestTagController is responsible for pointing the latest tag, of the ImageStream that ReleasePayloads are
+// created in, at the release image of the newest Accepted ReleasePayload of the release stream.  Moving the tag to a
+// newer ReleasePayload removes it from the previous one.  When the ReleasePayload holding the tag is deleted, or is
+// no longer Accepted, the tag is moved back to the newest Accepted ReleasePayload that remains.
+// The LatestTagController reads the following pieces of information:
+//   - .metadata.name
+//   - .metadata.creationTimestamp
+//   - .spec.payloadCoordinates.namespace
+//   - .spec.payloadCoordinates.imagestreamName
+//   - .spec.payloadCoordinates.imagestreamTagName
+//   - .status.conditions.PayloadAccepted
+//   - .status.releaseDigest
+//
+// and writes the following information:
+//   - the latest tag of the {payloadCoordinates.namespace}/{payloadCoordinates.imagestreamName} ImageStream
+type LatestTagController struct {
+	*ReleasePayloadController
+
+	// releasePayloadIndexer looks up the ReleasePayloads of a release stream, by the releasePayloadStreamIndex
+	releasePayloadIndexer cache.Indexer
+
+	imageStreamLister imagev1lister.ImageStreamLister
+	imageStreamClient imagev1client.ImageStreamsGetter
+}
+
+func NewLatestTagController(
+	releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer,
+	releasePayloadClient releasepayloadclient.ReleaseV1alpha1Interface,
+	imageStreamInformer imagev1informer.ImageStreamInformer,
+	imageStreamClient imagev1client.ImageStreamsGetter,
+	eventRecorder events.Recorder,
+) (*LatestTagController, error) {
+	c := &LatestTagController{
+		ReleasePayloadController: NewReleasePayloadController("Latest Tag Controller",
+			releasePayloadInformer,
+			releasePayloadClient,
+			eventRecorder.WithComponentSuffix("latest-tag-controller"),
+			workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "LatestTagController")),
+		releasePayloadIndexer: releasePayloadInformer.Informer().GetIndexer(),
+		imageStreamLister:     imageStreamInformer.Lister(),
+		imageStreamClient:     imageStreamClient,
+	}
+
+	c.syncFn = c.sync
+	c.cachesToSync = append(c.cachesToSync, imageStreamInformer.Informer().HasSynced)
+
+	if err := releasePayloadInformer.Informer().AddIndexers(cache.Indexers{releasePayloadStreamIndex: releasePayloadStreamIndexFunc}); err != nil {
+		return nil, err
+	}
+
+	releasePayloadInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if releasePayload, ok := obj.(*v1alpha1.ReleasePayload); ok && isAccepted(releasePayload) {
+				c.Enqueue(releasePayload)
+			}
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldReleasePayload, ok := old.(*v1alpha1.ReleasePayload)
+			if !ok {
+				return
+			}
+			newReleasePayload, ok := new.(*v1alpha1.ReleasePayload)
+			if !ok {
+				return
+			}
+			switch {
+			case isAccepted(newReleasePayload):
+				c.Enqueue(newReleasePayload)
+			case isAccepted(oldReleasePayload):
+				c.enqueueNewestAccepted(newReleasePayload)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if releasePayload, ok := obj.(*v1alpha1.ReleasePayload); ok && isAccepted(releasePayload) {
+				c.enqueueNewestAccepted(releasePayload)
+			}
+		},
+	})
+
+	return c, nil
+}
+
+// enqueueNewestAccepted queues the newest Accepted ReleasePayload, of the release stream of the ReleasePayload that
+// has been deleted or is no longer Accepted, so that the latest tag is moved back to it
+func (c *LatestTagController) enqueueNewestAccepted(releasePayload *v1alpha1.ReleasePayload) {
+	newest, err := c.newestAccepted(releasePayload, releasePayload.Name)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("unable to find the newest Accepted ReleasePayload of stream %s: %w", releasePayloadStreamKey(releasePayload), err))
+		return
+	}
+	if newest != nil {
+		c.Enqueue(newest)
+	}
+}
+
+// newestAccepted returns the newest Accepted ReleasePayload, other than the excluded one, of the release stream of
+// the ReleasePayload.  Nil is returned if there is none.
+func (c *LatestTagController) newestAccepted(releasePayload *v1alpha1.ReleasePayload, exclude string) (*v1alpha1.ReleasePayload, error) {
+	objs, err := c.releasePayloadIndexer.ByIndex(releasePayloadStreamIndex, releasePayloadStreamKey(releasePayload))
+	if err != nil {
+		return nil, err
+	}
+	var newest *v1alpha1.ReleasePayload
+	for _, obj := range objs {
+		other, ok := obj.(*v1alpha1.ReleasePayload)
+		if !ok || other.Name == exclude || !isAccepted(other) {
+			continue
+		}
+		if newest == nil || createdBefore(newest, other) {
+			newest = other
+		}
+	}
+	return newest, nil
+}
+
+// isAccepted returns true if the ReleasePayload has been Accepted
+func isAccepted(releasePayload *v1alpha1.ReleasePayload) bool {
+	return v1helpers.IsConditionTrue(releasePayload.Status.Conditions, v1alpha1.ConditionPayloadAccepted)
+}
+
+func (c *LatestTagController) sync(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting LatestTagController sync")
+	defer logger.V(4).Info("LatestTagController sync done")
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	// Get the ReleasePayload resource with this namespace/name
+	releasePayload, err := c.releasePayloadLister.ReleasePayloads(namespace).Get(name)
+	// The ReleasePayload resource may no longer exist, in which case we stop processing.
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(releasePayload) {
+		return nil
+	}
+
+	if !isAccepted(releasePayload) {
+		return nil
+	}
+
+	// Only the newest Accepted ReleasePayload, of the release stream, is tagged as latest
+	newest, err := c.newestAccepted(releasePayload, "")
+	if err != nil {
+		return err
+	}
+	if newest != nil && newest.Name != releasePayload.Name {
+		klog.V(4).Infof("ReleasePayload %s/%s is not the newest Accepted ReleasePayload, %s is", releasePayload.Namespace, releasePayload.Name, newest.Name)
+		return nil
+	}
+
+	coordinates := releasePayload.Spec.PayloadCoordinates
+	imageStream, err := c.imageStreamLister.ImageStreams(coordinates.Namespace).Get(coordinates.ImagestreamName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	digest := releaseImageDigest(imageStream, releasePayload)
+	if len(digest) == 0 {
+		klog.V(4).Infof("The release image digest, of ReleasePayload %s/%s, is not known yet", releasePayload.Namespace, releasePayload.Name)
+		return nil
+	}
+
+	operations := computeLatestTagPatch(imageStream, fmt.Sprintf("%s@%s", imageStream.Name, digest))
+	if len(operations) == 0 {
+		return nil
+	}
+	patch, err := json.Marshal(operations)
+	if err != nil {
+		return err
+	}
+
+	klog.V(4).Infof("Tagging ReleasePayload %s/%s as %s in ImageStream %s/%s", releasePayload.Namespace, releasePayload.Name, LatestTag, imageStream.Namespace, imageStream.Name)
+	_, err = c.imageStreamClient.ImageStreams(imageStream.Namespace).Patch(ctx, imageStream.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c.eventRecorder.Eventf("ReleasePayloadTaggedLatest", "Tagged ReleasePayload %s/%s as %s in ImageStream %s/%s", releasePayload.Namespace, releasePayload.Name, LatestTag, imageStream.Namespace, imageStream.Name)
+	return nil
+}
+
+// releaseImageDigest returns the digest of the release image of the ReleasePayload, from its status or, if not
+// recorded there, from the tag of the ImageStream that the release image was pushed to
+func releaseImageDigest(imageStream *imagev1.ImageStream, releasePayload *v1alpha1.ReleasePayload) string {
+	if len(releasePayload.Status.ReleaseDigest) > 0 {
+		return releasePayload.Status.ReleaseDigest
+	}
+	for _, tag := range imageStream.Status.Tags {
+		if tag.Tag == releasePayload.Spec.PayloadCoordinates.ImagestreamTagName && len(tag.Items) > 0 {
+			return tag.Items[0].Image
+		}
+	}
+	return ""
+}
+
+// jsonPatchOperation is a single operation of an RFC 6902 JSON Patch.  The value is always sent, as a test for a
+// null value is how a patch guards against the path having been set since the object was cached.
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// computeLatestTagPatch returns the JSON Patch operations that point the latest tag, of the ImageStream, at the
+// image.  No operations are returned if the latest tag already points at the image.
+func computeLatestTagPatch(imageStream *imagev1.ImageStream, image string) []jsonPatchOperation {
+	from := &corev1.ObjectReference{Kind: "ImageStreamImage", Name: image}
+	for i, tag := range imageStream.Spec.Tags {
+		if tag.Name != LatestTag {
+			continue
+		}
+		if tag.From != nil && tag.From.Kind == from.Kind && tag.From.Name == from.Name && len(tag.From.Namespace) == 0 {
+			return nil
+		}
+		// Guard against the tags being reordered since the ImageStream was cached
+		return []jsonPatchOperation{
+			{Op: "test", Path: fmt.Sprintf("/spec/tags/%d/name", i), Value: LatestTag},
+			{Op: "replace", Path: fmt.Sprintf("/spec/tags/%d/from", i), Value: from},
+		}
+	}
+	var operations []jsonPatchOperation
+	if len(imageStream.Spec.Tags) == 0 {
+		// Guard against tags having been added since the ImageStream was cached, which adding the list would clobber
+		operations = append(operations,
+			jsonPatchOperation{Op: "test", Path: "/spec/tags", Value: nil},
+			jsonPatchOperation{Op: "add", Path: "/spec/tags", Value: []imagev1.TagReference{}},
+		)
+	}
+	return append(operations, jsonPatchOperation{
+		Op:   "add",
+		Path: "/spec/tags/-",
+		Value: imagev1.TagReference{
+			Name: LatestTag,
+			From: from,
+		},
+	})
+}
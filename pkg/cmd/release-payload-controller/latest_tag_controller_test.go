@@ -0,0 +1,362 @@
+package release_payload_controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	imagev1 "github.com/openshift/api/image/v1"
+	imagefake "github.com/openshift/client-go/image/clientset/versioned/fake"
+	imageinformers "github.com/openshift/client-go/image/informers/externalversions"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newLatestTagReleasePayload(name, imageStreamName, digest string, created time.Time, accepted bool) *v1alpha1.ReleasePayload {
+	status := metav1.ConditionFalse
+	if accepted {
+		status = metav1.ConditionTrue
+	}
+	return &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "ocp",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: v1alpha1.ReleasePayloadSpec{
+			PayloadCoordinates: v1alpha1.PayloadCoordinates{
+				Namespace:          "ocp",
+				ImagestreamName:    imageStreamName,
+				ImagestreamTagName: name,
+			},
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			Conditions: []metav1.Condition{
+				{Type: v1alpha1.ConditionPayloadAccepted, Status: status},
+			},
+			ReleaseDigest: digest,
+		},
+	}
+}
+
+func TestComputeLatestTagPatch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		tags     []imagev1.TagReference
+		expected []jsonPatchOperation
+	}{
+		{
+			name: "NoTags",
+			expected: []jsonPatchOperation{
+				{Op: "test", Path: "/spec/tags", Value: nil},
+				{Op: "add", Path: "/spec/tags", Value: []imagev1.TagReference{}},
+				{Op: "add", Path: "/spec/tags/-", Value: imagev1.TagReference{Name: "latest", From: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "release@sha256:new"}}},
+			},
+		},
+		{
+			name: "NoLatestTag",
+			tags: []imagev1.TagReference{
+				{Name: "4.11.0-0.nightly-2022-02-09-091559"},
+			},
+			expected: []jsonPatchOperation{
+				{Op: "add", Path: "/spec/tags/-", Value: imagev1.TagReference{Name: "latest", From: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "release@sha256:new"}}},
+			},
+		},
+		{
+			name: "OutdatedLatestTag",
+			tags: []imagev1.TagReference{
+				{Name: "4.11.0-0.nightly-2022-02-09-091559"},
+				{Name: "latest", From: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "release@sha256:old"}},
+			},
+			expected: []jsonPatchOperation{
+				{Op: "test", Path: "/spec/tags/1/name", Value: "latest"},
+				{Op: "replace", Path: "/spec/tags/1/from", Value: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "release@sha256:new"}},
+			},
+		},
+		{
+			name: "UpToDate",
+			tags: []imagev1.TagReference{
+				{Name: "latest", From: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "release@sha256:new"}},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			imageStream := &imagev1.ImageStream{
+				ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "ocp"},
+				Spec:       imagev1.ImageStreamSpec{Tags: testCase.tags},
+			}
+			if operations := computeLatestTagPatch(imageStream, "release@sha256:new"); !cmp.Equal(operations, testCase.expected) {
+				t.Errorf("%s: Expected %v, got %v", testCase.name, testCase.expected, operations)
+			}
+		})
+	}
+}
+
+func TestLatestTagSync(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name            string
+		imageStream     *imagev1.ImageStream
+		releasePayloads []runtime.Object
+		key             string
+		expected        *corev1.ObjectReference
+	}{
+		{
+			name: "NewestAcceptedReleasePayloadIsTagged",
+			imageStream: &imagev1.ImageStream{
+				ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "ocp"},
+			},
+			releasePayloads: []runtime.Object{
+				newLatestTagReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "release", "sha256:old", now.Add(-2*time.Hour), true),
+				newLatestTagReleasePayload("4.11.0-0.nightly-2022-02-10-091559", "release", "sha256:new", now.Add(-time.Hour), true),
+			},
+			key:      "ocp/4.11.0-0.nightly-2022-02-10-091559",
+			expected: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "release@sha256:new"},
+		},
+		{
+			name: "LatestTagIsMovedFromPreviousReleasePayload",
+			imageStream: &imagev1.ImageStream{
+				ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "ocp"},
+				Spec: imagev1.ImageStreamSpec{
+					Tags: []imagev1.TagReference{
+						{Name: "latest", From: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "release@sha256:old"}},
+					},
+				},
+			},
+			releasePayloads: []runtime.Object{
+				newLatestTagReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "release", "sha256:old", now.Add(-2*time.Hour), true),
+				newLatestTagReleasePayload("4.11.0-0.nightly-2022-02-10-091559", "release", "sha256:new", now.Add(-time.Hour), true),
+			},
+			key:      "ocp/4.11.0-0.nightly-2022-02-10-091559",
+			expected: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "release@sha256:new"},
+		},
+		{
+			name: "OlderAcceptedReleasePayloadIsNotTagged",
+			imageStream: &imagev1.ImageStream{
+				ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "ocp"},
+				Spec: imagev1.ImageStreamSpec{
+					Tags: []imagev1.TagReference{
+						{Name: "latest", From: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "release@sha256:new"}},
+					},
+				},
+			},
+			releasePayloads: []runtime.Object{
+				newLatestTagReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "release", "sha256:old", now.Add(-2*time.Hour), true),
+				newLatestTagReleasePayload("4.11.0-0.nightly-2022-02-10-091559", "release", "sha256:new", now.Add(-time.Hour), true),
+			},
+			key:      "ocp/4.11.0-0.nightly-2022-02-09-091559",
+			expected: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "release@sha256:new"},
+		},
+		{
+			name: "NewerRejectedReleasePayloadIsIgnored",
+			imageStream: &imagev1.ImageStream{
+				ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "ocp"},
+			},
+			releasePayloads: []runtime.Object{
+				newLatestTagReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "release", "sha256:old", now.Add(-2*time.Hour), true),
+				newLatestTagReleasePayload("4.11.0-0.nightly-2022-02-10-091559", "release", "sha256:new", now.Add(-time.Hour), false),
+			},
+			key:      "ocp/4.11.0-0.nightly-2022-02-09-091559",
+			expected: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "release@sha256:old"},
+		},
+		{
+			name: "NewerReleasePayloadOfOtherImageStreamIsIgnored",
+			imageStream: &imagev1.ImageStream{
+				ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "ocp"},
+			},
+			releasePayloads: []runtime.Object{
+				newLatestTagReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "release", "sha256:old", now.Add(-2*time.Hour), true),
+				newLatestTagReleasePayload("4.11.0-0.ci-2022-02-10-091559", "release-ci", "sha256:ci", now.Add(-time.Hour), true),
+			},
+			key:      "ocp/4.11.0-0.nightly-2022-02-09-091559",
+			expected: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "release@sha256:old"},
+		},
+		{
+			name: "DigestFromImageStreamTag",
+			imageStream: &imagev1.ImageStream{
+				ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "ocp"},
+				Status: imagev1.ImageStreamStatus{
+					Tags: []imagev1.NamedTagEventList{
+						{Tag: "4.11.0-0.nightly-2022-02-09-091559", Items: []imagev1.TagEvent{{Image: "sha256:tagged"}}},
+					},
+				},
+			},
+			releasePayloads: []runtime.Object{
+				newLatestTagReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "release", "", now.Add(-time.Hour), true),
+			},
+			key:      "ocp/4.11.0-0.nightly-2022-02-09-091559",
+			expected: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "release@sha256:tagged"},
+		},
+		{
+			name: "UnknownDigest",
+			imageStream: &imagev1.ImageStream{
+				ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "ocp"},
+			},
+			releasePayloads: []runtime.Object{
+				newLatestTagReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "release", "", now.Add(-time.Hour), true),
+			},
+			key: "ocp/4.11.0-0.nightly-2022-02-09-091559",
+		},
+		{
+			name: "NotAccepted",
+			imageStream: &imagev1.ImageStream{
+				ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "ocp"},
+			},
+			releasePayloads: []runtime.Object{
+				newLatestTagReleasePayload("4.11.0-0.nightly-2022-02-09-091559", "release", "sha256:old", now.Add(-time.Hour), false),
+			},
+			key: "ocp/4.11.0-0.nightly-2022-02-09-091559",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			releasePayloadClient := fake.NewSimpleClientset(testCase.releasePayloads...)
+			releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+			imageStreamClient := imagefake.NewSimpleClientset(testCase.imageStream)
+			imageStreamInformerFactory := imageinformers.NewSharedInformerFactory(imageStreamClient, controllerDefaultResyncDuration)
+
+			c, err := NewLatestTagController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), imageStreamInformerFactory.Image().V1().ImageStreams(), imageStreamClient.ImageV1(), events.NewInMemoryRecorder("latest-tag-controller-test"))
+			if err != nil {
+				t.Fatalf("unable to create controller: %v", err)
+			}
+			defer c.queue.ShutDown()
+
+			releasePayloadInformerFactory.Start(context.Background().Done())
+			imageStreamInformerFactory.Start(context.Background().Done())
+
+			if !cache.WaitForNamedCacheSync("LatestTagController", context.Background().Done(), c.cachesToSync...) {
+				t.Fatalf("%s: error waiting for caches to sync", testCase.name)
+			}
+
+			if err := c.sync(context.TODO(), testCase.key); err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+
+			output, err := imageStreamClient.ImageV1().ImageStreams("ocp").Get(context.TODO(), testCase.imageStream.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			var latest []*corev1.ObjectReference
+			for _, tag := range output.Spec.Tags {
+				if tag.Name == LatestTag {
+					latest = append(latest, tag.From)
+				}
+			}
+			var expected []*corev1.ObjectReference
+			if testCase.expected != nil {
+				expected = append(expected, testCase.expected)
+			}
+			if !cmp.Equal(latest, expected) {
+				t.Errorf("%s: Unexpected latest tag: %s", testCase.name, cmp.Diff(expected, latest))
+			}
+		})
+	}
+}
+
+// TestLatestTagMovedBack verifies that the latest tag is moved back to the newest Accepted ReleasePayload that remains
+// once the ReleasePayload holding it is deleted, or is no longer Accepted
+func TestLatestTagMovedBack(t *testing.T) {
+	now := time.Now()
+	const (
+		previous = "4.11.0-0.nightly-2022-02-09-091559"
+		holder   = "4.11.0-0.nightly-2022-02-10-091559"
+	)
+
+	testCases := []struct {
+		name   string
+		remove func(ctx context.Context, client *fake.Clientset) error
+	}{
+		{
+			name: "HolderDeleted",
+			remove: func(ctx context.Context, client *fake.Clientset) error {
+				return client.ReleaseV1alpha1().ReleasePayloads("ocp").Delete(ctx, holder, metav1.DeleteOptions{})
+			},
+		},
+		{
+			name: "HolderRejected",
+			remove: func(ctx context.Context, client *fake.Clientset) error {
+				_, err := client.ReleaseV1alpha1().ReleasePayloads("ocp").UpdateStatus(ctx, newLatestTagReleasePayload(holder, "release", "sha256:new", now.Add(-time.Hour), false), metav1.UpdateOptions{})
+				return err
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			releasePayloadClient := fake.NewSimpleClientset(
+				newLatestTagReleasePayload(previous, "release", "sha256:old", now.Add(-2*time.Hour), true),
+				newLatestTagReleasePayload(holder, "release", "sha256:new", now.Add(-time.Hour), true),
+			)
+			releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+			imageStreamClient := imagefake.NewSimpleClientset(&imagev1.ImageStream{
+				ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "ocp"},
+				Spec: imagev1.ImageStreamSpec{
+					Tags: []imagev1.TagReference{
+						{Name: "latest", From: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "release@sha256:new"}},
+					},
+				},
+			})
+			imageStreamInformerFactory := imageinformers.NewSharedInformerFactory(imageStreamClient, controllerDefaultResyncDuration)
+
+			c, err := NewLatestTagController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), imageStreamInformerFactory.Image().V1().ImageStreams(), imageStreamClient.ImageV1(), events.NewInMemoryRecorder("latest-tag-controller-test"))
+			if err != nil {
+				t.Fatalf("unable to create controller: %v", err)
+			}
+			defer c.queue.ShutDown()
+
+			releasePayloadInformerFactory.Start(ctx.Done())
+			imageStreamInformerFactory.Start(ctx.Done())
+			if !cache.WaitForNamedCacheSync("LatestTagController", ctx.Done(), c.cachesToSync...) {
+				t.Fatalf("error waiting for caches to sync")
+			}
+
+			// Drain the keys queued by the initial list
+			if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) { return c.queue.Len() == 2, nil }); err != nil {
+				t.Fatalf("Expected both Accepted ReleasePayloads to be queued, got %d", c.queue.Len())
+			}
+			for c.queue.Len() > 0 {
+				key, _ := c.queue.Get()
+				c.queue.Forget(key)
+				c.queue.Done(key)
+			}
+
+			if err := testCase.remove(ctx, releasePayloadClient); err != nil {
+				t.Fatalf("unable to remove the holder of the latest tag: %v", err)
+			}
+			key, _ := c.queue.Get()
+			c.queue.Done(key)
+			if expected := "ocp/" + previous; key != expected {
+				t.Fatalf("Expected %s to be queued, got %v", expected, key)
+			}
+
+			if err := c.sync(ctx, key.(string)); err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			output, err := imageStreamClient.ImageV1().ImageStreams("ocp").Get(ctx, "release", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			expected := []imagev1.TagReference{
+				{Name: "latest", From: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "release@sha256:old"}},
+			}
+			if !cmp.Equal(output.Spec.Tags, expected) {
+				t.Errorf("Unexpected tags: %s", cmp.Diff(expected, output.Spec.Tags))
+			}
+		})
+	}
+}
@@ -78,8 +78,9 @@ func NewLegacyJobStatusController(
 }
 
 func (c *LegacyJobStatusController) sync(ctx context.Context, key string) error {
-	klog.V(4).Infof("Starting LegacyJobStatusController sync")
-	defer klog.V(4).Infof("LegacyJobStatusController sync done")
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting LegacyJobStatusController sync")
+	defer logger.V(4).Info("LegacyJobStatusController sync done")
 
 	// Convert the namespace/name string into a distinct namespace and name
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
@@ -100,6 +101,11 @@ func (c *LegacyJobStatusController) sync(ctx context.Context, key string) error
 		return err
 	}
 
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(originalReleasePayload) {
+		return nil
+	}
+
 	// If PayloadVerificationDataSource is not the ImageStream Annotation, then we don't have anything to do...
 	if originalReleasePayload.Spec.PayloadVerificationConfig.PayloadVerificationDataSource != v1alpha1.PayloadVerificationDataSourceImageStream {
 		return nil
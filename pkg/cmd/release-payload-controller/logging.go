@@ -0,0 +1,168 @@
+package release_payload_controller
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+// The formats of the logs
+const (
+	// LogFormatText leaves the logging to klog
+	LogFormatText = "text"
+
+	// LogFormatJSON logs every record, through zap, as a JSON object
+	LogFormatJSON = "json"
+)
+
+var logFormats = []string{LogFormatText, LogFormatJSON}
+
+var zapLogLevels = []string{"debug", "info", "warn", "error"}
+
+// The fields, of every JSON log record, identifying the ReleasePayload being reconciled and the controller doing so.
+// They are empty if the record is not about a ReleasePayload.
+const (
+	logFieldController = "controller"
+	logFieldNamespace  = "namespace"
+	logFieldName       = "name"
+)
+
+// configureLogging makes zap the backend of klog, if the format is json, logging to stderr at the level
+func configureLogging(format, level string) error {
+	switch format {
+	case LogFormatText:
+		return nil
+	case LogFormatJSON:
+		logger, err := newZapLogger(os.Stderr, level)
+		if err != nil {
+			return err
+		}
+		klog.SetLogger(logr.New(newZapLogSink(logger)))
+		return nil
+	}
+	return fmt.Errorf("unknown log format %q, must be one of %v", format, logFormats)
+}
+
+// newZapLogger returns a zap.Logger that writes the records, at or above the level, as JSON to w
+func newZapLogger(w io.Writer, level string) (*zap.Logger, error) {
+	var minLevel zapcore.Level
+	if !sets.NewString(zapLogLevels...).Has(level) || minLevel.Set(level) != nil {
+		return nil, fmt.Errorf("unknown log level %q, must be one of %v", level, zapLogLevels)
+	}
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(w), minLevel)
+	return zap.New(core), nil
+}
+
+// zapLogSink is a logr.LogSink writing to a zap.Logger.  The verbosity of klog (i.e. klog.V(4)) is left to klog, every
+// verbose record is logged at the debug level.
+type zapLogSink struct {
+	logger *zap.Logger
+
+	// controller, namespace and name are added to every record unless the record has its own
+	controller string
+	namespace  string
+	name       string
+}
+
+func newZapLogSink(logger *zap.Logger) *zapLogSink {
+	return &zapLogSink{logger: logger}
+}
+
+func (s *zapLogSink) Init(logr.RuntimeInfo) {}
+
+func zapLevel(level int) zapcore.Level {
+	if level > 0 {
+		return zapcore.DebugLevel
+	}
+	return zapcore.InfoLevel
+}
+
+func (s *zapLogSink) Enabled(level int) bool {
+	return s.logger.Core().Enabled(zapLevel(level))
+}
+
+func (s *zapLogSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	if entry := s.logger.Check(zapLevel(level), msg); entry != nil {
+		entry.Write(s.fields(keysAndValues)...)
+	}
+}
+
+func (s *zapLogSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	if entry := s.logger.Check(zapcore.ErrorLevel, msg); entry != nil {
+		entry.Write(append(s.fields(keysAndValues), zap.Error(err))...)
+	}
+}
+
+func (s *zapLogSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	out := *s
+	var fields []zap.Field
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, value := logKeyValue(keysAndValues, i)
+		if !out.setIdentity(key, value) {
+			fields = append(fields, zap.Any(key, value))
+		}
+	}
+	out.logger = s.logger.With(fields...)
+	return &out
+}
+
+// WithName names the controller that the records are logged by
+func (s *zapLogSink) WithName(name string) logr.LogSink {
+	out := *s
+	out.controller = name
+	return &out
+}
+
+// setIdentity sets the controller, namespace or name if that is the key, returning false for any other key
+func (s *zapLogSink) setIdentity(key string, value interface{}) bool {
+	var field *string
+	switch key {
+	case logFieldController:
+		field = &s.controller
+	case logFieldNamespace:
+		field = &s.namespace
+	case logFieldName:
+		field = &s.name
+	default:
+		return false
+	}
+	*field = fmt.Sprint(value)
+	return true
+}
+
+// fields returns the controller, namespace and name fields, followed by the fields of the remaining key/value pairs
+func (s *zapLogSink) fields(keysAndValues []interface{}) []zap.Field {
+	identity := *s
+	fields := make([]zap.Field, 3, 3+len(keysAndValues)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, value := logKeyValue(keysAndValues, i)
+		if !identity.setIdentity(key, value) {
+			fields = append(fields, zap.Any(key, value))
+		}
+	}
+	fields[0] = zap.String(logFieldController, identity.controller)
+	fields[1] = zap.String(logFieldNamespace, identity.namespace)
+	fields[2] = zap.String(logFieldName, identity.name)
+	return fields
+}
+
+// logKeyValue returns the i-th key, and its value, of the key/value pairs.  A key missing its value is logged with a
+// nil value, like klog does.
+func logKeyValue(keysAndValues []interface{}, i int) (string, interface{}) {
+	key, ok := keysAndValues[i].(string)
+	if !ok {
+		key = fmt.Sprint(keysAndValues[i])
+	}
+	if i+1 >= len(keysAndValues) {
+		return key, nil
+	}
+	return key, keysAndValues[i+1]
+}
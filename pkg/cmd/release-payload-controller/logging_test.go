@@ -0,0 +1,163 @@
+package release_payload_controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+func TestZapLogSink(t *testing.T) {
+	testCases := []struct {
+		name     string
+		level    string
+		log      func(logger logr.Logger)
+		expected []map[string]interface{}
+	}{
+		{
+			name:  "InfoWithoutIdentity",
+			level: "info",
+			log: func(logger logr.Logger) {
+				logger.Info("Listening for health probes", "address", "0.0.0.0:8081")
+			},
+			expected: []map[string]interface{}{
+				{"level": "info", "msg": "Listening for health probes", "controller": "", "namespace": "", "name": "", "address": "0.0.0.0:8081"},
+			},
+		},
+		{
+			name:  "ErrorWithIdentity",
+			level: "info",
+			log: func(logger logr.Logger) {
+				logger.Error(errors.New("conflict"), "Sync failed", "controller", "Release Creation Status Controller", "namespace", "ocp", "name", "4.11.0-0.nightly-2022-02-09-091559")
+			},
+			expected: []map[string]interface{}{
+				{"level": "error", "msg": "Sync failed", "controller": "Release Creation Status Controller", "namespace": "ocp", "name": "4.11.0-0.nightly-2022-02-09-091559", "error": "conflict"},
+			},
+		},
+		{
+			name:  "IdentityFromNameAndValues",
+			level: "info",
+			log: func(logger logr.Logger) {
+				logger.WithName("SBOM Controller").WithValues("namespace", "ocp", "name", "4.11.0-0.nightly-2022-02-09-091559", "stream", "4-stable").Info("Storing SBOM")
+			},
+			expected: []map[string]interface{}{
+				{"level": "info", "msg": "Storing SBOM", "controller": "SBOM Controller", "namespace": "ocp", "name": "4.11.0-0.nightly-2022-02-09-091559", "stream": "4-stable"},
+			},
+		},
+		{
+			name:  "VerboseAtInfo",
+			level: "info",
+			log: func(logger logr.Logger) {
+				logger.V(4).Info("Starting sync")
+			},
+		},
+		{
+			name:  "VerboseAtDebug",
+			level: "debug",
+			log: func(logger logr.Logger) {
+				logger.V(4).Info("Starting sync")
+			},
+			expected: []map[string]interface{}{
+				{"level": "debug", "msg": "Starting sync", "controller": "", "namespace": "", "name": ""},
+			},
+		},
+		{
+			name:  "InfoAtError",
+			level: "error",
+			log: func(logger logr.Logger) {
+				logger.Info("Starting sync")
+				logger.Error(errors.New("conflict"), "Sync failed")
+			},
+			expected: []map[string]interface{}{
+				{"level": "error", "msg": "Sync failed", "controller": "", "namespace": "", "name": "", "error": "conflict"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var out bytes.Buffer
+			zapLogger, err := newZapLogger(&out, testCase.level)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			testCase.log(logr.New(newZapLogSink(zapLogger)))
+
+			if diff := cmp.Diff(testCase.expected, decodeLogRecords(t, &out)); diff != "" {
+				t.Errorf("unexpected log records (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// decodeLogRecords returns the JSON log records, without their timestamps, written to out
+func decodeLogRecords(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if len(line) == 0 {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("log record %q is not JSON: %v", line, err)
+		}
+		if _, ok := record["ts"]; !ok {
+			t.Errorf("log record %q has no timestamp", line)
+		}
+		delete(record, "ts")
+		records = append(records, record)
+	}
+	return records
+}
+
+// TestProcessNextItemContextLogger verifies that the records logged, by a sync, through the logger of its context
+// identify the ReleasePayload and the controller
+func TestProcessNextItemContextLogger(t *testing.T) {
+	var out bytes.Buffer
+	zapLogger, err := newZapLogger(&out, "info")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := klog.NewContext(context.TODO(), logr.New(newZapLogSink(zapLogger)))
+
+	releasePayloadClient := fake.NewSimpleClientset()
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+	c := NewReleasePayloadController("Test Controller",
+		releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(),
+		releasePayloadClient.ReleaseV1alpha1(),
+		events.NewInMemoryRecorder("release-payload-controller-test"),
+		workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "TestController"))
+	defer c.queue.ShutDown()
+	c.syncFn = func(ctx context.Context, key string) error {
+		klog.FromContext(ctx).Info("Syncing", "stream", "4-stable")
+		return errors.New("conflict")
+	}
+
+	c.queue.Add("ocp/4.11.0-0.nightly-2022-02-09-091559")
+	c.processNextItem(ctx)
+
+	expected := []map[string]interface{}{
+		{"level": "info", "msg": "Syncing", "controller": "Test Controller", "namespace": "ocp", "name": "4.11.0-0.nightly-2022-02-09-091559", "stream": "4-stable"},
+		{"level": "error", "msg": "Sync failed", "controller": "Test Controller", "namespace": "ocp", "name": "4.11.0-0.nightly-2022-02-09-091559", "error": "conflict"},
+	}
+	if diff := cmp.Diff(expected, decodeLogRecords(t, &out)); diff != "" {
+		t.Errorf("unexpected log records (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewZapLoggerInvalidLevel(t *testing.T) {
+	for _, level := range []string{"", "trace", "dpanic", "fatal"} {
+		if _, err := newZapLogger(&bytes.Buffer{}, level); err == nil {
+			t.Errorf("expected an error for level %q", level)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
+	releasepayloadlister "github.com/openshift/release-controller/pkg/client/listers/release/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// OperatorConditionName is the name of the OperatorCondition that reports the status of the release-controller to OLM
+	OperatorConditionName = "release-controller"
+
+	// OperatorConditionUpgradeable is the OperatorCondition condition type that OLM consults before upgrading an operator
+	OperatorConditionUpgradeable = "Upgradeable"
+
+	// OperatorConditionUpgradeableReasonAccepted is used when a ReleasePayload has been Accepted within the upgradeableWindow
+	OperatorConditionUpgradeableReasonAccepted = "ReleasePayloadAccepted"
+
+	// OperatorConditionUpgradeableReasonNotAccepted is used when no ReleasePayload has been Accepted within the upgradeableWindow
+	OperatorConditionUpgradeableReasonNotAccepted = "NoReleasePayloadAccepted"
+
+	// upgradeableWindow is how recently a ReleasePayload must have been Accepted for the release-controller to be Upgradeable
+	upgradeableWindow = 24 * time.Hour
+)
+
+var operatorConditionGVR = schema.GroupVersionResource{
+	Group:    "operators.coreos.com",
+	Version:  "v2",
+	Resource: "operatorconditions",
+}
+
+// OLMStatusController is responsible for reporting, via the release-controller OperatorCondition, whether it is safe
+// for OLM to upgrade the release-controller.  The release-controller is considered Upgradeable when at least one
+// ReleasePayload has been Accepted in the past 24 hours.
+// The OLMStatusController reads the following pieces of information:
+//   - .metadata.creationTimestamp
+//   - .status.conditions.PayloadAccepted
+//
+// and populates the following condition, on the OperatorCondition:
+//   - .spec.conditions.Upgradeable
+//
+// The OperatorCondition is reconciled whenever a ReleasePayload changes, and again when the most recent acceptance
+// falls out of the upgradeableWindow.
+type OLMStatusController struct {
+	name      string
+	namespace string
+	client    dynamic.NamespaceableResourceInterface
+
+	releasePayloadLister releasepayloadlister.ReleasePayloadLister
+	cachesToSync         []cache.InformerSynced
+
+	// queue only ever holds the key of the OperatorCondition, so that a burst of ReleasePayload changes results in a
+	// single sync
+	queue workqueue.RateLimitingInterface
+
+	eventRecorder events.Recorder
+
+	clock clock.PassiveClock
+}
+
+func NewOLMStatusController(
+	releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer,
+	dynamicClient dynamic.Interface,
+	namespace string,
+	eventRecorder events.Recorder,
+) *OLMStatusController {
+	c := &OLMStatusController{
+		name:                 "OLMStatusController",
+		namespace:            namespace,
+		client:               dynamicClient.Resource(operatorConditionGVR),
+		releasePayloadLister: releasePayloadInformer.Lister(),
+		cachesToSync:         []cache.InformerSynced{releasePayloadInformer.Informer().HasSynced},
+		queue:                workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "OLMStatusController"),
+		eventRecorder:        eventRecorder.WithComponentSuffix("olm-status-controller"),
+		clock:                clock.RealClock{},
+	}
+
+	releasePayloadInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueue() },
+		UpdateFunc: func(old, new interface{}) { c.enqueue() },
+		DeleteFunc: func(obj interface{}) { c.enqueue() },
+	})
+
+	return c
+}
+
+// key returns the queue key of the OperatorCondition
+func (c *OLMStatusController) key() string {
+	return fmt.Sprintf("%s/%s", c.namespace, OperatorConditionName)
+}
+
+func (c *OLMStatusController) enqueue() {
+	c.queue.Add(c.key())
+}
+
+// Run reconciles the OperatorCondition until the context is cancelled
+func (c *OLMStatusController) Run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s", c.name)
+	defer klog.Infof("Shutting down %s", c.name)
+
+	if !cache.WaitForNamedCacheSync(c.name, ctx.Done(), c.cachesToSync...) {
+		return
+	}
+
+	// Reconcile the OperatorCondition on startup, even if there are no ReleasePayloads
+	c.enqueue()
+
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		for c.processNextItem(ctx) {
+		}
+	}, time.Second)
+
+	<-ctx.Done()
+}
+
+func (c *OLMStatusController) processNextItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.sync(ctx); err != nil {
+		utilruntime.HandleError(fmt.Errorf("%s: unable to reconcile OperatorCondition %v: %w", c.name, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+// acceptedAt returns the time that the ReleasePayload was Accepted, and false if it has not been Accepted
+func acceptedAt(releasePayload *v1alpha1.ReleasePayload) (time.Time, bool) {
+	condition := meta.FindStatusCondition(releasePayload.Status.Conditions, v1alpha1.ConditionPayloadAccepted)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		return time.Time{}, false
+	}
+	if condition.LastTransitionTime.IsZero() {
+		return releasePayload.CreationTimestamp.Time, true
+	}
+	return condition.LastTransitionTime.Time, true
+}
+
+// latestAccepted returns the most recently Accepted ReleasePayload, within the upgradeableWindow, and the time that it
+// was Accepted.  A nil ReleasePayload is returned if none has been Accepted within the upgradeableWindow.
+func latestAccepted(releasePayloads []*v1alpha1.ReleasePayload, now time.Time) (*v1alpha1.ReleasePayload, time.Time) {
+	var latest *v1alpha1.ReleasePayload
+	var latestAccepted time.Time
+	for _, releasePayload := range releasePayloads {
+		accepted, ok := acceptedAt(releasePayload)
+		if !ok || now.Sub(accepted) > upgradeableWindow {
+			continue
+		}
+		if latest == nil || accepted.After(latestAccepted) {
+			latest, latestAccepted = releasePayload, accepted
+		}
+	}
+	return latest, latestAccepted
+}
+
+// computeUpgradeableCondition returns the Upgradeable condition for the specified ReleasePayloads.  The most recently
+// Accepted ReleasePayload is reported, so that the condition does not change between syncs.
+func computeUpgradeableCondition(releasePayloads []*v1alpha1.ReleasePayload, now time.Time) metav1.Condition {
+	latest, latestAccepted := latestAccepted(releasePayloads, now)
+	if latest == nil {
+		return metav1.Condition{
+			Type:    OperatorConditionUpgradeable,
+			Status:  metav1.ConditionFalse,
+			Reason:  OperatorConditionUpgradeableReasonNotAccepted,
+			Message: fmt.Sprintf("No ReleasePayload has been accepted in the past %s", upgradeableWindow),
+		}
+	}
+	return metav1.Condition{
+		Type:    OperatorConditionUpgradeable,
+		Status:  metav1.ConditionTrue,
+		Reason:  OperatorConditionUpgradeableReasonAccepted,
+		Message: fmt.Sprintf("ReleasePayload %s/%s was accepted at %s", latest.Namespace, latest.Name, latestAccepted.UTC().Format(time.RFC3339)),
+	}
+}
+
+func (c *OLMStatusController) sync(ctx context.Context) error {
+	klog.V(4).Infof("Starting %s sync", c.name)
+	defer klog.V(4).Infof("%s sync done", c.name)
+
+	releasePayloads, err := c.releasePayloadLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	now := c.clock.Now()
+	upgradeable := computeUpgradeableCondition(releasePayloads, now)
+
+	// No ReleasePayload changes when its acceptance falls out of the upgradeableWindow, so requeue for that moment
+	if latest, accepted := latestAccepted(releasePayloads, now); latest != nil {
+		c.queue.AddAfter(c.key(), accepted.Add(upgradeableWindow).Sub(now))
+	}
+
+	current, err := c.client.Namespace(c.namespace).Get(ctx, OperatorConditionName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		upgradeable.LastTransitionTime = metav1.NewTime(now)
+		expected, err := newOperatorCondition(c.namespace, []metav1.Condition{upgradeable})
+		if err != nil {
+			return err
+		}
+		if _, err := c.client.Namespace(c.namespace).Create(ctx, expected, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		c.eventRecorder.Eventf("OperatorConditionCreated", "Created OperatorCondition %s/%s with %s=%s", c.namespace, OperatorConditionName, upgradeable.Type, upgradeable.Status)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	conditions, err := operatorConditionConditions(current)
+	if err != nil {
+		return err
+	}
+	original := make([]metav1.Condition, len(conditions))
+	copy(original, conditions)
+
+	// SetStatusCondition only bumps the LastTransitionTime when the status of the condition changes
+	upgradeable.LastTransitionTime = metav1.NewTime(now)
+	meta.SetStatusCondition(&conditions, upgradeable)
+	if reflect.DeepEqual(original, conditions) {
+		return nil
+	}
+
+	updated := current.DeepCopy()
+	if err := setOperatorConditionConditions(updated, conditions); err != nil {
+		return err
+	}
+	if _, err := c.client.Namespace(c.namespace).Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	c.eventRecorder.Eventf("OperatorConditionUpdated", "Updated OperatorCondition %s/%s with %s=%s", c.namespace, OperatorConditionName, upgradeable.Type, upgradeable.Status)
+	return nil
+}
+
+// newOperatorCondition returns the release-controller OperatorCondition, in the specified namespace, with the
+// specified conditions
+func newOperatorCondition(namespace string, conditions []metav1.Condition) (*unstructured.Unstructured, error) {
+	operatorCondition := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": operatorConditionGVR.GroupVersion().String(),
+			"kind":       "OperatorCondition",
+			"metadata": map[string]interface{}{
+				"name":      OperatorConditionName,
+				"namespace": namespace,
+			},
+		},
+	}
+	if err := setOperatorConditionConditions(operatorCondition, conditions); err != nil {
+		return nil, err
+	}
+	return operatorCondition, nil
+}
+
+// operatorConditionConditions returns the .spec.conditions of the OperatorCondition
+func operatorConditionConditions(operatorCondition *unstructured.Unstructured) ([]metav1.Condition, error) {
+	items, _, err := unstructured.NestedSlice(operatorCondition.Object, "spec", "conditions")
+	if err != nil {
+		return nil, err
+	}
+	var conditions []metav1.Condition
+	for _, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected condition in OperatorCondition %s/%s: %v", operatorCondition.GetNamespace(), operatorCondition.GetName(), item)
+		}
+		var condition metav1.Condition
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object, &condition); err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// setOperatorConditionConditions replaces the .spec.conditions of the OperatorCondition
+func setOperatorConditionConditions(operatorCondition *unstructured.Unstructured, conditions []metav1.Condition) error {
+	var items []interface{}
+	for i := range conditions {
+		object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&conditions[i])
+		if err != nil {
+			return err
+		}
+		items = append(items, object)
+	}
+	return unstructured.SetNestedSlice(operatorCondition.Object, items, "spec", "conditions")
+}
@@ -0,0 +1,235 @@
+package release_payload_controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestOLMStatusControllerSync(t *testing.T) {
+	now := time.Date(2022, 2, 12, 9, 15, 59, 0, time.UTC)
+	yesterday := now.Add(-48 * time.Hour)
+
+	newReleasePayload := func(name string, accepted time.Time) *v1alpha1.ReleasePayload {
+		return &v1alpha1.ReleasePayload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "ocp",
+				CreationTimestamp: metav1.NewTime(accepted.Add(-time.Hour)),
+			},
+			Status: v1alpha1.ReleasePayloadStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:               v1alpha1.ConditionPayloadAccepted,
+						Status:             metav1.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(accepted),
+					},
+				},
+			},
+		}
+	}
+
+	upgradeable := metav1.Condition{
+		Type:               OperatorConditionUpgradeable,
+		Status:             metav1.ConditionTrue,
+		Reason:             OperatorConditionUpgradeableReasonAccepted,
+		Message:            "ReleasePayload ocp/4.11.0-0.nightly-2022-02-12-061559 was accepted at 2022-02-12T07:15:59Z",
+		LastTransitionTime: metav1.NewTime(now),
+	}
+	notUpgradeable := metav1.Condition{
+		Type:               OperatorConditionUpgradeable,
+		Status:             metav1.ConditionFalse,
+		Reason:             OperatorConditionUpgradeableReasonNotAccepted,
+		Message:            "No ReleasePayload has been accepted in the past 24h0m0s",
+		LastTransitionTime: metav1.NewTime(now),
+	}
+	upgradeableSinceYesterday := upgradeable
+	upgradeableSinceYesterday.LastTransitionTime = metav1.NewTime(yesterday)
+	unrelated := metav1.Condition{
+		Type:               "Unrelated",
+		Status:             metav1.ConditionTrue,
+		Reason:             "Unrelated",
+		LastTransitionTime: metav1.NewTime(yesterday),
+	}
+
+	newOperatorConditionObject := func(conditions ...metav1.Condition) runtime.Object {
+		operatorCondition, err := newOperatorCondition("ci", conditions)
+		if err != nil {
+			t.Fatalf("unable to create OperatorCondition: %v", err)
+		}
+		return operatorCondition
+	}
+
+	testCases := []struct {
+		name               string
+		releasePayloads    []runtime.Object
+		operatorConditions []runtime.Object
+		expectedActions    []string
+		expected           []metav1.Condition
+	}{
+		{
+			name: "OperatorConditionMissing",
+			releasePayloads: []runtime.Object{
+				newReleasePayload("4.11.0-0.nightly-2022-02-12-061559", now.Add(-2*time.Hour)),
+			},
+			expectedActions: []string{"get", "create"},
+			expected:        []metav1.Condition{upgradeable},
+		},
+		{
+			name: "RecentlyAcceptedReleasePayload",
+			releasePayloads: []runtime.Object{
+				newReleasePayload("4.11.0-0.nightly-2022-02-10-061559", yesterday),
+				newReleasePayload("4.11.0-0.nightly-2022-02-12-061559", now.Add(-2*time.Hour)),
+				newReleasePayload("4.11.0-0.nightly-2022-02-11-201559", now.Add(-12*time.Hour)),
+			},
+			operatorConditions: []runtime.Object{newOperatorConditionObject(unrelated, notUpgradeable)},
+			expectedActions:    []string{"get", "update"},
+			expected:           []metav1.Condition{unrelated, upgradeable},
+		},
+		{
+			name: "NoRecentlyAcceptedReleasePayload",
+			releasePayloads: []runtime.Object{
+				newReleasePayload("4.11.0-0.nightly-2022-02-10-061559", yesterday),
+			},
+			operatorConditions: []runtime.Object{newOperatorConditionObject(upgradeableSinceYesterday)},
+			expectedActions:    []string{"get", "update"},
+			expected:           []metav1.Condition{notUpgradeable},
+		},
+		{
+			name:               "NoReleasePayloads",
+			operatorConditions: []runtime.Object{newOperatorConditionObject(upgradeable)},
+			expectedActions:    []string{"get", "update"},
+			expected:           []metav1.Condition{notUpgradeable},
+		},
+		{
+			name: "OperatorConditionUpToDate",
+			releasePayloads: []runtime.Object{
+				newReleasePayload("4.11.0-0.nightly-2022-02-12-061559", now.Add(-2*time.Hour)),
+			},
+			operatorConditions: []runtime.Object{newOperatorConditionObject(upgradeableSinceYesterday)},
+			expectedActions:    []string{"get"},
+			expected:           []metav1.Condition{upgradeableSinceYesterday},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			releasePayloadClient := fake.NewSimpleClientset(testCase.releasePayloads...)
+			releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+			releasePayloadInformer := releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads()
+
+			dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), testCase.operatorConditions...)
+
+			c := NewOLMStatusController(releasePayloadInformer, dynamicClient, "ci", events.NewInMemoryRecorder("olm-status-controller-test"))
+			c.clock = clocktesting.NewFakePassiveClock(now)
+			defer c.queue.ShutDown()
+
+			releasePayloadInformerFactory.Start(context.Background().Done())
+
+			if !cache.WaitForNamedCacheSync("OLMStatusController", context.Background().Done(), c.cachesToSync...) {
+				t.Fatalf("%s: error waiting for caches to sync", testCase.name)
+			}
+
+			if err := c.sync(context.TODO()); err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+
+			var actions []string
+			for _, action := range dynamicClient.Actions() {
+				actions = append(actions, action.GetVerb())
+			}
+			if !cmp.Equal(actions, testCase.expectedActions) {
+				t.Errorf("%s: Expected actions %v, got %v", testCase.name, testCase.expectedActions, actions)
+			}
+
+			output, err := dynamicClient.Resource(operatorConditionGVR).Namespace("ci").Get(context.TODO(), OperatorConditionName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			conditions, err := operatorConditionConditions(output)
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			if !cmp.Equal(conditions, testCase.expected) {
+				t.Errorf("%s: %s", testCase.name, cmp.Diff(testCase.expected, conditions))
+			}
+		})
+	}
+}
+
+// TestOLMStatusControllerRun verifies that the OperatorCondition is reconciled as soon as a ReleasePayload is Accepted,
+// rather than on the next periodic resync
+func TestOLMStatusControllerRun(t *testing.T) {
+	releasePayloadClient := fake.NewSimpleClientset()
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+	releasePayloadInformer := releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads()
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+	c := NewOLMStatusController(releasePayloadInformer, dynamicClient, "ci", events.NewInMemoryRecorder("olm-status-controller-test"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	releasePayloadInformerFactory.Start(ctx.Done())
+	go c.Run(ctx)
+
+	upgradeableStatus := func() (metav1.ConditionStatus, error) {
+		output, err := dynamicClient.Resource(operatorConditionGVR).Namespace("ci").Get(ctx, OperatorConditionName, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		conditions, err := operatorConditionConditions(output)
+		if err != nil {
+			return "", err
+		}
+		if condition := meta.FindStatusCondition(conditions, OperatorConditionUpgradeable); condition != nil {
+			return condition.Status, nil
+		}
+		return "", nil
+	}
+
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		status, err := upgradeableStatus()
+		return err == nil && status == metav1.ConditionFalse, nil
+	}); err != nil {
+		t.Fatalf("expected the OperatorCondition to be created as not Upgradeable on startup")
+	}
+
+	releasePayload := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-12-061559",
+			Namespace: "ocp",
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:               v1alpha1.ConditionPayloadAccepted,
+					Status:             metav1.ConditionTrue,
+					LastTransitionTime: metav1.Now(),
+				},
+			},
+		},
+	}
+	if _, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads("ocp").Create(ctx, releasePayload, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unable to create ReleasePayload: %v", err)
+	}
+
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		status, err := upgradeableStatus()
+		return err == nil && status == metav1.ConditionTrue, nil
+	}); err != nil {
+		t.Errorf("expected the OperatorCondition to become Upgradeable once a ReleasePayload was Accepted")
+	}
+}
@@ -35,6 +35,7 @@ const (
 //  2. the payload is manually accepted
 //
 // The PayloadAcceptedController reads the following pieces of information:
+//   - .metadata.annotations["release.openshift.io/block-promotion"]
 //   - .spec.payloadOverride.override
 //   - .status.blockingJobResults
 //   - .status.releaseCreationJobResult.status
@@ -72,8 +73,9 @@ func NewPayloadAcceptedController(
 }
 
 func (c *PayloadAcceptedController) sync(ctx context.Context, key string) error {
-	klog.V(4).Infof("Starting PayloadAcceptedController sync")
-	defer klog.V(4).Infof("PayloadAcceptedController sync done")
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting PayloadAcceptedController sync")
+	defer logger.V(4).Info("PayloadAcceptedController sync done")
 
 	// Convert the namespace/name string into a distinct namespace and name
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
@@ -92,6 +94,11 @@ func (c *PayloadAcceptedController) sync(ctx context.Context, key string) error
 		return err
 	}
 
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(originalReleasePayload) {
+		return nil
+	}
+
 	acceptedCondition := computeReleasePayloadAcceptedCondition(originalReleasePayload)
 
 	releasePayload := originalReleasePayload.DeepCopy()
@@ -121,6 +128,14 @@ func computeReleasePayloadAcceptedCondition(payload *v1alpha1.ReleasePayload) me
 		Reason: ReleasePayloadAcceptedReason,
 	}
 
+	// If the payload is blocked from promotion, then it will never be Accepted
+	if reason, ok := promotionBlocked(payload); ok {
+		acceptedCondition.Status = metav1.ConditionFalse
+		acceptedCondition.Reason = ReleasePayloadPromotionBlockedReason
+		acceptedCondition.Message = reason
+		return acceptedCondition
+	}
+
 	// If the release creation job failed, then the payload will never be Accepted
 	if payload.Status.ReleaseCreationJobResult.Status == v1alpha1.ReleaseCreationJobFailed {
 		acceptedCondition.Status = metav1.ConditionFalse
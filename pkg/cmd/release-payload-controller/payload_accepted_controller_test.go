@@ -436,6 +436,49 @@ func TestPayloadAcceptedSync(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "TestPayloadPromotionBlockedOverridesAccepted",
+			input: &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+					Annotations: map[string]string{
+						ReleasePayloadBlockPromotionAnnotation: "Known regression in etcd",
+					},
+				},
+				Spec: v1alpha1.ReleasePayloadSpec{
+					PayloadOverride: v1alpha1.ReleasePayloadOverride{
+						Override: v1alpha1.ReleasePayloadOverrideAccepted,
+						Reason:   "Manually accepted per TRT",
+					},
+				},
+			},
+			expected: &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+					Annotations: map[string]string{
+						ReleasePayloadBlockPromotionAnnotation: "Known regression in etcd",
+					},
+				},
+				Spec: v1alpha1.ReleasePayloadSpec{
+					PayloadOverride: v1alpha1.ReleasePayloadOverride{
+						Override: v1alpha1.ReleasePayloadOverrideAccepted,
+						Reason:   "Manually accepted per TRT",
+					},
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:    v1alpha1.ConditionPayloadAccepted,
+							Status:  metav1.ConditionFalse,
+							Reason:  ReleasePayloadPromotionBlockedReason,
+							Message: "Known regression in etcd",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -82,8 +82,9 @@ func NewPayloadCreationController(
 }
 
 func (c *PayloadCreationController) sync(ctx context.Context, key string) error {
-	klog.V(4).Infof("Starting PayloadCreationController sync")
-	defer klog.V(4).Infof("PayloadCreationController sync done")
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting PayloadCreationController sync")
+	defer logger.V(4).Info("PayloadCreationController sync done")
 
 	// Convert the namespace/name string into a distinct namespace and name
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
@@ -104,6 +105,11 @@ func (c *PayloadCreationController) sync(ctx context.Context, key string) error
 		return err
 	}
 
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(originalReleasePayload) {
+		return nil
+	}
+
 	// If the conditions are both in their respective terminal states, then there is nothing else to do...
 	if (v1helpers.IsConditionTrue(originalReleasePayload.Status.Conditions, v1alpha1.ConditionPayloadCreated) ||
 		v1helpers.IsConditionFalse(originalReleasePayload.Status.Conditions, v1alpha1.ConditionPayloadCreated)) &&
@@ -16,6 +16,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"reflect"
+	"time"
 
 	"github.com/openshift/library-go/pkg/operator/events"
 )
@@ -34,8 +35,10 @@ const (
 //  1. any Blocking Job fails
 //  2. the payload is manually rejected
 //  2. the release creation job fails
+//  3. the payload is blocked from promotion by the release.openshift.io/block-promotion annotation
 //
 // The PayloadRejectedController reads the following pieces of information:
+//   - .metadata.annotations["release.openshift.io/block-promotion"]
 //   - .spec.payloadOverride.override
 //   - .status.blockingJobResults
 //   - .status.releaseCreationJobResult.status
@@ -73,8 +76,9 @@ func NewPayloadRejectedController(
 }
 
 func (c *PayloadRejectedController) sync(ctx context.Context, key string) error {
-	klog.V(4).Infof("Starting PayloadRejectedController sync")
-	defer klog.V(4).Infof("PayloadRejectedController sync done")
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting PayloadRejectedController sync")
+	defer logger.V(4).Info("PayloadRejectedController sync done")
 
 	// Convert the namespace/name string into a distinct namespace and name
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
@@ -93,6 +97,11 @@ func (c *PayloadRejectedController) sync(ctx context.Context, key string) error
 		return err
 	}
 
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(originalReleasePayload) {
+		return nil
+	}
+
 	rejectedCondition := computeReleasePayloadRejectedCondition(originalReleasePayload)
 
 	releasePayload := originalReleasePayload.DeepCopy()
@@ -112,6 +121,19 @@ func (c *PayloadRejectedController) sync(ctx context.Context, key string) error
 		return err
 	}
 
+	original := v1helpers.FindCondition(originalReleasePayload.Status.Conditions, v1alpha1.ConditionPayloadRejected)
+	if rejectedCondition.Reason == ReleasePayloadPromotionBlockedReason && (original == nil || original.Reason != ReleasePayloadPromotionBlockedReason) {
+		manager, at := blockPromotionSetBy(releasePayload)
+		if len(manager) == 0 {
+			manager = "unknown"
+		}
+		setAt := "an unknown time"
+		if at != nil {
+			setAt = at.UTC().Format(time.RFC3339)
+		}
+		c.eventRecorder.Warningf(ReleasePayloadPromotionBlockedReason, "ReleasePayload %s/%s was blocked from promotion, by %s at %s: %s", releasePayload.Namespace, releasePayload.Name, manager, setAt, rejectedCondition.Message)
+	}
+
 	return nil
 }
 
@@ -122,6 +144,14 @@ func computeReleasePayloadRejectedCondition(payload *v1alpha1.ReleasePayload) me
 		Reason: ReleasePayloadRejectedReason,
 	}
 
+	// If the payload is blocked from promotion, then it is Rejected regardless of anything else
+	if reason, ok := promotionBlocked(payload); ok {
+		rejectedCondition.Status = metav1.ConditionTrue
+		rejectedCondition.Reason = ReleasePayloadPromotionBlockedReason
+		rejectedCondition.Message = reason
+		return rejectedCondition
+	}
+
 	// If the release creation job failed, then the payload should be Rejected
 	if payload.Status.ReleaseCreationJobResult.Status == v1alpha1.ReleaseCreationJobFailed {
 		rejectedCondition.Status = metav1.ConditionTrue
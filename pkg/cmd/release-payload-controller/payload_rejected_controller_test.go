@@ -13,6 +13,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"testing"
+	"time"
 )
 
 func TestPayloadRejectedSync(t *testing.T) {
@@ -436,6 +437,49 @@ func TestPayloadRejectedSync(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "TestPayloadPromotionBlockedOverridesAccepted",
+			input: &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+					Annotations: map[string]string{
+						ReleasePayloadBlockPromotionAnnotation: "Known regression in etcd",
+					},
+				},
+				Spec: v1alpha1.ReleasePayloadSpec{
+					PayloadOverride: v1alpha1.ReleasePayloadOverride{
+						Override: v1alpha1.ReleasePayloadOverrideAccepted,
+						Reason:   "Manually accepted per TRT",
+					},
+				},
+			},
+			expected: &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+					Annotations: map[string]string{
+						ReleasePayloadBlockPromotionAnnotation: "Known regression in etcd",
+					},
+				},
+				Spec: v1alpha1.ReleasePayloadSpec{
+					PayloadOverride: v1alpha1.ReleasePayloadOverride{
+						Override: v1alpha1.ReleasePayloadOverrideAccepted,
+						Reason:   "Manually accepted per TRT",
+					},
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:    v1alpha1.ConditionPayloadRejected,
+							Status:  metav1.ConditionTrue,
+							Reason:  ReleasePayloadPromotionBlockedReason,
+							Message: "Known regression in etcd",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -481,3 +525,132 @@ func TestPayloadRejectedSync(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockPromotionSetBy(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC))
+	later := metav1.NewTime(earlier.Add(time.Hour))
+	blockPromotionFields := &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{"f:release.openshift.io/block-promotion":{}}}}`)}
+
+	testCases := []struct {
+		name            string
+		managedFields   []metav1.ManagedFieldsEntry
+		expectedManager string
+		expectedTime    *metav1.Time
+	}{
+		{
+			name: "NoManagedFields",
+		},
+		{
+			name: "AnnotationNotManaged",
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:  "release-controller",
+					Time:     &earlier,
+					FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{"f:release.openshift.io/approved-by":{}}}}`)},
+				},
+			},
+		},
+		{
+			name: "AnnotationManaged",
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:  "release-controller",
+					Time:     &later,
+					FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:conditions":{}}}`)},
+				},
+				{
+					Manager:  "kubectl-annotate",
+					Time:     &earlier,
+					FieldsV1: blockPromotionFields,
+				},
+			},
+			expectedManager: "kubectl-annotate",
+			expectedTime:    &earlier,
+		},
+		{
+			name: "MostRecentManager",
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:  "kubectl-annotate",
+					Time:     &earlier,
+					FieldsV1: blockPromotionFields,
+				},
+				{
+					Manager:  "kubectl-edit",
+					Time:     &later,
+					FieldsV1: blockPromotionFields,
+				},
+			},
+			expectedManager: "kubectl-edit",
+			expectedTime:    &later,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			payload := &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					ManagedFields: testCase.managedFields,
+				},
+			}
+			manager, at := blockPromotionSetBy(payload)
+			if manager != testCase.expectedManager {
+				t.Errorf("%s: Expected manager %q, got %q", testCase.name, testCase.expectedManager, manager)
+			}
+			if !cmp.Equal(at, testCase.expectedTime) {
+				t.Errorf("%s: Expected time %v, got %v", testCase.name, testCase.expectedTime, at)
+			}
+		})
+	}
+}
+
+func TestPayloadRejectedSyncPromotionBlockedEvent(t *testing.T) {
+	setAt := metav1.NewTime(time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC))
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+			Annotations: map[string]string{
+				ReleasePayloadBlockPromotionAnnotation: "Known regression in etcd",
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:  "kubectl-annotate",
+					Time:     &setAt,
+					FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{"f:release.openshift.io/block-promotion":{}}}}`)},
+				},
+			},
+		},
+	}
+
+	releasePayloadClient := fake.NewSimpleClientset(input)
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+	recorder := events.NewInMemoryRecorder("payload-rejected-controller-test")
+
+	c, err := NewPayloadRejectedController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), recorder)
+	if err != nil {
+		t.Fatalf("unable to create controller: %v", err)
+	}
+	defer c.queue.ShutDown()
+
+	releasePayloadInformerFactory.Start(context.Background().Done())
+
+	if !cache.WaitForNamedCacheSync("PayloadRejectedController", context.Background().Done(), c.cachesToSync...) {
+		t.Fatalf("error waiting for caches to sync")
+	}
+
+	key := fmt.Sprintf("%s/%s", input.Namespace, input.Name)
+	if err := c.sync(context.TODO(), key); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	var blockedEvents []string
+	for _, event := range recorder.Events() {
+		if event.Reason == ReleasePayloadPromotionBlockedReason {
+			blockedEvents = append(blockedEvents, event.Message)
+		}
+	}
+	expected := []string{"ReleasePayload ocp/4.11.0-0.nightly-2022-02-09-091559 was blocked from promotion, by kubectl-annotate at 2022-02-09T09:15:59Z: Known regression in etcd"}
+	if !cmp.Equal(blockedEvents, expected) {
+		t.Errorf("Expected events %v, got %v", expected, blockedEvents)
+	}
+}
@@ -57,8 +57,9 @@ func NewPayloadVerificationController(
 }
 
 func (c *PayloadVerificationController) sync(ctx context.Context, key string) error {
-	klog.V(4).Infof("Starting PayloadVerificationController sync")
-	defer klog.V(4).Infof("PayloadVerificationController sync done")
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting PayloadVerificationController sync")
+	defer logger.V(4).Info("PayloadVerificationController sync done")
 
 	// Convert the namespace/name string into a distinct namespace and name
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
@@ -77,6 +78,11 @@ func (c *PayloadVerificationController) sync(ctx context.Context, key string) er
 		return err
 	}
 
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(originalReleasePayload) {
+		return nil
+	}
+
 	// If there are any JobResults defined, then we don't need to do anything else here...
 	if len(originalReleasePayload.Status.BlockingJobResults) != 0 || len(originalReleasePayload.Status.InformingJobResults) != 0 || len(originalReleasePayload.Status.UpgradeJobResults) != 0 {
 		klog.V(5).Infof("ReleasePayload: '%s/%s' already synced", originalReleasePayload.Namespace, originalReleasePayload.Name)
@@ -0,0 +1,135 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// PrometheusRuleName is the name of the PrometheusRule that houses the ReleasePayload alerts
+	PrometheusRuleName = "release-controller-alerts"
+
+	// ReleasePayloadFailedAlert fires when a ReleasePayload has been in the PayloadFailed state for more than an hour
+	ReleasePayloadFailedAlert = "ReleasePayloadFailed"
+
+	// releasePayloadConditionMetric is exported, by kube-state-metrics, for every condition of every ReleasePayload.
+	// See: artifacts/kube-state-metrics-releasepayloads.yaml
+	releasePayloadConditionMetric = "kube_customresource_releasepayload_condition"
+)
+
+var prometheusRuleGVR = schema.GroupVersionResource{
+	Group:    "monitoring.coreos.com",
+	Version:  "v1",
+	Resource: "prometheusrules",
+}
+
+// PrometheusRuleController is responsible for ensuring that the PrometheusRule, containing the alerts for
+// ReleasePayloads, exists in the namespace that the release-payload-controller is running in.  The alerts are
+// evaluated against the ReleasePayload metrics that kube-state-metrics exports using its custom resource state
+// configuration.
+type PrometheusRuleController struct {
+	name          string
+	namespace     string
+	client        dynamic.NamespaceableResourceInterface
+	eventRecorder events.Recorder
+}
+
+func NewPrometheusRuleController(dynamicClient dynamic.Interface, namespace string, eventRecorder events.Recorder) *PrometheusRuleController {
+	return &PrometheusRuleController{
+		name:          "PrometheusRuleController",
+		namespace:     namespace,
+		client:        dynamicClient.Resource(prometheusRuleGVR),
+		eventRecorder: eventRecorder,
+	}
+}
+
+// Run reconciles the PrometheusRule until the context is cancelled
+func (c *PrometheusRuleController) Run(ctx context.Context) {
+	klog.Infof("Starting %s", c.name)
+	defer klog.Infof("Shutting down %s", c.name)
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.sync(ctx); err != nil {
+			klog.Errorf("%s: unable to reconcile PrometheusRule %s/%s: %v", c.name, c.namespace, PrometheusRuleName, err)
+		}
+	}, controllerDefaultResyncDuration)
+}
+
+func (c *PrometheusRuleController) sync(ctx context.Context) error {
+	klog.V(4).Infof("Starting %s sync", c.name)
+	defer klog.V(4).Infof("%s sync done", c.name)
+
+	expected := newPrometheusRule(c.namespace)
+
+	current, err := c.client.Namespace(c.namespace).Get(ctx, PrometheusRuleName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err := c.client.Namespace(c.namespace).Create(ctx, expected, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		c.eventRecorder.Eventf("PrometheusRuleCreated", "Created PrometheusRule %s/%s", c.namespace, PrometheusRuleName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(current.Object["spec"], expected.Object["spec"]) {
+		return nil
+	}
+
+	updated := current.DeepCopy()
+	updated.Object["spec"] = expected.Object["spec"]
+	if _, err := c.client.Namespace(c.namespace).Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	c.eventRecorder.Eventf("PrometheusRuleUpdated", "Updated PrometheusRule %s/%s", c.namespace, PrometheusRuleName)
+	return nil
+}
+
+// newPrometheusRule returns the PrometheusRule, in the specified namespace, that alerts on ReleasePayloads that
+// have been failing for more than an hour.  Any change made here must also be made to:
+// artifacts/release-controller-alerts.prometheusrule.yaml
+func newPrometheusRule(namespace string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": prometheusRuleGVR.GroupVersion().String(),
+			"kind":       "PrometheusRule",
+			"metadata": map[string]interface{}{
+				"name":      PrometheusRuleName,
+				"namespace": namespace,
+			},
+			"spec": map[string]interface{}{
+				"groups": []interface{}{
+					map[string]interface{}{
+						"name": "release-payloads",
+						"rules": []interface{}{
+							map[string]interface{}{
+								"alert": ReleasePayloadFailedAlert,
+								"expr":  fmt.Sprintf(`max by (namespace, name) (%s{condition=%q}) == 1`, releasePayloadConditionMetric, v1alpha1.ConditionPayloadFailed),
+								"for":   "1h",
+								"labels": map[string]interface{}{
+									"severity": "warning",
+								},
+								"annotations": map[string]interface{}{
+									"summary":     "ReleasePayload {{ $labels.namespace }}/{{ $labels.name }} has failed",
+									"description": "ReleasePayload {{ $labels.namespace }}/{{ $labels.name }} has been in the PayloadFailed state for more than 1 hour.",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
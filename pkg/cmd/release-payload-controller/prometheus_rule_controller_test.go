@@ -0,0 +1,87 @@
+package release_payload_controller
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/library-go/pkg/operator/events"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestPrometheusRuleControllerSync(t *testing.T) {
+	outdated := newPrometheusRule("ci")
+	if err := unstructured.SetNestedField(outdated.Object, []interface{}{}, "spec", "groups"); err != nil {
+		t.Fatalf("unable to create outdated PrometheusRule: %v", err)
+	}
+
+	testCases := []struct {
+		name            string
+		input           []runtime.Object
+		expectedActions []string
+	}{
+		{
+			name:            "PrometheusRuleMissing",
+			expectedActions: []string{"get", "create"},
+		},
+		{
+			name:            "PrometheusRuleOutdated",
+			input:           []runtime.Object{outdated},
+			expectedActions: []string{"get", "update"},
+		},
+		{
+			name:            "PrometheusRuleUpToDate",
+			input:           []runtime.Object{newPrometheusRule("ci")},
+			expectedActions: []string{"get"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme(), testCase.input...)
+
+			c := NewPrometheusRuleController(dynamicClient, "ci", events.NewInMemoryRecorder("prometheus-rule-controller-test"))
+
+			if err := c.sync(context.TODO()); err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+
+			var actions []string
+			for _, action := range dynamicClient.Actions() {
+				actions = append(actions, action.GetVerb())
+			}
+			if !cmp.Equal(actions, testCase.expectedActions) {
+				t.Errorf("%s: Expected actions %v, got %v", testCase.name, testCase.expectedActions, actions)
+			}
+
+			output, err := dynamicClient.Resource(prometheusRuleGVR).Namespace("ci").Get(context.TODO(), PrometheusRuleName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			if expected := newPrometheusRule("ci"); !cmp.Equal(output.Object["spec"], expected.Object["spec"]) {
+				t.Errorf("%s: Expected %v, got %v", testCase.name, expected.Object["spec"], output.Object["spec"])
+			}
+		})
+	}
+}
+
+func TestPrometheusRuleArtifact(t *testing.T) {
+	data, err := os.ReadFile("../../../artifacts/release-controller-alerts.prometheusrule.yaml")
+	if err != nil {
+		t.Fatalf("unable to read PrometheusRule artifact: %v", err)
+	}
+	artifact := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(data, &artifact.Object); err != nil {
+		t.Fatalf("unable to decode PrometheusRule artifact: %v", err)
+	}
+
+	expected := newPrometheusRule("")
+	unstructured.RemoveNestedField(expected.Object, "metadata", "namespace")
+	if !cmp.Equal(artifact.Object, expected.Object) {
+		t.Errorf("PrometheusRule artifact is out of date: %s", cmp.Diff(expected.Object, artifact.Object))
+	}
+}
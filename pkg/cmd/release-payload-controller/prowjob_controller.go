@@ -124,8 +124,9 @@ type jobRunResultUpdate struct {
 }
 
 func (c *ProwJobStatusController) sync(ctx context.Context, key string) error {
-	klog.V(4).Infof("Starting ProwJobStatusController sync")
-	defer klog.V(4).Infof("ProwJobStatusController sync done")
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting ProwJobStatusController sync")
+	defer logger.V(4).Info("ProwJobStatusController sync done")
 
 	// Convert the namespace/name string into a distinct namespace and name
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
@@ -146,6 +147,11 @@ func (c *ProwJobStatusController) sync(ctx context.Context, key string) error {
 		return err
 	}
 
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(originalReleasePayload) {
+		return nil
+	}
+
 	// If PayloadVerificationDataSource is not the Build Farm, then we don't have anything to do...
 	if originalReleasePayload.Spec.PayloadVerificationConfig.PayloadVerificationDataSource != v1alpha1.PayloadVerificationDataSourceBuildFarm {
 		return nil
@@ -0,0 +1,53 @@
+package release_payload_controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	prowjobclientset "k8s.io/test-infra/prow/client/clientset/versioned"
+	prowjobinformers "k8s.io/test-infra/prow/client/informers/externalversions"
+	prowjobinformer "k8s.io/test-infra/prow/client/informers/externalversions/prowjobs/v1"
+	prowjoblister "k8s.io/test-infra/prow/client/listers/prowjobs/v1"
+)
+
+// newProwJobInformerFactories returns a SharedInformerFactory for each of the specified namespaces.  If no namespaces
+// are specified, a single SharedInformerFactory, watching all namespaces, is returned.
+func newProwJobInformerFactories(prowJobClient prowjobclientset.Interface, namespaces []string, tweakListOptions func(*metav1.ListOptions)) map[string]prowjobinformers.SharedInformerFactory {
+	if len(namespaces) == 0 {
+		return map[string]prowjobinformers.SharedInformerFactory{
+			metav1.NamespaceAll: prowjobinformers.NewSharedInformerFactoryWithOptions(prowJobClient, controllerDefaultResyncDuration, prowjobinformers.WithTweakListOptions(tweakListOptions)),
+		}
+	}
+	factories := make(map[string]prowjobinformers.SharedInformerFactory)
+	for _, namespace := range namespaces {
+		factories[namespace] = prowjobinformers.NewSharedInformerFactoryWithOptions(prowJobClient, controllerDefaultResyncDuration, prowjobinformers.WithNamespace(namespace), prowjobinformers.WithTweakListOptions(tweakListOptions))
+	}
+	return factories
+}
+
+// newProwJobInformer returns a ProwJobInformer that spans the ProwJob informers of all the factories.  The informer of
+// the factory is returned, as is, if there is only one.
+func newProwJobInformer(factories map[string]prowjobinformers.SharedInformerFactory) prowjobinformer.ProwJobInformer {
+	informers := make(map[string]cache.SharedIndexInformer)
+	for namespace, factory := range factories {
+		if len(factories) == 1 {
+			return factory.Prow().V1().ProwJobs()
+		}
+		informers[namespace] = factory.Prow().V1().ProwJobs().Informer()
+	}
+	return &multiNamespaceProwJobInformer{
+		informer: &multiNamespaceInformer{informers: informers},
+	}
+}
+
+// multiNamespaceProwJobInformer implements prowjobinformer.ProwJobInformer on top of several namespace scoped informers
+type multiNamespaceProwJobInformer struct {
+	informer *multiNamespaceInformer
+}
+
+func (i *multiNamespaceProwJobInformer) Informer() cache.SharedIndexInformer {
+	return i.informer
+}
+
+func (i *multiNamespaceProwJobInformer) Lister() prowjoblister.ProwJobLister {
+	return prowjoblister.NewProwJobLister(i.informer.GetIndexer())
+}
@@ -0,0 +1,70 @@
+package release_payload_controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	prowjobsv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+	prowfake "k8s.io/test-infra/prow/client/clientset/versioned/fake"
+)
+
+func newNamespacedProwJob(namespace, name string) *prowjobsv1.ProwJob {
+	return &prowjobsv1.ProwJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+}
+
+func TestMultiNamespaceProwJobInformer(t *testing.T) {
+	prowJobClient := prowfake.NewSimpleClientset(
+		newNamespacedProwJob("ci", "aggregated-aws-ovn-upgrade"),
+		newNamespacedProwJob("ci-release", "aws-serial"),
+		newNamespacedProwJob("default", "aws-serial"),
+	)
+
+	factories := newProwJobInformerFactories(prowJobClient, []string{"ci", "ci-release"}, nil)
+	if len(factories) != 2 {
+		t.Fatalf("expected 2 informer factories, got %d", len(factories))
+	}
+	informer := newProwJobInformer(factories)
+	// Request the informers before the factories are started
+	informer.Informer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, factory := range factories {
+		factory.Start(ctx.Done())
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), informer.Informer().HasSynced) {
+		t.Fatalf("informers did not sync")
+	}
+
+	prowJobs, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		t.Fatalf("unable to list prowjobs: %v", err)
+	}
+	if len(prowJobs) != 2 {
+		t.Errorf("expected 2 prowjobs, got %d", len(prowJobs))
+	}
+	if _, err := informer.Lister().ProwJobs("ci-release").Get("aws-serial"); err != nil {
+		t.Errorf("unable to get prowjob from a watched namespace: %v", err)
+	}
+	if _, err := informer.Lister().ProwJobs("default").Get("aws-serial"); err == nil {
+		t.Errorf("expected no prowjobs in a namespace that is not watched")
+	}
+}
+
+func TestProwJobInformerAllNamespaces(t *testing.T) {
+	factories := newProwJobInformerFactories(prowfake.NewSimpleClientset(), nil, nil)
+	if _, ok := factories[metav1.NamespaceAll]; !ok || len(factories) != 1 {
+		t.Fatalf("expected a single informer factory for all namespaces, got %d", len(factories))
+	}
+	if _, ok := newProwJobInformer(factories).(*multiNamespaceProwJobInformer); ok {
+		t.Errorf("expected the informer of the factory to be used as is")
+	}
+}
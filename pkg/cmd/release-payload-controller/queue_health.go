@@ -0,0 +1,32 @@
+package release_payload_controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"k8s.io/klog/v2"
+)
+
+// queueHealthPath is the path, on the health probe server, that reports whether the work queue of the
+// ReleaseCreationStatusController is keeping up
+const queueHealthPath = "/healthz/queue"
+
+// queueDepthHandler responds with a 503 once the depth, of the work queue of the controller, exceeds the threshold.
+// A queue that keeps growing means that the workers cannot keep up with the events, i.e. because the API server is
+// slow, and that the statuses of the ReleasePayloads are going stale.
+func queueDepthHandler(c *ReleasePayloadController, threshold int) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		depth := c.queue.Len()
+		if depth > threshold {
+			http.Error(w, fmt.Sprintf("the work queue of the %s has %d items, more than the threshold of %d", c.name, depth, threshold), http.StatusServiceUnavailable)
+			return
+		}
+		if _, err := fmt.Fprintf(w, "ok: the work queue of the %s has %d items\n", c.name, depth); err != nil {
+			klog.Errorf("Unable to write queue health response: %v", err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package release_payload_controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestQueueDepthHandler(t *testing.T) {
+	testCases := []struct {
+		name     string
+		depth    int
+		method   string
+		expected int
+	}{
+		{
+			name:     "EmptyQueue",
+			method:   http.MethodGet,
+			expected: http.StatusOK,
+		},
+		{
+			name:     "AtThreshold",
+			depth:    defaultQueueDepthAlertThreshold,
+			method:   http.MethodGet,
+			expected: http.StatusOK,
+		},
+		{
+			name:     "AboveThreshold",
+			depth:    defaultQueueDepthAlertThreshold + 1,
+			method:   http.MethodGet,
+			expected: http.StatusServiceUnavailable,
+		},
+		{
+			name:     "LargeQueue",
+			depth:    10 * defaultQueueDepthAlertThreshold,
+			method:   http.MethodGet,
+			expected: http.StatusServiceUnavailable,
+		},
+		{
+			name:     "MethodNotAllowed",
+			method:   http.MethodPost,
+			expected: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := &ReleasePayloadController{
+				name:  "Release Creation Status Controller",
+				queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ReleaseCreationStatusController"),
+			}
+			defer c.queue.ShutDown()
+			for i := 0; i < testCase.depth; i++ {
+				c.queue.Add(fmt.Sprintf("ocp/4.11.0-0.nightly-2022-02-09-%06d", i))
+			}
+
+			server := httptest.NewServer(queueDepthHandler(c, defaultQueueDepthAlertThreshold))
+			defer server.Close()
+
+			req, err := http.NewRequest(testCase.method, server.URL+queueHealthPath, nil)
+			if err != nil {
+				t.Fatalf("unable to create request: %v", err)
+			}
+			resp, err := server.Client().Do(req)
+			if err != nil {
+				t.Fatalf("unable to get %s: %v", queueHealthPath, err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != testCase.expected {
+				t.Errorf("%s: Expected status %d, got %d", testCase.name, testCase.expected, resp.StatusCode)
+			}
+		})
+	}
+}
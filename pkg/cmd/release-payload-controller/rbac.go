@@ -0,0 +1,307 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/client-go/tools/cache"
+	prowjobsv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// rbacName is the name of the Role, and RoleBinding, that grant the release-payload-controller access to the
+// resources of a namespace
+const rbacName = "release-payload-controller"
+
+// releasePayloadPolicyRules are the permissions that the release-payload-controller requires in every namespace that
+// it watches for ReleasePayloads.  The release imagestreams, and the signature, SBOM and summary ConfigMaps, live
+// alongside the ReleasePayloads.
+var releasePayloadPolicyRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{v1alpha1.SchemeGroupVersion.Group},
+		Resources: []string{"releasepayloads"},
+		Verbs:     []string{"get", "list", "watch", "update", "patch", "delete"},
+	},
+	{
+		APIGroups: []string{v1alpha1.SchemeGroupVersion.Group},
+		Resources: []string{"releasepayloads/status"},
+		Verbs:     []string{"get", "update"},
+	},
+	{
+		APIGroups: []string{imagev1.GroupName},
+		Resources: []string{"imagestreams"},
+		Verbs:     []string{"list", "watch", "patch"},
+	},
+	{
+		APIGroups: []string{corev1.GroupName},
+		Resources: []string{"configmaps"},
+		Verbs:     []string{"get", "create", "update"},
+	},
+	{
+		APIGroups: []string{corev1.GroupName},
+		Resources: []string{"secrets"},
+		Verbs:     []string{"get"},
+	},
+}
+
+// jobPolicyRules are the permissions that the release-payload-controller requires in every namespace that it watches
+// for release creation jobs
+var jobPolicyRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{batchv1.GroupName},
+		Resources: []string{"jobs"},
+		Verbs:     []string{"list", "watch", "patch", "delete"},
+	},
+	{
+		APIGroups: []string{corev1.GroupName},
+		Resources: []string{"pods"},
+		Verbs:     []string{"list"},
+	},
+	{
+		APIGroups: []string{corev1.GroupName},
+		Resources: []string{"pods/log"},
+		Verbs:     []string{"get"},
+	},
+}
+
+// prowJobPolicyRules are the permissions that the release-payload-controller requires in every namespace that it
+// watches for ProwJobs
+var prowJobPolicyRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{prowjobsv1.SchemeGroupVersion.Group},
+		Resources: []string{"prowjobs"},
+		Verbs:     []string{"list", "watch"},
+	},
+}
+
+// approvedUsersPolicyRules are the permissions that the release-payload-controller requires in the namespace of the
+// approved users ConfigMap
+var approvedUsersPolicyRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{corev1.GroupName},
+		Resources: []string{"configmaps"},
+		Verbs:     []string{"list", "watch"},
+	},
+}
+
+// namespacedRBACScope holds the namespaces that the release-payload-controller watches for each kind of resource
+type namespacedRBACScope struct {
+	WatchNamespaces        []string
+	JobNamespaces          []string
+	ProwJobNamespaces      []string
+	ApprovedUsersNamespace string
+}
+
+// policyRules returns the permissions that the release-payload-controller requires, by namespace.  A namespace that is
+// watched for several kinds of resources is granted the permissions of all of them.
+func (s namespacedRBACScope) policyRules() map[string][]rbacv1.PolicyRule {
+	rules := make(map[string][]rbacv1.PolicyRule)
+	for _, namespace := range s.WatchNamespaces {
+		rules[namespace] = append(rules[namespace], releasePayloadPolicyRules...)
+	}
+	for _, namespace := range s.JobNamespaces {
+		rules[namespace] = append(rules[namespace], jobPolicyRules...)
+	}
+	for _, namespace := range s.ProwJobNamespaces {
+		rules[namespace] = append(rules[namespace], prowJobPolicyRules...)
+	}
+	if len(s.ApprovedUsersNamespace) > 0 {
+		rules[s.ApprovedUsersNamespace] = append(rules[s.ApprovedUsersNamespace], approvedUsersPolicyRules...)
+	}
+	return rules
+}
+
+// namespaces returns the namespaces that the release-payload-controller requires permissions in, sorted
+func (s namespacedRBACScope) namespaces() []string {
+	return sets.StringKeySet(s.policyRules()).List()
+}
+
+// newNamespacedRBAC returns a Role, and a RoleBinding to the service account, granting the permissions required in
+// each of the namespaces of the scope
+func newNamespacedRBAC(scope namespacedRBACScope, serviceAccountNamespace, serviceAccountName string) []interface{} {
+	rules := scope.policyRules()
+	var objects []interface{}
+	for _, namespace := range scope.namespaces() {
+		objects = append(objects,
+			&rbacv1.Role{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+				ObjectMeta: metav1.ObjectMeta{Name: rbacName, Namespace: namespace},
+				Rules:      rules[namespace],
+			},
+			&rbacv1.RoleBinding{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "RoleBinding"},
+				ObjectMeta: metav1.ObjectMeta{Name: rbacName, Namespace: namespace},
+				Subjects: []rbacv1.Subject{
+					{
+						Kind:      rbacv1.ServiceAccountKind,
+						Name:      serviceAccountName,
+						Namespace: serviceAccountNamespace,
+					},
+				},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: rbacv1.GroupName,
+					Kind:     "Role",
+					Name:     rbacName,
+				},
+			},
+		)
+	}
+	return objects
+}
+
+// writeNamespacedRBAC writes the namespaced RBAC manifests as a multi-document YAML stream
+func writeNamespacedRBAC(out io.Writer, scope namespacedRBACScope, serviceAccountNamespace, serviceAccountName string) error {
+	for _, object := range newNamespacedRBAC(scope, serviceAccountNamespace, serviceAccountName) {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(out, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyNamespacedAccess checks, with SelfSubjectAccessReviews, that the release-payload-controller has been granted
+// the permissions it requires in each of the namespaces of the scope.  All the missing permissions are reported at
+// once.
+func verifyNamespacedAccess(ctx context.Context, client authorizationv1client.SelfSubjectAccessReviewsGetter, scope namespacedRBACScope) error {
+	rules := scope.policyRules()
+	var missing []string
+	for _, namespace := range scope.namespaces() {
+		for _, rule := range rules[namespace] {
+			for _, group := range rule.APIGroups {
+				for _, resource := range rule.Resources {
+					for _, verb := range rule.Verbs {
+						resourceName, subresource := resource, ""
+						if parts := strings.SplitN(resource, "/", 2); len(parts) == 2 {
+							resourceName, subresource = parts[0], parts[1]
+						}
+						review, err := client.SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+							Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+								ResourceAttributes: &authorizationv1.ResourceAttributes{
+									Namespace:   namespace,
+									Verb:        verb,
+									Group:       group,
+									Resource:    resourceName,
+									Subresource: subresource,
+								},
+							},
+						}, metav1.CreateOptions{})
+						if err != nil {
+							return fmt.Errorf("unable to review access to %s in namespace %s: %w", resource, namespace, err)
+						}
+						if !review.Status.Allowed {
+							missing = append(missing, fmt.Sprintf("%s %s in namespace %s", verb, resource, namespace))
+						}
+					}
+				}
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing permissions: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+type generateRBACOptions struct {
+	WatchNamespaces        []string
+	JobNamespaces          []string
+	ProwJobNamespaces      []string
+	ApprovedUsersConfigMap string
+	ServiceAccount         string
+	Output                 string
+}
+
+func (o *generateRBACOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&o.WatchNamespaces, "watch-namespaces", o.WatchNamespaces, "A comma-separated list of the namespaces watched for release payloads to generate a Role, and RoleBinding, for.")
+	fs.StringSliceVar(&o.JobNamespaces, "job-namespace", o.JobNamespaces, "A namespace watched for release creation jobs to generate a Role, and RoleBinding, for.  May be specified multiple times.")
+	fs.StringSliceVar(&o.ProwJobNamespaces, "prowjob-namespace", o.ProwJobNamespaces, "A namespace watched for ProwJobs to generate a Role, and RoleBinding, for.  May be specified multiple times.")
+	fs.StringVar(&o.ApprovedUsersConfigMap, "approved-users-configmap", o.ApprovedUsersConfigMap, "The namespace/name of the ConfigMap listing the users allowed to approve release payloads.  No access is generated for it if unset.")
+	fs.StringVar(&o.ServiceAccount, "service-account", o.ServiceAccount, "The namespace/name of the service account that the release-payload-controller runs as.")
+	fs.StringVar(&o.Output, "output", o.Output, "The file to write the RBAC manifests to.  Written to stdout if unset.")
+}
+
+func (o *generateRBACOptions) Validate() error {
+	if len(o.WatchNamespaces) == 0 {
+		return fmt.Errorf("--watch-namespaces must be set")
+	}
+	if len(o.JobNamespaces) == 0 {
+		return fmt.Errorf("--job-namespace must be set")
+	}
+	if len(o.ProwJobNamespaces) == 0 {
+		return fmt.Errorf("--prowjob-namespace must be set")
+	}
+	if len(o.ApprovedUsersConfigMap) > 0 {
+		if namespace, name, err := cache.SplitMetaNamespaceKey(o.ApprovedUsersConfigMap); err != nil || len(namespace) == 0 || len(name) == 0 {
+			return fmt.Errorf("--approved-users-configmap must be of the form namespace/name")
+		}
+	}
+	if namespace, name, err := cache.SplitMetaNamespaceKey(o.ServiceAccount); err != nil || len(namespace) == 0 || len(name) == 0 {
+		return fmt.Errorf("--service-account must be of the form namespace/name")
+	}
+	return nil
+}
+
+func (o *generateRBACOptions) scope() namespacedRBACScope {
+	approvedUsersNamespace, _, _ := cache.SplitMetaNamespaceKey(o.ApprovedUsersConfigMap)
+	return namespacedRBACScope{
+		WatchNamespaces:        o.WatchNamespaces,
+		JobNamespaces:          o.JobNamespaces,
+		ProwJobNamespaces:      o.ProwJobNamespaces,
+		ApprovedUsersNamespace: approvedUsersNamespace,
+	}
+}
+
+func (o *generateRBACOptions) Run() error {
+	namespace, name, _ := cache.SplitMetaNamespaceKey(o.ServiceAccount)
+	if len(o.Output) == 0 {
+		return writeNamespacedRBAC(os.Stdout, o.scope(), namespace, name)
+	}
+	f, err := os.Create(o.Output)
+	if err != nil {
+		return err
+	}
+	if err := writeNamespacedRBAC(f, o.scope(), namespace, name); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// NewGenerateRBACCommand returns the command that generates the Roles, and RoleBindings, that grant the
+// release-payload-controller access to the resources of the namespaces it watches
+func NewGenerateRBACCommand(name string) *cobra.Command {
+	o := &generateRBACOptions{}
+
+	cmd := &cobra.Command{
+		Use:   name,
+		Short: "Generate the namespace scoped RBAC manifests of the Release Payload Controller",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
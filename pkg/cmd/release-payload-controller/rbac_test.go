@@ -0,0 +1,136 @@
+package release_payload_controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/yaml"
+)
+
+var testRBACScope = namespacedRBACScope{
+	WatchNamespaces:        []string{"ocp", "ocp-arm64"},
+	JobNamespaces:          []string{"ci-release"},
+	ProwJobNamespaces:      []string{"ci"},
+	ApprovedUsersNamespace: "ocp",
+}
+
+func TestWriteNamespacedRBAC(t *testing.T) {
+	out := &bytes.Buffer{}
+	if err := writeNamespacedRBAC(out, testRBACScope, "ci", "release-payload-controller"); err != nil {
+		t.Fatalf("unable to generate RBAC manifests: %v", err)
+	}
+
+	documents := strings.Split(strings.TrimPrefix(out.String(), "---\n"), "---\n")
+	if len(documents) != 8 {
+		t.Fatalf("expected 8 manifests, got %d:\n%s", len(documents), out.String())
+	}
+	expectedRules := map[string][]rbacv1.PolicyRule{
+		"ci":         prowJobPolicyRules,
+		"ci-release": jobPolicyRules,
+		"ocp":        append(append([]rbacv1.PolicyRule{}, releasePayloadPolicyRules...), approvedUsersPolicyRules...),
+		"ocp-arm64":  releasePayloadPolicyRules,
+	}
+	for i, namespace := range []string{"ci", "ci-release", "ocp", "ocp-arm64"} {
+		role := &rbacv1.Role{}
+		if err := yaml.UnmarshalStrict([]byte(documents[2*i]), role); err != nil {
+			t.Fatalf("unable to decode Role: %v", err)
+		}
+		if role.Kind != "Role" || role.Namespace != namespace || !reflect.DeepEqual(role.Rules, expectedRules[namespace]) {
+			t.Errorf("unexpected Role: %s", documents[2*i])
+		}
+		roleBinding := &rbacv1.RoleBinding{}
+		if err := yaml.UnmarshalStrict([]byte(documents[2*i+1]), roleBinding); err != nil {
+			t.Fatalf("unable to decode RoleBinding: %v", err)
+		}
+		if roleBinding.Kind != "RoleBinding" || roleBinding.Namespace != namespace || roleBinding.RoleRef.Name != role.Name {
+			t.Errorf("unexpected RoleBinding: %s", documents[2*i+1])
+		}
+		if len(roleBinding.Subjects) != 1 || roleBinding.Subjects[0].Namespace != "ci" || roleBinding.Subjects[0].Name != "release-payload-controller" {
+			t.Errorf("unexpected RoleBinding subjects: %v", roleBinding.Subjects)
+		}
+	}
+}
+
+func TestVerifyNamespacedAccess(t *testing.T) {
+	testCases := []struct {
+		name          string
+		denied        func(attributes *authorizationv1.ResourceAttributes) bool
+		reviewError   error
+		expectedError string
+	}{
+		{
+			name:   "Allowed",
+			denied: func(attributes *authorizationv1.ResourceAttributes) bool { return false },
+		},
+		{
+			name: "StatusUpdateDenied",
+			denied: func(attributes *authorizationv1.ResourceAttributes) bool {
+				return attributes.Namespace == "ocp-arm64" && attributes.Subresource == "status" && attributes.Verb == "update"
+			},
+			expectedError: "missing permissions: update releasepayloads/status in namespace ocp-arm64",
+		},
+		{
+			name: "WatchDenied",
+			denied: func(attributes *authorizationv1.ResourceAttributes) bool {
+				return attributes.Resource == "releasepayloads" && attributes.Subresource == "" && attributes.Verb == "watch"
+			},
+			expectedError: "missing permissions: watch releasepayloads in namespace ocp, watch releasepayloads in namespace ocp-arm64",
+		},
+		{
+			name: "PodLogsDenied",
+			denied: func(attributes *authorizationv1.ResourceAttributes) bool {
+				return attributes.Resource == "pods" && attributes.Subresource == "log"
+			},
+			expectedError: "missing permissions: get pods/log in namespace ci-release",
+		},
+		{
+			// The pods of failed release creation jobs are listed, rather than watched
+			name: "PodWatchDenied",
+			denied: func(attributes *authorizationv1.ResourceAttributes) bool {
+				return attributes.Resource == "pods" && attributes.Verb == "watch"
+			},
+		},
+		{
+			name: "ProwJobListDenied",
+			denied: func(attributes *authorizationv1.ResourceAttributes) bool {
+				return attributes.Group == "prow.k8s.io" && attributes.Resource == "prowjobs" && attributes.Verb == "list"
+			},
+			expectedError: "missing permissions: list prowjobs in namespace ci",
+		},
+		{
+			name:          "ReviewFailed",
+			reviewError:   fmt.Errorf("connection refused"),
+			expectedError: "unable to review access to prowjobs in namespace ci: connection refused",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			kubeClient.PrependReactor("create", "selfsubjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				if testCase.reviewError != nil {
+					return true, nil, testCase.reviewError
+				}
+				review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+				review.Status.Allowed = !testCase.denied(review.Spec.ResourceAttributes)
+				return true, review, nil
+			})
+
+			err := verifyNamespacedAccess(context.TODO(), kubeClient.AuthorizationV1(), testRBACScope)
+			switch {
+			case len(testCase.expectedError) == 0 && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case len(testCase.expectedError) > 0 && (err == nil || err.Error() != testCase.expectedError):
+				t.Errorf("expected error %q, got %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
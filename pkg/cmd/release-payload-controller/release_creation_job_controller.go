@@ -29,6 +29,10 @@ const (
 // The ReleaseCreationJobController writes the following pieces of information:
 //   - .status.ReleaseCreationJobResult.ReleaseCreationJobCoordinates.Namespace
 //   - .status.ReleaseCreationJobResult.ReleaseCreationJobCoordinates.Name
+//
+// When the ReleasePayload lists its .spec.architectures, a release creation job is expected for each of them, and
+// their coordinates are written to:
+//   - .status.ArchitectureResults[architecture].ReleaseCreationJobCoordinates
 type ReleaseCreationJobController struct {
 	*ReleasePayloadController
 }
@@ -60,8 +64,9 @@ func NewReleaseCreationJobController(
 }
 
 func (c *ReleaseCreationJobController) sync(ctx context.Context, key string) error {
-	klog.V(4).Infof("Starting ReleaseCreationJobController sync")
-	defer klog.V(4).Infof("ReleaseCreationJobController sync done")
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting ReleaseCreationJobController sync")
+	defer logger.V(4).Info("ReleaseCreationJobController sync done")
 
 	// Convert the namespace/name string into a distinct namespace and name
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
@@ -82,8 +87,13 @@ func (c *ReleaseCreationJobController) sync(ctx context.Context, key string) err
 		return err
 	}
 
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(originalReleasePayload) {
+		return nil
+	}
+
 	// If the Coordinates are already set, then don't do anything...
-	if len(originalReleasePayload.Status.ReleaseCreationJobResult.Coordinates.Namespace) > 0 && len(originalReleasePayload.Status.ReleaseCreationJobResult.Coordinates.Name) > 0 {
+	if len(originalReleasePayload.Status.ReleaseCreationJobResult.Coordinates.Namespace) > 0 && len(originalReleasePayload.Status.ReleaseCreationJobResult.Coordinates.Name) > 0 && architectureCoordinatesSet(originalReleasePayload) {
 		return nil
 	}
 
@@ -100,6 +110,20 @@ func (c *ReleaseCreationJobController) sync(ctx context.Context, key string) err
 		},
 	}
 
+	// Each architecture gets its own release creation job, named after the release creation job of the ReleasePayload
+	releasePayload.Status.ArchitectureResults = nil
+	for _, architecture := range originalReleasePayload.Spec.Architectures {
+		if releasePayload.Status.ArchitectureResults == nil {
+			releasePayload.Status.ArchitectureResults = make(map[string]v1alpha1.ReleaseCreationJobResult)
+		}
+		releasePayload.Status.ArchitectureResults[architecture] = v1alpha1.ReleaseCreationJobResult{
+			Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+				Name:      releasepayloadhelpers.ReleaseCreationJobNameForArchitecture(originalReleasePayload.Spec.PayloadCreationConfig.ReleaseCreationCoordinates.ReleaseCreationJobName, architecture),
+				Namespace: originalReleasePayload.Spec.PayloadCreationConfig.ReleaseCreationCoordinates.Namespace,
+			},
+		}
+	}
+
 	releasepayloadhelpers.CanonicalizeReleasePayloadStatus(releasePayload)
 
 	if reflect.DeepEqual(originalReleasePayload, releasePayload) {
@@ -116,3 +140,15 @@ func (c *ReleaseCreationJobController) sync(ctx context.Context, key string) err
 
 	return nil
 }
+
+// architectureCoordinatesSet returns true if the coordinates, of the release creation job, of every one of the
+// .spec.architectures have been set
+func architectureCoordinatesSet(releasePayload *v1alpha1.ReleasePayload) bool {
+	for _, architecture := range releasePayload.Spec.Architectures {
+		result, ok := releasePayload.Status.ArchitectureResults[architecture]
+		if !ok || len(result.Coordinates.Namespace) == 0 || len(result.Coordinates.Name) == 0 {
+			return false
+		}
+	}
+	return true
+}
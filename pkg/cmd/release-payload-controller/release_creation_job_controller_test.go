@@ -107,6 +107,69 @@ func TestReleaseCreationJobSync(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "ReleaseCreationJobResultsOfArchitecturesNotPresent",
+			payload: &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+				},
+				Spec: v1alpha1.ReleasePayloadSpec{
+					PayloadCreationConfig: v1alpha1.PayloadCreationConfig{
+						ReleaseCreationCoordinates: v1alpha1.ReleaseCreationCoordinates{
+							Namespace:              "ci-release",
+							ReleaseCreationJobName: "4.11.0-0.nightly-2022-02-09-091559",
+						},
+					},
+					Architectures: []string{"amd64", "arm64"},
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+							Name:      "4.11.0-0.nightly-2022-02-09-091559",
+							Namespace: "ci-release",
+						},
+					},
+				},
+			},
+			expected: &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+				},
+				Spec: v1alpha1.ReleasePayloadSpec{
+					PayloadCreationConfig: v1alpha1.PayloadCreationConfig{
+						ReleaseCreationCoordinates: v1alpha1.ReleaseCreationCoordinates{
+							Namespace:              "ci-release",
+							ReleaseCreationJobName: "4.11.0-0.nightly-2022-02-09-091559",
+						},
+					},
+					Architectures: []string{"amd64", "arm64"},
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+							Name:      "4.11.0-0.nightly-2022-02-09-091559",
+							Namespace: "ci-release",
+						},
+					},
+					ArchitectureResults: map[string]v1alpha1.ReleaseCreationJobResult{
+						"amd64": {
+							Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+								Name:      "4.11.0-0.nightly-2022-02-09-091559-amd64",
+								Namespace: "ci-release",
+							},
+						},
+						"arm64": {
+							Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+								Name:      "4.11.0-0.nightly-2022-02-09-091559-arm64",
+								Namespace: "ci-release",
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -0,0 +1,123 @@
+package release_payload_controller
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadhelpers "github.com/openshift/release-controller/pkg/releasepayload/v1alpha1helpers"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+)
+
+// releaseCreationJobMetrics are the metrics, of the release creation jobs, reported by the
+// ReleaseCreationStatusController
+type releaseCreationJobMetrics struct {
+	// statusTotal counts the transitions of the ReleaseCreationJobResult, of every ReleasePayload, by the status
+	// transitioned to
+	statusTotal *metrics.CounterVec
+
+	// duration observes how long each successful release creation job ran for
+	duration *metrics.Histogram
+
+	// timeToStatus observes how long after the creation of each ReleasePayload its release creation job completed, by
+	// the terminal status and the release stream of the ReleasePayload
+	timeToStatus *metrics.HistogramVec
+
+	// queueRetriesTotal counts the ReleasePayloads that were requeued, with backoff, after a failed sync
+	queueRetriesTotal *metrics.Counter
+
+	// queueBackoffDuration observes the backoff, imposed by the rate limiter, before each requeued ReleasePayload is
+	// synced again
+	queueBackoffDuration *metrics.Histogram
+
+	registerOnce sync.Once
+}
+
+func newReleaseCreationJobMetrics() *releaseCreationJobMetrics {
+	return &releaseCreationJobMetrics{
+		statusTotal: metrics.NewCounterVec(
+			&metrics.CounterOpts{
+				Name:           "release_creation_job_status_total",
+				Help:           "The number of times the release creation job of a release payload transitioned to a status: success, failed, unknown or pending",
+				StabilityLevel: metrics.ALPHA,
+			},
+			[]string{"status"},
+		),
+		duration: metrics.NewHistogram(
+			&metrics.HistogramOpts{
+				Name:           "release_creation_job_duration_seconds",
+				Help:           "The time, from its start to its completion, taken by a successful release creation job",
+				Buckets:        metrics.ExponentialBuckets(60, 2, 8),
+				StabilityLevel: metrics.ALPHA,
+			},
+		),
+		timeToStatus: metrics.NewHistogramVec(
+			&metrics.HistogramOpts{
+				Name:           "release_controller_job_time_to_status_seconds",
+				Help:           "The time, from the creation of a release payload, until its release creation job reached a terminal status: success or failed",
+				Buckets:        metrics.ExponentialBuckets(60, 2, 8),
+				StabilityLevel: metrics.ALPHA,
+			},
+			[]string{"status", "stream"},
+		),
+		queueRetriesTotal: metrics.NewCounter(
+			&metrics.CounterOpts{
+				Name:           "release_creation_queue_retries_total",
+				Help:           "The number of times a release payload was requeued, with backoff, after a failed sync of its release creation job status",
+				StabilityLevel: metrics.ALPHA,
+			},
+		),
+		queueBackoffDuration: metrics.NewHistogram(
+			&metrics.HistogramOpts{
+				Name:           "release_creation_queue_backoff_duration_seconds",
+				Help:           "The backoff, imposed by the rate limiter, before a requeued release payload is synced again",
+				Buckets:        metrics.ExponentialBuckets(0.005, 4, 10),
+				StabilityLevel: metrics.ALPHA,
+			},
+		),
+	}
+}
+
+// register registers the metrics, i.e. with legacyregistry.MustRegister to serve them on /metrics.  The metrics are
+// only registered once, subsequent calls do nothing.
+func (m *releaseCreationJobMetrics) register(mustRegister func(...metrics.Registerable)) {
+	m.registerOnce.Do(func() {
+		mustRegister(m.statusTotal, m.duration, m.timeToStatus, m.queueRetriesTotal, m.queueBackoffDuration)
+	})
+}
+
+// recordTransition records the transition, of the ReleaseCreationJobResult, from the current status to the desired
+// one.  The job, if known, is used to observe the duration of successful release creation jobs.
+func (m *releaseCreationJobMetrics) recordTransition(current, desired v1alpha1.ReleaseCreationJobStatus, job *batchv1.Job) {
+	if current == desired || len(desired) == 0 {
+		return
+	}
+	m.statusTotal.WithLabelValues(strings.ToLower(string(desired))).Inc()
+
+	if desired != v1alpha1.ReleaseCreationJobSuccess || job == nil || job.Status.StartTime == nil || job.Status.CompletionTime == nil {
+		return
+	}
+	duration := job.Status.CompletionTime.Sub(job.Status.StartTime.Time)
+	if duration < 0 {
+		klog.V(4).Infof("Release creation job %s/%s completed before it started", job.Namespace, job.Name)
+		return
+	}
+	m.duration.Observe(duration.Seconds())
+}
+
+// recordTimeToStatus observes, when the ReleaseCreationJobResult transitions from the current status to a terminal
+// one, how long it took since the ReleasePayload was created
+func (m *releaseCreationJobMetrics) recordTimeToStatus(releasePayload *v1alpha1.ReleasePayload, current, desired v1alpha1.ReleaseCreationJobStatus, now time.Time) {
+	if current == desired || (desired != v1alpha1.ReleaseCreationJobSuccess && desired != v1alpha1.ReleaseCreationJobFailed) {
+		return
+	}
+	timeToStatus := now.Sub(releasePayload.CreationTimestamp.Time)
+	if timeToStatus < 0 {
+		klog.V(4).Infof("ReleasePayload %s/%s reached status %s before it was created", releasePayload.Namespace, releasePayload.Name, desired)
+		return
+	}
+	m.timeToStatus.WithLabelValues(strings.ToLower(string(desired)), releasepayloadhelpers.ReleasePayloadStream(releasePayload)).Observe(timeToStatus.Seconds())
+}
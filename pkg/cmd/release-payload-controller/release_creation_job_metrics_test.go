@@ -0,0 +1,412 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/testutil"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+)
+
+// noReleaseCreationJobDurations is the release_creation_job_duration_seconds histogram before any observation
+const noReleaseCreationJobDurations = `
+	# HELP release_creation_job_duration_seconds [ALPHA] The time, from its start to its completion, taken by a successful release creation job
+	# TYPE release_creation_job_duration_seconds histogram
+	release_creation_job_duration_seconds_bucket{le="60"} 0
+	release_creation_job_duration_seconds_bucket{le="120"} 0
+	release_creation_job_duration_seconds_bucket{le="240"} 0
+	release_creation_job_duration_seconds_bucket{le="480"} 0
+	release_creation_job_duration_seconds_bucket{le="960"} 0
+	release_creation_job_duration_seconds_bucket{le="1920"} 0
+	release_creation_job_duration_seconds_bucket{le="3840"} 0
+	release_creation_job_duration_seconds_bucket{le="7680"} 0
+	release_creation_job_duration_seconds_bucket{le="+Inf"} 0
+	release_creation_job_duration_seconds_sum 0
+	release_creation_job_duration_seconds_count 0
+`
+
+func TestReleaseCreationJobMetricsRecordTransition(t *testing.T) {
+	start := time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC)
+	completedJob := &batchv1.Job{
+		Status: batchv1.JobStatus{
+			StartTime:      &metav1.Time{Time: start},
+			CompletionTime: &metav1.Time{Time: start.Add(10 * time.Minute)},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		current  v1alpha1.ReleaseCreationJobStatus
+		desired  v1alpha1.ReleaseCreationJobStatus
+		job      *batchv1.Job
+		expected string
+	}{
+		{
+			name:     "NoTransition",
+			current:  v1alpha1.ReleaseCreationJobPending,
+			desired:  v1alpha1.ReleaseCreationJobPending,
+			expected: noReleaseCreationJobDurations,
+		},
+		{
+			name:    "Pending",
+			current: v1alpha1.ReleaseCreationJobUnknown,
+			desired: v1alpha1.ReleaseCreationJobPending,
+			expected: noReleaseCreationJobDurations + `
+				# HELP release_creation_job_status_total [ALPHA] The number of times the release creation job of a release payload transitioned to a status: success, failed, unknown or pending
+				# TYPE release_creation_job_status_total counter
+				release_creation_job_status_total{status="pending"} 1
+			`,
+		},
+		{
+			name:    "Failed",
+			current: v1alpha1.ReleaseCreationJobPending,
+			desired: v1alpha1.ReleaseCreationJobFailed,
+			expected: noReleaseCreationJobDurations + `
+				# HELP release_creation_job_status_total [ALPHA] The number of times the release creation job of a release payload transitioned to a status: success, failed, unknown or pending
+				# TYPE release_creation_job_status_total counter
+				release_creation_job_status_total{status="failed"} 1
+			`,
+		},
+		{
+			name:    "Success",
+			current: v1alpha1.ReleaseCreationJobPending,
+			desired: v1alpha1.ReleaseCreationJobSuccess,
+			job:     completedJob,
+			expected: `
+				# HELP release_creation_job_duration_seconds [ALPHA] The time, from its start to its completion, taken by a successful release creation job
+				# TYPE release_creation_job_duration_seconds histogram
+				release_creation_job_duration_seconds_bucket{le="60"} 0
+				release_creation_job_duration_seconds_bucket{le="120"} 0
+				release_creation_job_duration_seconds_bucket{le="240"} 0
+				release_creation_job_duration_seconds_bucket{le="480"} 0
+				release_creation_job_duration_seconds_bucket{le="960"} 1
+				release_creation_job_duration_seconds_bucket{le="1920"} 1
+				release_creation_job_duration_seconds_bucket{le="3840"} 1
+				release_creation_job_duration_seconds_bucket{le="7680"} 1
+				release_creation_job_duration_seconds_bucket{le="+Inf"} 1
+				release_creation_job_duration_seconds_sum 600
+				release_creation_job_duration_seconds_count 1
+				# HELP release_creation_job_status_total [ALPHA] The number of times the release creation job of a release payload transitioned to a status: success, failed, unknown or pending
+				# TYPE release_creation_job_status_total counter
+				release_creation_job_status_total{status="success"} 1
+			`,
+		},
+		{
+			name:    "SuccessWithoutStartTime",
+			current: v1alpha1.ReleaseCreationJobPending,
+			desired: v1alpha1.ReleaseCreationJobSuccess,
+			job: &batchv1.Job{
+				Status: batchv1.JobStatus{
+					CompletionTime: &metav1.Time{Time: start},
+				},
+			},
+			expected: noReleaseCreationJobDurations + `
+				# HELP release_creation_job_status_total [ALPHA] The number of times the release creation job of a release payload transitioned to a status: success, failed, unknown or pending
+				# TYPE release_creation_job_status_total counter
+				release_creation_job_status_total{status="success"} 1
+			`,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			registry := metrics.NewKubeRegistry()
+			m := newReleaseCreationJobMetrics()
+			m.register(registry.MustRegister)
+
+			m.recordTransition(testCase.current, testCase.desired, testCase.job)
+
+			if err := testutil.GatherAndCompare(registry, strings.NewReader(testCase.expected), "release_creation_job_status_total", "release_creation_job_duration_seconds"); err != nil {
+				t.Errorf("%s: %v", testCase.name, err)
+			}
+		})
+	}
+}
+
+func TestReleaseCreationStatusSyncMetrics(t *testing.T) {
+	start := time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC)
+	job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+					Name:      job.Name,
+					Namespace: job.Namespace,
+				},
+			},
+		},
+	}
+	key := fmt.Sprintf("%s/%s", input.Namespace, input.Name)
+
+	c := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		WithBatchJob(job).
+		Build()
+
+	registry := metrics.NewKubeRegistry()
+	c.metrics.register(registry.MustRegister)
+
+	steps := []struct {
+		jobStatus batchv1.JobStatus
+		expected  v1alpha1.ReleaseCreationJobStatus
+	}{
+		{
+			jobStatus: batchv1.JobStatus{},
+			expected:  v1alpha1.ReleaseCreationJobUnknown,
+		},
+		{
+			jobStatus: batchv1.JobStatus{StartTime: &metav1.Time{Time: start}, Active: 1},
+			expected:  v1alpha1.ReleaseCreationJobPending,
+		},
+		// Only the message changes, which is not a transition
+		{
+			jobStatus: batchv1.JobStatus{StartTime: &metav1.Time{Time: start}, Active: 1, Ready: pointer.Int32(1)},
+			expected:  v1alpha1.ReleaseCreationJobPending,
+		},
+		{
+			jobStatus: batchv1.JobStatus{StartTime: &metav1.Time{Time: start}, CompletionTime: &metav1.Time{Time: start.Add(5 * time.Minute)}},
+			expected:  v1alpha1.ReleaseCreationJobSuccess,
+		},
+	}
+	for i, step := range steps {
+		updated := job.DeepCopy()
+		updated.Status = step.jobStatus
+		if _, err := c.batchJobClient.Jobs(job.Namespace).Update(context.TODO(), updated, metav1.UpdateOptions{}); err != nil {
+			t.Fatalf("step %d: unable to update job: %v", i, err)
+		}
+		if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+			current, err := c.batchJobLister.Jobs(job.Namespace).Get(job.Name)
+			return err == nil && cmp.Equal(current.Status, step.jobStatus), nil
+		}); err != nil {
+			t.Fatalf("step %d: the job update was not observed: %v", i, err)
+		}
+
+		if err := c.sync(context.TODO(), key); err != nil {
+			t.Fatalf("step %d: unexpected err: %v", i, err)
+		}
+
+		if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+			current, err := c.releasePayloadLister.ReleasePayloads(input.Namespace).Get(input.Name)
+			return err == nil && current.Status.ReleaseCreationJobResult.Status == step.expected, nil
+		}); err != nil {
+			t.Fatalf("step %d: Expected status %s: %v", i, step.expected, err)
+		}
+	}
+
+	// Resyncing the completed job is not a transition either
+	if err := c.sync(context.TODO(), key); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	expected := `
+		# HELP release_creation_job_duration_seconds [ALPHA] The time, from its start to its completion, taken by a successful release creation job
+		# TYPE release_creation_job_duration_seconds histogram
+		release_creation_job_duration_seconds_bucket{le="60"} 0
+		release_creation_job_duration_seconds_bucket{le="120"} 0
+		release_creation_job_duration_seconds_bucket{le="240"} 0
+		release_creation_job_duration_seconds_bucket{le="480"} 1
+		release_creation_job_duration_seconds_bucket{le="960"} 1
+		release_creation_job_duration_seconds_bucket{le="1920"} 1
+		release_creation_job_duration_seconds_bucket{le="3840"} 1
+		release_creation_job_duration_seconds_bucket{le="7680"} 1
+		release_creation_job_duration_seconds_bucket{le="+Inf"} 1
+		release_creation_job_duration_seconds_sum 300
+		release_creation_job_duration_seconds_count 1
+		# HELP release_creation_job_status_total [ALPHA] The number of times the release creation job of a release payload transitioned to a status: success, failed, unknown or pending
+		# TYPE release_creation_job_status_total counter
+		release_creation_job_status_total{status="pending"} 1
+		release_creation_job_status_total{status="success"} 1
+		release_creation_job_status_total{status="unknown"} 1
+	`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "release_creation_job_status_total", "release_creation_job_duration_seconds"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReleaseCreationStatusSyncMetricsWaitingForCoordinates(t *testing.T) {
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+	}
+	c := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		Build()
+
+	registry := metrics.NewKubeRegistry()
+	c.metrics.register(registry.MustRegister)
+
+	if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != ErrCoordinatesNotSet {
+		t.Fatalf("Expected %v, got: %v", ErrCoordinatesNotSet, err)
+	}
+
+	expected := `
+		# HELP release_creation_job_status_total [ALPHA] The number of times the release creation job of a release payload transitioned to a status: success, failed, unknown or pending
+		# TYPE release_creation_job_status_total counter
+		release_creation_job_status_total{status="unknown"} 1
+	`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "release_creation_job_status_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReleaseCreationJobMetricsRecordTimeToStatus(t *testing.T) {
+	created := time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC)
+	releasePayload := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace:         "ocp",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		current  v1alpha1.ReleaseCreationJobStatus
+		desired  v1alpha1.ReleaseCreationJobStatus
+		now      time.Time
+		expected string
+	}{
+		{
+			name:    "Pending",
+			current: v1alpha1.ReleaseCreationJobUnknown,
+			desired: v1alpha1.ReleaseCreationJobPending,
+			now:     created.Add(time.Minute),
+		},
+		{
+			name:    "NoTransition",
+			current: v1alpha1.ReleaseCreationJobSuccess,
+			desired: v1alpha1.ReleaseCreationJobSuccess,
+			now:     created.Add(time.Hour),
+		},
+		{
+			name:    "CompletedBeforeCreation",
+			current: v1alpha1.ReleaseCreationJobPending,
+			desired: v1alpha1.ReleaseCreationJobSuccess,
+			now:     created.Add(-time.Minute),
+		},
+		{
+			name:    "Success",
+			current: v1alpha1.ReleaseCreationJobPending,
+			desired: v1alpha1.ReleaseCreationJobSuccess,
+			now:     created.Add(10 * time.Minute),
+			expected: `
+				# HELP release_controller_job_time_to_status_seconds [ALPHA] The time, from the creation of a release payload, until its release creation job reached a terminal status: success or failed
+				# TYPE release_controller_job_time_to_status_seconds histogram
+				release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="60"} 0
+				release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="120"} 0
+				release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="240"} 0
+				release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="480"} 0
+				release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="960"} 1
+				release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="1920"} 1
+				release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="3840"} 1
+				release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="7680"} 1
+				release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="+Inf"} 1
+				release_controller_job_time_to_status_seconds_sum{status="success",stream="4.11.0-0.nightly"} 600
+				release_controller_job_time_to_status_seconds_count{status="success",stream="4.11.0-0.nightly"} 1
+			`,
+		},
+		{
+			name:    "Failed",
+			current: v1alpha1.ReleaseCreationJobUnknown,
+			desired: v1alpha1.ReleaseCreationJobFailed,
+			now:     created.Add(2 * time.Hour),
+			expected: `
+				# HELP release_controller_job_time_to_status_seconds [ALPHA] The time, from the creation of a release payload, until its release creation job reached a terminal status: success or failed
+				# TYPE release_controller_job_time_to_status_seconds histogram
+				release_controller_job_time_to_status_seconds_bucket{status="failed",stream="4.11.0-0.nightly",le="60"} 0
+				release_controller_job_time_to_status_seconds_bucket{status="failed",stream="4.11.0-0.nightly",le="120"} 0
+				release_controller_job_time_to_status_seconds_bucket{status="failed",stream="4.11.0-0.nightly",le="240"} 0
+				release_controller_job_time_to_status_seconds_bucket{status="failed",stream="4.11.0-0.nightly",le="480"} 0
+				release_controller_job_time_to_status_seconds_bucket{status="failed",stream="4.11.0-0.nightly",le="960"} 0
+				release_controller_job_time_to_status_seconds_bucket{status="failed",stream="4.11.0-0.nightly",le="1920"} 0
+				release_controller_job_time_to_status_seconds_bucket{status="failed",stream="4.11.0-0.nightly",le="3840"} 0
+				release_controller_job_time_to_status_seconds_bucket{status="failed",stream="4.11.0-0.nightly",le="7680"} 1
+				release_controller_job_time_to_status_seconds_bucket{status="failed",stream="4.11.0-0.nightly",le="+Inf"} 1
+				release_controller_job_time_to_status_seconds_sum{status="failed",stream="4.11.0-0.nightly"} 7200
+				release_controller_job_time_to_status_seconds_count{status="failed",stream="4.11.0-0.nightly"} 1
+			`,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			registry := metrics.NewKubeRegistry()
+			m := newReleaseCreationJobMetrics()
+			m.register(registry.MustRegister)
+
+			m.recordTimeToStatus(releasePayload, testCase.current, testCase.desired, testCase.now)
+
+			if err := testutil.GatherAndCompare(registry, strings.NewReader(testCase.expected), "release_controller_job_time_to_status_seconds"); err != nil {
+				t.Errorf("%s: %v", testCase.name, err)
+			}
+		})
+	}
+}
+
+func TestReleaseCreationStatusSyncTimeToStatusMetrics(t *testing.T) {
+	created := time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC)
+	job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+	job.Status = batchv1.JobStatus{StartTime: &metav1.Time{Time: created}, CompletionTime: &metav1.Time{Time: created.Add(3 * time.Minute)}}
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace:         "ocp",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+					Name:      job.Name,
+					Namespace: job.Namespace,
+				},
+				Status: v1alpha1.ReleaseCreationJobPending,
+			},
+		},
+	}
+
+	c := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		WithBatchJob(job).
+		WithClock(clocktesting.NewFakePassiveClock(created.Add(5 * time.Minute))).
+		Build()
+
+	registry := metrics.NewKubeRegistry()
+	c.metrics.register(registry.MustRegister)
+
+	if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	expected := `
+		# HELP release_controller_job_time_to_status_seconds [ALPHA] The time, from the creation of a release payload, until its release creation job reached a terminal status: success or failed
+		# TYPE release_controller_job_time_to_status_seconds histogram
+		release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="60"} 0
+		release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="120"} 0
+		release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="240"} 0
+		release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="480"} 1
+		release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="960"} 1
+		release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="1920"} 1
+		release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="3840"} 1
+		release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="7680"} 1
+		release_controller_job_time_to_status_seconds_bucket{status="success",stream="4.11.0-0.nightly",le="+Inf"} 1
+		release_controller_job_time_to_status_seconds_sum{status="success",stream="4.11.0-0.nightly"} 300
+		release_controller_job_time_to_status_seconds_count{status="success",stream="4.11.0-0.nightly"} 1
+	`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "release_controller_job_time_to_status_seconds"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,181 @@
+package release_payload_controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
+	releasecontroller "github.com/openshift/release-controller/pkg/release-controller"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	batchv1informers "k8s.io/client-go/informers/batch/v1"
+	batchv1client "k8s.io/client-go/kubernetes/typed/batch/v1"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+)
+
+// ReleaseCreationJobSuspensionController is responsible for suspending, and resuming, the release creation jobs of a
+// ReleasePayload.  The jobs of a Suspended ReleasePayload have their .spec.suspend set, so that they do not start any
+// pods, and it is removed again once the ReleasePayload is no longer Suspended.  Jobs that have completed are left
+// alone.
+// The ReleaseCreationJobSuspensionController reads the following pieces of information:
+//   - .spec.suspended
+//   - .status.releaseCreationJobResult.coordinates
+//   - .status.architectureResults[*].coordinates
+//
+// and writes the following information:
+//   - the .spec.suspend of the release creation jobs
+type ReleaseCreationJobSuspensionController struct {
+	*ReleasePayloadController
+
+	batchJobLister batchv1listers.JobLister
+	batchJobClient batchv1client.JobsGetter
+}
+
+func NewReleaseCreationJobSuspensionController(
+	releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer,
+	releasePayloadClient releasepayloadclient.ReleaseV1alpha1Interface,
+	batchJobInformers map[string]batchv1informers.JobInformer,
+	batchJobClient batchv1client.JobsGetter,
+	eventRecorder events.Recorder,
+) (*ReleaseCreationJobSuspensionController, error) {
+	c := &ReleaseCreationJobSuspensionController{
+		ReleasePayloadController: NewReleasePayloadController("Release Creation Job Suspension Controller",
+			releasePayloadInformer,
+			releasePayloadClient,
+			eventRecorder.WithComponentSuffix("release-creation-job-suspension-controller"),
+			workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ReleaseCreationJobSuspensionController")),
+		batchJobLister: newMultiNamespaceJobLister(batchJobInformers),
+		batchJobClient: batchJobClient,
+	}
+
+	c.syncFn = c.sync
+
+	// A release creation job may be created after its ReleasePayload was Suspended
+	for _, batchJobInformer := range batchJobInformers {
+		c.cachesToSync = append(c.cachesToSync, batchJobInformer.Informer().HasSynced)
+
+		batchJobInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+			FilterFunc: func(obj interface{}) bool {
+				batchJob, ok := obj.(*batchv1.Job)
+				if !ok {
+					return false
+				}
+				_, ok = batchJob.Annotations[releasecontroller.ReleaseAnnotationReleaseTag]
+				return ok
+			},
+			Handler: cache.ResourceEventHandlerFuncs{
+				AddFunc:    c.lookupReleasePayload,
+				UpdateFunc: func(old, new interface{}) { c.lookupReleasePayload(new) },
+			},
+		})
+	}
+
+	releasePayloadInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.Enqueue,
+		UpdateFunc: func(old, new interface{}) { c.Enqueue(new) },
+	})
+
+	return c, nil
+}
+
+func (c *ReleaseCreationJobSuspensionController) lookupReleasePayload(obj interface{}) {
+	releasePayloadKey, err := releaseCreationJobReleasePayloadKey(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(releasePayloadKey)
+}
+
+func (c *ReleaseCreationJobSuspensionController) sync(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting ReleaseCreationJobSuspensionController sync")
+	defer logger.V(4).Info("ReleaseCreationJobSuspensionController sync done")
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	// Get the ReleasePayload resource with this namespace/name
+	releasePayload, err := c.releasePayloadLister.ReleasePayloads(namespace).Get(name)
+	// The ReleasePayload resource may no longer exist, in which case we stop processing.
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(releasePayload) {
+		return nil
+	}
+
+	for _, coordinates := range releaseCreationJobCoordinates(releasePayload) {
+		job, err := c.batchJobLister.Jobs(coordinates.Namespace).Get(coordinates.Name)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := c.syncJobSuspension(ctx, job, releasePayload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncJobSuspension suspends, or resumes, the release creation job to match the ReleasePayload
+func (c *ReleaseCreationJobSuspensionController) syncJobSuspension(ctx context.Context, job *batchv1.Job, releasePayload *v1alpha1.ReleasePayload) error {
+	if job.Status.CompletionTime != nil || isJobFailed(job) {
+		return nil
+	}
+	suspended := pointer.BoolDeref(job.Spec.Suspend, false)
+	if suspended == releasePayload.Spec.Suspended {
+		return nil
+	}
+
+	// Resuming removes the suspend flag, rather than setting it to false, to leave the job as it was created
+	var suspend interface{}
+	if releasePayload.Spec.Suspended {
+		suspend = true
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"suspend": suspend,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	klog.V(4).Infof("Setting suspend of release creation job %s/%s to %t", job.Namespace, job.Name, releasePayload.Spec.Suspended)
+	_, err = c.batchJobClient.Jobs(job.Namespace).Patch(ctx, job.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if releasePayload.Spec.Suspended {
+		c.eventRecorder.Eventf("ReleaseCreationJobSuspended", "Suspended release creation job %s/%s of ReleasePayload %s/%s", job.Namespace, job.Name, releasePayload.Namespace, releasePayload.Name)
+	} else {
+		c.eventRecorder.Eventf("ReleaseCreationJobResumed", "Resumed release creation job %s/%s of ReleasePayload %s/%s", job.Namespace, job.Name, releasePayload.Namespace, releasePayload.Name)
+	}
+	return nil
+}
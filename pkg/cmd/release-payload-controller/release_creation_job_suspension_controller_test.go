@@ -0,0 +1,145 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	batchv1informers "k8s.io/client-go/informers/batch/v1"
+	fake2 "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+)
+
+func TestReleaseCreationJobSuspensionSync(t *testing.T) {
+	testCases := []struct {
+		name            string
+		suspended       bool
+		jobSuspend      *bool
+		completed       bool
+		expectedSuspend *bool
+		expectedEvent   string
+	}{
+		{
+			name:            "Suspend",
+			suspended:       true,
+			expectedSuspend: pointer.Bool(true),
+			expectedEvent:   "ReleaseCreationJobSuspended",
+		},
+		{
+			name:          "Resume",
+			jobSuspend:    pointer.Bool(true),
+			expectedEvent: "ReleaseCreationJobResumed",
+		},
+		{
+			name:            "AlreadySuspended",
+			suspended:       true,
+			jobSuspend:      pointer.Bool(true),
+			expectedSuspend: pointer.Bool(true),
+		},
+		{
+			name:            "NotSuspended",
+			jobSuspend:      pointer.Bool(false),
+			expectedSuspend: pointer.Bool(false),
+		},
+		{
+			name:      "CompletedJob",
+			suspended: true,
+			completed: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+			job.Spec.Suspend = testCase.jobSuspend
+			if testCase.completed {
+				job.Status.CompletionTime = &metav1.Time{Time: time.Date(2022, 2, 9, 10, 15, 59, 0, time.UTC)}
+			}
+			input := &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+				},
+				Spec: v1alpha1.ReleasePayloadSpec{
+					Suspended: testCase.suspended,
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+							Name:      job.Name,
+							Namespace: job.Namespace,
+						},
+					},
+				},
+			}
+
+			releasePayloadClient := fake.NewSimpleClientset(input)
+			releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+			kubeClient := fake2.NewSimpleClientset(job)
+			kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, controllerDefaultResyncDuration)
+			eventRecorder := events.NewInMemoryRecorder("release-creation-job-suspension-controller-test")
+
+			c, err := NewReleaseCreationJobSuspensionController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), map[string]batchv1informers.JobInformer{metav1.NamespaceAll: kubeInformerFactory.Batch().V1().Jobs()}, kubeClient.BatchV1(), eventRecorder)
+			if err != nil {
+				t.Fatalf("%s: unable to create controller: %v", testCase.name, err)
+			}
+			defer c.queue.ShutDown()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			releasePayloadInformerFactory.Start(stopCh)
+			kubeInformerFactory.Start(stopCh)
+
+			if !cache.WaitForNamedCacheSync("ReleaseCreationJobSuspensionController", stopCh, c.cachesToSync...) {
+				t.Fatalf("%s: error waiting for caches to sync", testCase.name)
+			}
+
+			kubeClient.ClearActions()
+			if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+				t.Fatalf("%s: unexpected error: %v", testCase.name, err)
+			}
+
+			output, err := kubeClient.BatchV1().Jobs(job.Namespace).Get(context.TODO(), job.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("%s: unable to get job: %v", testCase.name, err)
+			}
+			expectedSuspend := testCase.expectedSuspend
+			if testCase.completed {
+				expectedSuspend = testCase.jobSuspend
+			}
+			if !pointer.BoolEqual(output.Spec.Suspend, expectedSuspend) {
+				t.Errorf("%s: Expected suspend %v, got: %v", testCase.name, pointer.BoolDeref(expectedSuspend, false), output.Spec.Suspend)
+			}
+
+			var patches int
+			for _, action := range kubeClient.Actions() {
+				if action.GetVerb() == "patch" {
+					patches++
+				}
+			}
+			var recorded []string
+			for _, event := range eventRecorder.Events() {
+				recorded = append(recorded, event.Reason)
+			}
+			if len(testCase.expectedEvent) == 0 {
+				if patches != 0 || len(recorded) != 0 {
+					t.Errorf("%s: Expected the job to be left alone, got %d patches and events: %v", testCase.name, patches, recorded)
+				}
+				return
+			}
+			if patches != 1 {
+				t.Errorf("%s: Expected 1 patch, got: %d", testCase.name, patches)
+			}
+			if len(recorded) != 1 || recorded[0] != testCase.expectedEvent {
+				t.Errorf("%s: Expected a %s event, got: %v", testCase.name, testCase.expectedEvent, recorded)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package release_payload_controller
+
+import (
+	"k8s.io/client-go/util/workqueue"
+)
+
+// backoffMetricsQueue is a workqueue.RateLimitingInterface that records, in the releaseCreationJobMetrics, every item
+// that is requeued with backoff and how long its backoff is
+type backoffMetricsQueue struct {
+	workqueue.RateLimitingInterface
+
+	rateLimiter workqueue.RateLimiter
+	metrics     *releaseCreationJobMetrics
+}
+
+// newBackoffMetricsQueue returns a named rate limiting queue, using the rate limiter, that records its backoffs in the
+// metrics
+func newBackoffMetricsQueue(rateLimiter workqueue.RateLimiter, name string, metrics *releaseCreationJobMetrics) workqueue.RateLimitingInterface {
+	return &backoffMetricsQueue{
+		RateLimitingInterface: workqueue.NewNamedRateLimitingQueue(rateLimiter, name),
+		rateLimiter:           rateLimiter,
+		metrics:               metrics,
+	}
+}
+
+// AddRateLimited adds the item once the rate limiter says it is ok, like the wrapped queue does, but records the
+// retry and the backoff first.  Forget and NumRequeues are left to the wrapped queue, which shares the rate limiter.
+func (q *backoffMetricsQueue) AddRateLimited(item interface{}) {
+	backoff := q.rateLimiter.When(item)
+	q.metrics.queueRetriesTotal.Inc()
+	q.metrics.queueBackoffDuration.Observe(backoff.Seconds())
+	q.AddAfter(item, backoff)
+}
@@ -0,0 +1,83 @@
+package release_payload_controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+// fakeRateLimiter returns its backoffs, in order, for every item it is asked about
+type fakeRateLimiter struct {
+	backoffs []time.Duration
+	requeues map[interface{}]int
+}
+
+func (r *fakeRateLimiter) When(item interface{}) time.Duration {
+	backoff := r.backoffs[0]
+	r.backoffs = r.backoffs[1:]
+	r.requeues[item]++
+	return backoff
+}
+
+func (r *fakeRateLimiter) Forget(item interface{}) {
+	delete(r.requeues, item)
+}
+
+func (r *fakeRateLimiter) NumRequeues(item interface{}) int {
+	return r.requeues[item]
+}
+
+func TestBackoffMetricsQueue(t *testing.T) {
+	rateLimiter := &fakeRateLimiter{
+		backoffs: []time.Duration{0, 2 * time.Second},
+		requeues: map[interface{}]int{},
+	}
+	m := newReleaseCreationJobMetrics()
+	registry := metrics.NewKubeRegistry()
+	m.register(registry.MustRegister)
+
+	queue := newBackoffMetricsQueue(rateLimiter, "TestBackoffMetricsQueue", m)
+	defer queue.ShutDown()
+
+	queue.AddRateLimited("ocp/4.11.0-0.nightly-2022-02-09-091559")
+	queue.AddRateLimited("ocp/4.11.0-0.nightly-2022-02-09-091559")
+
+	if got := queue.NumRequeues("ocp/4.11.0-0.nightly-2022-02-09-091559"); got != 2 {
+		t.Errorf("expected 2 requeues, got %d", got)
+	}
+	// Only the item without backoff is queued immediately
+	if got := queue.Len(); got != 1 {
+		t.Errorf("expected 1 queued item, got %d", got)
+	}
+	queue.Forget("ocp/4.11.0-0.nightly-2022-02-09-091559")
+	if got := queue.NumRequeues("ocp/4.11.0-0.nightly-2022-02-09-091559"); got != 0 {
+		t.Errorf("expected no requeues after Forget, got %d", got)
+	}
+
+	expected := `
+		# HELP release_creation_queue_retries_total [ALPHA] The number of times a release payload was requeued, with backoff, after a failed sync of its release creation job status
+		# TYPE release_creation_queue_retries_total counter
+		release_creation_queue_retries_total 2
+		# HELP release_creation_queue_backoff_duration_seconds [ALPHA] The backoff, imposed by the rate limiter, before a requeued release payload is synced again
+		# TYPE release_creation_queue_backoff_duration_seconds histogram
+		release_creation_queue_backoff_duration_seconds_bucket{le="0.005"} 1
+		release_creation_queue_backoff_duration_seconds_bucket{le="0.02"} 1
+		release_creation_queue_backoff_duration_seconds_bucket{le="0.08"} 1
+		release_creation_queue_backoff_duration_seconds_bucket{le="0.32"} 1
+		release_creation_queue_backoff_duration_seconds_bucket{le="1.28"} 1
+		release_creation_queue_backoff_duration_seconds_bucket{le="5.12"} 2
+		release_creation_queue_backoff_duration_seconds_bucket{le="20.48"} 2
+		release_creation_queue_backoff_duration_seconds_bucket{le="81.92"} 2
+		release_creation_queue_backoff_duration_seconds_bucket{le="327.68"} 2
+		release_creation_queue_backoff_duration_seconds_bucket{le="1310.72"} 2
+		release_creation_queue_backoff_duration_seconds_bucket{le="+Inf"} 2
+		release_creation_queue_backoff_duration_seconds_sum 2
+		release_creation_queue_backoff_duration_seconds_count 2
+	`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "release_creation_queue_retries_total", "release_creation_queue_backoff_duration_seconds"); err != nil {
+		t.Error(err)
+	}
+}
@@ -2,8 +2,12 @@ package release_payload_controller
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	imagev1 "github.com/openshift/api/image/v1"
+	imagev1informer "github.com/openshift/client-go/image/informers/externalversions/image/v1"
+	imagev1lister "github.com/openshift/client-go/image/listers/image/v1"
 	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
 	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
 	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
@@ -15,65 +19,180 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	batchv1informers "k8s.io/client-go/informers/batch/v1"
+	batchv1client "k8s.io/client-go/kubernetes/typed/batch/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	batchv1listers "k8s.io/client-go/listers/batch/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/legacyregistry"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/pointer"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
 )
 
 const (
 	// ReleaseCreationJobUnknownMessage release creation job unknown message
 	ReleaseCreationJobUnknownMessage = "Release creation job unknown"
 
-	// ReleaseCreationJobPendingMessage release creation job pending message
-	ReleaseCreationJobPendingMessage = "Release creation job pending"
+	// ReleaseCreationJobPendingMessage release creation job waiting for its pod to be scheduled message
+	ReleaseCreationJobPendingMessage = "Job is pending pod scheduling"
+
+	// ReleaseCreationJobRunningMessage release creation job pod running message
+	ReleaseCreationJobRunningMessage = "Job pod is running"
 
 	// ReleaseCreationJobFailureMessage release creation job failure message
 	ReleaseCreationJobFailureMessage = "Release creation Job failed"
 
 	// ReleaseCreationJobSuccessMessage release creation job success message
 	ReleaseCreationJobSuccessMessage = "Release creation Job completed"
+
+	// ReleaseCreationJobUnknownStatusTimeoutMessage release creation job unknown for too long message
+	ReleaseCreationJobUnknownStatusTimeoutMessage = "UnknownStatusTimeout"
+
+	// ReleaseCreationJobWaitingForCoordinatesMessage release creation job coordinates not set message
+	ReleaseCreationJobWaitingForCoordinatesMessage = "Waiting for coordinates to be set"
+
+	// ReleaseCreationJobWaitingForManifestListMessage release images of all the architectures created, but not yet
+	// assembled into the manifest list of the release, message
+	ReleaseCreationJobWaitingForManifestListMessage = "Waiting for the manifest list job"
 )
 
+const (
+	// CreationJobSucceededReason programmatic identifier indicating that the ReleasePayload is Ready, because its release
+	// creation job succeeded
+	CreationJobSucceededReason string = "CreationJobSucceeded"
+
+	// CreationJobFailedReason programmatic identifier indicating that the ReleasePayload will never be Ready, because
+	// its release creation job failed
+	CreationJobFailedReason string = "CreationJobFailed"
+
+	// CreationJobPendingReason programmatic identifier indicating that the ReleasePayload is not Ready yet, because its
+	// release creation job has not completed
+	CreationJobPendingReason string = "CreationJobPending"
+)
+
+// reReleaseDigest matches the digest of the release image pushed by the release creation job
+var reReleaseDigest = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// releasePayloadLabelPrefix is the prefix of the ReleasePayload labels that are kept in sync on the release creation job
+const releasePayloadLabelPrefix = "release.openshift.io/"
+
 var ErrCoordinatesNotSet = errors.New("unable to lookup release creation job: coordinates not set")
 
-// ReleaseCreationStatusController is responsible for watching batchv1.Jobs, in the job-namespace, and
+// ErrJobNameMismatch is returned when the release creation job, referenced by the coordinates of a ReleasePayload, is
+// not named after the ReleasePayload, which means the coordinates point at the job of another release
+var ErrJobNameMismatch = errors.New("release creation job name does not match the ReleasePayload name")
+
+// ErrJobNamespaceMismatch is returned when the coordinates of the release creation job, of a ReleasePayload, point at
+// a namespace that does not match the job namespaces, where the job would never be found.  The ReleasePayload is
+// failed, rather than retried, with this error as the message.
+var ErrJobNamespaceMismatch = errors.New("release creation job namespace does not match the job namespaces")
+
+// ReleaseCreationStatusController is responsible for watching batchv1.Jobs, in the job-namespace(s), and
 // updating the respective ReleasePayload with the status, of the job, when it completes.
 // The ReleaseCreationStatusController watches for changes to the following resources:
 //   - batchv1.Jobs
+//   - imagev1.ImageStreams, that the release images are pushed to
 //
 // and write the following information:
 //   - .status.releaseCreationJobResult.status
 //   - .status.releaseCreationJobResult.message
+//   - .status.releaseDigest, from the tag, of the release imagestream, that the release image was pushed to
+//   - .status.architectureResults, of the ReleasePayloads that list their .spec.architectures
+//   - .status.conditions[type=Ready]
+//
+// When a release creation job fails, the tail of the logs, of its most recent failed pod, is appended to the
+// .status.releaseCreationJobResult.message so that the cause of the failure is visible on the ReleasePayload.
+//
+// The ReleaseCreationStatusController also keeps the release.openshift.io/* labels, of the job, in sync with
+// the labels of the ReleasePayload.
 type ReleaseCreationStatusController struct {
 	*ReleasePayloadController
 
-	batchJobLister batchv1listers.JobLister
+	batchJobLister    batchv1listers.JobLister
+	batchJobClient    batchv1client.JobsGetter
+	imageStreamLister imagev1lister.ImageStreamLister
+	podClient         corev1client.PodsGetter
+
+	// batchJobNamespaces are the namespaces that the release creation jobs are watched in, or all namespaces if it
+	// contains metav1.NamespaceAll
+	batchJobNamespaces sets.String
+
+	// jobLogTailBytes is how many bytes, from the end of the logs of a failed release creation job, are appended to
+	// the failure message.  A value of 0 disables the logs.
+	jobLogTailBytes int
+
+	// rejectUnknownStatusDuration is how long a release creation job result can remain Unknown, or Pending, before it
+	// is considered Failed.  A value of 0 disables the timeout.
+	rejectUnknownStatusDuration time.Duration
+	clock                       clock.PassiveClock
+
+	// circuitBreaker stops the ReleasePayload status updates while the API server is unavailable
+	circuitBreaker *circuitBreaker
+
+	// metrics are registered, on /metrics, when the workers are started
+	metrics *releaseCreationJobMetrics
+
+	// workers is the number of ReleasePayloads that are reconciled concurrently by Run
+	workers int
+
+	// dryRun sends every write, i.e. the status updates and job label patches, as a server-side dry run and logs the
+	// change that would have been made instead
+	dryRun bool
 }
 
 func NewReleaseCreationStatusController(
 	releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer,
 	releasePayloadClient releasepayloadclient.ReleaseV1alpha1Interface,
-	batchJobInformer batchv1informers.JobInformer,
+	batchJobInformers map[string]batchv1informers.JobInformer,
+	batchJobClient batchv1client.JobsGetter,
+	imageStreamInformer imagev1informer.ImageStreamInformer,
+	podClient corev1client.PodsGetter,
 	eventRecorder events.Recorder,
+	rejectUnknownStatusDuration time.Duration,
+	jobLogTailBytes int,
+	workers int,
+	dryRun bool,
 ) (*ReleaseCreationStatusController, error) {
+	metrics := newReleaseCreationJobMetrics()
 	c := &ReleaseCreationStatusController{
 		ReleasePayloadController: NewReleasePayloadController("Release Creation Status Controller",
 			releasePayloadInformer,
 			releasePayloadClient,
 			eventRecorder.WithComponentSuffix("release-creation-status-controller"),
-			workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ReleaseCreationStatusController")),
-		batchJobLister: batchJobInformer.Lister(),
+			newBackoffMetricsQueue(workqueue.DefaultControllerRateLimiter(), "ReleaseCreationStatusController", metrics)),
+		batchJobLister:              newMultiNamespaceJobLister(batchJobInformers),
+		batchJobNamespaces:          sets.StringKeySet(batchJobInformers),
+		batchJobClient:              batchJobClient,
+		imageStreamLister:           imageStreamInformer.Lister(),
+		podClient:                   podClient,
+		jobLogTailBytes:             jobLogTailBytes,
+		rejectUnknownStatusDuration: rejectUnknownStatusDuration,
+		clock:                       clock.RealClock{},
+		circuitBreaker:              newCircuitBreaker("ReleaseCreationStatusController"),
+		metrics:                     metrics,
+		workers:                     workers,
+		dryRun:                      dryRun,
 	}
 
 	c.syncFn = c.sync
-	c.cachesToSync = append(c.cachesToSync, batchJobInformer.Informer().HasSynced)
+	c.reportsPause = true
+
+	c.cachesToSync = append(c.cachesToSync, imageStreamInformer.Informer().HasSynced)
 
 	batchJobFilter := func(obj interface{}) bool {
 		if batchJob, ok := obj.(*batchv1.Job); ok {
@@ -84,13 +203,24 @@ func NewReleaseCreationStatusController(
 		return false
 	}
 
-	batchJobInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
-		FilterFunc: batchJobFilter,
-		Handler: cache.ResourceEventHandlerFuncs{
-			AddFunc:    c.lookupReleasePayload,
-			UpdateFunc: func(old, new interface{}) { c.lookupReleasePayload(new) },
-			DeleteFunc: c.lookupReleasePayload,
-		},
+	// Events from the informers, of every watched namespace, are funneled into the same queue
+	for _, batchJobInformer := range batchJobInformers {
+		c.cachesToSync = append(c.cachesToSync, batchJobInformer.Informer().HasSynced)
+
+		batchJobInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+			FilterFunc: batchJobFilter,
+			Handler: cache.ResourceEventHandlerFuncs{
+				AddFunc:    c.lookupReleasePayload,
+				UpdateFunc: func(old, new interface{}) { c.lookupReleasePayload(new) },
+				DeleteFunc: c.lookupReleasePayload,
+			},
+		})
+	}
+
+	// The release image can be pushed to the release imagestream after the job completion was observed...
+	imageStreamInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.lookupReleaseDigestChanges,
+		UpdateFunc: func(old, new interface{}) { c.lookupReleaseDigestChanges(new) },
 	})
 
 	// In case someone/something deletes the ReleaseCreationJobResult.Status, try and rectify it...
@@ -103,6 +233,9 @@ func NewReleaseCreationStatusController(
 			// Check if we need to process this ReleasePayload at all
 			case len(releasePayload.Status.ReleaseCreationJobResult.Status) == 0 || len(releasePayload.Status.ReleaseCreationJobResult.Message) == 0:
 				return true
+			// Check if the release creation job could have been Unknown, or Pending, for too long
+			case releaseCreationJobIncomplete(releasePayload.Status.ReleaseCreationJobResult.Status) && c.rejectUnknownStatusDuration > 0:
+				return true
 			}
 		}
 		return false
@@ -117,38 +250,92 @@ func NewReleaseCreationStatusController(
 		},
 	})
 
+	// Labels added to the ReleasePayload, after the job was created, need to be propagated to the job, and a pause of
+	// the reconciliation needs to be reported...
+	releasePayloadInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			oldReleasePayload, oldOk := old.(*v1alpha1.ReleasePayload)
+			newReleasePayload, newOk := new.(*v1alpha1.ReleasePayload)
+			if !oldOk || !newOk {
+				return
+			}
+			if !reflect.DeepEqual(oldReleasePayload.Labels, newReleasePayload.Labels) || oldReleasePayload.Spec.PauseReconciliation != newReleasePayload.Spec.PauseReconciliation {
+				c.Enqueue(new)
+			}
+		},
+	})
+
 	return c, nil
 }
 
+// RunWorkers registers the metrics of the ReleaseCreationStatusController before starting its workers
+func (c *ReleaseCreationStatusController) RunWorkers(ctx context.Context, workers int) {
+	c.metrics.register(legacyregistry.MustRegister)
+	c.ReleasePayloadController.RunWorkers(ctx, workers)
+}
+
+// Run starts the configured number of workers, which all drain the same queue, and blocks until the context is done
+func (c *ReleaseCreationStatusController) Run(ctx context.Context) {
+	c.RunWorkers(ctx, c.workers)
+}
+
+// lookupReleaseDigestChanges queues the successful ReleasePayloads, of the release imagestream, whose
+// .status.releaseDigest does not match the image of their tag
+func (c *ReleaseCreationStatusController) lookupReleaseDigestChanges(obj interface{}) {
+	imageStream, ok := obj.(*imagev1.ImageStream)
+	if !ok {
+		return
+	}
+	for _, tag := range imageStream.Status.Tags {
+		releasePayload, err := c.releasePayloadLister.ReleasePayloads(imageStream.Namespace).Get(tag.Tag)
+		if err != nil {
+			continue
+		}
+		if releasePayload.Spec.PayloadCoordinates.ImagestreamName != imageStream.Name || releasePayload.Status.ReleaseCreationJobResult.Status != v1alpha1.ReleaseCreationJobSuccess {
+			continue
+		}
+		if digest := imageStreamTagDigest(imageStream, releasePayload.Spec.PayloadCoordinates.ImagestreamTagName); len(digest) > 0 && digest != releasePayload.Status.ReleaseDigest {
+			c.Enqueue(releasePayload)
+		}
+	}
+}
+
 func (c *ReleaseCreationStatusController) lookupReleasePayload(obj interface{}) {
+	releasePayloadKey, err := releaseCreationJobReleasePayloadKey(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	klog.V(4).Infof("Queueing ReleasePayload: %s", releasePayloadKey)
+	c.queue.Add(releasePayloadKey)
+}
+
+// releaseCreationJobReleasePayloadKey returns the namespace/name key, of the ReleasePayload, that the release creation
+// job was created for, from the annotations of the job
+func releaseCreationJobReleasePayloadKey(obj interface{}) (string, error) {
 	object, ok := obj.(runtime.Object)
 	if !ok {
-		utilruntime.HandleError(fmt.Errorf("unable to cast obj: %v", obj))
-		return
+		return "", fmt.Errorf("unable to cast obj: %v", obj)
 	}
 	target, err := controller.GetAnnotation(object, releasecontroller.ReleaseAnnotationTarget)
 	if err != nil {
-		utilruntime.HandleError(fmt.Errorf("unable to determine releasepayload key: %v", err))
-		return
+		return "", fmt.Errorf("unable to determine releasepayload key: %v", err)
 	}
 	parts := strings.Split(target, "/")
 	if len(parts) != 2 {
-		utilruntime.HandleError(fmt.Errorf("invalid target with %d parts: %q", len(parts), target))
-		return
+		return "", fmt.Errorf("invalid target with %d parts: %q", len(parts), target)
 	}
 	release, err := controller.GetAnnotation(object, releasecontroller.ReleaseAnnotationReleaseTag)
 	if err != nil {
-		utilruntime.HandleError(fmt.Errorf("unable to determine releasepayload key: %v", err))
-		return
+		return "", fmt.Errorf("unable to determine releasepayload key: %v", err)
 	}
-	releasePayloadKey := fmt.Sprintf("%s/%s", parts[0], release)
-	klog.V(4).Infof("Queueing ReleasePayload: %s", releasePayloadKey)
-	c.queue.Add(releasePayloadKey)
+	return fmt.Sprintf("%s/%s", parts[0], release), nil
 }
 
 func (c *ReleaseCreationStatusController) sync(ctx context.Context, key string) error {
-	klog.V(4).Infof("Starting ReleaseCreationStatusController sync")
-	defer klog.V(4).Infof("ReleaseCreationStatusController sync done")
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting ReleaseCreationStatusController sync")
+	defer logger.V(4).Info("ReleaseCreationStatusController sync done")
 
 	// Convert the namespace/name string into a distinct namespace and name
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
@@ -157,6 +344,12 @@ func (c *ReleaseCreationStatusController) sync(ctx context.Context, key string)
 		return nil
 	}
 
+	// Wait, rather than fail and log, until the API server is expected to be available again
+	if retryAfter := c.circuitBreaker.retryAfter(); retryAfter > 0 {
+		c.queue.AddAfter(key, retryAfter)
+		return nil
+	}
+
 	klog.V(4).Infof("Processing ReleasePayload: '%s/%s' from workQueue", namespace, name)
 
 	// Get the ReleasePayload resource with this namespace/name
@@ -169,17 +362,62 @@ func (c *ReleaseCreationStatusController) sync(ctx context.Context, key string)
 		return err
 	}
 
-	// If the release creation job status is terminal (Success), then we have noting else to do
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(originalReleasePayload) {
+		return nil
+	}
+
+	// A ReleasePayload that lists its architectures has a release creation job for each of them
+	if len(originalReleasePayload.Spec.Architectures) > 0 {
+		return c.syncArchitectures(ctx, key, originalReleasePayload)
+	}
+
+	// If the release creation job status is terminal (Success), then all that is left to do is keep the job's labels
+	// current and record the digest of the release
 	if originalReleasePayload.Status.ReleaseCreationJobResult.Status == v1alpha1.ReleaseCreationJobSuccess {
+		releasePayload := originalReleasePayload.DeepCopy()
+		setReleasePayloadReadyCondition(releasePayload)
+		c.setReleaseDigest(releasePayload)
+		if len(originalReleasePayload.Status.ReleaseCreationJobResult.Coordinates.Namespace) > 0 && len(originalReleasePayload.Status.ReleaseCreationJobResult.Coordinates.Name) > 0 {
+			job, err := c.lookupCreatedReleaseJob(originalReleasePayload, originalReleasePayload.Status.ReleaseCreationJobResult.Coordinates)
+			switch {
+			case err != nil:
+				return err
+			case job != nil:
+				if err := validateReleaseCreationJobName(job, originalReleasePayload); err != nil {
+					return err
+				}
+				if err := c.updateJobLabels(ctx, job, originalReleasePayload); err != nil {
+					return err
+				}
+			}
+		}
+		if originalReleasePayload.Status.ReleaseDigest == releasePayload.Status.ReleaseDigest && !readyConditionChanged(originalReleasePayload, releasePayload) {
+			return nil
+		}
+		releasepayloadhelpers.CanonicalizeReleasePayloadStatus(releasePayload)
+		klog.V(4).Infof("Syncing release digest for ReleasePayload: %s/%s", releasePayload.Namespace, releasePayload.Name)
+		err = c.updateStatus(ctx, releasePayload)
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	// A release creation job that was Unknown for too long has already been failed, don't let the job revive it...
+	if originalReleasePayload.Status.ReleaseCreationJobResult.Status == v1alpha1.ReleaseCreationJobFailed && originalReleasePayload.Status.ReleaseCreationJobResult.Message == ReleaseCreationJobUnknownStatusTimeoutMessage {
 		return nil
 	}
 
 	if len(originalReleasePayload.Status.ReleaseCreationJobResult.Coordinates.Namespace) == 0 || len(originalReleasePayload.Status.ReleaseCreationJobResult.Coordinates.Name) == 0 {
-		return ErrCoordinatesNotSet
+		return c.setWaitingForCoordinates(ctx, key, originalReleasePayload)
+	}
+
+	if err := c.validateReleaseCreationJobNamespace(originalReleasePayload, originalReleasePayload.Status.ReleaseCreationJobResult.Coordinates); err != nil {
+		return c.failJobNamespaceMismatch(ctx, originalReleasePayload, err)
 	}
 
 	// Lookup the job. If not found, then the status should be unknown...
-	// TODO: consider a timeout...
 	jobNotFound := false
 	job, err := c.batchJobLister.Jobs(originalReleasePayload.Status.ReleaseCreationJobResult.Coordinates.Namespace).Get(originalReleasePayload.Status.ReleaseCreationJobResult.Coordinates.Name)
 	if k8serrors.IsNotFound(err) {
@@ -193,6 +431,15 @@ func (c *ReleaseCreationStatusController) sync(ctx context.Context, key string)
 		return err
 	}
 
+	if !jobNotFound {
+		if err := validateReleaseCreationJobName(job, originalReleasePayload); err != nil {
+			return err
+		}
+		if err := c.updateJobLabels(ctx, job, originalReleasePayload); err != nil {
+			return err
+		}
+	}
+
 	releasePayload := originalReleasePayload.DeepCopy()
 
 	// Update the Status and Message of the ReleaseCreationJobResult
@@ -201,55 +448,784 @@ func (c *ReleaseCreationStatusController) sync(ctx context.Context, key string)
 		releasePayload.Status.ReleaseCreationJobResult.Status = v1alpha1.ReleaseCreationJobUnknown
 		releasePayload.Status.ReleaseCreationJobResult.Message = ReleaseCreationJobUnknownMessage
 	default:
-		releasePayload.Status.ReleaseCreationJobResult.Status = computeReleaseCreationJobStatus(job)
+		releasePayload.Status.ReleaseCreationJobResult.Status = computeReleaseCreationJobStatus(newJobStatusSnapshot(job))
 		releasePayload.Status.ReleaseCreationJobResult.Message = computeReleaseCreationJobMessage(job)
+		releasePayload.Status.ReleaseCreationJobResult.Attempts = job.Status.Failed
+		// The job only starts once, so keep the first StartTime observed
+		if releasePayload.Status.ReleaseCreationJobResult.StartTime == nil && job.Status.StartTime != nil {
+			releasePayload.Status.ReleaseCreationJobResult.StartTime = job.Status.StartTime.DeepCopy()
+		}
+		switch releasePayload.Status.ReleaseCreationJobResult.Status {
+		case v1alpha1.ReleaseCreationJobSuccess:
+			c.setReleaseDigest(releasePayload)
+		case v1alpha1.ReleaseCreationJobFailed:
+			releasePayload.Status.ReleaseCreationJobResult.Message = c.withJobFailureLogs(ctx, job, originalReleasePayload.Status.ReleaseCreationJobResult, releasePayload.Status.ReleaseCreationJobResult.Message)
+		}
 	}
 
-	releasepayloadhelpers.CanonicalizeReleasePayloadStatus(releasePayload)
+	// Give up on release creation jobs that have been Unknown, or Pending, for too long...
+	c.rejectIncompleteAfterTimeout(key, originalReleasePayload, releasePayload)
+
+	setReleasePayloadReadyCondition(releasePayload)
 
-	if reflect.DeepEqual(originalReleasePayload, releasePayload) {
+	// Only write when a field owned by this controller changes, so that a re-computation of the same result doesn't
+	// cost a call to the API server
+	if !statusChanged(originalReleasePayload.Status.ReleaseCreationJobResult, releasePayload.Status.ReleaseCreationJobResult) && originalReleasePayload.Status.ReleaseDigest == releasePayload.Status.ReleaseDigest && !readyConditionChanged(originalReleasePayload, releasePayload) {
 		return nil
 	}
 
+	if statusTransitioned(originalReleasePayload.Status.ReleaseCreationJobResult, releasePayload.Status.ReleaseCreationJobResult) {
+		releasepayloadhelpers.AppendStatusHistory(releasePayload, metav1.NewTime(c.clock.Now()))
+	}
+	releasepayloadhelpers.CanonicalizeReleasePayloadStatus(releasePayload)
+
 	klog.V(4).Infof("Syncing release creation job status for ReleasePayload: %s/%s", releasePayload.Namespace, releasePayload.Name)
-	_, err = c.releasePayloadClient.ReleasePayloads(releasePayload.Namespace).UpdateStatus(ctx, releasePayload, metav1.UpdateOptions{})
+	err = c.updateStatus(ctx, releasePayload)
 	if k8serrors.IsNotFound(err) {
 		return nil
 	}
 	if err != nil {
 		return err
 	}
+	// Nothing transitioned if the update was a dry run
+	if c.dryRun {
+		return nil
+	}
+	c.metrics.recordTransition(originalReleasePayload.Status.ReleaseCreationJobResult.Status, releasePayload.Status.ReleaseCreationJobResult.Status, job)
+	c.metrics.recordTimeToStatus(originalReleasePayload, originalReleasePayload.Status.ReleaseCreationJobResult.Status, releasePayload.Status.ReleaseCreationJobResult.Status, c.clock.Now())
 
 	return nil
 }
 
-func computeReleaseCreationJobStatus(job *batchv1.Job) v1alpha1.ReleaseCreationJobStatus {
-	if job.Status.CompletionTime != nil {
+// syncArchitectures updates the ArchitectureResults, of a ReleasePayload that lists its .spec.architectures, from the
+// release creation job of each architecture.  The ReleaseCreationJobResult is set to the worst of the
+// ArchitectureResults, so that the release is only created once the jobs of all the architectures have succeeded and
+// fails as soon as the job of any architecture does.
+func (c *ReleaseCreationStatusController) syncArchitectures(ctx context.Context, key string, originalReleasePayload *v1alpha1.ReleasePayload) error {
+	// A release that was created, or that was Unknown for too long, is never revisited, except to keep the labels of
+	// the jobs, and the digest of the release, current
+	switch {
+	case originalReleasePayload.Status.ReleaseCreationJobResult.Status == v1alpha1.ReleaseCreationJobSuccess:
+		if job, err := c.lookupManifestListJob(originalReleasePayload); errors.Is(err, ErrJobNamespaceMismatch) {
+			c.eventRecorder.Warningf("ReleaseCreationJobNamespaceMismatch", "%v", err)
+		} else if err != nil {
+			return err
+		} else if job != nil {
+			if err := c.updateJobLabels(ctx, job, originalReleasePayload); err != nil {
+				return err
+			}
+		}
+		for _, architecture := range originalReleasePayload.Spec.Architectures {
+			coordinates := originalReleasePayload.Status.ArchitectureResults[architecture].Coordinates
+			if len(coordinates.Namespace) == 0 || len(coordinates.Name) == 0 {
+				continue
+			}
+			job, err := c.lookupCreatedReleaseJob(originalReleasePayload, coordinates)
+			if err != nil {
+				return err
+			}
+			if job == nil {
+				continue
+			}
+			if err := validateArchitectureReleaseCreationJobName(job, originalReleasePayload, architecture); err != nil {
+				return err
+			}
+			if err := c.updateJobLabels(ctx, job, originalReleasePayload); err != nil {
+				return err
+			}
+		}
+		releasePayload := originalReleasePayload.DeepCopy()
+		c.setReleaseDigest(releasePayload)
+		if originalReleasePayload.Status.ReleaseDigest == releasePayload.Status.ReleaseDigest {
+			return nil
+		}
+		klog.V(4).Infof("Syncing release digest for ReleasePayload: %s/%s", releasePayload.Namespace, releasePayload.Name)
+		err := c.updateStatus(ctx, releasePayload)
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	case originalReleasePayload.Status.ReleaseCreationJobResult.Status == v1alpha1.ReleaseCreationJobFailed && originalReleasePayload.Status.ReleaseCreationJobResult.Message == ReleaseCreationJobUnknownStatusTimeoutMessage:
+		return nil
+	}
+
+	if len(originalReleasePayload.Status.ReleaseCreationJobResult.Coordinates.Namespace) == 0 || len(originalReleasePayload.Status.ReleaseCreationJobResult.Coordinates.Name) == 0 || !architectureCoordinatesSet(originalReleasePayload) {
+		return c.setWaitingForCoordinates(ctx, key, originalReleasePayload)
+	}
+
+	releasePayload := originalReleasePayload.DeepCopy()
+
+	for _, architecture := range originalReleasePayload.Spec.Architectures {
+		current := originalReleasePayload.Status.ArchitectureResults[architecture]
+		// The result of a job that has completed is final
+		if current.Status == v1alpha1.ReleaseCreationJobSuccess || current.Status == v1alpha1.ReleaseCreationJobFailed {
+			continue
+		}
+		result := *current.DeepCopy()
+
+		if err := c.validateReleaseCreationJobNamespace(originalReleasePayload, current.Coordinates); err != nil {
+			return c.failJobNamespaceMismatch(ctx, originalReleasePayload, err)
+		}
+		job, err := c.batchJobLister.Jobs(current.Coordinates.Namespace).Get(current.Coordinates.Name)
+		switch {
+		case k8serrors.IsNotFound(err):
+			klog.V(4).Infof("Unable to locate %s release creation job: %s/%s", architecture, current.Coordinates.Namespace, current.Coordinates.Name)
+			result.Status = v1alpha1.ReleaseCreationJobUnknown
+			result.Message = ReleaseCreationJobUnknownMessage
+		case err != nil:
+			return err
+		default:
+			if err := validateArchitectureReleaseCreationJobName(job, originalReleasePayload, architecture); err != nil {
+				return err
+			}
+			if err := c.updateJobLabels(ctx, job, originalReleasePayload); err != nil {
+				return err
+			}
+			result.Status = computeReleaseCreationJobStatus(newJobStatusSnapshot(job))
+			result.Message = computeReleaseCreationJobMessage(job)
+			result.Attempts = job.Status.Failed
+			if result.StartTime == nil && job.Status.StartTime != nil {
+				result.StartTime = job.Status.StartTime.DeepCopy()
+			}
+			if result.Status == v1alpha1.ReleaseCreationJobFailed {
+				result.Message = c.withJobFailureLogs(ctx, job, current, result.Message)
+			}
+		}
+		releasePayload.Status.ArchitectureResults[architecture] = result
+	}
+
+	releasePayload.Status.ReleaseCreationJobResult = aggregateArchitectureResults(releasePayload)
+
+	// The release is only created once the release images, of all the architectures, have been assembled into its
+	// manifest list
+	if releasePayload.Status.ReleaseCreationJobResult.Status == v1alpha1.ReleaseCreationJobSuccess {
+		job, err := c.lookupManifestListJob(originalReleasePayload)
+		if errors.Is(err, ErrJobNamespaceMismatch) {
+			return c.failJobNamespaceMismatch(ctx, originalReleasePayload, err)
+		}
+		if err != nil {
+			return err
+		}
+		result := &releasePayload.Status.ReleaseCreationJobResult
+		switch {
+		case job == nil:
+			result.Status = v1alpha1.ReleaseCreationJobPending
+			result.Message = ReleaseCreationJobWaitingForManifestListMessage
+		default:
+			if err := c.updateJobLabels(ctx, job, originalReleasePayload); err != nil {
+				return err
+			}
+			result.Status = computeReleaseCreationJobStatus(newJobStatusSnapshot(job))
+			result.Message = computeReleaseCreationJobMessage(job)
+			if result.Status == v1alpha1.ReleaseCreationJobFailed {
+				result.Message = c.withJobFailureLogs(ctx, job, originalReleasePayload.Status.ReleaseCreationJobResult, result.Message)
+			}
+		}
+	}
+	if releasePayload.Status.ReleaseCreationJobResult.Status == v1alpha1.ReleaseCreationJobSuccess {
+		c.setReleaseDigest(releasePayload)
+	}
+
+	// Give up on release creation jobs that have been Unknown, or Pending, for too long...
+	c.rejectIncompleteAfterTimeout(key, originalReleasePayload, releasePayload)
+
+	setReleasePayloadReadyCondition(releasePayload)
+
+	if !statusChanged(originalReleasePayload.Status.ReleaseCreationJobResult, releasePayload.Status.ReleaseCreationJobResult) && reflect.DeepEqual(originalReleasePayload.Status.ArchitectureResults, releasePayload.Status.ArchitectureResults) && originalReleasePayload.Status.ReleaseDigest == releasePayload.Status.ReleaseDigest && !readyConditionChanged(originalReleasePayload, releasePayload) {
+		return nil
+	}
+
+	if statusTransitioned(originalReleasePayload.Status.ReleaseCreationJobResult, releasePayload.Status.ReleaseCreationJobResult) {
+		releasepayloadhelpers.AppendStatusHistory(releasePayload, metav1.NewTime(c.clock.Now()))
+	}
+	releasepayloadhelpers.CanonicalizeReleasePayloadStatus(releasePayload)
+
+	klog.V(4).Infof("Syncing release creation job status, of %d architectures, for ReleasePayload: %s/%s", len(releasePayload.Spec.Architectures), releasePayload.Namespace, releasePayload.Name)
+	err := c.updateStatus(ctx, releasePayload)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Nothing transitioned if the update was a dry run
+	if c.dryRun {
+		return nil
+	}
+	c.metrics.recordTransition(originalReleasePayload.Status.ReleaseCreationJobResult.Status, releasePayload.Status.ReleaseCreationJobResult.Status, nil)
+	c.metrics.recordTimeToStatus(originalReleasePayload, originalReleasePayload.Status.ReleaseCreationJobResult.Status, releasePayload.Status.ReleaseCreationJobResult.Status, c.clock.Now())
+
+	return nil
+}
+
+// lookupManifestListJob returns the job, at the coordinates of the release creation job of a ReleasePayload that lists
+// its .spec.architectures, that assembles the release images of the architectures into the manifest list of the
+// release.  Nil is returned if the job has not been created yet.
+func (c *ReleaseCreationStatusController) lookupManifestListJob(releasePayload *v1alpha1.ReleasePayload) (*batchv1.Job, error) {
+	coordinates := releasePayload.Status.ReleaseCreationJobResult.Coordinates
+	if err := c.validateReleaseCreationJobNamespace(releasePayload, coordinates); err != nil {
+		return nil, err
+	}
+	job, err := c.batchJobLister.Jobs(coordinates.Namespace).Get(coordinates.Name)
+	if k8serrors.IsNotFound(err) {
+		klog.V(4).Infof("Unable to locate manifest list job: %s/%s", coordinates.Namespace, coordinates.Name)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := validateReleaseCreationJobName(job, releasePayload); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// releaseCreationJobStatusSeverity orders the statuses of the release creation jobs from the best to the worst
+var releaseCreationJobStatusSeverity = map[v1alpha1.ReleaseCreationJobStatus]int{
+	v1alpha1.ReleaseCreationJobSuccess: 0,
+	v1alpha1.ReleaseCreationJobPending: 1,
+	v1alpha1.ReleaseCreationJobUnknown: 2,
+	v1alpha1.ReleaseCreationJobFailed:  3,
+}
+
+// aggregateArchitectureResults returns the ReleaseCreationJobResult, of the ReleasePayload, with the worst status of
+// all its ArchitectureResults.  The message is that of the first architecture, in the order of .spec.architectures,
+// with the worst status, the StartTime is that of the earliest job to start and the Attempts are the total of all the
+// architectures.
+func aggregateArchitectureResults(releasePayload *v1alpha1.ReleasePayload) v1alpha1.ReleaseCreationJobResult {
+	aggregate := v1alpha1.ReleaseCreationJobResult{
+		Coordinates: releasePayload.Status.ReleaseCreationJobResult.Coordinates,
+		Status:      v1alpha1.ReleaseCreationJobSuccess,
+		Message:     ReleaseCreationJobSuccessMessage,
+	}
+	for _, architecture := range releasePayload.Spec.Architectures {
+		result := releasePayload.Status.ArchitectureResults[architecture]
+		if releaseCreationJobStatusSeverity[result.Status] > releaseCreationJobStatusSeverity[aggregate.Status] {
+			aggregate.Status = result.Status
+			aggregate.Message = fmt.Sprintf("%s: %s", architecture, result.Message)
+		}
+		if result.StartTime != nil && (aggregate.StartTime == nil || result.StartTime.Before(aggregate.StartTime)) {
+			aggregate.StartTime = result.StartTime.DeepCopy()
+		}
+		aggregate.Attempts += result.Attempts
+	}
+	return aggregate
+}
+
+// validateArchitectureReleaseCreationJobName returns an ErrJobNameMismatch if the release creation job, of the
+// architecture, is not named after the ReleasePayload and the architecture
+func validateArchitectureReleaseCreationJobName(job *batchv1.Job, releasePayload *v1alpha1.ReleasePayload, architecture string) error {
+	expected := releasepayloadhelpers.ReleaseCreationJobNameForArchitecture(releasePayload.Name, architecture)
+	if job.Name == expected {
+		return nil
+	}
+	return fmt.Errorf("%w: ReleasePayload %s/%s references %s release creation job %s/%s, expected %q",
+		ErrJobNameMismatch, releasePayload.Namespace, releasePayload.Name, architecture, job.Namespace, job.Name, expected)
+}
+
+// lookupCreatedReleaseJob returns the release creation job, at the coordinates, of a ReleasePayload whose release has
+// been created.  Nil is returned if the job no longer exists, or if it is not in one of the job namespaces, in which
+// case a Warning event is emitted instead, because the release has been created regardless.
+func (c *ReleaseCreationStatusController) lookupCreatedReleaseJob(releasePayload *v1alpha1.ReleasePayload, coordinates v1alpha1.ReleaseCreationJobCoordinates) (*batchv1.Job, error) {
+	if err := c.validateReleaseCreationJobNamespace(releasePayload, coordinates); err != nil {
+		c.eventRecorder.Warningf("ReleaseCreationJobNamespaceMismatch", "%v", err)
+		return nil, nil
+	}
+	job, err := c.batchJobLister.Jobs(coordinates.Namespace).Get(coordinates.Name)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return job, err
+}
+
+// failJobNamespaceMismatch fails the ReleaseCreationJobResult, of a ReleasePayload, that references a release creation
+// job outside the job namespaces and emits a Warning event.  The coordinates of a ReleasePayload don't change, so
+// retrying would never find the job.
+func (c *ReleaseCreationStatusController) failJobNamespaceMismatch(ctx context.Context, originalReleasePayload *v1alpha1.ReleasePayload, mismatch error) error {
+	releasePayload := originalReleasePayload.DeepCopy()
+	releasePayload.Status.ReleaseCreationJobResult.Status = v1alpha1.ReleaseCreationJobFailed
+	releasePayload.Status.ReleaseCreationJobResult.Message = mismatch.Error()
+	setReleasePayloadReadyCondition(releasePayload)
+
+	if !statusChanged(originalReleasePayload.Status.ReleaseCreationJobResult, releasePayload.Status.ReleaseCreationJobResult) && !readyConditionChanged(originalReleasePayload, releasePayload) {
+		return nil
+	}
+
+	if statusTransitioned(originalReleasePayload.Status.ReleaseCreationJobResult, releasePayload.Status.ReleaseCreationJobResult) {
+		releasepayloadhelpers.AppendStatusHistory(releasePayload, metav1.NewTime(c.clock.Now()))
+	}
+	releasepayloadhelpers.CanonicalizeReleasePayloadStatus(releasePayload)
+
+	klog.V(4).Infof("Failing ReleasePayload %s/%s: %v", releasePayload.Namespace, releasePayload.Name, mismatch)
+	err := c.updateStatus(ctx, releasePayload)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c.eventRecorder.Warningf("ReleaseCreationJobNamespaceMismatch", "%v", mismatch)
+	// Nothing transitioned if the update was a dry run
+	if c.dryRun {
+		return nil
+	}
+	c.metrics.recordTransition(originalReleasePayload.Status.ReleaseCreationJobResult.Status, releasePayload.Status.ReleaseCreationJobResult.Status, nil)
+	c.metrics.recordTimeToStatus(originalReleasePayload, originalReleasePayload.Status.ReleaseCreationJobResult.Status, releasePayload.Status.ReleaseCreationJobResult.Status, c.clock.Now())
+	return nil
+}
+
+// validateReleaseCreationJobNamespace returns an ErrJobNamespaceMismatch if the release creation job, referenced by the
+// coordinates, is not in one of the job namespaces.  Looking it up there would report the job as not found, and the
+// ReleasePayload as Unknown, rather than the misconfiguration.
+func (c *ReleaseCreationStatusController) validateReleaseCreationJobNamespace(releasePayload *v1alpha1.ReleasePayload, coordinates v1alpha1.ReleaseCreationJobCoordinates) error {
+	if c.batchJobNamespaces.Has(metav1.NamespaceAll) || c.batchJobNamespaces.Has(coordinates.Namespace) {
+		return nil
+	}
+	return fmt.Errorf("%w: ReleasePayload %s/%s references release creation job %s/%s, but only jobs in the %s namespaces are watched",
+		ErrJobNamespaceMismatch, releasePayload.Namespace, releasePayload.Name, coordinates.Namespace, coordinates.Name, strings.Join(c.batchJobNamespaces.List(), ", "))
+}
+
+// validateReleaseCreationJobName returns an ErrJobNameMismatch if the release creation job, looked up by the
+// coordinates of the ReleasePayload, is not named after the ReleasePayload.  Using the job of another release would
+// report its status, and digest, on the wrong ReleasePayload.
+func validateReleaseCreationJobName(job *batchv1.Job, releasePayload *v1alpha1.ReleasePayload) error {
+	if job.Name == releasePayload.Name {
+		return nil
+	}
+	return fmt.Errorf("%w: ReleasePayload %s/%s references release creation job %s/%s, set .spec.payloadCreationConfig.releaseCreationCoordinates.releaseCreationJobName to %q",
+		ErrJobNameMismatch, releasePayload.Namespace, releasePayload.Name, job.Namespace, job.Name, releasePayload.Name)
+}
+
+// updateStatus updates the status of the ReleasePayload, via the circuitBreaker.  If the ReleasePayload was modified
+// in the meantime, i.e. by another controller, the computed status is applied to the latest version of the
+// ReleasePayload and the update is retried.
+func (c *ReleaseCreationStatusController) updateStatus(ctx context.Context, releasePayload *v1alpha1.ReleasePayload) error {
+	return c.circuitBreaker.execute(func() error {
+		desired := releasePayload
+		opts := metav1.UpdateOptions{}
+		if c.dryRun {
+			opts.DryRun = []string{metav1.DryRunAll}
+			c.logDryRunStatusUpdate(releasePayload)
+		}
+		return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			_, err := c.releasePayloadClient.ReleasePayloads(desired.Namespace).UpdateStatus(ctx, desired, opts)
+			if !k8serrors.IsConflict(err) {
+				return err
+			}
+			latest, getErr := c.releasePayloadClient.ReleasePayloads(releasePayload.Namespace).Get(ctx, releasePayload.Name, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+			desired = latest.DeepCopy()
+			desired.Status = *releasePayload.Status.DeepCopy()
+			return err
+		})
+	})
+}
+
+// logDryRunStatusUpdate logs, as a JSON Patch, the change that updating the status of the ReleasePayload would make to
+// the one in the informer cache
+func (c *ReleaseCreationStatusController) logDryRunStatusUpdate(releasePayload *v1alpha1.ReleasePayload) {
+	current, err := c.releasePayloadLister.ReleasePayloads(releasePayload.Namespace).Get(releasePayload.Name)
+	if err != nil {
+		klog.Infof("Dry run: would update the status of ReleasePayload %s/%s", releasePayload.Namespace, releasePayload.Name)
+		return
+	}
+	patch, err := diffStatuses(current.Status, releasePayload.Status)
+	if err != nil {
+		klog.Infof("Dry run: would update the status of ReleasePayload %s/%s, unable to diff the statuses: %v", releasePayload.Namespace, releasePayload.Name, err)
+		return
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		klog.Infof("Dry run: would update the status of ReleasePayload %s/%s, unable to marshal the diff: %v", releasePayload.Namespace, releasePayload.Name, err)
+		return
+	}
+	klog.Infof("Dry run: would update the status of ReleasePayload %s/%s: %s", releasePayload.Namespace, releasePayload.Name, data)
+}
+
+// setReleasePayloadReadyCondition sets the Ready condition, of the ReleasePayload, from the status of its
+// ReleaseCreationJobResult
+func setReleasePayloadReadyCondition(releasePayload *v1alpha1.ReleasePayload) {
+	readyCondition := metav1.Condition{
+		Type:    v1alpha1.ConditionPayloadReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  CreationJobPendingReason,
+		Message: releasePayload.Status.ReleaseCreationJobResult.Message,
+	}
+	switch releasePayload.Status.ReleaseCreationJobResult.Status {
+	case v1alpha1.ReleaseCreationJobSuccess:
+		readyCondition.Status = metav1.ConditionTrue
+		readyCondition.Reason = CreationJobSucceededReason
+	case v1alpha1.ReleaseCreationJobFailed:
+		readyCondition.Reason = CreationJobFailedReason
+	}
+	v1helpers.SetCondition(&releasePayload.Status.Conditions, readyCondition)
+}
+
+// readyConditionChanged returns true if the status, reason or message, of the Ready condition, differs
+func readyConditionChanged(current, desired *v1alpha1.ReleasePayload) bool {
+	currentCondition := v1helpers.FindCondition(current.Status.Conditions, v1alpha1.ConditionPayloadReady)
+	desiredCondition := v1helpers.FindCondition(desired.Status.Conditions, v1alpha1.ConditionPayloadReady)
+	if currentCondition == nil || desiredCondition == nil {
+		return currentCondition != desiredCondition
+	}
+	return currentCondition.Status != desiredCondition.Status || currentCondition.Reason != desiredCondition.Reason || currentCondition.Message != desiredCondition.Message
+}
+
+// statusChanged returns true if the Status, Message, StartTime or Attempts, of the desired ReleaseCreationJobResult,
+// differs from the current one
+func statusChanged(current, desired v1alpha1.ReleaseCreationJobResult) bool {
+	return current.Status != desired.Status || current.Message != desired.Message || !current.StartTime.Equal(desired.StartTime) || current.Attempts != desired.Attempts
+}
+
+// statusTransitioned returns true if the Status or base Message, of the desired ReleaseCreationJobResult, differs from
+// the current one.  Each transition is recorded in the StatusHistory of the ReleasePayload.
+func statusTransitioned(current, desired v1alpha1.ReleaseCreationJobResult) bool {
+	return current.Status != desired.Status || releasepayloadhelpers.ReleaseCreationJobBaseMessage(current.Message) != releasepayloadhelpers.ReleaseCreationJobBaseMessage(desired.Message)
+}
+
+// setReleaseDigest populates .status.releaseDigest with the digest of the image, that the release creation job pushed
+// to the tag of the release imagestream.  Nothing is set until the image has been imported into the imagestream.
+func (c *ReleaseCreationStatusController) setReleaseDigest(releasePayload *v1alpha1.ReleasePayload) {
+	coordinates := releasePayload.Spec.PayloadCoordinates
+	imageStream, err := c.imageStreamLister.ImageStreams(coordinates.Namespace).Get(coordinates.ImagestreamName)
+	if err != nil {
+		klog.V(4).Infof("Unable to locate release imagestream %s/%s: %v", coordinates.Namespace, coordinates.ImagestreamName, err)
+		return
+	}
+	if digest := imageStreamTagDigest(imageStream, releasePayload.Spec.PayloadCoordinates.ImagestreamTagName); len(digest) > 0 {
+		releasePayload.Status.ReleaseDigest = digest
+	}
+}
+
+// imageStreamTagDigest returns the digest of the most recent image of the tag, of the release imagestream, or an empty
+// string if no image has been imported into the tag
+func imageStreamTagDigest(imageStream *imagev1.ImageStream, tagName string) string {
+	for _, tag := range imageStream.Status.Tags {
+		if tag.Tag != tagName || len(tag.Items) == 0 {
+			continue
+		}
+		if reReleaseDigest.MatchString(tag.Items[0].Image) {
+			return tag.Items[0].Image
+		}
+	}
+	return ""
+}
+
+// withJobFailureLogs appends the logs, of the most recent failed pod of the job, to the failure message.  The logs are
+// only fetched once: a current result that is already Failed, with the same failure message, is kept as is.
+func (c *ReleaseCreationStatusController) withJobFailureLogs(ctx context.Context, job *batchv1.Job, current v1alpha1.ReleaseCreationJobResult, message string) string {
+	if current.Status == v1alpha1.ReleaseCreationJobFailed && strings.HasPrefix(current.Message, message) {
+		return current.Message
+	}
+	if logs := c.fetchJobFailureLogs(ctx, job); len(logs) > 0 {
+		return fmt.Sprintf("%s\n%s", message, logs)
+	}
+	return message
+}
+
+// jobLogLimitBytes is the most, of the logs of a failed pod, that is read from the API server, unless jobLogTailBytes
+// is larger
+const jobLogLimitBytes = 1 << 20
+
+// fetchJobFailureLogs returns the last jobLogTailBytes of the logs of the most recent failed pod of the job.  The logs
+// only add context to the failure message, so an empty string is returned if they cannot be retrieved.  The pods are
+// only listed once a job has failed, so they are not watched, and only the tail of the logs is read.
+func (c *ReleaseCreationStatusController) fetchJobFailureLogs(ctx context.Context, job *batchv1.Job) string {
+	if c.jobLogTailBytes <= 0 || job.Spec.Selector == nil {
+		return ""
+	}
+	selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+	if err != nil {
+		klog.V(4).Infof("Unable to parse selector of release creation job %s/%s: %v", job.Namespace, job.Name, err)
+		return ""
+	}
+	pods, err := c.podClient.Pods(job.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		klog.V(4).Infof("Unable to list pods of release creation job %s/%s: %v", job.Namespace, job.Name, err)
+		return ""
+	}
+	var latest *corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodFailed {
+			continue
+		}
+		if latest == nil || latest.CreationTimestamp.Before(&pod.CreationTimestamp) || (latest.CreationTimestamp.Equal(&pod.CreationTimestamp) && latest.Name < pod.Name) {
+			latest = pod
+		}
+	}
+	if latest == nil {
+		klog.V(4).Infof("Unable to locate a failed pod of release creation job %s/%s", job.Namespace, job.Name)
+		return ""
+	}
+	// The last jobLogTailBytes can't span more lines than that, while the limit stops a pod, that logs very long lines,
+	// from exhausting the memory of the controller
+	limitBytes := jobLogLimitBytes
+	if c.jobLogTailBytes > limitBytes {
+		limitBytes = c.jobLogTailBytes
+	}
+	options := &corev1.PodLogOptions{
+		Container:  failedContainerName(latest),
+		TailLines:  pointer.Int64(int64(c.jobLogTailBytes)),
+		LimitBytes: pointer.Int64(int64(limitBytes)),
+	}
+	data, err := c.podClient.Pods(latest.Namespace).GetLogs(latest.Name, options).DoRaw(ctx)
+	if err != nil {
+		klog.V(4).Infof("Unable to get logs of pod %s/%s of release creation job %s/%s: %v", latest.Namespace, latest.Name, job.Namespace, job.Name, err)
+		return ""
+	}
+	if len(data) > c.jobLogTailBytes {
+		data = data[len(data)-c.jobLogTailBytes:]
+		// Don't start in the middle of a multi-byte character
+		for len(data) > 0 && !utf8.RuneStart(data[0]) {
+			data = data[1:]
+		}
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// failedContainerName returns the name of the first container, of the pod, that exited with an error.  The first
+// container is returned if none did.
+func failedContainerName(pod *corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil && status.State.Terminated.ExitCode != 0 {
+			return status.Name
+		}
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
+
+// rejectIncompleteAfterTimeout fails the release creation job of the ReleasePayload, if it has been Unknown, or Pending,
+// for longer than the rejectUnknownStatusDuration, and otherwise requeues the ReleasePayload for when it will have been
+func (c *ReleaseCreationStatusController) rejectIncompleteAfterTimeout(key string, originalReleasePayload, releasePayload *v1alpha1.ReleasePayload) {
+	if !releaseCreationJobIncomplete(releasePayload.Status.ReleaseCreationJobResult.Status) || c.rejectUnknownStatusDuration <= 0 {
+		return
+	}
+	// The ReleaseCreationJobResult is Unknown, or Pending, from the moment the ReleasePayload is created until the job
+	// completes, so the age of the ReleasePayload is how long the job has been incomplete
+	unknownFor := c.clock.Since(originalReleasePayload.CreationTimestamp.Time)
+	if unknownFor < c.rejectUnknownStatusDuration {
+		c.queue.AddAfter(key, c.rejectUnknownStatusDuration-unknownFor)
+		return
+	}
+	klog.V(4).Infof("Release creation job for ReleasePayload %s/%s has been %s for %s", releasePayload.Namespace, releasePayload.Name, releasePayload.Status.ReleaseCreationJobResult.Status, unknownFor)
+	releasePayload.Status.ReleaseCreationJobResult.Status = v1alpha1.ReleaseCreationJobFailed
+	releasePayload.Status.ReleaseCreationJobResult.Message = ReleaseCreationJobUnknownStatusTimeoutMessage
+}
+
+// setWaitingForCoordinates records, on the ReleasePayload, that the release creation job cannot be looked up until its
+// coordinates have been set, so that anything observing the ReleasePayload can detect the problem.  ErrCoordinatesNotSet
+// is returned, so that the ReleasePayload is retried, unless it has been waiting for too long and has been failed.
+func (c *ReleaseCreationStatusController) setWaitingForCoordinates(ctx context.Context, key string, originalReleasePayload *v1alpha1.ReleasePayload) error {
+	releasePayload := originalReleasePayload.DeepCopy()
+	releasePayload.Status.ReleaseCreationJobResult.Status = v1alpha1.ReleaseCreationJobUnknown
+	releasePayload.Status.ReleaseCreationJobResult.Message = ReleaseCreationJobWaitingForCoordinatesMessage
+	c.rejectIncompleteAfterTimeout(key, originalReleasePayload, releasePayload)
+	setReleasePayloadReadyCondition(releasePayload)
+
+	var result error = ErrCoordinatesNotSet
+	if releasePayload.Status.ReleaseCreationJobResult.Status == v1alpha1.ReleaseCreationJobFailed {
+		result = nil
+	}
+
+	if !statusChanged(originalReleasePayload.Status.ReleaseCreationJobResult, releasePayload.Status.ReleaseCreationJobResult) && !readyConditionChanged(originalReleasePayload, releasePayload) {
+		return result
+	}
+
+	// Waiting for the coordinates is not a transition of the release creation job, giving up on it is
+	if result == nil && statusTransitioned(originalReleasePayload.Status.ReleaseCreationJobResult, releasePayload.Status.ReleaseCreationJobResult) {
+		releasepayloadhelpers.AppendStatusHistory(releasePayload, metav1.NewTime(c.clock.Now()))
+	}
+	releasepayloadhelpers.CanonicalizeReleasePayloadStatus(releasePayload)
+
+	klog.V(4).Infof("Release creation job coordinates not set for ReleasePayload: %s/%s", releasePayload.Namespace, releasePayload.Name)
+	err := c.updateStatus(ctx, releasePayload)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Nothing transitioned if the update was a dry run
+	if c.dryRun {
+		return result
+	}
+	c.metrics.recordTransition(originalReleasePayload.Status.ReleaseCreationJobResult.Status, releasePayload.Status.ReleaseCreationJobResult.Status, nil)
+	c.metrics.recordTimeToStatus(originalReleasePayload, originalReleasePayload.Status.ReleaseCreationJobResult.Status, releasePayload.Status.ReleaseCreationJobResult.Status, c.clock.Now())
+	return result
+}
+
+// updateJobLabels patches the release.openshift.io/* labels, of the specified job, to match the ReleasePayload
+func (c *ReleaseCreationStatusController) updateJobLabels(ctx context.Context, job *batchv1.Job, releasePayload *v1alpha1.ReleasePayload) error {
+	labels := shouldUpdateJobLabels(job, releasePayload)
+	if len(labels) == 0 {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": labels,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	opts := metav1.PatchOptions{}
+	if c.dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+		klog.Infof("Dry run: would update the labels of release creation job %s/%s: %v", job.Namespace, job.Name, labels)
+	} else {
+		klog.V(4).Infof("Updating labels of release creation job %s/%s: %v", job.Namespace, job.Name, labels)
+	}
+	_, err = c.batchJobClient.Jobs(job.Namespace).Patch(ctx, job.Name, types.MergePatchType, patch, opts)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// shouldUpdateJobLabels returns the release.openshift.io/* labels, of the ReleasePayload, that are either missing
+// from, or different on, the specified job
+func shouldUpdateJobLabels(job *batchv1.Job, releasePayload *v1alpha1.ReleasePayload) map[string]string {
+	labels := make(map[string]string)
+	for key, value := range releasePayload.Labels {
+		if !strings.HasPrefix(key, releasePayloadLabelPrefix) {
+			continue
+		}
+		if current, ok := job.Labels[key]; !ok || current != value {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// jobStatusSnapshot holds the fields, of the status of a batchv1.Job, that determine the status of a release creation
+// job.  Taking a snapshot, by value, means the status is computed from a single, consistent, view of the job even if
+// the job is modified concurrently.
+type jobStatusSnapshot struct {
+	completed bool
+	failed    bool
+	active    int32
+	ready     int32
+}
+
+func newJobStatusSnapshot(job *batchv1.Job) jobStatusSnapshot {
+	snapshot := jobStatusSnapshot{
+		completed: isJobCompleted(job),
+		failed:    isJobFailed(job),
+		active:    job.Status.Active,
+	}
+	if job.Status.Ready != nil {
+		snapshot.ready = *job.Status.Ready
+	}
+	return snapshot
+}
+
+func computeReleaseCreationJobStatus(job jobStatusSnapshot) v1alpha1.ReleaseCreationJobStatus {
+	switch {
+	// A job reporting both a CompletionTime and a Failed condition is inconsistent, so we cannot trust either one
+	case job.completed && job.failed:
+		return v1alpha1.ReleaseCreationJobUnknown
+	case job.completed:
 		return v1alpha1.ReleaseCreationJobSuccess
+	case job.failed:
+		return v1alpha1.ReleaseCreationJobFailed
+	case job.active > 0 || job.ready > 0:
+		return v1alpha1.ReleaseCreationJobPending
 	}
+	return v1alpha1.ReleaseCreationJobUnknown
+}
+
+// releaseCreationJobIncomplete returns true if the release creation job has not, as far as we know, completed
+func releaseCreationJobIncomplete(status v1alpha1.ReleaseCreationJobStatus) bool {
+	return status == v1alpha1.ReleaseCreationJobUnknown || status == v1alpha1.ReleaseCreationJobPending
+}
+
+func isJobFailed(job *batchv1.Job) bool {
 	for _, condition := range job.Status.Conditions {
 		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
-			return v1alpha1.ReleaseCreationJobFailed
+			return true
 		}
 	}
-	return v1alpha1.ReleaseCreationJobUnknown
+	return false
 }
 
-func computeReleaseCreationJobMessage(job *batchv1.Job) string {
+// isJobCompleted returns true if the job reports a CompletionTime, a Complete condition or, for an Indexed job, if
+// every one of its indexes has completed
+func isJobCompleted(job *batchv1.Job) bool {
 	if job.Status.CompletionTime != nil {
-		return ReleaseCreationJobSuccessMessage
+		return true
 	}
 	for _, condition := range job.Status.Conditions {
-		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
-			switch {
-			case len(condition.Reason) > 0 && len(condition.Message) > 0:
-				return fmt.Sprintf("%s: %s", condition.Reason, condition.Message)
-			default:
-				return ReleaseCreationJobFailureMessage
+		if condition.Type == batchv1.JobComplete && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	if job.Spec.CompletionMode == nil || *job.Spec.CompletionMode != batchv1.IndexedCompletion || job.Spec.Completions == nil {
+		return false
+	}
+	return allIndexesCompleted(job.Status.CompletedIndexes, *job.Spec.Completions)
+}
+
+// allIndexesCompleted returns true if the completedIndexes, of an Indexed job, cover every index from 0 to
+// completions-1.  The completedIndexes are a comma separated list of indexes and of ranges of indexes
+// (i.e. "1,3-5,7").  A malformed list is never considered complete.
+func allIndexesCompleted(completedIndexes string, completions int32) bool {
+	if completions <= 0 || len(completedIndexes) == 0 {
+		return false
+	}
+	completed := make(map[int64]bool, completions)
+	for _, interval := range strings.Split(completedIndexes, ",") {
+		first, last, isRange := strings.Cut(interval, "-")
+		start, err := strconv.ParseInt(first, 10, 32)
+		if err != nil {
+			klog.V(4).Infof("Unable to parse completed indexes %q: %v", completedIndexes, err)
+			return false
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.ParseInt(last, 10, 32); err != nil {
+				klog.V(4).Infof("Unable to parse completed indexes %q: %v", completedIndexes, err)
+				return false
 			}
 		}
+		for index := start; index <= end && index < int64(completions); index++ {
+			if index >= 0 {
+				completed[index] = true
+			}
+		}
+	}
+	return len(completed) == int(completions)
+}
+
+func computeReleaseCreationJobMessage(job *batchv1.Job) string {
+	if isJobCompleted(job) {
+		if isJobFailed(job) {
+			return ReleaseCreationJobUnknownMessage
+		}
+		return ReleaseCreationJobSuccessMessage
+	}
+	// A job can report more than one JobFailed condition (i.e. pod failure and deadline exceeded)
+	if isJobFailed(job) {
+		var messages []string
+		for _, condition := range job.Status.Conditions {
+			if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue && len(condition.Reason) > 0 && len(condition.Message) > 0 {
+				messages = append(messages, fmt.Sprintf("%s: %s", condition.Reason, condition.Message))
+			}
+		}
+		if len(messages) == 0 {
+			return ReleaseCreationJobFailureMessage
+		}
+		return strings.Join(messages, "; ")
+	}
+	if job.Status.Ready != nil && *job.Status.Ready >= 1 {
+		return ReleaseCreationJobRunningMessage
 	}
-	if (job.Status.Ready != nil && *job.Status.Ready >= 1) || job.Status.Active >= 1 {
+	if job.Status.Active >= 1 {
 		return ReleaseCreationJobPendingMessage
 	}
 	return ReleaseCreationJobUnknownMessage
@@ -0,0 +1,190 @@
+//go:build e2e
+// +build e2e
+
+package release_payload_controller
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	imagefake "github.com/openshift/client-go/image/clientset/versioned/fake"
+	imageinformers "github.com/openshift/client-go/image/informers/externalversions"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	batchv1informers "k8s.io/client-go/informers/batch/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+// TestReleaseCreationStatusControllerE2E runs the ReleaseCreationStatusController against a real API server, started
+// by envtest, and verifies that the outcome of a release creation job is reflected on its ReleasePayload.  There is no
+// kube-controller-manager, so the test plays the part of the job controller and sets the status of the jobs itself.
+//
+// Run with: make test-e2e
+func TestReleaseCreationStatusControllerE2E(t *testing.T) {
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "..", "artifacts")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		t.Fatalf("unable to start the test environment: %v", err)
+	}
+	defer func() {
+		if err := testEnv.Stop(); err != nil {
+			t.Errorf("unable to stop the test environment: %v", err)
+		}
+	}()
+
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		t.Fatalf("unable to create kubernetes client: %v", err)
+	}
+	releasePayloadClient, err := releasepayloadclient.NewForConfig(cfg)
+	if err != nil {
+		t.Fatalf("unable to create releasepayload client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, namespace := range []string{"ocp", "ci-release"} {
+		if _, err := kubeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("unable to create namespace %s: %v", namespace, err)
+		}
+	}
+
+	// Deploy the controller
+	kubeFactories := newJobInformerFactories(kubeClient, []string{"ci-release"}, nil)
+	batchJobInformers := make(map[string]batchv1informers.JobInformer)
+	for namespace, factory := range kubeFactories {
+		batchJobInformers[namespace] = factory.Batch().V1().Jobs()
+	}
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+	// envtest does not serve the image API, so the release imagestreams are faked
+	imageStreamInformerFactory := imageinformers.NewSharedInformerFactory(imagefake.NewSimpleClientset(), controllerDefaultResyncDuration)
+
+	c, err := NewReleaseCreationStatusController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), batchJobInformers, kubeClient.BatchV1(), imageStreamInformerFactory.Image().V1().ImageStreams(), kubeClient.CoreV1(), events.NewInMemoryRecorder("release-creation-status-controller-e2e"), 0, 0, 1, false)
+	if err != nil {
+		t.Fatalf("unable to create controller: %v", err)
+	}
+
+	for _, factory := range kubeFactories {
+		factory.Start(ctx.Done())
+	}
+	releasePayloadInformerFactory.Start(ctx.Done())
+	imageStreamInformerFactory.Start(ctx.Done())
+	go c.RunWorkers(ctx, 1)
+
+	testCases := []struct {
+		name      string
+		release   string
+		condition batchv1.JobConditionType
+		expected  v1alpha1.ReleaseCreationJobStatus
+	}{
+		{
+			name:      "JobSucceeded",
+			release:   "4.11.0-0.nightly-2022-02-09-091559",
+			condition: batchv1.JobComplete,
+			expected:  v1alpha1.ReleaseCreationJobSuccess,
+		},
+		{
+			name:      "JobFailed",
+			release:   "4.11.0-0.nightly-2022-02-09-101559",
+			condition: batchv1.JobFailed,
+			expected:  v1alpha1.ReleaseCreationJobFailed,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			job, err := kubeClient.BatchV1().Jobs("ci-release").Create(ctx, newE2EReleaseCreationJob(testCase.release), metav1.CreateOptions{})
+			if err != nil {
+				t.Fatalf("unable to create job: %v", err)
+			}
+
+			payload, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads("ocp").Create(ctx, &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testCase.release,
+					Namespace: "ocp",
+				},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				t.Fatalf("unable to create releasepayload: %v", err)
+			}
+			payload.Status.ReleaseCreationJobResult.Coordinates = v1alpha1.ReleaseCreationJobCoordinates{
+				Name:      job.Name,
+				Namespace: job.Namespace,
+			}
+			if _, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads("ocp").UpdateStatus(ctx, payload, metav1.UpdateOptions{}); err != nil {
+				t.Fatalf("unable to update releasepayload status: %v", err)
+			}
+
+			// Until the job reports any progress, the outcome is Unknown
+			g.Eventually(func() v1alpha1.ReleaseCreationJobStatus {
+				return getReleaseCreationJobStatus(ctx, releasePayloadClient, testCase.release)
+			}, 60*time.Second, time.Second).Should(gomega.Equal(v1alpha1.ReleaseCreationJobUnknown))
+
+			now := metav1.Now()
+			job.Status.StartTime = &now
+			job.Status.Conditions = []batchv1.JobCondition{
+				{
+					Type:               testCase.condition,
+					Status:             corev1.ConditionTrue,
+					LastProbeTime:      now,
+					LastTransitionTime: now,
+				},
+			}
+			switch testCase.condition {
+			case batchv1.JobComplete:
+				job.Status.CompletionTime = &now
+				job.Status.Succeeded = 1
+			case batchv1.JobFailed:
+				job.Status.Failed = 1
+			}
+			if _, err := kubeClient.BatchV1().Jobs("ci-release").UpdateStatus(ctx, job, metav1.UpdateOptions{}); err != nil {
+				t.Fatalf("unable to update job status: %v", err)
+			}
+
+			g.Eventually(func() v1alpha1.ReleaseCreationJobStatus {
+				return getReleaseCreationJobStatus(ctx, releasePayloadClient, testCase.release)
+			}, 60*time.Second, time.Second).Should(gomega.Equal(testCase.expected))
+		})
+	}
+}
+
+func newE2EReleaseCreationJob(release string) *batchv1.Job {
+	job := newReleaseCreationJob("ci-release", release, "ocp/release")
+	job.Spec = batchv1.JobSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				RestartPolicy: corev1.RestartPolicyNever,
+				Containers: []corev1.Container{
+					{
+						Name:    "build",
+						Image:   "registry.ci.openshift.org/ocp/4.11:cli",
+						Command: []string{"/bin/true"},
+					},
+				},
+			},
+		},
+	}
+	return job
+}
+
+func getReleaseCreationJobStatus(ctx context.Context, client releasepayloadclient.Interface, release string) v1alpha1.ReleaseCreationJobStatus {
+	payload, err := client.ReleaseV1alpha1().ReleasePayloads("ocp").Get(ctx, release, metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	return payload.Status.ReleaseCreationJobResult.Status
+}
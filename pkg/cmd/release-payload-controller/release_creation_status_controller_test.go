@@ -2,27 +2,40 @@ package release_payload_controller
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	imagev1 "github.com/openshift/api/image/v1"
 	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
 	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
-	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
-	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
+	releasepayloadfake "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1/fake"
 	releasecontroller "github.com/openshift/release-controller/pkg/release-controller"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/informers"
-	fake2 "k8s.io/client-go/kubernetes/fake"
-	"k8s.io/client-go/tools/cache"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	fakecorev1 "k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/util/workqueue"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+	"pgregory.net/rapid"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
 
 func TestComputeReleaseCreationJobStatus(t *testing.T) {
+	indexedCompletion := batchv1.IndexedCompletion
+
 	testCases := []struct {
 		name     string
 		job      *batchv1.Job
@@ -43,6 +56,42 @@ func TestComputeReleaseCreationJobStatus(t *testing.T) {
 			},
 			expected: v1alpha1.ReleaseCreationJobSuccess,
 		},
+		{
+			name: "JobStatusConditionCompleteSetWithoutCompletionTime",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Status: batchv1.JobStatus{
+					Conditions: []batchv1.JobCondition{
+						{
+							Type:   batchv1.JobComplete,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+			expected: v1alpha1.ReleaseCreationJobSuccess,
+		},
+		{
+			name: "JobStatusConditionCompleteFalse",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Status: batchv1.JobStatus{
+					Conditions: []batchv1.JobCondition{
+						{
+							Type:   batchv1.JobComplete,
+							Status: corev1.ConditionFalse,
+						},
+					},
+				},
+			},
+			expected: v1alpha1.ReleaseCreationJobUnknown,
+		},
 		{
 			name: "JobStatusConditionsNotSet",
 			job: &batchv1.Job{
@@ -92,10 +141,175 @@ func TestComputeReleaseCreationJobStatus(t *testing.T) {
 			},
 			expected: v1alpha1.ReleaseCreationJobFailed,
 		},
+		{
+			name: "JobStatusCompletionTimeAndConditionsFailedSet",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Status: batchv1.JobStatus{
+					CompletionTime: &metav1.Time{
+						Time: time.Now(),
+					},
+					Conditions: []batchv1.JobCondition{
+						{
+							Type:   batchv1.JobFailed,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+			expected: v1alpha1.ReleaseCreationJobUnknown,
+		},
+		{
+			name: "JobStatusActive",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Status: batchv1.JobStatus{
+					Active: 1,
+				},
+			},
+			expected: v1alpha1.ReleaseCreationJobPending,
+		},
+		{
+			name: "JobStatusReady",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Status: batchv1.JobStatus{
+					Ready: pointer.Int32(1),
+				},
+			},
+			expected: v1alpha1.ReleaseCreationJobPending,
+		},
+		{
+			name: "JobStatusReadyZero",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Status: batchv1.JobStatus{
+					Ready: pointer.Int32(0),
+				},
+			},
+			expected: v1alpha1.ReleaseCreationJobUnknown,
+		},
+		{
+			name: "JobStatusActiveAndConditionsFailedSet",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Status: batchv1.JobStatus{
+					Active: 1,
+					Conditions: []batchv1.JobCondition{
+						{
+							Type:   batchv1.JobFailed,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+			expected: v1alpha1.ReleaseCreationJobFailed,
+		},
+		{
+			name: "IndexedJobNoIndexesCompleted",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Spec: batchv1.JobSpec{
+					Completions:    pointer.Int32(3),
+					CompletionMode: &indexedCompletion,
+				},
+				Status: batchv1.JobStatus{
+					Active: 3,
+				},
+			},
+			expected: v1alpha1.ReleaseCreationJobPending,
+		},
+		{
+			name: "IndexedJobSomeIndexesCompleted",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Spec: batchv1.JobSpec{
+					Completions:    pointer.Int32(3),
+					CompletionMode: &indexedCompletion,
+				},
+				Status: batchv1.JobStatus{
+					Active:           1,
+					CompletedIndexes: "0,2",
+				},
+			},
+			expected: v1alpha1.ReleaseCreationJobPending,
+		},
+		{
+			name: "IndexedJobAllIndexesCompleted",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Spec: batchv1.JobSpec{
+					Completions:    pointer.Int32(3),
+					CompletionMode: &indexedCompletion,
+				},
+				Status: batchv1.JobStatus{
+					CompletedIndexes: "0-2",
+				},
+			},
+			expected: v1alpha1.ReleaseCreationJobSuccess,
+		},
+		{
+			name: "IndexedJobAllIndexesCompletedAsList",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Spec: batchv1.JobSpec{
+					Completions:    pointer.Int32(3),
+					CompletionMode: &indexedCompletion,
+				},
+				Status: batchv1.JobStatus{
+					CompletedIndexes: "0,1,2",
+				},
+			},
+			expected: v1alpha1.ReleaseCreationJobSuccess,
+		},
+		{
+			name: "IndexedJobMalformedIndexes",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Spec: batchv1.JobSpec{
+					Completions:    pointer.Int32(3),
+					CompletionMode: &indexedCompletion,
+				},
+				Status: batchv1.JobStatus{
+					CompletedIndexes: "0-two",
+				},
+			},
+			expected: v1alpha1.ReleaseCreationJobUnknown,
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			releaseCreationJobStatus := computeReleaseCreationJobStatus(testCase.job)
+			releaseCreationJobStatus := computeReleaseCreationJobStatus(newJobStatusSnapshot(testCase.job))
 
 			if !cmp.Equal(releaseCreationJobStatus, testCase.expected) {
 				t.Errorf("%s: Expected %v, got %v", testCase.name, testCase.expected, releaseCreationJobStatus)
@@ -104,7 +318,192 @@ func TestComputeReleaseCreationJobStatus(t *testing.T) {
 	}
 }
 
+// benchmarkReleaseCreationJob is a failed release creation job, with the conditions of a job that exhausted its
+// retries, so that determining its status has to go through all of its conditions
+var benchmarkReleaseCreationJob = &batchv1.Job{
+	Status: batchv1.JobStatus{
+		Conditions: []batchv1.JobCondition{
+			{
+				Type:   batchv1.JobSuspended,
+				Status: corev1.ConditionFalse,
+			},
+			{
+				Type:   batchv1.JobFailureTarget,
+				Status: corev1.ConditionTrue,
+			},
+			{
+				Type:    batchv1.JobFailed,
+				Status:  corev1.ConditionTrue,
+				Reason:  "BackoffLimitExceeded",
+				Message: "Job has reached the specified backoff limit",
+			},
+		},
+		Failed: 4,
+		Ready:  pointer.Int32(0),
+	},
+}
+
+func BenchmarkNewJobStatusSnapshot(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		newJobStatusSnapshot(benchmarkReleaseCreationJob)
+	}
+}
+
+func BenchmarkComputeReleaseCreationJobStatus(b *testing.B) {
+	snapshot := newJobStatusSnapshot(benchmarkReleaseCreationJob)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		computeReleaseCreationJobStatus(snapshot)
+	}
+}
+
+func TestAllIndexesCompleted(t *testing.T) {
+	testCases := []struct {
+		name             string
+		completedIndexes string
+		completions      int32
+		expected         bool
+	}{
+		{
+			name:        "NoIndexesCompleted",
+			completions: 3,
+		},
+		{
+			name:             "FirstIndexCompleted",
+			completedIndexes: "0",
+			completions:      3,
+		},
+		{
+			name:             "GapInIndexes",
+			completedIndexes: "0,2",
+			completions:      3,
+		},
+		{
+			name:             "Range",
+			completedIndexes: "0-2",
+			completions:      3,
+			expected:         true,
+		},
+		{
+			name:             "RangeAndIndexes",
+			completedIndexes: "0,1-2",
+			completions:      3,
+			expected:         true,
+		},
+		{
+			name:             "IndexesBeyondCompletions",
+			completedIndexes: "1-4",
+			completions:      3,
+		},
+		{
+			name:             "Malformed",
+			completedIndexes: "0,1,",
+			completions:      2,
+		},
+		{
+			name:             "NoCompletions",
+			completedIndexes: "0",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if completed := allIndexesCompleted(testCase.completedIndexes, testCase.completions); completed != testCase.expected {
+				t.Errorf("%s: Expected %t, got %t", testCase.name, testCase.expected, completed)
+			}
+		})
+	}
+}
+
+func TestComputeReleaseCreationJobStatusProperties(t *testing.T) {
+	// rapid only reads the number of checks from its flag, which is restored so that it does not leak into other tests
+	checks := flag.Lookup("rapid.checks").Value.String()
+	if err := flag.Set("rapid.checks", "10000"); err != nil {
+		t.Fatalf("unable to set the number of checks: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := flag.Set("rapid.checks", checks); err != nil {
+			t.Errorf("unable to restore the number of checks: %v", err)
+		}
+	})
+
+	validStatuses := sets.New[v1alpha1.ReleaseCreationJobStatus](
+		v1alpha1.ReleaseCreationJobUnknown,
+		v1alpha1.ReleaseCreationJobPending,
+		v1alpha1.ReleaseCreationJobSuccess,
+		v1alpha1.ReleaseCreationJobFailed,
+	)
+	conditionTypes := []batchv1.JobConditionType{
+		batchv1.JobSuspended,
+		batchv1.JobComplete,
+		batchv1.JobFailed,
+		batchv1.JobFailureTarget,
+	}
+	conditionStatuses := []corev1.ConditionStatus{
+		corev1.ConditionTrue,
+		corev1.ConditionFalse,
+		corev1.ConditionUnknown,
+	}
+
+	jobCondition := rapid.Custom(func(t *rapid.T) batchv1.JobCondition {
+		return batchv1.JobCondition{
+			Type:    rapid.SampledFrom(conditionTypes).Draw(t, "type"),
+			Status:  rapid.SampledFrom(conditionStatuses).Draw(t, "status"),
+			Reason:  rapid.String().Draw(t, "reason"),
+			Message: rapid.String().Draw(t, "message"),
+		}
+	})
+
+	rapid.Check(t, func(t *rapid.T) {
+		jobStatus := batchv1.JobStatus{
+			Conditions: rapid.SliceOfN(jobCondition, 0, 5).Draw(t, "conditions"),
+			Active:     rapid.Int32Range(0, 5).Draw(t, "active"),
+			Succeeded:  rapid.Int32Range(0, 5).Draw(t, "succeeded"),
+			Failed:     rapid.Int32Range(0, 5).Draw(t, "failed"),
+		}
+		if rapid.Bool().Draw(t, "completionTimeSet") {
+			jobStatus.CompletionTime = &metav1.Time{Time: time.Unix(rapid.Int64Range(0, 1<<32).Draw(t, "completionTime"), 0)}
+		}
+		if rapid.Bool().Draw(t, "readySet") {
+			ready := rapid.Int32Range(0, 5).Draw(t, "ready")
+			jobStatus.Ready = &ready
+		}
+
+		failed := false
+		completed := jobStatus.CompletionTime != nil
+		for _, condition := range jobStatus.Conditions {
+			if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+				failed = true
+			}
+			if condition.Type == batchv1.JobComplete && condition.Status == corev1.ConditionTrue {
+				completed = true
+			}
+		}
+
+		status := computeReleaseCreationJobStatus(newJobStatusSnapshot(&batchv1.Job{Status: jobStatus}))
+
+		if !validStatuses.Has(status) {
+			t.Fatalf("unexpected status: %q", status)
+		}
+		if completed && status == v1alpha1.ReleaseCreationJobFailed {
+			t.Fatalf("completed job reported as %q", status)
+		}
+		if failed && status == v1alpha1.ReleaseCreationJobSuccess {
+			t.Fatalf("job with a %s condition reported as %q", batchv1.JobFailed, status)
+		}
+		running := jobStatus.Active > 0 || (jobStatus.Ready != nil && *jobStatus.Ready > 0)
+		if status == v1alpha1.ReleaseCreationJobPending && (!running || failed || completed) {
+			t.Fatalf("job that is not running reported as %q", status)
+		}
+		if running && !failed && !completed && status != v1alpha1.ReleaseCreationJobPending {
+			t.Fatalf("running job reported as %q", status)
+		}
+	})
+}
+
 func TestReleaseCreationStatusSync(t *testing.T) {
+	var ready int32 = 1
+	startTime := metav1.NewTime(time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC))
+
 	testCases := []struct {
 		name        string
 		job         runtime.Object
@@ -126,11 +525,25 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 					Name:      "4.11.0-0.nightly-2022-02-09-091559",
 					Namespace: "ocp",
 				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:    v1alpha1.ConditionPayloadReady,
+							Status:  metav1.ConditionFalse,
+							Reason:  CreationJobPendingReason,
+							Message: ReleaseCreationJobWaitingForCoordinatesMessage,
+						},
+					},
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Status:  v1alpha1.ReleaseCreationJobUnknown,
+						Message: ReleaseCreationJobWaitingForCoordinatesMessage,
+					},
+				},
 			},
 			expectedErr: ErrCoordinatesNotSet,
 		},
 		{
-			name: "ReleasePayloadStatusSetWithNoJob",
+			name: "ReleasePayloadCoordinatesPartiallySet",
 			job:  &batchv1.Job{},
 			input: &v1alpha1.ReleasePayload{
 				ObjectMeta: metav1.ObjectMeta{
@@ -140,10 +553,10 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 				Status: v1alpha1.ReleasePayloadStatus{
 					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
 						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
-							Name:      "4.11.0-0.nightly-2022-02-09-091559",
 							Namespace: "ci-release",
 						},
-						Status: v1alpha1.ReleaseCreationJobUnknown,
+						Status:  v1alpha1.ReleaseCreationJobUnknown,
+						Message: ReleaseCreationJobUnknownMessage,
 					},
 				},
 			},
@@ -153,30 +566,28 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 					Namespace: "ocp",
 				},
 				Status: v1alpha1.ReleasePayloadStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:    v1alpha1.ConditionPayloadReady,
+							Status:  metav1.ConditionFalse,
+							Reason:  CreationJobPendingReason,
+							Message: ReleaseCreationJobWaitingForCoordinatesMessage,
+						},
+					},
 					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
 						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
-							Name:      "4.11.0-0.nightly-2022-02-09-091559",
 							Namespace: "ci-release",
 						},
 						Status:  v1alpha1.ReleaseCreationJobUnknown,
-						Message: ReleaseCreationJobUnknownMessage,
+						Message: ReleaseCreationJobWaitingForCoordinatesMessage,
 					},
 				},
 			},
+			expectedErr: ErrCoordinatesNotSet,
 		},
 		{
-			name: "ReleasePayloadStatusSetWithCompleteJob",
-			job: &batchv1.Job{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "4.11.0-0.nightly-2022-02-09-091559",
-					Namespace: "ci-release",
-				},
-				Status: batchv1.JobStatus{
-					CompletionTime: &metav1.Time{
-						Time: time.Now(),
-					},
-				},
-			},
+			name: "ReleasePayloadStatusSetWithNoJob",
+			job:  &batchv1.Job{},
 			input: &v1alpha1.ReleasePayload{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "4.11.0-0.nightly-2022-02-09-091559",
@@ -198,16 +609,91 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 					Namespace: "ocp",
 				},
 				Status: v1alpha1.ReleasePayloadStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:    v1alpha1.ConditionPayloadReady,
+							Status:  metav1.ConditionFalse,
+							Reason:  CreationJobPendingReason,
+							Message: ReleaseCreationJobUnknownMessage,
+						},
+					},
 					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
 						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
 							Name:      "4.11.0-0.nightly-2022-02-09-091559",
 							Namespace: "ci-release",
 						},
-						Status:  v1alpha1.ReleaseCreationJobSuccess,
-						Message: ReleaseCreationJobSuccessMessage,
+						Status:  v1alpha1.ReleaseCreationJobUnknown,
+						Message: ReleaseCreationJobUnknownMessage,
 					},
-				},
-			},
+					StatusHistory: []v1alpha1.ReleasePayloadStatusSnapshot{
+						{
+							Status:  v1alpha1.ReleaseCreationJobUnknown,
+							Message: ReleaseCreationJobUnknownMessage,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "ReleasePayloadStatusSetWithCompleteJob",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Status: batchv1.JobStatus{
+					StartTime: &startTime,
+					CompletionTime: &metav1.Time{
+						Time: time.Now(),
+					},
+				},
+			},
+			input: &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+							Name:      "4.11.0-0.nightly-2022-02-09-091559",
+							Namespace: "ci-release",
+						},
+						Status: v1alpha1.ReleaseCreationJobUnknown,
+					},
+				},
+			},
+			expected: &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:    v1alpha1.ConditionPayloadReady,
+							Status:  metav1.ConditionTrue,
+							Reason:  CreationJobSucceededReason,
+							Message: ReleaseCreationJobSuccessMessage,
+						},
+					},
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+							Name:      "4.11.0-0.nightly-2022-02-09-091559",
+							Namespace: "ci-release",
+						},
+						Status:    v1alpha1.ReleaseCreationJobSuccess,
+						Message:   ReleaseCreationJobSuccessMessage,
+						StartTime: &startTime,
+					},
+					StatusHistory: []v1alpha1.ReleasePayloadStatusSnapshot{
+						{
+							Status:  v1alpha1.ReleaseCreationJobSuccess,
+							Message: ReleaseCreationJobSuccessMessage,
+						},
+					},
+				},
+			},
 		},
 		{
 			name: "ReleasePayloadStatusSetWithFailedJob",
@@ -246,6 +732,14 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 					Namespace: "ocp",
 				},
 				Status: v1alpha1.ReleasePayloadStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:    v1alpha1.ConditionPayloadReady,
+							Status:  metav1.ConditionFalse,
+							Reason:  CreationJobFailedReason,
+							Message: ReleaseCreationJobFailureMessage,
+						},
+					},
 					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
 						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
 							Name:      "4.11.0-0.nightly-2022-02-09-091559",
@@ -254,6 +748,12 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 						Status:  v1alpha1.ReleaseCreationJobFailed,
 						Message: ReleaseCreationJobFailureMessage,
 					},
+					StatusHistory: []v1alpha1.ReleasePayloadStatusSnapshot{
+						{
+							Status:  v1alpha1.ReleaseCreationJobFailed,
+							Message: ReleaseCreationJobFailureMessage,
+						},
+					},
 				},
 			},
 		},
@@ -265,6 +765,7 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 					Namespace: "ci-release",
 				},
 				Status: batchv1.JobStatus{
+					Failed: 2,
 					Conditions: []batchv1.JobCondition{
 						{
 							Type:    batchv1.JobFailed,
@@ -296,13 +797,147 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 					Namespace: "ocp",
 				},
 				Status: v1alpha1.ReleasePayloadStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:    v1alpha1.ConditionPayloadReady,
+							Status:  metav1.ConditionFalse,
+							Reason:  CreationJobFailedReason,
+							Message: "BackoffLimitExceeded: Job has reached the specified backoff limit",
+						},
+					},
 					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
 						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
 							Name:      "4.11.0-0.nightly-2022-02-09-091559",
 							Namespace: "ci-release",
 						},
-						Status:  v1alpha1.ReleaseCreationJobFailed,
-						Message: "BackoffLimitExceeded: Job has reached the specified backoff limit",
+						Status:   v1alpha1.ReleaseCreationJobFailed,
+						Message:  "BackoffLimitExceeded: Job has reached the specified backoff limit",
+						Attempts: 2,
+					},
+					StatusHistory: []v1alpha1.ReleasePayloadStatusSnapshot{
+						{
+							Status:  v1alpha1.ReleaseCreationJobFailed,
+							Message: "BackoffLimitExceeded: Job has reached the specified backoff limit",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "ReleasePayloadStatusSetWithActiveJob",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Status: batchv1.JobStatus{
+					StartTime: &startTime,
+					Active:    1,
+				},
+			},
+			input: &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+							Name:      "4.11.0-0.nightly-2022-02-09-091559",
+							Namespace: "ci-release",
+						},
+						Status: v1alpha1.ReleaseCreationJobUnknown,
+					},
+				},
+			},
+			expected: &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:    v1alpha1.ConditionPayloadReady,
+							Status:  metav1.ConditionFalse,
+							Reason:  CreationJobPendingReason,
+							Message: ReleaseCreationJobPendingMessage,
+						},
+					},
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+							Name:      "4.11.0-0.nightly-2022-02-09-091559",
+							Namespace: "ci-release",
+						},
+						Status:    v1alpha1.ReleaseCreationJobPending,
+						Message:   ReleaseCreationJobPendingMessage,
+						StartTime: &startTime,
+					},
+					StatusHistory: []v1alpha1.ReleasePayloadStatusSnapshot{
+						{
+							Status:  v1alpha1.ReleaseCreationJobPending,
+							Message: ReleaseCreationJobPendingMessage,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "ReleasePayloadStatusSetWithRunningJob",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Status: batchv1.JobStatus{
+					StartTime: &startTime,
+					Active:    1,
+					Ready:     &ready,
+				},
+			},
+			input: &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+							Name:      "4.11.0-0.nightly-2022-02-09-091559",
+							Namespace: "ci-release",
+						},
+						Status: v1alpha1.ReleaseCreationJobUnknown,
+					},
+				},
+			},
+			expected: &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:    v1alpha1.ConditionPayloadReady,
+							Status:  metav1.ConditionFalse,
+							Reason:  CreationJobPendingReason,
+							Message: ReleaseCreationJobRunningMessage,
+						},
+					},
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+							Name:      "4.11.0-0.nightly-2022-02-09-091559",
+							Namespace: "ci-release",
+						},
+						Status:    v1alpha1.ReleaseCreationJobPending,
+						Message:   ReleaseCreationJobRunningMessage,
+						StartTime: &startTime,
+					},
+					StatusHistory: []v1alpha1.ReleasePayloadStatusSnapshot{
+						{
+							Status:  v1alpha1.ReleaseCreationJobPending,
+							Message: ReleaseCreationJobRunningMessage,
+						},
 					},
 				},
 			},
@@ -343,6 +978,14 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 					Namespace: "ocp",
 				},
 				Status: v1alpha1.ReleasePayloadStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:    v1alpha1.ConditionPayloadReady,
+							Status:  metav1.ConditionFalse,
+							Reason:  CreationJobPendingReason,
+							Message: ReleaseCreationJobUnknownMessage,
+						},
+					},
 					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
 						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
 							Name:      "4.11.0-0.nightly-2022-02-09-091559",
@@ -351,6 +994,12 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 						Status:  v1alpha1.ReleaseCreationJobUnknown,
 						Message: ReleaseCreationJobUnknownMessage,
 					},
+					StatusHistory: []v1alpha1.ReleasePayloadStatusSnapshot{
+						{
+							Status:  v1alpha1.ReleaseCreationJobUnknown,
+							Message: ReleaseCreationJobUnknownMessage,
+						},
+					},
 				},
 			},
 		},
@@ -387,6 +1036,14 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 					Namespace: "ocp",
 				},
 				Status: v1alpha1.ReleasePayloadStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:    v1alpha1.ConditionPayloadReady,
+							Status:  metav1.ConditionTrue,
+							Reason:  CreationJobSucceededReason,
+							Message: ReleaseCreationJobSuccessMessage,
+						},
+					},
 					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
 						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
 							Name:      "4.11.0-0.nightly-2022-02-09-091559",
@@ -395,6 +1052,12 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 						Status:  v1alpha1.ReleaseCreationJobSuccess,
 						Message: ReleaseCreationJobSuccessMessage,
 					},
+					StatusHistory: []v1alpha1.ReleasePayloadStatusSnapshot{
+						{
+							Status:  v1alpha1.ReleaseCreationJobSuccess,
+							Message: ReleaseCreationJobSuccessMessage,
+						},
+					},
 				},
 			},
 		},
@@ -421,6 +1084,14 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 					Namespace: "ocp",
 				},
 				Status: v1alpha1.ReleasePayloadStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:    v1alpha1.ConditionPayloadReady,
+							Status:  metav1.ConditionFalse,
+							Reason:  CreationJobPendingReason,
+							Message: ReleaseCreationJobUnknownMessage,
+						},
+					},
 					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
 						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
 							Name:      "4.11.0-0.nightly-2022-02-09-091559",
@@ -429,6 +1100,12 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 						Status:  v1alpha1.ReleaseCreationJobUnknown,
 						Message: ReleaseCreationJobUnknownMessage,
 					},
+					StatusHistory: []v1alpha1.ReleasePayloadStatusSnapshot{
+						{
+							Status:  v1alpha1.ReleaseCreationJobUnknown,
+							Message: ReleaseCreationJobUnknownMessage,
+						},
+					},
 				},
 			},
 		},
@@ -436,73 +1113,10 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			kubeClient := fake2.NewSimpleClientset(testCase.job)
-			kubeFactory := informers.NewSharedInformerFactory(kubeClient, controllerDefaultResyncDuration)
-			batchJobInformer := kubeFactory.Batch().V1().Jobs()
-
-			releasePayloadClient := fake.NewSimpleClientset(testCase.input)
-			releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
-			releasePayloadInformer := releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads()
-
-			c := &ReleaseCreationStatusController{
-				ReleasePayloadController: NewReleasePayloadController("Release Creation Status Controller",
-					releasePayloadInformer,
-					releasePayloadClient.ReleaseV1alpha1(),
-					events.NewInMemoryRecorder("release-creation-status-controller-test"),
-					workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ReleaseCreationStatusController")),
-				batchJobLister: batchJobInformer.Lister(),
-			}
-			c.cachesToSync = append(c.cachesToSync, batchJobInformer.Informer().HasSynced)
-
-			batchJobFilter := func(obj interface{}) bool {
-				if batchJob, ok := obj.(*batchv1.Job); ok {
-					if _, ok := batchJob.Annotations[releasecontroller.ReleaseAnnotationReleaseTag]; ok {
-						return true
-					}
-				}
-				return false
-			}
-
-			batchJobInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
-				FilterFunc: batchJobFilter,
-				Handler: cache.ResourceEventHandlerFuncs{
-					AddFunc:    c.lookupReleasePayload,
-					UpdateFunc: func(old, new interface{}) { c.lookupReleasePayload(new) },
-					DeleteFunc: c.lookupReleasePayload,
-				},
-			})
-
-			// In case someone/something deletes the ReleaseCreationJobResult.Status, try and rectify it...
-			releasePayloadFilter := func(obj interface{}) bool {
-				if releasePayload, ok := obj.(*v1alpha1.ReleasePayload); ok {
-					switch {
-					// Check that we have the necessary information to proceed
-					case len(releasePayload.Status.ReleaseCreationJobResult.Coordinates.Namespace) == 0 || len(releasePayload.Status.ReleaseCreationJobResult.Coordinates.Name) == 0:
-						return false
-					// Check if we need to process this ReleasePayload at all
-					case len(releasePayload.Status.ReleaseCreationJobResult.Status) == 0 || len(releasePayload.Status.ReleaseCreationJobResult.Message) == 0:
-						return true
-					}
-				}
-				return false
-			}
-
-			releasePayloadInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
-				FilterFunc: releasePayloadFilter,
-				Handler: cache.ResourceEventHandlerFuncs{
-					AddFunc:    c.Enqueue,
-					UpdateFunc: func(old, new interface{}) { c.Enqueue(new) },
-					DeleteFunc: c.Enqueue,
-				},
-			})
-
-			releasePayloadInformerFactory.Start(context.Background().Done())
-			kubeFactory.Start(context.Background().Done())
-
-			if !cache.WaitForNamedCacheSync("ReleaseCreationStatusController", context.Background().Done(), c.cachesToSync...) {
-				t.Errorf("%s: error waiting for caches to sync", testCase.name)
-				return
-			}
+			c := newReleasePayloadControllerTestBuilder(t).
+				WithReleasePayload(testCase.input).
+				WithKubeObject(testCase.job).
+				Build()
 
 			err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", testCase.input.Namespace, testCase.input.Name))
 			if err != nil && err != testCase.expectedErr {
@@ -511,7 +1125,7 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 
 			// Performing a live lookup instead of having to wait for the cache to sink (again)...
 			output, err := c.releasePayloadClient.ReleasePayloads(testCase.input.Namespace).Get(context.TODO(), testCase.input.Name, metav1.GetOptions{})
-			if !cmp.Equal(output, testCase.expected, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")) {
+			if !cmp.Equal(output, testCase.expected, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime"), cmpopts.IgnoreFields(v1alpha1.ReleasePayloadStatusSnapshot{}, "TransitionTime")) {
 				t.Errorf("%s: Expected %v, got %v", testCase.name, testCase.expected, output)
 			}
 		})
@@ -520,6 +1134,7 @@ func TestReleaseCreationStatusSync(t *testing.T) {
 
 func TestComputeReleaseCreationJobMessage(t *testing.T) {
 	var value int32 = 1
+	var zero int32 = 0
 	testCases := []struct {
 		name     string
 		job      *batchv1.Job
@@ -573,6 +1188,50 @@ func TestComputeReleaseCreationJobMessage(t *testing.T) {
 			},
 			expected: "BackoffLimitExceeded: Job has reached the specified backoff limit",
 		},
+		{
+			name: "JobStatusMultipleConditionsFailedSet",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Status: batchv1.JobStatus{
+					Conditions: []batchv1.JobCondition{
+						{
+							Type:    batchv1.JobFailed,
+							Status:  corev1.ConditionTrue,
+							Reason:  "BackoffLimitExceeded",
+							Message: "Job has reached the specified backoff limit",
+						},
+						{
+							Type:    batchv1.JobFailed,
+							Status:  corev1.ConditionTrue,
+							Reason:  "DeadlineExceeded",
+							Message: "Job was active longer than specified deadline",
+						},
+					},
+				},
+			},
+			expected: "BackoffLimitExceeded: Job has reached the specified backoff limit; DeadlineExceeded: Job was active longer than specified deadline",
+		},
+		{
+			name: "JobStatusConditionsFailedSetWithoutMessage",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Status: batchv1.JobStatus{
+					Conditions: []batchv1.JobCondition{
+						{
+							Type:   batchv1.JobFailed,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+			expected: ReleaseCreationJobFailureMessage,
+		},
 		{
 			name: "JobStatusReady",
 			job: &batchv1.Job{
@@ -584,7 +1243,21 @@ func TestComputeReleaseCreationJobMessage(t *testing.T) {
 					Ready: &value,
 				},
 			},
-			expected: ReleaseCreationJobPendingMessage,
+			expected: ReleaseCreationJobRunningMessage,
+		},
+		{
+			name: "JobStatusActiveAndReady",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Status: batchv1.JobStatus{
+					Active: 1,
+					Ready:  &value,
+				},
+			},
+			expected: ReleaseCreationJobRunningMessage,
 		},
 		{
 			name: "JobStatusActive",
@@ -599,6 +1272,20 @@ func TestComputeReleaseCreationJobMessage(t *testing.T) {
 			},
 			expected: ReleaseCreationJobPendingMessage,
 		},
+		{
+			name: "JobStatusActiveAndNotReady",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Status: batchv1.JobStatus{
+					Active: 1,
+					Ready:  &zero,
+				},
+			},
+			expected: ReleaseCreationJobPendingMessage,
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -610,3 +1297,2069 @@ func TestComputeReleaseCreationJobMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestShouldUpdateJobLabels(t *testing.T) {
+	testCases := []struct {
+		name          string
+		jobLabels     map[string]string
+		payloadLabels map[string]string
+		expected      map[string]string
+	}{
+		{
+			name:     "NoLabels",
+			expected: map[string]string{},
+		},
+		{
+			name:          "LabelsInSync",
+			jobLabels:     map[string]string{"release.openshift.io/stream": "4.11.0-0.nightly"},
+			payloadLabels: map[string]string{"release.openshift.io/stream": "4.11.0-0.nightly"},
+			expected:      map[string]string{},
+		},
+		{
+			name:          "MissingLabel",
+			jobLabels:     map[string]string{"release.openshift.io/stream": "4.11.0-0.nightly"},
+			payloadLabels: map[string]string{"release.openshift.io/stream": "4.11.0-0.nightly", "release.openshift.io/architecture": "amd64"},
+			expected:      map[string]string{"release.openshift.io/architecture": "amd64"},
+		},
+		{
+			name:          "DriftedLabel",
+			jobLabels:     map[string]string{"release.openshift.io/stream": "4.10.0-0.nightly"},
+			payloadLabels: map[string]string{"release.openshift.io/stream": "4.11.0-0.nightly"},
+			expected:      map[string]string{"release.openshift.io/stream": "4.11.0-0.nightly"},
+		},
+		{
+			name:          "IgnoresUnrelatedLabels",
+			jobLabels:     map[string]string{"app": "release-controller"},
+			payloadLabels: map[string]string{"owner": "ART", "release.openshift.io/stream": "4.11.0-0.nightly"},
+			expected:      map[string]string{"release.openshift.io/stream": "4.11.0-0.nightly"},
+		},
+		{
+			name:          "KeepsExtraJobLabels",
+			jobLabels:     map[string]string{"release.openshift.io/stream": "4.11.0-0.nightly", "release.openshift.io/extra": "true"},
+			payloadLabels: map[string]string{"release.openshift.io/stream": "4.11.0-0.nightly"},
+			expected:      map[string]string{},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Labels: testCase.jobLabels}}
+			releasePayload := &v1alpha1.ReleasePayload{ObjectMeta: metav1.ObjectMeta{Labels: testCase.payloadLabels}}
+			result := shouldUpdateJobLabels(job, releasePayload)
+			if !cmp.Equal(result, testCase.expected) {
+				t.Errorf("%s: Expected %v, got %v", testCase.name, testCase.expected, result)
+			}
+		})
+	}
+}
+
+func TestReleaseCreationStatusSyncJobLabels(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   v1alpha1.ReleaseCreationJobStatus
+		expected map[string]string
+	}{
+		{
+			name:     "JobPending",
+			expected: map[string]string{"app": "release-controller", "release.openshift.io/stream": "4.11.0-0.nightly", "release.openshift.io/architecture": "amd64"},
+		},
+		{
+			name:     "JobCompleted",
+			status:   v1alpha1.ReleaseCreationJobSuccess,
+			expected: map[string]string{"app": "release-controller", "release.openshift.io/stream": "4.11.0-0.nightly", "release.openshift.io/architecture": "amd64"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			job := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+					Labels:    map[string]string{"app": "release-controller", "release.openshift.io/stream": "4.10.0-0.nightly"},
+				},
+			}
+			input := &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+					Labels:    map[string]string{"release.openshift.io/stream": "4.11.0-0.nightly", "release.openshift.io/architecture": "amd64"},
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+							Name:      job.Name,
+							Namespace: job.Namespace,
+						},
+						Status: testCase.status,
+					},
+				},
+			}
+
+			c := newReleasePayloadControllerTestBuilder(t).
+				WithReleasePayload(input).
+				WithBatchJob(job).
+				Build()
+
+			if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+				t.Errorf("%s: unexpected err: %v", testCase.name, err)
+			}
+
+			output, err := c.batchJobClient.Jobs(job.Namespace).Get(context.TODO(), job.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			if !cmp.Equal(output.Labels, testCase.expected) {
+				t.Errorf("%s: Expected %v, got %v", testCase.name, testCase.expected, output.Labels)
+			}
+		})
+	}
+}
+
+const releaseDigest = "sha256:4cf3a0b3a2e2a8a1d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c7"
+
+// newReleaseImageStream returns the release imagestream, of the ocp namespace, with the images pushed to its tags
+func newReleaseImageStream(tags map[string]string) *imagev1.ImageStream {
+	imageStream := &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "release",
+			Namespace: "ocp",
+		},
+		Status: imagev1.ImageStreamStatus{
+			PublicDockerImageRepository: "registry.ci.openshift.org/ocp/release",
+		},
+	}
+	for tag, image := range tags {
+		imageStream.Status.Tags = append(imageStream.Status.Tags, imagev1.NamedTagEventList{
+			Tag:   tag,
+			Items: []imagev1.TagEvent{{Image: image}},
+		})
+	}
+	return imageStream
+}
+
+func TestImageStreamTagDigest(t *testing.T) {
+	testCases := []struct {
+		name        string
+		imageStream *imagev1.ImageStream
+		expected    string
+	}{
+		{
+			name:        "NoTag",
+			imageStream: newReleaseImageStream(nil),
+			expected:    "",
+		},
+		{
+			name: "NoImage",
+			imageStream: &imagev1.ImageStream{
+				Status: imagev1.ImageStreamStatus{
+					Tags: []imagev1.NamedTagEventList{{Tag: "4.11.0-0.nightly-2022-02-09-091559"}},
+				},
+			},
+			expected: "",
+		},
+		{
+			name:        "InvalidDigest",
+			imageStream: newReleaseImageStream(map[string]string{"4.11.0-0.nightly-2022-02-09-091559": "sha256:1234"}),
+			expected:    "",
+		},
+		{
+			name:        "OtherTag",
+			imageStream: newReleaseImageStream(map[string]string{"4.11.0-0.nightly-2022-02-09-101559": releaseDigest}),
+			expected:    "",
+		},
+		{
+			name:        "ReleaseImagePushed",
+			imageStream: newReleaseImageStream(map[string]string{"4.11.0-0.nightly-2022-02-09-091559": releaseDigest}),
+			expected:    releaseDigest,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if result := imageStreamTagDigest(testCase.imageStream, "4.11.0-0.nightly-2022-02-09-091559"); result != testCase.expected {
+				t.Errorf("%s: Expected %q, got %q", testCase.name, testCase.expected, result)
+			}
+		})
+	}
+}
+
+// newReleaseDigestTestReleasePayload returns a ReleasePayload, of the release imagestream, whose release creation job
+// is the job
+func newReleaseDigestTestReleasePayload(job *batchv1.Job, status v1alpha1.ReleaseCreationJobResult) *v1alpha1.ReleasePayload {
+	releasePayload := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      job.Name,
+			Namespace: "ocp",
+		},
+		Spec: v1alpha1.ReleasePayloadSpec{
+			PayloadCoordinates: v1alpha1.PayloadCoordinates{
+				Namespace:          "ocp",
+				ImagestreamName:    "release",
+				ImagestreamTagName: job.Name,
+			},
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: status,
+		},
+	}
+	releasePayload.Status.ReleaseCreationJobResult.Coordinates = v1alpha1.ReleaseCreationJobCoordinates{
+		Name:      job.Name,
+		Namespace: job.Namespace,
+	}
+	return releasePayload
+}
+
+func TestReleaseCreationStatusSyncReleaseDigest(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      v1alpha1.ReleaseCreationJobResult
+		imageStream *imagev1.ImageStream
+		expected    v1alpha1.ReleasePayloadStatus
+	}{
+		{
+			name:        "JobCompleted",
+			imageStream: newReleaseImageStream(map[string]string{"4.11.0-0.nightly-2022-02-09-091559": releaseDigest}),
+			expected: v1alpha1.ReleasePayloadStatus{
+				ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+					Status:  v1alpha1.ReleaseCreationJobSuccess,
+					Message: ReleaseCreationJobSuccessMessage,
+				},
+				ReleaseDigest: releaseDigest,
+				StatusHistory: []v1alpha1.ReleasePayloadStatusSnapshot{
+					{
+						Status:  v1alpha1.ReleaseCreationJobSuccess,
+						Message: ReleaseCreationJobSuccessMessage,
+					},
+				},
+			},
+		},
+		{
+			name:        "JobCompletedBeforeImport",
+			imageStream: newReleaseImageStream(nil),
+			expected: v1alpha1.ReleasePayloadStatus{
+				ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+					Status:  v1alpha1.ReleaseCreationJobSuccess,
+					Message: ReleaseCreationJobSuccessMessage,
+				},
+				StatusHistory: []v1alpha1.ReleasePayloadStatusSnapshot{
+					{
+						Status:  v1alpha1.ReleaseCreationJobSuccess,
+						Message: ReleaseCreationJobSuccessMessage,
+					},
+				},
+			},
+		},
+		{
+			name: "ReleasePayloadAlreadySuccessful",
+			status: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobSuccess,
+				Message: ReleaseCreationJobSuccessMessage,
+			},
+			imageStream: newReleaseImageStream(map[string]string{"4.11.0-0.nightly-2022-02-09-091559": releaseDigest}),
+			expected: v1alpha1.ReleasePayloadStatus{
+				ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+					Status:  v1alpha1.ReleaseCreationJobSuccess,
+					Message: ReleaseCreationJobSuccessMessage,
+				},
+				ReleaseDigest: releaseDigest,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+			job.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+			input := newReleaseDigestTestReleasePayload(job, testCase.status)
+			testCase.expected.ReleaseCreationJobResult.Coordinates = input.Status.ReleaseCreationJobResult.Coordinates
+			testCase.expected.Conditions = []metav1.Condition{
+				{
+					Type:    v1alpha1.ConditionPayloadReady,
+					Status:  metav1.ConditionTrue,
+					Reason:  CreationJobSucceededReason,
+					Message: ReleaseCreationJobSuccessMessage,
+				},
+			}
+
+			c := newReleasePayloadControllerTestBuilder(t).
+				WithReleasePayload(input).
+				WithBatchJob(job).
+				WithImageStream(testCase.imageStream).
+				Build()
+
+			if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+				t.Errorf("%s: unexpected err: %v", testCase.name, err)
+			}
+
+			output, err := c.releasePayloadClient.ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			if !cmp.Equal(output.Status, testCase.expected, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime"), cmpopts.IgnoreFields(v1alpha1.ReleasePayloadStatusSnapshot{}, "TransitionTime")) {
+				t.Errorf("%s: Expected %v, got %v", testCase.name, testCase.expected, output.Status)
+			}
+		})
+	}
+}
+
+// TestReleaseCreationStatusReleaseDigestPushedAfterCompletion runs the controller, as it is deployed, while the
+// release creation job completes before the registry imports the release image into the release imagestream.  The
+// digest has to be picked up from the imagestream event.
+func TestReleaseCreationStatusReleaseDigestPushedAfterCompletion(t *testing.T) {
+	job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+	job.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+	input := newReleaseDigestTestReleasePayload(job, v1alpha1.ReleaseCreationJobResult{})
+
+	c, imageClient := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		WithBatchJob(job).
+		WithImageStream(newReleaseImageStream(nil)).
+		BuildWithImageClient()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.ReleasePayloadController.RunWorkers(ctx, 1)
+
+	waitForReleasePayload := func(description string, condition func(*v1alpha1.ReleasePayload) bool) {
+		t.Helper()
+		if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+			releasePayload, err := c.releasePayloadLister.ReleasePayloads(input.Namespace).Get(input.Name)
+			if err != nil {
+				return false, nil
+			}
+			return condition(releasePayload), nil
+		}); err != nil {
+			t.Fatalf("Expected the ReleasePayload to be %s: %v", description, err)
+		}
+	}
+
+	waitForReleasePayload("successful", func(releasePayload *v1alpha1.ReleasePayload) bool {
+		return releasePayload.Status.ReleaseCreationJobResult.Status == v1alpha1.ReleaseCreationJobSuccess
+	})
+
+	// The registry records the image, pushed by the release creation job, on the status of the release imagestream
+	if _, err := imageClient.ImageV1().ImageStreams("ocp").UpdateStatus(context.TODO(), newReleaseImageStream(map[string]string{job.Name: releaseDigest}), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("unable to push the release image: %v", err)
+	}
+
+	waitForReleasePayload("recording the release digest", func(releasePayload *v1alpha1.ReleasePayload) bool {
+		return releasePayload.Status.ReleaseDigest == releaseDigest
+	})
+}
+
+func TestReleaseCreationStatusSyncUnknownStatusTimeout(t *testing.T) {
+	now := time.Date(2022, 2, 9, 12, 0, 0, 0, time.UTC)
+	activeJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ci-release",
+		},
+		Status: batchv1.JobStatus{
+			Active: 1,
+		},
+	}
+	completedJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ci-release",
+		},
+		Status: batchv1.JobStatus{
+			CompletionTime: &metav1.Time{Time: now},
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		job              *batchv1.Job
+		age              time.Duration
+		duration         time.Duration
+		status           v1alpha1.ReleaseCreationJobResult
+		noCoordinates    bool
+		architectures    []string
+		expected         v1alpha1.ReleaseCreationJobResult
+		expectedErr      error
+		expectedRequeue  time.Duration
+		expectedRequeued bool
+	}{
+		{
+			name:     "PendingWithinDuration",
+			job:      activeJob,
+			age:      time.Hour,
+			duration: 2 * time.Hour,
+			expected: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobPending,
+				Message: ReleaseCreationJobPendingMessage,
+			},
+			expectedRequeue:  time.Hour,
+			expectedRequeued: true,
+		},
+		{
+			name:     "PendingLongerThanDuration",
+			job:      activeJob,
+			age:      3 * time.Hour,
+			duration: 2 * time.Hour,
+			expected: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobFailed,
+				Message: ReleaseCreationJobUnknownStatusTimeoutMessage,
+			},
+		},
+		{
+			name:     "JobNotFoundLongerThanDuration",
+			age:      3 * time.Hour,
+			duration: 2 * time.Hour,
+			expected: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobFailed,
+				Message: ReleaseCreationJobUnknownStatusTimeoutMessage,
+			},
+		},
+		{
+			name:     "UnknownStatusTimeoutDisabled",
+			job:      activeJob,
+			age:      3 * time.Hour,
+			duration: 0,
+			expected: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobPending,
+				Message: ReleaseCreationJobPendingMessage,
+			},
+		},
+		{
+			name:     "CompletedLongerThanDuration",
+			job:      completedJob,
+			age:      3 * time.Hour,
+			duration: 2 * time.Hour,
+			expected: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobSuccess,
+				Message: ReleaseCreationJobSuccessMessage,
+			},
+		},
+		{
+			name:     "AlreadyTimedOut",
+			job:      completedJob,
+			age:      3 * time.Hour,
+			duration: 2 * time.Hour,
+			status: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobFailed,
+				Message: ReleaseCreationJobUnknownStatusTimeoutMessage,
+			},
+			expected: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobFailed,
+				Message: ReleaseCreationJobUnknownStatusTimeoutMessage,
+			},
+		},
+		{
+			name:          "CoordinatesNotSetWithinDuration",
+			age:           time.Hour,
+			duration:      2 * time.Hour,
+			noCoordinates: true,
+			expected: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobUnknown,
+				Message: ReleaseCreationJobWaitingForCoordinatesMessage,
+			},
+			expectedErr:      ErrCoordinatesNotSet,
+			expectedRequeue:  time.Hour,
+			expectedRequeued: true,
+		},
+		{
+			name:          "CoordinatesNotSetLongerThanDuration",
+			age:           3 * time.Hour,
+			duration:      2 * time.Hour,
+			noCoordinates: true,
+			expected: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobFailed,
+				Message: ReleaseCreationJobUnknownStatusTimeoutMessage,
+			},
+		},
+		{
+			name:          "ArchitectureCoordinatesNotSetLongerThanDuration",
+			age:           3 * time.Hour,
+			duration:      2 * time.Hour,
+			noCoordinates: true,
+			architectures: []string{"amd64", "arm64"},
+			expected: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobFailed,
+				Message: ReleaseCreationJobUnknownStatusTimeoutMessage,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			coordinates := v1alpha1.ReleaseCreationJobCoordinates{
+				Name:      "4.11.0-0.nightly-2022-02-09-091559",
+				Namespace: "ci-release",
+			}
+			input := &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace:         "ocp",
+					CreationTimestamp: metav1.NewTime(now.Add(-testCase.age)),
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					ReleaseCreationJobResult: testCase.status,
+				},
+			}
+			input.Spec.Architectures = testCase.architectures
+			if !testCase.noCoordinates {
+				input.Status.ReleaseCreationJobResult.Coordinates = coordinates
+				testCase.expected.Coordinates = coordinates
+			}
+
+			queue := &recordingQueue{
+				RateLimitingInterface: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ReleaseCreationStatusController"),
+				addAfter:              map[interface{}]time.Duration{},
+			}
+
+			builder := newReleasePayloadControllerTestBuilder(t).
+				WithReleasePayload(input).
+				WithQueue(queue).
+				WithClock(clocktesting.NewFakePassiveClock(now)).
+				WithRejectUnknownStatusDuration(testCase.duration)
+			if testCase.job != nil {
+				builder.WithBatchJob(testCase.job)
+			}
+			c := builder.Build()
+
+			key := fmt.Sprintf("%s/%s", input.Namespace, input.Name)
+			if err := c.sync(context.TODO(), key); err != testCase.expectedErr {
+				t.Errorf("%s: expected err %v, got: %v", testCase.name, testCase.expectedErr, err)
+			}
+
+			output, err := c.releasePayloadClient.ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			if !cmp.Equal(output.Status.ReleaseCreationJobResult, testCase.expected) {
+				t.Errorf("%s: Expected %v, got %v", testCase.name, testCase.expected, output.Status.ReleaseCreationJobResult)
+			}
+
+			requeue, requeued := queue.addAfter[key]
+			if requeued != testCase.expectedRequeued {
+				t.Errorf("%s: Expected requeued to be %t, got %t", testCase.name, testCase.expectedRequeued, requeued)
+			}
+			if requeue != testCase.expectedRequeue {
+				t.Errorf("%s: Expected requeue after %s, got %s", testCase.name, testCase.expectedRequeue, requeue)
+			}
+		})
+	}
+}
+
+func TestReleaseCreationStatusSyncSkipsNoOpUpdates(t *testing.T) {
+	job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+	job.Status.Conditions = []batchv1.JobCondition{
+		{
+			Type:   batchv1.JobFailed,
+			Status: corev1.ConditionTrue,
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		status          v1alpha1.ReleaseCreationJobResult
+		expectedUpdates int
+	}{
+		{
+			name: "StatusUnchanged",
+			status: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobFailed,
+				Message: ReleaseCreationJobFailureMessage,
+			},
+		},
+		{
+			name: "MessageChanged",
+			status: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobFailed,
+				Message: ReleaseCreationJobUnknownMessage,
+			},
+			expectedUpdates: 1,
+		},
+		{
+			name: "StatusChanged",
+			status: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobUnknown,
+				Message: ReleaseCreationJobFailureMessage,
+			},
+			expectedUpdates: 1,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			input := &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					ReleaseCreationJobResult: testCase.status,
+					// Out of canonical order, which must not cause a write on its own
+					BlockingJobResults: []v1alpha1.JobStatus{
+						{CIConfigurationName: "gcp"},
+						{CIConfigurationName: "aws"},
+					},
+				},
+			}
+			input.Status.ReleaseCreationJobResult.Coordinates = v1alpha1.ReleaseCreationJobCoordinates{
+				Name:      job.Name,
+				Namespace: job.Namespace,
+			}
+			setReleasePayloadReadyCondition(input)
+
+			c := newReleasePayloadControllerTestBuilder(t).
+				WithReleasePayload(input).
+				WithBatchJob(job).
+				Build()
+
+			releasePayloadClient := c.releasePayloadClient.(*releasepayloadfake.FakeReleaseV1alpha1)
+			releasePayloadClient.ClearActions()
+
+			if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+				t.Errorf("%s: unexpected err: %v", testCase.name, err)
+			}
+
+			var updates int
+			for _, action := range releasePayloadClient.Actions() {
+				if action.GetVerb() == "update" && action.GetSubresource() == "status" {
+					updates++
+				}
+			}
+			if updates != testCase.expectedUpdates {
+				t.Errorf("%s: Expected %d UpdateStatus calls, got %d", testCase.name, testCase.expectedUpdates, updates)
+			}
+		})
+	}
+}
+
+// TestReleaseCreationStatusSyncIdempotent verifies that repeatedly syncing the same ReleasePayload, against the same
+// release creation job, only writes the status once
+func TestReleaseCreationStatusSyncIdempotent(t *testing.T) {
+	job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+	job.Status.Conditions = []batchv1.JobCondition{
+		{
+			Type:   batchv1.JobFailed,
+			Status: corev1.ConditionTrue,
+		},
+	}
+
+	coordinates := v1alpha1.ReleaseCreationJobCoordinates{
+		Name:      job.Name,
+		Namespace: job.Namespace,
+	}
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Coordinates: coordinates,
+				Status:      v1alpha1.ReleaseCreationJobUnknown,
+			},
+		},
+	}
+	expected := v1alpha1.ReleasePayloadStatus{
+		ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+			Coordinates: coordinates,
+			Status:      v1alpha1.ReleaseCreationJobFailed,
+			Message:     ReleaseCreationJobFailureMessage,
+		},
+		// A single transition, however many times the ReleasePayload is synced
+		StatusHistory: []v1alpha1.ReleasePayloadStatusSnapshot{
+			{
+				Status:  v1alpha1.ReleaseCreationJobFailed,
+				Message: ReleaseCreationJobFailureMessage,
+			},
+		},
+		Conditions: []metav1.Condition{
+			{
+				Type:    v1alpha1.ConditionPayloadReady,
+				Status:  metav1.ConditionFalse,
+				Reason:  CreationJobFailedReason,
+				Message: ReleaseCreationJobFailureMessage,
+			},
+		},
+	}
+
+	c := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		WithBatchJob(job).
+		Build()
+
+	releasePayloadClient := c.releasePayloadClient.(*releasepayloadfake.FakeReleaseV1alpha1)
+	releasePayloadClient.ClearActions()
+
+	for i := 0; i < 5; i++ {
+		if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+			t.Fatalf("sync %d: unexpected err: %v", i, err)
+		}
+
+		// Wait for the informer to observe any update, as the controller would before its next sync
+		current, err := releasePayloadClient.ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("sync %d: unexpected err: %v", i, err)
+		}
+		if err := wait.PollImmediate(10*time.Millisecond, 10*time.Second, func() (bool, error) {
+			cached, err := c.releasePayloadLister.ReleasePayloads(input.Namespace).Get(input.Name)
+			if err != nil {
+				return false, err
+			}
+			return cmp.Equal(cached.Status, current.Status), nil
+		}); err != nil {
+			t.Fatalf("sync %d: the informer did not observe the ReleasePayload: %v", i, err)
+		}
+	}
+
+	var updates int
+	for _, action := range releasePayloadClient.Actions() {
+		if action.GetVerb() == "update" && action.GetSubresource() == "status" {
+			updates++
+		}
+	}
+	if updates != 1 {
+		t.Errorf("Expected 1 UpdateStatus call, got %d", updates)
+	}
+
+	output, err := releasePayloadClient.ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !cmp.Equal(output.Status, expected, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime"), cmpopts.IgnoreFields(v1alpha1.ReleasePayloadStatusSnapshot{}, "TransitionTime")) {
+		t.Errorf("%s", cmp.Diff(expected, output.Status, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime"), cmpopts.IgnoreFields(v1alpha1.ReleasePayloadStatusSnapshot{}, "TransitionTime")))
+	}
+}
+
+// TestReleaseCreationStatusSyncStatusHistory verifies that a transition, of a ReleasePayload whose StatusHistory is
+// full, evicts the oldest snapshot
+func TestReleaseCreationStatusSyncStatusHistory(t *testing.T) {
+	now := time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC)
+	job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+	job.Status.Conditions = []batchv1.JobCondition{
+		{
+			Type:   batchv1.JobFailed,
+			Status: corev1.ConditionTrue,
+		},
+	}
+
+	var history []v1alpha1.ReleasePayloadStatusSnapshot
+	for i := 0; i < v1alpha1.StatusHistoryLimit; i++ {
+		history = append(history, v1alpha1.ReleasePayloadStatusSnapshot{
+			Status:         v1alpha1.ReleaseCreationJobUnknown,
+			Message:        fmt.Sprintf("%s %d", ReleaseCreationJobUnknownMessage, i),
+			TransitionTime: metav1.NewTime(now.Add(time.Duration(i-v1alpha1.StatusHistoryLimit) * time.Minute)),
+		})
+	}
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+					Name:      job.Name,
+					Namespace: job.Namespace,
+				},
+				Status:  v1alpha1.ReleaseCreationJobUnknown,
+				Message: ReleaseCreationJobUnknownMessage,
+			},
+			StatusHistory: history,
+		},
+	}
+	expected := append(append([]v1alpha1.ReleasePayloadStatusSnapshot{}, history[1:]...), v1alpha1.ReleasePayloadStatusSnapshot{
+		Status:         v1alpha1.ReleaseCreationJobFailed,
+		Message:        ReleaseCreationJobFailureMessage,
+		TransitionTime: metav1.NewTime(now),
+	})
+
+	c := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		WithBatchJob(job).
+		WithClock(clocktesting.NewFakePassiveClock(now)).
+		Build()
+
+	if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	output, err := c.releasePayloadClient.ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(output.Status.StatusHistory) != v1alpha1.StatusHistoryLimit {
+		t.Errorf("Expected %d snapshots, got %d", v1alpha1.StatusHistoryLimit, len(output.Status.StatusHistory))
+	}
+	if !cmp.Equal(output.Status.StatusHistory, expected) {
+		t.Errorf("%s", cmp.Diff(expected, output.Status.StatusHistory))
+	}
+}
+
+// TestReleaseCreationStatusSyncOnResync verifies that the unchanged objects, that an informer re-sync replays as
+// Update events, cause the ReleasePayload to be synced without its status being written
+func TestReleaseCreationStatusSyncOnResync(t *testing.T) {
+	job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+	job.Status.Conditions = []batchv1.JobCondition{
+		{
+			Type:   batchv1.JobFailed,
+			Status: corev1.ConditionTrue,
+		},
+	}
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+					Name:      job.Name,
+					Namespace: job.Namespace,
+				},
+				Status:  v1alpha1.ReleaseCreationJobFailed,
+				Message: ReleaseCreationJobFailureMessage,
+			},
+		},
+	}
+	setReleasePayloadReadyCondition(input)
+
+	c := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		WithBatchJob(job).
+		Build()
+
+	// Drain the events of the initial listing, so that only the re-sync is observed
+	for c.queue.Len() > 0 {
+		key, _ := c.queue.Get()
+		c.queue.Forget(key)
+		c.queue.Done(key)
+	}
+
+	var syncs int
+	c.syncFn = func(ctx context.Context, key string) error {
+		syncs++
+		return c.sync(ctx, key)
+	}
+
+	releasePayloadClient := c.releasePayloadClient.(*releasepayloadfake.FakeReleaseV1alpha1)
+	releasePayloadClient.ClearActions()
+
+	// Replay the unchanged job, exactly like a re-sync of the informer would
+	if _, err := c.batchJobClient.Jobs(job.Namespace).Update(context.TODO(), job, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("unable to update job: %v", err)
+	}
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return c.queue.Len() > 0, nil
+	}); err != nil {
+		t.Fatalf("the ReleasePayload was not enqueued: %v", err)
+	}
+	if !c.processNextItem(context.TODO()) {
+		t.Fatalf("unexpected queue shutdown")
+	}
+
+	if syncs != 1 {
+		t.Errorf("Expected 1 sync, got %d", syncs)
+	}
+	for _, action := range releasePayloadClient.Actions() {
+		if action.GetVerb() == "update" && action.GetSubresource() == "status" {
+			t.Errorf("Expected no UpdateStatus calls, got: %v", action)
+		}
+	}
+}
+
+func TestReleaseCreationStatusSyncMalformedKeys(t *testing.T) {
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+	}
+
+	c := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		Build()
+
+	releasePayloadClient := c.releasePayloadClient.(*releasepayloadfake.FakeReleaseV1alpha1)
+	releasePayloadClient.ClearActions()
+
+	for _, key := range []string{"", "/", "a/b/c", "justname"} {
+		c.queue.Add(key)
+		if !c.processNextItem(context.TODO()) {
+			t.Fatalf("%q: unexpected queue shutdown", key)
+		}
+		// A key that can never be synced is dropped, rather than retried
+		if requeues := c.queue.NumRequeues(key); requeues != 0 {
+			t.Errorf("%q: Expected the key to be dropped, got %d requeues", key, requeues)
+		}
+	}
+
+	if c.queue.Len() != 0 {
+		t.Errorf("Expected an empty queue, got %d items", c.queue.Len())
+	}
+	for _, action := range releasePayloadClient.Actions() {
+		if action.GetVerb() == "update" && action.GetSubresource() == "status" {
+			t.Errorf("Expected no UpdateStatus calls, got: %v", action)
+		}
+	}
+}
+
+// dryRunReleasePayloadsClient records the options of every status update, and, like the API server, persists nothing
+// for the dry runs
+type dryRunReleasePayloadsClient struct {
+	releasepayloadclient.ReleasePayloadInterface
+	updateStatusOptions *[]metav1.UpdateOptions
+}
+
+func (c *dryRunReleasePayloadsClient) UpdateStatus(ctx context.Context, releasePayload *v1alpha1.ReleasePayload, opts metav1.UpdateOptions) (*v1alpha1.ReleasePayload, error) {
+	*c.updateStatusOptions = append(*c.updateStatusOptions, opts)
+	if len(opts.DryRun) > 0 {
+		return releasePayload.DeepCopy(), nil
+	}
+	return c.ReleasePayloadInterface.UpdateStatus(ctx, releasePayload, opts)
+}
+
+type dryRunReleaseV1alpha1Client struct {
+	releasepayloadclient.ReleaseV1alpha1Interface
+	updateStatusOptions []metav1.UpdateOptions
+}
+
+func (c *dryRunReleaseV1alpha1Client) ReleasePayloads(namespace string) releasepayloadclient.ReleasePayloadInterface {
+	return &dryRunReleasePayloadsClient{
+		ReleasePayloadInterface: c.ReleaseV1alpha1Interface.ReleasePayloads(namespace),
+		updateStatusOptions:     &c.updateStatusOptions,
+	}
+}
+
+func TestReleaseCreationStatusSyncDryRun(t *testing.T) {
+	job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+	job.Status.CompletionTime = &metav1.Time{Time: time.Date(2022, 2, 9, 9, 45, 59, 0, time.UTC)}
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+					Name:      job.Name,
+					Namespace: job.Namespace,
+				},
+			},
+		},
+	}
+
+	c := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		WithBatchJob(job).
+		WithDryRun().
+		Build()
+	fakeClient := c.releasePayloadClient
+	client := &dryRunReleaseV1alpha1Client{ReleaseV1alpha1Interface: fakeClient}
+	c.releasePayloadClient = client
+
+	if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedOptions := []metav1.UpdateOptions{{DryRun: []string{metav1.DryRunAll}}}
+	if diff := cmp.Diff(expectedOptions, client.updateStatusOptions); diff != "" {
+		t.Errorf("unexpected status update options (-want +got):\n%s", diff)
+	}
+	output, err := fakeClient.ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get ReleasePayload: %v", err)
+	}
+	if diff := cmp.Diff(input, output); diff != "" {
+		t.Errorf("expected the ReleasePayload to be left alone (-want +got):\n%s", diff)
+	}
+}
+
+func TestReleaseCreationStatusSyncCircuitBreaker(t *testing.T) {
+	job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+					Name:      job.Name,
+					Namespace: job.Namespace,
+				},
+			},
+		},
+	}
+	key := fmt.Sprintf("%s/%s", input.Namespace, input.Name)
+
+	queue := &recordingQueue{
+		RateLimitingInterface: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ReleaseCreationStatusController"),
+		addAfter:              map[interface{}]time.Duration{},
+	}
+	c := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		WithBatchJob(job).
+		WithQueue(queue).
+		Build()
+	fakeClock := clocktesting.NewFakePassiveClock(time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC))
+	c.circuitBreaker.clock = fakeClock
+
+	releasePayloadClient := c.releasePayloadClient.(*releasepayloadfake.FakeReleaseV1alpha1)
+	releasePayloadClient.PrependReactor("update", "releasepayloads", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewServiceUnavailable("unavailable")
+	})
+
+	for i := 0; i < defaultCircuitBreakerFailureThreshold; i++ {
+		if err := c.sync(context.TODO(), key); err == nil {
+			t.Fatalf("sync %d: expected an error", i)
+		}
+	}
+
+	// The circuit is open, so the sync is postponed without calling the API server
+	releasePayloadClient.ClearActions()
+	fakeClock.SetTime(fakeClock.Now().Add(10 * time.Second))
+	if err := c.sync(context.TODO(), key); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if actions := releasePayloadClient.Actions(); len(actions) != 0 {
+		t.Errorf("Expected no actions while the circuit is open, got: %v", actions)
+	}
+	if retryAfter, ok := queue.addAfter[key]; !ok || retryAfter != 20*time.Second {
+		t.Errorf("Expected %s to be requeued after 20s, got: %v", key, queue.addAfter)
+	}
+
+	// Once the circuit is half-open, a successful update closes it
+	releasePayloadClient.ReactionChain = releasePayloadClient.ReactionChain[1:]
+	fakeClock.SetTime(fakeClock.Now().Add(20 * time.Second))
+	if err := c.sync(context.TODO(), key); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if c.circuitBreaker.state != circuitClosed {
+		t.Errorf("Expected the circuit to be closed, got: %s", c.circuitBreaker.state)
+	}
+}
+
+func TestReleaseCreationStatusSyncTransitions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		terminal batchv1.JobStatus
+		expected v1alpha1.ReleaseCreationJobResult
+	}{
+		{
+			name: "UnknownPendingSuccess",
+			terminal: batchv1.JobStatus{
+				CompletionTime: &metav1.Time{Time: time.Date(2022, 2, 9, 10, 15, 59, 0, time.UTC)},
+			},
+			expected: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobSuccess,
+				Message: ReleaseCreationJobSuccessMessage,
+			},
+		},
+		{
+			name: "UnknownPendingFailed",
+			terminal: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{
+					{
+						Type:   batchv1.JobFailed,
+						Status: corev1.ConditionTrue,
+					},
+				},
+			},
+			expected: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobFailed,
+				Message: ReleaseCreationJobFailureMessage,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+			input := &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+							Name:      job.Name,
+							Namespace: job.Namespace,
+						},
+					},
+				},
+			}
+			key := fmt.Sprintf("%s/%s", input.Namespace, input.Name)
+
+			c := newReleasePayloadControllerTestBuilder(t).
+				WithReleasePayload(input).
+				WithBatchJob(job).
+				Build()
+
+			steps := []struct {
+				jobStatus batchv1.JobStatus
+				expected  v1alpha1.ReleaseCreationJobResult
+			}{
+				{
+					jobStatus: batchv1.JobStatus{},
+					expected: v1alpha1.ReleaseCreationJobResult{
+						Status:  v1alpha1.ReleaseCreationJobUnknown,
+						Message: ReleaseCreationJobUnknownMessage,
+					},
+				},
+				{
+					jobStatus: batchv1.JobStatus{Active: 1},
+					expected: v1alpha1.ReleaseCreationJobResult{
+						Status:  v1alpha1.ReleaseCreationJobPending,
+						Message: ReleaseCreationJobPendingMessage,
+					},
+				},
+				{
+					jobStatus: batchv1.JobStatus{Active: 1, Ready: pointer.Int32(1)},
+					expected: v1alpha1.ReleaseCreationJobResult{
+						Status:  v1alpha1.ReleaseCreationJobPending,
+						Message: ReleaseCreationJobRunningMessage,
+					},
+				},
+				{
+					jobStatus: testCase.terminal,
+					expected:  testCase.expected,
+				},
+			}
+			for i, step := range steps {
+				// Update the job, and wait for the lister to observe it, as the controller would before its sync
+				updated := job.DeepCopy()
+				updated.Status = step.jobStatus
+				if _, err := c.batchJobClient.Jobs(job.Namespace).Update(context.TODO(), updated, metav1.UpdateOptions{}); err != nil {
+					t.Fatalf("%s: step %d: unable to update job: %v", testCase.name, i, err)
+				}
+				if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+					current, err := c.batchJobLister.Jobs(job.Namespace).Get(job.Name)
+					return err == nil && cmp.Equal(current.Status, step.jobStatus), nil
+				}); err != nil {
+					t.Fatalf("%s: step %d: the job update was not observed: %v", testCase.name, i, err)
+				}
+
+				if err := c.sync(context.TODO(), key); err != nil {
+					t.Fatalf("%s: step %d: unexpected err: %v", testCase.name, i, err)
+				}
+
+				expected := step.expected
+				expected.Coordinates = input.Status.ReleaseCreationJobResult.Coordinates
+				if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+					current, err := c.releasePayloadLister.ReleasePayloads(input.Namespace).Get(input.Name)
+					return err == nil && cmp.Equal(current.Status.ReleaseCreationJobResult, expected), nil
+				}); err != nil {
+					current, _ := c.releasePayloadLister.ReleasePayloads(input.Namespace).Get(input.Name)
+					t.Fatalf("%s: step %d: Expected %v, got %v", testCase.name, i, expected, current.Status.ReleaseCreationJobResult)
+				}
+			}
+		})
+	}
+}
+
+// newReleaseCreationJobPod returns a pod, of the job, whose failedContainer exited with an error
+func newReleaseCreationJobPod(job *batchv1.Job, name string, phase corev1.PodPhase, created time.Time, failedContainer string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         job.Namespace,
+			Labels:            job.Spec.Selector.MatchLabels,
+			CreationTimestamp: metav1.Time{Time: created},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "setup"},
+				{Name: "build"},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "setup",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+				},
+				{
+					Name:  "build",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+				},
+			},
+		},
+	}
+	for i := range pod.Status.ContainerStatuses {
+		if pod.Status.ContainerStatuses[i].Name == failedContainer {
+			pod.Status.ContainerStatuses[i].State.Terminated.ExitCode = 1
+		}
+	}
+	return pod
+}
+
+// podLogActions returns the container of every pod log request made through the fake client.  The fake client does
+// not record the name of the pod.
+func podLogActions(c *ReleaseCreationStatusController) []string {
+	var containers []string
+	for _, action := range c.podClient.(*fakecorev1.FakeCoreV1).Actions() {
+		if action.GetVerb() == "get" && action.GetSubresource() == "log" {
+			options := action.(clienttesting.GenericAction).GetValue().(*corev1.PodLogOptions)
+			containers = append(containers, options.Container)
+		}
+	}
+	return containers
+}
+
+func TestFetchJobFailureLogs(t *testing.T) {
+	job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+	job.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"controller-uid": "release-creation-job"}}
+	created := time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		jobLogTailBytes int
+		pods            []*corev1.Pod
+		expected        string
+		expectedLogs    []string
+	}{
+		{
+			name:            "Disabled",
+			jobLogTailBytes: 0,
+			pods:            []*corev1.Pod{newReleaseCreationJobPod(job, "pod-1", corev1.PodFailed, created, "build")},
+			expected:        "",
+		},
+		{
+			name:            "NoPods",
+			jobLogTailBytes: 4096,
+			expected:        "",
+		},
+		{
+			name:            "NoFailedPods",
+			jobLogTailBytes: 4096,
+			pods:            []*corev1.Pod{newReleaseCreationJobPod(job, "pod-1", corev1.PodRunning, created, "build")},
+			expected:        "",
+		},
+		{
+			name:            "FailedPod",
+			jobLogTailBytes: 4096,
+			pods:            []*corev1.Pod{newReleaseCreationJobPod(job, "pod-1", corev1.PodFailed, created, "build")},
+			expected:        "fake logs",
+			expectedLogs:    []string{"build"},
+		},
+		{
+			name:            "TailBytes",
+			jobLogTailBytes: 4,
+			pods:            []*corev1.Pod{newReleaseCreationJobPod(job, "pod-1", corev1.PodFailed, created, "build")},
+			expected:        "logs",
+			expectedLogs:    []string{"build"},
+		},
+		{
+			name:            "MostRecentFailedPod",
+			jobLogTailBytes: 4096,
+			pods: []*corev1.Pod{
+				newReleaseCreationJobPod(job, "pod-1", corev1.PodFailed, created, "setup"),
+				newReleaseCreationJobPod(job, "pod-2", corev1.PodFailed, created.Add(time.Minute), "build"),
+				newReleaseCreationJobPod(job, "pod-3", corev1.PodRunning, created.Add(2*time.Minute), "setup"),
+			},
+			expected:     "fake logs",
+			expectedLogs: []string{"build"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			builder := newReleasePayloadControllerTestBuilder(t).
+				WithBatchJob(job).
+				WithJobLogTailBytes(testCase.jobLogTailBytes)
+			for _, pod := range testCase.pods {
+				builder.WithKubeObject(pod)
+			}
+			c := builder.Build()
+
+			if logs := c.fetchJobFailureLogs(context.TODO(), job); logs != testCase.expected {
+				t.Errorf("%s: Expected %q, got %q", testCase.name, testCase.expected, logs)
+			}
+			if logs := podLogActions(c); !cmp.Equal(logs, testCase.expectedLogs) {
+				t.Errorf("%s: Expected log requests %v, got %v", testCase.name, testCase.expectedLogs, logs)
+			}
+			// Only the tail of the logs is read
+			for _, action := range c.podClient.(*fakecorev1.FakeCoreV1).Actions() {
+				if action.GetVerb() != "get" || action.GetSubresource() != "log" {
+					continue
+				}
+				options := action.(clienttesting.GenericAction).GetValue().(*corev1.PodLogOptions)
+				if options.TailLines == nil || *options.TailLines != int64(testCase.jobLogTailBytes) {
+					t.Errorf("%s: Expected the last %d lines to be requested, got %v", testCase.name, testCase.jobLogTailBytes, options.TailLines)
+				}
+				if options.LimitBytes == nil || *options.LimitBytes != jobLogLimitBytes {
+					t.Errorf("%s: Expected at most %d bytes to be requested, got %v", testCase.name, jobLogLimitBytes, options.LimitBytes)
+				}
+			}
+		})
+	}
+}
+
+func TestReleaseCreationStatusSyncJobFailureLogs(t *testing.T) {
+	job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+	job.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"controller-uid": "release-creation-job"}}
+	job.Status = batchv1.JobStatus{
+		Conditions: []batchv1.JobCondition{
+			{
+				Type:    batchv1.JobFailed,
+				Status:  corev1.ConditionTrue,
+				Reason:  "BackoffLimitExceeded",
+				Message: "Job has reached the specified backoff limit",
+			},
+		},
+	}
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+					Name:      job.Name,
+					Namespace: job.Namespace,
+				},
+			},
+		},
+	}
+	key := fmt.Sprintf("%s/%s", input.Namespace, input.Name)
+
+	c := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		WithBatchJob(job).
+		WithKubeObject(newReleaseCreationJobPod(job, "pod-1", corev1.PodFailed, time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC), "build")).
+		WithJobLogTailBytes(4096).
+		Build()
+
+	expected := v1alpha1.ReleaseCreationJobResult{
+		Coordinates: input.Status.ReleaseCreationJobResult.Coordinates,
+		Status:      v1alpha1.ReleaseCreationJobFailed,
+		Message:     "BackoffLimitExceeded: Job has reached the specified backoff limit\nfake logs",
+	}
+
+	// The logs are only fetched by the sync that fails the ReleasePayload
+	for i := 0; i < 2; i++ {
+		if err := c.sync(context.TODO(), key); err != nil {
+			t.Fatalf("sync %d: unexpected err: %v", i, err)
+		}
+		if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+			current, err := c.releasePayloadLister.ReleasePayloads(input.Namespace).Get(input.Name)
+			return err == nil && cmp.Equal(current.Status.ReleaseCreationJobResult, expected), nil
+		}); err != nil {
+			current, _ := c.releasePayloadLister.ReleasePayloads(input.Namespace).Get(input.Name)
+			t.Fatalf("sync %d: Expected %v, got %v", i, expected, current.Status.ReleaseCreationJobResult)
+		}
+		if logs := podLogActions(c); len(logs) != 1 {
+			t.Errorf("sync %d: Expected a single log request, got %v", i, logs)
+		}
+	}
+}
+
+func TestReleaseCreationStatusSyncJobNameMismatch(t *testing.T) {
+	testCases := []struct {
+		name   string
+		status v1alpha1.ReleaseCreationJobStatus
+	}{
+		{
+			name: "Unset",
+		},
+		{
+			name:   "Success",
+			status: v1alpha1.ReleaseCreationJobSuccess,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// The coordinates reference the job of the previous nightly
+			job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-08-091559", "ocp/release")
+			job.Status.CompletionTime = &metav1.Time{Time: time.Date(2022, 2, 9, 10, 15, 59, 0, time.UTC)}
+			input := &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+							Name:      job.Name,
+							Namespace: job.Namespace,
+						},
+						Status: testCase.status,
+					},
+				},
+			}
+
+			c := newReleasePayloadControllerTestBuilder(t).
+				WithReleasePayload(input).
+				WithBatchJob(job).
+				Build()
+
+			err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name))
+			if !errors.Is(err, ErrJobNameMismatch) {
+				t.Fatalf("%s: Expected %v, got: %v", testCase.name, ErrJobNameMismatch, err)
+			}
+
+			output, err := c.releasePayloadClient.ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("%s: unable to get ReleasePayload: %v", testCase.name, err)
+			}
+			if !cmp.Equal(output.Status, input.Status) {
+				t.Errorf("%s: Expected the status to be left alone, got: %v", testCase.name, cmp.Diff(input.Status, output.Status))
+			}
+		})
+	}
+}
+
+func TestReleaseCreationStatusSyncJobNamespaceMismatch(t *testing.T) {
+	testCases := []struct {
+		name           string
+		status         v1alpha1.ReleaseCreationJobStatus
+		architectures  []string
+		expectedStatus v1alpha1.ReleaseCreationJobStatus
+	}{
+		{
+			name:           "Unset",
+			expectedStatus: v1alpha1.ReleaseCreationJobFailed,
+		},
+		{
+			// The release has been created, so only the maintenance of the job's labels is skipped
+			name:           "Success",
+			status:         v1alpha1.ReleaseCreationJobSuccess,
+			expectedStatus: v1alpha1.ReleaseCreationJobSuccess,
+		},
+		{
+			name:           "Architectures",
+			architectures:  []string{"amd64"},
+			expectedStatus: v1alpha1.ReleaseCreationJobFailed,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// The job was created in ci-release, but the coordinates reference the namespace of the ReleasePayload
+			job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+			coordinates := v1alpha1.ReleaseCreationJobCoordinates{
+				Name:      job.Name,
+				Namespace: "ocp",
+			}
+			input := &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ocp",
+				},
+				Spec: v1alpha1.ReleasePayloadSpec{
+					Architectures: testCase.architectures,
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Coordinates: coordinates,
+						Status:      testCase.status,
+					},
+				},
+			}
+			for _, architecture := range testCase.architectures {
+				if input.Status.ArchitectureResults == nil {
+					input.Status.ArchitectureResults = map[string]v1alpha1.ReleaseCreationJobResult{}
+				}
+				input.Status.ArchitectureResults[architecture] = v1alpha1.ReleaseCreationJobResult{
+					Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+						Name:      input.Name + "-" + architecture,
+						Namespace: "ocp",
+					},
+				}
+			}
+
+			c := newReleasePayloadControllerTestBuilder(t).
+				WithReleasePayload(input).
+				WithBatchJob(job).
+				WithJobsNamespace("ci-release").
+				Build()
+
+			// The mismatch is permanent, so it must not be retried
+			if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+				t.Fatalf("%s: Expected no error, got: %v", testCase.name, err)
+			}
+
+			output, err := c.releasePayloadClient.ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("%s: unable to get ReleasePayload: %v", testCase.name, err)
+			}
+			result := output.Status.ReleaseCreationJobResult
+			if result.Status != testCase.expectedStatus {
+				t.Errorf("%s: Expected status %q, got: %q", testCase.name, testCase.expectedStatus, result.Status)
+			}
+			if testCase.expectedStatus == v1alpha1.ReleaseCreationJobFailed && !strings.Contains(result.Message, ErrJobNamespaceMismatch.Error()) {
+				t.Errorf("%s: Expected the message to report the namespace mismatch, got: %q", testCase.name, result.Message)
+			}
+
+			recorded := c.eventRecorder.(events.InMemoryRecorder).Events()
+			if len(recorded) != 1 || recorded[0].Reason != "ReleaseCreationJobNamespaceMismatch" || recorded[0].Type != corev1.EventTypeWarning {
+				t.Errorf("%s: Expected a ReleaseCreationJobNamespaceMismatch Warning event, got: %v", testCase.name, recorded)
+			}
+		})
+	}
+}
+
+func TestReleaseCreationStatusSyncConflict(t *testing.T) {
+	job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+	job.Status.CompletionTime = &metav1.Time{Time: time.Date(2022, 2, 9, 10, 15, 59, 0, time.UTC)}
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+					Name:      job.Name,
+					Namespace: job.Namespace,
+				},
+			},
+		},
+	}
+
+	c := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		WithBatchJob(job).
+		Build()
+
+	// The first update conflicts with the write of another controller
+	conflicts := 0
+	releasePayloadClient := c.releasePayloadClient.(*releasepayloadfake.FakeReleaseV1alpha1)
+	releasePayloadClient.PrependReactor("update", "releasepayloads", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if conflicts > 0 {
+			return false, nil, nil
+		}
+		conflicts++
+		return true, nil, k8serrors.NewConflict(v1alpha1.Resource("releasepayloads"), input.Name, fmt.Errorf("the object has been modified"))
+	})
+
+	if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	var updates int
+	for _, action := range releasePayloadClient.Actions() {
+		if action.GetVerb() == "update" {
+			updates++
+		}
+	}
+	if updates != 2 {
+		t.Errorf("Expected the conflicting update to be retried once, got %d updates", updates)
+	}
+
+	output, err := c.releasePayloadClient.ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get ReleasePayload: %v", err)
+	}
+	if output.Status.ReleaseCreationJobResult.Status != v1alpha1.ReleaseCreationJobSuccess {
+		t.Errorf("Expected status %s, got %s", v1alpha1.ReleaseCreationJobSuccess, output.Status.ReleaseCreationJobResult.Status)
+	}
+	// The whole computed status is applied to the latest ReleasePayload
+	if len(output.Status.StatusHistory) != 1 {
+		t.Errorf("Expected the transition to be recorded in the status history, got %v", output.Status.StatusHistory)
+	}
+	if v1helpers.FindCondition(output.Status.Conditions, v1alpha1.ConditionPayloadReady) == nil {
+		t.Errorf("Expected the %s condition to be set, got %v", v1alpha1.ConditionPayloadReady, output.Status.Conditions)
+	}
+}
+
+// TestReleaseCreationStatusControllerWorkers is meant to be run with -race, to detect the data races between the
+// workers reconciling the ReleasePayloads concurrently
+func TestReleaseCreationStatusControllerWorkers(t *testing.T) {
+	// Stay below the 100 events that the fake watch buffers, as the informer may fall behind the workers
+	const count = 50
+
+	builder := newReleasePayloadControllerTestBuilder(t).WithWorkers(defaultReleaseCreationStatusWorkers)
+	var keys []string
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("4.11.0-0.nightly-2022-02-09-%06d", i)
+		job := newReleaseCreationJob("ci-release", name, "ocp/release")
+		job.Status.CompletionTime = &metav1.Time{Time: time.Date(2022, 2, 9, 10, 15, 59, 0, time.UTC)}
+		builder.WithBatchJob(job).WithReleasePayload(&v1alpha1.ReleasePayload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ocp",
+			},
+			Status: v1alpha1.ReleasePayloadStatus{
+				ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+					Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+						Name:      job.Name,
+						Namespace: job.Namespace,
+					},
+				},
+			},
+		})
+		keys = append(keys, fmt.Sprintf("ocp/%s", name))
+	}
+	c := builder.Build()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Run(ctx)
+
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			c.queue.Add(key)
+		}(key)
+	}
+	wg.Wait()
+
+	var pending []string
+	err := wait.PollImmediate(100*time.Millisecond, 30*time.Second, func() (bool, error) {
+		pending = nil
+		releasePayloads, err := c.releasePayloadClient.ReleasePayloads("ocp").List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, releasePayload := range releasePayloads.Items {
+			if releasePayload.Status.ReleaseCreationJobResult.Status != v1alpha1.ReleaseCreationJobSuccess {
+				pending = append(pending, releasePayload.Name)
+			}
+		}
+		return len(releasePayloads.Items) == count && len(pending) == 0, nil
+	})
+	if err != nil {
+		t.Fatalf("Expected all %d ReleasePayloads to be processed, %d are pending: %v", count, len(pending), pending)
+	}
+}
+
+func TestReleaseCreationStatusSyncKeepsStartTime(t *testing.T) {
+	startTime := metav1.NewTime(time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC))
+	job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+	job.Status = batchv1.JobStatus{StartTime: &startTime, Active: 1}
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+					Name:      job.Name,
+					Namespace: job.Namespace,
+				},
+			},
+		},
+	}
+	key := fmt.Sprintf("%s/%s", input.Namespace, input.Name)
+
+	c := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		WithBatchJob(job).
+		Build()
+
+	if err := c.sync(context.TODO(), key); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		current, err := c.releasePayloadLister.ReleasePayloads(input.Namespace).Get(input.Name)
+		return err == nil && current.Status.ReleaseCreationJobResult.Status == v1alpha1.ReleaseCreationJobPending, nil
+	}); err != nil {
+		t.Fatalf("Expected status %s: %v", v1alpha1.ReleaseCreationJobPending, err)
+	}
+
+	// The job completes, reporting a different StartTime, which must not replace the one already recorded
+	completed := job.DeepCopy()
+	completed.Status = batchv1.JobStatus{
+		StartTime:      &metav1.Time{Time: startTime.Add(time.Minute)},
+		CompletionTime: &metav1.Time{Time: startTime.Add(10 * time.Minute)},
+	}
+	if _, err := c.batchJobClient.Jobs(job.Namespace).Update(context.TODO(), completed, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("unable to update job: %v", err)
+	}
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		current, err := c.batchJobLister.Jobs(job.Namespace).Get(job.Name)
+		return err == nil && current.Status.CompletionTime != nil, nil
+	}); err != nil {
+		t.Fatalf("the job update was not observed: %v", err)
+	}
+
+	if err := c.sync(context.TODO(), key); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	output, err := c.releasePayloadClient.ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get ReleasePayload: %v", err)
+	}
+	if status := output.Status.ReleaseCreationJobResult.Status; status != v1alpha1.ReleaseCreationJobSuccess {
+		t.Errorf("Expected status %s, got %s", v1alpha1.ReleaseCreationJobSuccess, status)
+	}
+	if recorded := output.Status.ReleaseCreationJobResult.StartTime; !recorded.Equal(&startTime) {
+		t.Errorf("Expected StartTime %v, got %v", startTime, recorded)
+	}
+}
+
+func TestReleaseCreationStatusSyncArchitectures(t *testing.T) {
+	startTime := metav1.NewTime(time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC))
+	succeeded := batchv1.JobStatus{
+		StartTime:      &metav1.Time{Time: startTime.Add(time.Minute)},
+		CompletionTime: &metav1.Time{Time: startTime.Add(10 * time.Minute)},
+	}
+	failed := batchv1.JobStatus{
+		StartTime: &startTime,
+		Failed:    3,
+		Conditions: []batchv1.JobCondition{
+			{
+				Type:   batchv1.JobFailed,
+				Status: corev1.ConditionTrue,
+			},
+		},
+	}
+	running := batchv1.JobStatus{
+		StartTime: &startTime,
+		Active:    1,
+		Failed:    1,
+	}
+
+	testCases := []struct {
+		name             string
+		amd64            *batchv1.JobStatus
+		arm64            *batchv1.JobStatus
+		manifestList     *batchv1.JobStatus
+		expectedStatus   v1alpha1.ReleaseCreationJobStatus
+		expectedMessage  string
+		expectedStart    *metav1.Time
+		expectedAttempts int32
+		expectedResults  map[string]v1alpha1.ReleaseCreationJobStatus
+	}{
+		{
+			name:            "AllSucceeded",
+			amd64:           &succeeded,
+			arm64:           &succeeded,
+			manifestList:    &succeeded,
+			expectedStatus:  v1alpha1.ReleaseCreationJobSuccess,
+			expectedMessage: ReleaseCreationJobSuccessMessage,
+			expectedStart:   succeeded.StartTime,
+			expectedResults: map[string]v1alpha1.ReleaseCreationJobStatus{
+				"amd64": v1alpha1.ReleaseCreationJobSuccess,
+				"arm64": v1alpha1.ReleaseCreationJobSuccess,
+			},
+		},
+		{
+			name:            "AllSucceededWithoutManifestList",
+			amd64:           &succeeded,
+			arm64:           &succeeded,
+			expectedStatus:  v1alpha1.ReleaseCreationJobPending,
+			expectedMessage: ReleaseCreationJobWaitingForManifestListMessage,
+			expectedStart:   succeeded.StartTime,
+			expectedResults: map[string]v1alpha1.ReleaseCreationJobStatus{
+				"amd64": v1alpha1.ReleaseCreationJobSuccess,
+				"arm64": v1alpha1.ReleaseCreationJobSuccess,
+			},
+		},
+		{
+			name:            "AllSucceededManifestListFailed",
+			amd64:           &succeeded,
+			arm64:           &succeeded,
+			manifestList:    &failed,
+			expectedStatus:  v1alpha1.ReleaseCreationJobFailed,
+			expectedMessage: ReleaseCreationJobFailureMessage,
+			expectedStart:   succeeded.StartTime,
+			expectedResults: map[string]v1alpha1.ReleaseCreationJobStatus{
+				"amd64": v1alpha1.ReleaseCreationJobSuccess,
+				"arm64": v1alpha1.ReleaseCreationJobSuccess,
+			},
+		},
+		{
+			name:             "OneSucceededOneFailed",
+			amd64:            &succeeded,
+			arm64:            &failed,
+			expectedStatus:   v1alpha1.ReleaseCreationJobFailed,
+			expectedMessage:  fmt.Sprintf("arm64: %s", ReleaseCreationJobFailureMessage),
+			expectedStart:    &startTime,
+			expectedAttempts: 3,
+			expectedResults: map[string]v1alpha1.ReleaseCreationJobStatus{
+				"amd64": v1alpha1.ReleaseCreationJobSuccess,
+				"arm64": v1alpha1.ReleaseCreationJobFailed,
+			},
+		},
+		{
+			name:             "OneSucceededOneRunning",
+			amd64:            &succeeded,
+			arm64:            &running,
+			expectedStatus:   v1alpha1.ReleaseCreationJobPending,
+			expectedMessage:  fmt.Sprintf("arm64: %s", ReleaseCreationJobPendingMessage),
+			expectedStart:    &startTime,
+			expectedAttempts: 1,
+			expectedResults: map[string]v1alpha1.ReleaseCreationJobStatus{
+				"amd64": v1alpha1.ReleaseCreationJobSuccess,
+				"arm64": v1alpha1.ReleaseCreationJobPending,
+			},
+		},
+		{
+			name:             "OneFailedOneMissing",
+			amd64:            &failed,
+			expectedStatus:   v1alpha1.ReleaseCreationJobFailed,
+			expectedMessage:  fmt.Sprintf("amd64: %s", ReleaseCreationJobFailureMessage),
+			expectedStart:    &startTime,
+			expectedAttempts: 3,
+			expectedResults: map[string]v1alpha1.ReleaseCreationJobStatus{
+				"amd64": v1alpha1.ReleaseCreationJobFailed,
+				"arm64": v1alpha1.ReleaseCreationJobUnknown,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			const release = "4.11.0-0.nightly-2022-02-09-091559"
+			input := &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      release,
+					Namespace: "ocp",
+				},
+				Spec: v1alpha1.ReleasePayloadSpec{
+					Architectures: []string{"amd64", "arm64"},
+				},
+				Status: v1alpha1.ReleasePayloadStatus{
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Coordinates: v1alpha1.ReleaseCreationJobCoordinates{Name: release, Namespace: "ci-release"},
+					},
+					ArchitectureResults: map[string]v1alpha1.ReleaseCreationJobResult{
+						"amd64": {Coordinates: v1alpha1.ReleaseCreationJobCoordinates{Name: release + "-amd64", Namespace: "ci-release"}},
+						"arm64": {Coordinates: v1alpha1.ReleaseCreationJobCoordinates{Name: release + "-arm64", Namespace: "ci-release"}},
+					},
+				},
+			}
+
+			builder := newReleasePayloadControllerTestBuilder(t).WithReleasePayload(input)
+			if testCase.manifestList != nil {
+				job := newReleaseCreationJob("ci-release", release, "ocp/release")
+				job.Status = *testCase.manifestList
+				builder = builder.WithBatchJob(job)
+			}
+			for architecture, status := range map[string]*batchv1.JobStatus{"amd64": testCase.amd64, "arm64": testCase.arm64} {
+				if status == nil {
+					continue
+				}
+				job := newReleaseCreationJob("ci-release", fmt.Sprintf("%s-%s", release, architecture), "ocp/release")
+				job.Annotations[releasecontroller.ReleaseAnnotationReleaseTag] = release
+				job.Status = *status
+				builder = builder.WithBatchJob(job)
+			}
+			c := builder.Build()
+
+			if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+
+			output, err := c.releasePayloadClient.ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("unable to get ReleasePayload: %v", err)
+			}
+			if status := output.Status.ReleaseCreationJobResult.Status; status != testCase.expectedStatus {
+				t.Errorf("Expected status %s, got %s", testCase.expectedStatus, status)
+			}
+			if message := output.Status.ReleaseCreationJobResult.Message; message != testCase.expectedMessage {
+				t.Errorf("Expected message %q, got %q", testCase.expectedMessage, message)
+			}
+			if recorded := output.Status.ReleaseCreationJobResult.StartTime; !recorded.Equal(testCase.expectedStart) {
+				t.Errorf("Expected the earliest StartTime %v, got %v", testCase.expectedStart, recorded)
+			}
+			if attempts := output.Status.ReleaseCreationJobResult.Attempts; attempts != testCase.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", testCase.expectedAttempts, attempts)
+			}
+			for architecture, expected := range testCase.expectedResults {
+				if status := output.Status.ArchitectureResults[architecture].Status; status != expected {
+					t.Errorf("Expected %s status %s, got %s", architecture, expected, status)
+				}
+			}
+		})
+	}
+}
+
+func TestSetReleasePayloadReadyCondition(t *testing.T) {
+	testCases := []struct {
+		name           string
+		result         v1alpha1.ReleaseCreationJobResult
+		conditions     []metav1.Condition
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name: "JobSucceeded",
+			result: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobSuccess,
+				Message: ReleaseCreationJobSuccessMessage,
+			},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: CreationJobSucceededReason,
+		},
+		{
+			name: "JobFailed",
+			result: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobFailed,
+				Message: ReleaseCreationJobFailureMessage,
+			},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: CreationJobFailedReason,
+		},
+		{
+			name: "JobPending",
+			result: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobPending,
+				Message: ReleaseCreationJobPendingMessage,
+			},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: CreationJobPendingReason,
+		},
+		{
+			name: "JobUnknown",
+			result: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobUnknown,
+				Message: ReleaseCreationJobUnknownMessage,
+			},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: CreationJobPendingReason,
+		},
+		{
+			name:           "NoJobResult",
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: CreationJobPendingReason,
+		},
+		{
+			name: "JobSucceededAfterPending",
+			result: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobSuccess,
+				Message: ReleaseCreationJobSuccessMessage,
+			},
+			conditions: []metav1.Condition{
+				{
+					Type:   v1alpha1.ConditionPayloadAccepted,
+					Status: metav1.ConditionFalse,
+					Reason: "InProgress",
+				},
+				{
+					Type:    v1alpha1.ConditionPayloadReady,
+					Status:  metav1.ConditionFalse,
+					Reason:  CreationJobPendingReason,
+					Message: ReleaseCreationJobPendingMessage,
+				},
+			},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: CreationJobSucceededReason,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			releasePayload := &v1alpha1.ReleasePayload{
+				Status: v1alpha1.ReleasePayloadStatus{
+					ReleaseCreationJobResult: testCase.result,
+					Conditions:               testCase.conditions,
+				},
+			}
+			setReleasePayloadReadyCondition(releasePayload)
+
+			condition := v1helpers.FindCondition(releasePayload.Status.Conditions, v1alpha1.ConditionPayloadReady)
+			if condition == nil {
+				t.Fatalf("%s: Expected a %s condition, got %v", testCase.name, v1alpha1.ConditionPayloadReady, releasePayload.Status.Conditions)
+			}
+			if condition.Status != testCase.expectedStatus || condition.Reason != testCase.expectedReason {
+				t.Errorf("%s: Expected %s/%s, got %s/%s", testCase.name, testCase.expectedStatus, testCase.expectedReason, condition.Status, condition.Reason)
+			}
+			if condition.Message != testCase.result.Message {
+				t.Errorf("%s: Expected message %q, got %q", testCase.name, testCase.result.Message, condition.Message)
+			}
+			if expected := len(testCase.conditions); expected > 0 && len(releasePayload.Status.Conditions) != expected {
+				t.Errorf("%s: Expected %d conditions, got %d", testCase.name, expected, len(releasePayload.Status.Conditions))
+			}
+		})
+	}
+}
+
+func TestReleaseCreationStatusSyncAttempts(t *testing.T) {
+	job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+					Name:      job.Name,
+					Namespace: job.Namespace,
+				},
+			},
+		},
+	}
+	key := fmt.Sprintf("%s/%s", input.Namespace, input.Name)
+
+	c := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		WithBatchJob(job).
+		Build()
+
+	for _, failed := range []int32{0, 1, 2} {
+		updated := job.DeepCopy()
+		updated.Status = batchv1.JobStatus{Active: 1, Failed: failed}
+		if _, err := c.batchJobClient.Jobs(job.Namespace).Update(context.TODO(), updated, metav1.UpdateOptions{}); err != nil {
+			t.Fatalf("failed %d: unable to update job: %v", failed, err)
+		}
+		if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+			current, err := c.batchJobLister.Jobs(job.Namespace).Get(job.Name)
+			return err == nil && current.Status.Active == 1 && current.Status.Failed == failed, nil
+		}); err != nil {
+			t.Fatalf("failed %d: the job update was not observed: %v", failed, err)
+		}
+
+		if err := c.sync(context.TODO(), key); err != nil {
+			t.Fatalf("failed %d: unexpected err: %v", failed, err)
+		}
+
+		if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+			current, err := c.releasePayloadLister.ReleasePayloads(input.Namespace).Get(input.Name)
+			return err == nil && current.Status.ReleaseCreationJobResult.Status == v1alpha1.ReleaseCreationJobPending && current.Status.ReleaseCreationJobResult.Attempts == failed, nil
+		}); err != nil {
+			t.Fatalf("Expected %d attempts: %v", failed, err)
+		}
+	}
+}
+
+func TestReleaseCreationStatusSyncAttemptsConflict(t *testing.T) {
+	job := newReleaseCreationJob("ci-release", "4.11.0-0.nightly-2022-02-09-091559", "ocp/release")
+	job.Status = batchv1.JobStatus{Active: 1, Failed: 2}
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+					Name:      job.Name,
+					Namespace: job.Namespace,
+				},
+			},
+		},
+	}
+
+	c := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		WithBatchJob(job).
+		Build()
+
+	// The first update conflicts with the write of another controller, so the attempts are applied to the latest version
+	conflicts := 0
+	releasePayloadClient := c.releasePayloadClient.(*releasepayloadfake.FakeReleaseV1alpha1)
+	releasePayloadClient.PrependReactor("update", "releasepayloads", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if conflicts > 0 {
+			return false, nil, nil
+		}
+		conflicts++
+		return true, nil, k8serrors.NewConflict(v1alpha1.Resource("releasepayloads"), input.Name, fmt.Errorf("the object has been modified"))
+	})
+
+	if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if conflicts != 1 {
+		t.Fatalf("Expected the first update to conflict, got %d conflicts", conflicts)
+	}
+
+	output, err := c.releasePayloadClient.ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get ReleasePayload: %v", err)
+	}
+	if output.Status.ReleaseCreationJobResult.Status != v1alpha1.ReleaseCreationJobPending {
+		t.Errorf("Expected status %s, got %s", v1alpha1.ReleaseCreationJobPending, output.Status.ReleaseCreationJobResult.Status)
+	}
+	if output.Status.ReleaseCreationJobResult.Attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", output.Status.ReleaseCreationJobResult.Attempts)
+	}
+}
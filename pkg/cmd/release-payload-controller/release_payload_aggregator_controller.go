@@ -0,0 +1,188 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ReleasePayloadSummaryConfigMapName is the name of the ConfigMap, in every namespace with ReleasePayloads, that
+	// summarizes the state of the ReleasePayloads of the namespace
+	ReleasePayloadSummaryConfigMapName = "release-payload-summary"
+
+	// ReleasePayloadSummarySuccessKey is the key, of the summary ConfigMap, holding the number of Accepted ReleasePayloads
+	ReleasePayloadSummarySuccessKey = "success"
+
+	// ReleasePayloadSummaryFailedKey is the key, of the summary ConfigMap, holding the number of Rejected, or Failed,
+	// ReleasePayloads
+	ReleasePayloadSummaryFailedKey = "failed"
+
+	// ReleasePayloadSummaryPendingKey is the key, of the summary ConfigMap, holding the number of ReleasePayloads that
+	// are still in progress
+	ReleasePayloadSummaryPendingKey = "pending"
+
+	// releasePayloadSummaryDebounce is how long the ReleasePayloadAggregatorController waits, after the first change to
+	// the ReleasePayloads of a namespace, before writing its summary.  Every change within that window is folded into a
+	// single write.
+	releasePayloadSummaryDebounce = 30 * time.Second
+)
+
+// ReleasePayloadAggregatorController is responsible for summarizing, per namespace, how many ReleasePayloads have
+// succeeded, failed or are still pending.  The summary is written to the release-payload-summary ConfigMap of the
+// namespace.
+// The ReleasePayloadAggregatorController reads the following pieces of information:
+//   - .status.conditions.PayloadAccepted
+//   - .status.conditions.PayloadRejected
+//   - .status.conditions.PayloadFailed
+//
+// and writes the following information:
+//   - the release-payload-summary ConfigMap
+type ReleasePayloadAggregatorController struct {
+	*ReleasePayloadController
+
+	configMapClient corev1client.ConfigMapsGetter
+}
+
+func NewReleasePayloadAggregatorController(
+	releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer,
+	releasePayloadClient releasepayloadclient.ReleaseV1alpha1Interface,
+	configMapClient corev1client.ConfigMapsGetter,
+	eventRecorder events.Recorder,
+) (*ReleasePayloadAggregatorController, error) {
+	c := &ReleasePayloadAggregatorController{
+		ReleasePayloadController: NewReleasePayloadController("Release Payload Aggregator Controller",
+			releasePayloadInformer,
+			releasePayloadClient,
+			eventRecorder.WithComponentSuffix("release-payload-aggregator-controller"),
+			workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ReleasePayloadAggregatorController")),
+		configMapClient: configMapClient,
+	}
+
+	c.syncFn = c.sync
+
+	releasePayloadInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueNamespace,
+		UpdateFunc: func(old, new interface{}) { c.enqueueNamespace(new) },
+		DeleteFunc: c.enqueueNamespace,
+	})
+
+	return c, nil
+}
+
+// enqueueNamespace queues the namespace of the ReleasePayload to be summarized after releasePayloadSummaryDebounce.
+// A namespace that is already waiting keeps its original deadline, so a burst of changes results in a single write.
+func (c *ReleasePayloadAggregatorController) enqueueNamespace(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid queue key '%v': %v", obj, err))
+		return
+	}
+	namespace, _, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return
+	}
+	c.queue.AddAfter(namespace, releasePayloadSummaryDebounce)
+}
+
+// releasePayloadSummary holds the number of ReleasePayloads, of a namespace, in each state
+type releasePayloadSummary struct {
+	success int
+	failed  int
+	pending int
+}
+
+func summarizeReleasePayloads(releasePayloads []*v1alpha1.ReleasePayload) releasePayloadSummary {
+	var summary releasePayloadSummary
+	for _, releasePayload := range releasePayloads {
+		switch {
+		case v1helpers.IsConditionTrue(releasePayload.Status.Conditions, v1alpha1.ConditionPayloadAccepted):
+			summary.success++
+		case v1helpers.IsConditionTrue(releasePayload.Status.Conditions, v1alpha1.ConditionPayloadRejected),
+			v1helpers.IsConditionTrue(releasePayload.Status.Conditions, v1alpha1.ConditionPayloadFailed):
+			summary.failed++
+		default:
+			summary.pending++
+		}
+	}
+	return summary
+}
+
+func (s releasePayloadSummary) data() map[string]string {
+	return map[string]string{
+		ReleasePayloadSummarySuccessKey: strconv.Itoa(s.success),
+		ReleasePayloadSummaryFailedKey:  strconv.Itoa(s.failed),
+		ReleasePayloadSummaryPendingKey: strconv.Itoa(s.pending),
+	}
+}
+
+func (c *ReleasePayloadAggregatorController) sync(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting ReleasePayloadAggregatorController sync")
+	defer logger.V(4).Info("ReleasePayloadAggregatorController sync done")
+
+	// The queue holds namespaces, except for the namespace/name keys of the ReleasePayloads enqueued on startup
+	namespace, _, _ := strings.Cut(key, "/")
+	if len(namespace) == 0 {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	releasePayloads, err := c.releasePayloadLister.ReleasePayloads(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	data := summarizeReleasePayloads(releasePayloads).data()
+
+	existing, err := c.configMapClient.ConfigMaps(namespace).Get(ctx, ReleasePayloadSummaryConfigMapName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		// Don't leave a summary behind in namespaces that never had any ReleasePayloads
+		if len(releasePayloads) == 0 {
+			return nil
+		}
+		existing = nil
+	case err != nil:
+		return err
+	case reflect.DeepEqual(existing.Data, data):
+		return nil
+	}
+
+	klog.V(4).Infof("Updating ReleasePayload summary %s/%s: %v", namespace, ReleasePayloadSummaryConfigMapName, data)
+	if existing == nil {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ReleasePayloadSummaryConfigMapName,
+				Namespace: namespace,
+			},
+			Data: data,
+		}
+		_, err = c.configMapClient.ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
+		return err
+	}
+
+	// Only the data is owned by the controller, the labels, annotations, etc. added by others are kept
+	configMap := existing.DeepCopy()
+	configMap.Data = data
+	_, err = c.configMapClient.ConfigMaps(namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
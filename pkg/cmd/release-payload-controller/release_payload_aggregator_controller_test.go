@@ -0,0 +1,233 @@
+package release_payload_controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	fake2 "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func newAggregatorReleasePayload(name string, conditions ...string) *v1alpha1.ReleasePayload {
+	releasePayload := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ocp",
+		},
+	}
+	for _, condition := range conditions {
+		releasePayload.Status.Conditions = append(releasePayload.Status.Conditions, metav1.Condition{Type: condition, Status: metav1.ConditionTrue})
+	}
+	return releasePayload
+}
+
+func TestSummarizeReleasePayloads(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    []*v1alpha1.ReleasePayload
+		expected map[string]string
+	}{
+		{
+			name:     "NoReleasePayloads",
+			expected: map[string]string{"success": "0", "failed": "0", "pending": "0"},
+		},
+		{
+			name: "MixedReleasePayloads",
+			input: []*v1alpha1.ReleasePayload{
+				newAggregatorReleasePayload("4.11.0-0.nightly-2022-02-09-091559", v1alpha1.ConditionPayloadAccepted),
+				newAggregatorReleasePayload("4.11.0-0.nightly-2022-02-09-101559", v1alpha1.ConditionPayloadRejected),
+				newAggregatorReleasePayload("4.11.0-0.nightly-2022-02-09-111559", v1alpha1.ConditionPayloadFailed),
+				newAggregatorReleasePayload("4.11.0-0.nightly-2022-02-09-121559", v1alpha1.ConditionPayloadCreated),
+				newAggregatorReleasePayload("4.11.0-0.nightly-2022-02-09-131559"),
+			},
+			expected: map[string]string{"success": "1", "failed": "2", "pending": "2"},
+		},
+		{
+			name: "FalseConditionsArePending",
+			input: []*v1alpha1.ReleasePayload{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "4.11.0-0.nightly-2022-02-09-091559", Namespace: "ocp"},
+					Status: v1alpha1.ReleasePayloadStatus{
+						Conditions: []metav1.Condition{
+							{Type: v1alpha1.ConditionPayloadAccepted, Status: metav1.ConditionFalse},
+							{Type: v1alpha1.ConditionPayloadRejected, Status: metav1.ConditionFalse},
+							{Type: v1alpha1.ConditionPayloadFailed, Status: metav1.ConditionFalse},
+						},
+					},
+				},
+			},
+			expected: map[string]string{"success": "0", "failed": "0", "pending": "1"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if diff := cmp.Diff(testCase.expected, summarizeReleasePayloads(testCase.input).data()); diff != "" {
+				t.Errorf("%s: unexpected summary (-want +got):\n%s", testCase.name, diff)
+			}
+		})
+	}
+}
+
+func TestReleasePayloadAggregatorSync(t *testing.T) {
+	ctx := context.Background()
+
+	releasePayloadClient := fake.NewSimpleClientset(
+		newAggregatorReleasePayload("4.11.0-0.nightly-2022-02-09-091559", v1alpha1.ConditionPayloadAccepted),
+		newAggregatorReleasePayload("4.11.0-0.nightly-2022-02-09-101559"),
+	)
+	kubeClient := fake2.NewSimpleClientset()
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+	releasePayloadInformer := releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads()
+
+	c, err := NewReleasePayloadAggregatorController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), kubeClient.CoreV1(), events.NewInMemoryRecorder("release-payload-aggregator-controller-test"))
+	if err != nil {
+		t.Fatalf("Failed to create Release Payload Aggregator Controller: %v", err)
+	}
+	defer c.queue.ShutDown()
+
+	releasePayloadInformerFactory.Start(ctx.Done())
+	if !cache.WaitForNamedCacheSync("ReleasePayloadAggregatorController", ctx.Done(), c.cachesToSync...) {
+		t.Fatalf("error waiting for caches to sync")
+	}
+
+	// syncAndCheck waits for the informer cache to hold the expected number of ReleasePayloads, syncs the namespace and
+	// compares the summary ConfigMap with the expected data
+	syncAndCheck := func(step string, count int, expected map[string]string) {
+		t.Helper()
+		if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+			return len(releasePayloadInformer.Informer().GetStore().List()) == count, nil
+		}); err != nil {
+			t.Fatalf("%s: timed out waiting for %d ReleasePayloads in the informer cache", step, count)
+		}
+		if err := c.sync(ctx, "ocp"); err != nil {
+			t.Fatalf("%s: unexpected err: %v", step, err)
+		}
+		configMap, err := kubeClient.CoreV1().ConfigMaps("ocp").Get(ctx, ReleasePayloadSummaryConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("%s: unable to get summary configmap: %v", step, err)
+		}
+		if diff := cmp.Diff(expected, configMap.Data); diff != "" {
+			t.Errorf("%s: unexpected summary (-want +got):\n%s", step, diff)
+		}
+	}
+
+	syncAndCheck("Initial", 2, map[string]string{"success": "1", "failed": "0", "pending": "1"})
+
+	// Add
+	if _, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads("ocp").Create(ctx, newAggregatorReleasePayload("4.11.0-0.nightly-2022-02-09-111559", v1alpha1.ConditionPayloadFailed), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unable to create releasepayload: %v", err)
+	}
+	syncAndCheck("Add", 3, map[string]string{"success": "1", "failed": "1", "pending": "1"})
+
+	// Update
+	if _, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads("ocp").UpdateStatus(ctx, newAggregatorReleasePayload("4.11.0-0.nightly-2022-02-09-101559", v1alpha1.ConditionPayloadAccepted), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("unable to update releasepayload: %v", err)
+	}
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		releasePayload, err := c.releasePayloadLister.ReleasePayloads("ocp").Get("4.11.0-0.nightly-2022-02-09-101559")
+		return err == nil && len(releasePayload.Status.Conditions) > 0, nil
+	}); err != nil {
+		t.Fatalf("timed out waiting for the updated releasepayload in the informer cache")
+	}
+	syncAndCheck("Update", 3, map[string]string{"success": "2", "failed": "1", "pending": "0"})
+
+	// Delete
+	if err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads("ocp").Delete(ctx, "4.11.0-0.nightly-2022-02-09-091559", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("unable to delete releasepayload: %v", err)
+	}
+	syncAndCheck("Delete", 2, map[string]string{"success": "1", "failed": "1", "pending": "0"})
+}
+
+func TestReleasePayloadAggregatorSyncEmptyNamespace(t *testing.T) {
+	releasePayloadClient := fake.NewSimpleClientset()
+	kubeClient := fake2.NewSimpleClientset()
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+
+	c, err := NewReleasePayloadAggregatorController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), kubeClient.CoreV1(), events.NewInMemoryRecorder("release-payload-aggregator-controller-test"))
+	if err != nil {
+		t.Fatalf("Failed to create Release Payload Aggregator Controller: %v", err)
+	}
+	defer c.queue.ShutDown()
+
+	releasePayloadInformerFactory.Start(context.Background().Done())
+	if !cache.WaitForNamedCacheSync("ReleasePayloadAggregatorController", context.Background().Done(), c.cachesToSync...) {
+		t.Fatalf("error waiting for caches to sync")
+	}
+
+	if err := c.sync(context.TODO(), "ocp"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err := kubeClient.CoreV1().ConfigMaps("ocp").Get(context.TODO(), ReleasePayloadSummaryConfigMapName, metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("Expected no summary configmap, got: %v", err)
+	}
+
+	// A namespace whose ReleasePayloads have all been deleted keeps a summary of zeros, along with the metadata added by others
+	if _, err := kubeClient.CoreV1().ConfigMaps("ocp").Create(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ReleasePayloadSummaryConfigMapName,
+			Namespace:   "ocp",
+			Labels:      map[string]string{"app": "release-dashboard"},
+			Annotations: map[string]string{"release.openshift.io/owner": "ci"},
+		},
+		Data: map[string]string{"success": "1", "failed": "0", "pending": "0"},
+	}, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unable to create configmap: %v", err)
+	}
+	if err := c.sync(context.TODO(), "ocp/4.11.0-0.nightly-2022-02-09-091559"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	configMap, err := kubeClient.CoreV1().ConfigMaps("ocp").Get(context.TODO(), ReleasePayloadSummaryConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get summary configmap: %v", err)
+	}
+	if diff := cmp.Diff(map[string]string{"success": "0", "failed": "0", "pending": "0"}, configMap.Data); diff != "" {
+		t.Errorf("unexpected summary (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"app": "release-dashboard"}, configMap.Labels); diff != "" {
+		t.Errorf("unexpected labels (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"release.openshift.io/owner": "ci"}, configMap.Annotations); diff != "" {
+		t.Errorf("unexpected annotations (-want +got):\n%s", diff)
+	}
+}
+
+func TestReleasePayloadAggregatorDebounce(t *testing.T) {
+	releasePayloadClient := fake.NewSimpleClientset()
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+
+	c, err := NewReleasePayloadAggregatorController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), fake2.NewSimpleClientset().CoreV1(), events.NewInMemoryRecorder("release-payload-aggregator-controller-test"))
+	if err != nil {
+		t.Fatalf("Failed to create Release Payload Aggregator Controller: %v", err)
+	}
+	queue := &recordingQueue{
+		RateLimitingInterface: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ReleasePayloadAggregatorController"),
+		addAfter:              map[interface{}]time.Duration{},
+	}
+	defer queue.ShutDown()
+	c.queue = queue
+
+	c.enqueueNamespace(newAggregatorReleasePayload("4.11.0-0.nightly-2022-02-09-091559"))
+	c.enqueueNamespace(cache.DeletedFinalStateUnknown{Key: "ci/4.11.0-0.ci-2022-02-09-091559"})
+
+	expected := map[interface{}]time.Duration{
+		"ocp": releasePayloadSummaryDebounce,
+		"ci":  releasePayloadSummaryDebounce,
+	}
+	if diff := cmp.Diff(expected, queue.addAfter); diff != "" {
+		t.Errorf("unexpected queue (-want +got):\n%s", diff)
+	}
+	if queue.Len() != 0 {
+		t.Errorf("Expected nothing to be queued immediately, got %d items", queue.Len())
+	}
+}
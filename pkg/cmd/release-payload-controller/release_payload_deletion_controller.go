@@ -0,0 +1,251 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
+	releasecontroller "github.com/openshift/release-controller/pkg/release-controller"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	batchv1informers "k8s.io/client-go/informers/batch/v1"
+	batchv1client "k8s.io/client-go/kubernetes/typed/batch/v1"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+)
+
+// JobCleanupFinalizer keeps a deleted ReleasePayload around until its release creation job has been deleted
+const JobCleanupFinalizer = "release.openshift.io/job-cleanup"
+
+// ReleasePayloadDeletionController is responsible for deleting the release creation jobs of a ReleasePayload, once the
+// ReleasePayload has been deleted.  The JobCleanupFinalizer is added to every ReleasePayload and, once a deleted
+// ReleasePayload's release creation job has reached a terminal state (Success or Failed), the jobs are deleted and the
+// finalizer is removed.  A ReleasePayload whose release creation jobs were never created, no longer exist or are
+// not running (i.e. the ReleasePayload is Suspended) is released immediately, since its status would never become
+// terminal.
+// The ReleasePayloadDeletionController reads the following pieces of information:
+//   - .metadata.deletionTimestamp
+//   - .metadata.finalizers
+//   - .spec.architectures
+//   - .spec.suspended
+//   - .status.releaseCreationJobResult.coordinates
+//   - .status.releaseCreationJobResult.status
+//   - .status.architectureResults[architecture].coordinates
+//
+// and updates the following pieces of information:
+//   - .metadata.finalizers
+type ReleasePayloadDeletionController struct {
+	*ReleasePayloadController
+
+	batchJobLister batchv1listers.JobLister
+	batchJobClient batchv1client.JobsGetter
+}
+
+func NewReleasePayloadDeletionController(
+	releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer,
+	releasePayloadClient releasepayloadclient.ReleaseV1alpha1Interface,
+	batchJobInformers map[string]batchv1informers.JobInformer,
+	batchJobClient batchv1client.JobsGetter,
+	eventRecorder events.Recorder,
+) (*ReleasePayloadDeletionController, error) {
+	c := &ReleasePayloadDeletionController{
+		ReleasePayloadController: NewReleasePayloadController("Release Payload Deletion Controller",
+			releasePayloadInformer,
+			releasePayloadClient,
+			eventRecorder.WithComponentSuffix("release-payload-deletion-controller"),
+			workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ReleasePayloadDeletionController")),
+		batchJobLister: newMultiNamespaceJobLister(batchJobInformers),
+		batchJobClient: batchJobClient,
+	}
+
+	c.syncFn = c.sync
+	c.shrinksCache = true
+
+	// A deleted ReleasePayload, waiting on its release creation job, is released once the job is deleted out of band
+	for _, batchJobInformer := range batchJobInformers {
+		c.cachesToSync = append(c.cachesToSync, batchJobInformer.Informer().HasSynced)
+
+		batchJobInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+			FilterFunc: func(obj interface{}) bool {
+				batchJob, ok := obj.(*batchv1.Job)
+				if !ok {
+					return false
+				}
+				_, ok = batchJob.Annotations[releasecontroller.ReleaseAnnotationReleaseTag]
+				return ok
+			},
+			Handler: cache.ResourceEventHandlerFuncs{
+				DeleteFunc: c.lookupReleasePayload,
+			},
+		})
+	}
+
+	releasePayloadInformer.Informer().AddEventHandler(&cache.ResourceEventHandlerFuncs{
+		AddFunc: c.Enqueue,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.Enqueue(newObj)
+		},
+	})
+
+	return c, nil
+}
+
+// hasFinalizer returns true if the finalizer is set on the ReleasePayload
+func hasFinalizer(releasePayload *v1alpha1.ReleasePayload, finalizer string) bool {
+	for _, f := range releasePayload.Finalizers {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// isReleaseCreationJobTerminal returns true if the release creation job has either succeeded or failed
+func isReleaseCreationJobTerminal(releasePayload *v1alpha1.ReleasePayload) bool {
+	switch releasePayload.Status.ReleaseCreationJobResult.Status {
+	case v1alpha1.ReleaseCreationJobSuccess, v1alpha1.ReleaseCreationJobFailed:
+		return true
+	}
+	return false
+}
+
+// releaseCreationJobCoordinates returns the coordinates, that have been set, of every release creation job of the
+// ReleasePayload.  The release creation job, of a ReleasePayload that lists its .spec.architectures, is the job that
+// assembles the manifest list of the release images of the architectures.
+func releaseCreationJobCoordinates(releasePayload *v1alpha1.ReleasePayload) []v1alpha1.ReleaseCreationJobCoordinates {
+	var coordinates []v1alpha1.ReleaseCreationJobCoordinates
+	add := func(c v1alpha1.ReleaseCreationJobCoordinates) {
+		if len(c.Namespace) > 0 && len(c.Name) > 0 {
+			coordinates = append(coordinates, c)
+		}
+	}
+	add(releasePayload.Status.ReleaseCreationJobResult.Coordinates)
+	for _, architecture := range releasePayload.Spec.Architectures {
+		add(releasePayload.Status.ArchitectureResults[architecture].Coordinates)
+	}
+	return coordinates
+}
+
+func (c *ReleasePayloadDeletionController) lookupReleasePayload(obj interface{}) {
+	releasePayloadKey, err := releaseCreationJobReleasePayloadKey(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(releasePayloadKey)
+}
+
+// releaseCreationJobRunning returns true if any of the release creation jobs exists and is neither finished nor
+// suspended
+func (c *ReleasePayloadDeletionController) releaseCreationJobRunning(coordinates []v1alpha1.ReleaseCreationJobCoordinates) (bool, error) {
+	for _, coordinate := range coordinates {
+		job, err := c.batchJobLister.Jobs(coordinate.Namespace).Get(coordinate.Name)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if !isJobCompleted(job) && !isJobFailed(job) && !pointer.BoolDeref(job.Spec.Suspend, false) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *ReleasePayloadDeletionController) sync(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting ReleasePayloadDeletionController sync")
+	defer logger.V(4).Info("ReleasePayloadDeletionController sync done")
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	// Get the ReleasePayload resource with this namespace/name
+	originalReleasePayload, err := c.releasePayloadLister.ReleasePayloads(namespace).Get(name)
+	// The ReleasePayload resource may no longer exist, in which case we stop processing.
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// A ReleasePayload that is being deleted has its release creation jobs cleaned up, even if its reconciliation has
+	// been paused, otherwise the finalizer would block its deletion indefinitely
+	if originalReleasePayload.DeletionTimestamp == nil {
+		// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+		if c.reconciliationPaused(originalReleasePayload) {
+			return nil
+		}
+		if hasFinalizer(originalReleasePayload, JobCleanupFinalizer) {
+			return nil
+		}
+		releasePayload := originalReleasePayload.DeepCopy()
+		releasePayload.Finalizers = append(releasePayload.Finalizers, JobCleanupFinalizer)
+		klog.V(4).Infof("Adding %s finalizer to ReleasePayload %s/%s", JobCleanupFinalizer, releasePayload.Namespace, releasePayload.Name)
+		_, err = c.releasePayloadClient.ReleasePayloads(releasePayload.Namespace).Update(ctx, releasePayload, metav1.UpdateOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !hasFinalizer(originalReleasePayload, JobCleanupFinalizer) {
+		return nil
+	}
+
+	// A ReleasePayload that lists its .spec.architectures has a release creation job per architecture
+	coordinates := releaseCreationJobCoordinates(originalReleasePayload)
+
+	// Wait for the release creation job to finish.  The ReleasePayload is requeued when its status is updated, or when
+	// a release creation job is deleted.  A Suspended ReleasePayload is not waited on, as its jobs will not finish.
+	if len(coordinates) > 0 && !isReleaseCreationJobTerminal(originalReleasePayload) && !originalReleasePayload.Spec.Suspended {
+		running, err := c.releaseCreationJobRunning(coordinates)
+		if err != nil {
+			return err
+		}
+		if running {
+			klog.V(4).Infof("Waiting for the release creation job, of deleted ReleasePayload %s/%s, to finish", originalReleasePayload.Namespace, originalReleasePayload.Name)
+			return nil
+		}
+	}
+
+	for _, job := range coordinates {
+		propagationPolicy := metav1.DeletePropagationBackground
+		err = c.batchJobClient.Jobs(job.Namespace).Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: &propagationPolicy})
+		switch {
+		case errors.IsNotFound(err):
+		case err != nil:
+			return err
+		default:
+			c.eventRecorder.Eventf("ReleaseCreationJobDeleted", "Deleted release creation job %s/%s of ReleasePayload %s/%s", job.Namespace, job.Name, originalReleasePayload.Namespace, originalReleasePayload.Name)
+		}
+	}
+
+	releasePayload := originalReleasePayload.DeepCopy()
+	releasePayload.Finalizers = nil
+	for _, finalizer := range originalReleasePayload.Finalizers {
+		if finalizer != JobCleanupFinalizer {
+			releasePayload.Finalizers = append(releasePayload.Finalizers, finalizer)
+		}
+	}
+	klog.V(4).Infof("Removing %s finalizer from ReleasePayload %s/%s", JobCleanupFinalizer, releasePayload.Namespace, releasePayload.Name)
+	_, err = c.releasePayloadClient.ReleasePayloads(releasePayload.Namespace).Update(ctx, releasePayload, metav1.UpdateOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
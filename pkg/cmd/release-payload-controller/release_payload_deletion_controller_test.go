@@ -0,0 +1,426 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	batchv1informers "k8s.io/client-go/informers/batch/v1"
+	fake2 "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+)
+
+func TestReleasePayloadDeletionSync(t *testing.T) {
+	deletionTimestamp := metav1.NewTime(time.Date(2022, 2, 12, 9, 15, 59, 0, time.UTC))
+
+	newReleasePayload := func(deleted bool, status v1alpha1.ReleaseCreationJobStatus, jobCreated bool, finalizers ...string) *v1alpha1.ReleasePayload {
+		releasePayload := &v1alpha1.ReleasePayload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "4.11.0-0.nightly-2022-02-09-091559",
+				Namespace:  "ocp",
+				Finalizers: finalizers,
+			},
+			Status: v1alpha1.ReleasePayloadStatus{
+				ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+					Status: status,
+				},
+			},
+		}
+		if deleted {
+			releasePayload.DeletionTimestamp = &deletionTimestamp
+		}
+		if jobCreated {
+			releasePayload.Status.ReleaseCreationJobResult.Coordinates = v1alpha1.ReleaseCreationJobCoordinates{
+				Name:      "4.11.0-0.nightly-2022-02-09-091559",
+				Namespace: "ci-release",
+			}
+		}
+		return releasePayload
+	}
+
+	suspended := func(releasePayload *v1alpha1.ReleasePayload) *v1alpha1.ReleasePayload {
+		releasePayload.Spec.Suspended = true
+		return releasePayload
+	}
+
+	paused := func(releasePayload *v1alpha1.ReleasePayload) *v1alpha1.ReleasePayload {
+		releasePayload.Spec.PauseReconciliation = true
+		return releasePayload
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ci-release",
+		},
+	}
+
+	testCases := []struct {
+		name               string
+		input              *v1alpha1.ReleasePayload
+		jobExists          bool
+		jobSuspended       bool
+		expectedFinalizers []string
+		expectedJobDeleted bool
+		expectedEvents     int
+	}{
+		{
+			name:               "NewReleasePayload",
+			input:              newReleasePayload(false, "", false),
+			expectedFinalizers: []string{JobCleanupFinalizer},
+		},
+		{
+			name:               "NewReleasePayloadWithOtherFinalizer",
+			input:              newReleasePayload(false, "", false, "example.com/finalizer"),
+			expectedFinalizers: []string{"example.com/finalizer", JobCleanupFinalizer},
+		},
+		{
+			name:               "FinalizerAlreadySet",
+			input:              newReleasePayload(false, v1alpha1.ReleaseCreationJobSuccess, true, JobCleanupFinalizer),
+			jobExists:          true,
+			expectedFinalizers: []string{JobCleanupFinalizer},
+		},
+		{
+			name:               "DeletedWithSucceededJob",
+			input:              newReleasePayload(true, v1alpha1.ReleaseCreationJobSuccess, true, JobCleanupFinalizer),
+			jobExists:          true,
+			expectedJobDeleted: true,
+			expectedEvents:     1,
+		},
+		{
+			name:               "DeletedWithFailedJob",
+			input:              newReleasePayload(true, v1alpha1.ReleaseCreationJobFailed, true, JobCleanupFinalizer),
+			jobExists:          true,
+			expectedJobDeleted: true,
+			expectedEvents:     1,
+		},
+		{
+			name:               "DeletedWithRunningJob",
+			input:              newReleasePayload(true, v1alpha1.ReleaseCreationJobUnknown, true, JobCleanupFinalizer),
+			jobExists:          true,
+			expectedFinalizers: []string{JobCleanupFinalizer},
+		},
+		{
+			name:               "DeletedWithPendingJob",
+			input:              newReleasePayload(true, v1alpha1.ReleaseCreationJobPending, true, JobCleanupFinalizer),
+			jobExists:          true,
+			expectedFinalizers: []string{JobCleanupFinalizer},
+		},
+		{
+			name:               "DeletedWithRunningJobDeletedOutOfBand",
+			input:              newReleasePayload(true, v1alpha1.ReleaseCreationJobUnknown, true, JobCleanupFinalizer),
+			expectedJobDeleted: true,
+		},
+		{
+			name:               "DeletedWithSuspendedReleasePayload",
+			input:              suspended(newReleasePayload(true, v1alpha1.ReleaseCreationJobPending, true, JobCleanupFinalizer)),
+			jobExists:          true,
+			expectedJobDeleted: true,
+			expectedEvents:     1,
+		},
+		{
+			name:  "NewPausedReleasePayload",
+			input: paused(newReleasePayload(false, "", false)),
+		},
+		{
+			name:               "DeletedWithPausedReleasePayload",
+			input:              paused(newReleasePayload(true, v1alpha1.ReleaseCreationJobSuccess, true, JobCleanupFinalizer)),
+			jobExists:          true,
+			expectedJobDeleted: true,
+			expectedEvents:     1,
+		},
+		{
+			name:               "DeletedWithSuspendedJob",
+			input:              newReleasePayload(true, v1alpha1.ReleaseCreationJobPending, true, JobCleanupFinalizer),
+			jobExists:          true,
+			jobSuspended:       true,
+			expectedJobDeleted: true,
+			expectedEvents:     1,
+		},
+		{
+			name:               "DeletedWithJobAlreadyDeleted",
+			input:              newReleasePayload(true, v1alpha1.ReleaseCreationJobSuccess, true, JobCleanupFinalizer),
+			expectedJobDeleted: true,
+		},
+		{
+			name:  "DeletedWithoutJob",
+			input: newReleasePayload(true, "", false, JobCleanupFinalizer),
+		},
+		{
+			name:               "DeletedWithOtherFinalizer",
+			input:              newReleasePayload(true, v1alpha1.ReleaseCreationJobSuccess, true, "example.com/finalizer", JobCleanupFinalizer),
+			jobExists:          true,
+			expectedFinalizers: []string{"example.com/finalizer"},
+			expectedJobDeleted: true,
+			expectedEvents:     1,
+		},
+		{
+			name:               "DeletedWithoutFinalizer",
+			input:              newReleasePayload(true, v1alpha1.ReleaseCreationJobSuccess, true, "example.com/finalizer"),
+			jobExists:          true,
+			expectedFinalizers: []string{"example.com/finalizer"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			releasePayloadClient := fake.NewSimpleClientset(testCase.input)
+			releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+			releasePayloadInformer := releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads()
+
+			kubeClient := fake2.NewSimpleClientset()
+			if testCase.jobExists {
+				existing := job.DeepCopy()
+				existing.Spec.Suspend = pointer.Bool(testCase.jobSuspended)
+				kubeClient = fake2.NewSimpleClientset(existing)
+			}
+			kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, controllerDefaultResyncDuration)
+
+			recorder := events.NewInMemoryRecorder("release-payload-deletion-controller-test")
+			c, err := NewReleasePayloadDeletionController(releasePayloadInformer, releasePayloadClient.ReleaseV1alpha1(), map[string]batchv1informers.JobInformer{metav1.NamespaceAll: kubeInformerFactory.Batch().V1().Jobs()}, kubeClient.BatchV1(), recorder)
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			defer c.queue.ShutDown()
+
+			releasePayloadInformerFactory.Start(context.Background().Done())
+			kubeInformerFactory.Start(context.Background().Done())
+
+			if !cache.WaitForNamedCacheSync("ReleasePayloadDeletionController", context.Background().Done(), c.cachesToSync...) {
+				t.Fatalf("%s: error waiting for caches to sync", testCase.name)
+			}
+
+			key := fmt.Sprintf("%s/%s", testCase.input.Namespace, testCase.input.Name)
+			if err := c.sync(context.TODO(), key); err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+
+			releasePayload, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads(testCase.input.Namespace).Get(context.TODO(), testCase.input.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			if !cmp.Equal(releasePayload.Finalizers, testCase.expectedFinalizers) {
+				t.Errorf("%s: Unexpected finalizers: %s", testCase.name, cmp.Diff(testCase.expectedFinalizers, releasePayload.Finalizers))
+			}
+
+			deleted := false
+			for _, action := range kubeClient.Actions() {
+				if action.Matches("delete", "jobs") && action.GetNamespace() == job.Namespace {
+					deleted = true
+				}
+			}
+			if deleted != testCase.expectedJobDeleted {
+				t.Errorf("%s: Expected job deleted: %t, got: %t", testCase.name, testCase.expectedJobDeleted, deleted)
+			}
+
+			if events := len(recorder.Events()); events != testCase.expectedEvents {
+				t.Errorf("%s: Expected %d events, got %d", testCase.name, testCase.expectedEvents, events)
+			}
+		})
+	}
+}
+
+// TestReleasePayloadDeletionSyncArchitectures verifies that the release creation job, of every architecture, of a
+// deleted ReleasePayload is deleted
+func TestReleasePayloadDeletionSyncArchitectures(t *testing.T) {
+	deletionTimestamp := metav1.NewTime(time.Date(2022, 2, 12, 9, 15, 59, 0, time.UTC))
+	input := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace:         "ocp",
+			Finalizers:        []string{JobCleanupFinalizer},
+			DeletionTimestamp: &deletionTimestamp,
+		},
+		Spec: v1alpha1.ReleasePayloadSpec{
+			Architectures: []string{"amd64", "arm64"},
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+					Name:      "4.11.0-0.nightly-2022-02-09-091559",
+					Namespace: "ci-release",
+				},
+				Status: v1alpha1.ReleaseCreationJobFailed,
+			},
+			ArchitectureResults: map[string]v1alpha1.ReleaseCreationJobResult{
+				"amd64": {
+					Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+						Name:      "4.11.0-0.nightly-2022-02-09-091559-amd64",
+						Namespace: "ci-release",
+					},
+					Status: v1alpha1.ReleaseCreationJobSuccess,
+				},
+				"arm64": {
+					Coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+						Name:      "4.11.0-0.nightly-2022-02-09-091559-arm64",
+						Namespace: "ci-release",
+					},
+					Status: v1alpha1.ReleaseCreationJobFailed,
+				},
+			},
+		},
+	}
+	var jobs []runtime.Object
+	for _, name := range []string{"4.11.0-0.nightly-2022-02-09-091559", "4.11.0-0.nightly-2022-02-09-091559-amd64", "4.11.0-0.nightly-2022-02-09-091559-arm64"} {
+		jobs = append(jobs, &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ci-release"}})
+	}
+
+	releasePayloadClient := fake.NewSimpleClientset(input)
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+	kubeClient := fake2.NewSimpleClientset(jobs...)
+	kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, controllerDefaultResyncDuration)
+
+	c, err := NewReleasePayloadDeletionController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), map[string]batchv1informers.JobInformer{metav1.NamespaceAll: kubeInformerFactory.Batch().V1().Jobs()}, kubeClient.BatchV1(), events.NewInMemoryRecorder("release-payload-deletion-controller-test"))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	defer c.queue.ShutDown()
+
+	releasePayloadInformerFactory.Start(context.Background().Done())
+	kubeInformerFactory.Start(context.Background().Done())
+	if !cache.WaitForNamedCacheSync("ReleasePayloadDeletionController", context.Background().Done(), c.cachesToSync...) {
+		t.Fatalf("error waiting for caches to sync")
+	}
+
+	if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	var deleted []string
+	for _, action := range kubeClient.Actions() {
+		if action.Matches("delete", "jobs") {
+			deleted = append(deleted, action.(clienttesting.DeleteAction).GetName())
+		}
+	}
+	expected := []string{"4.11.0-0.nightly-2022-02-09-091559", "4.11.0-0.nightly-2022-02-09-091559-amd64", "4.11.0-0.nightly-2022-02-09-091559-arm64"}
+	if !cmp.Equal(deleted, expected) {
+		t.Errorf("Unexpected deleted jobs: %s", cmp.Diff(expected, deleted))
+	}
+
+	releasePayload, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if hasFinalizer(releasePayload, JobCleanupFinalizer) {
+		t.Errorf("Expected the %s finalizer to be removed", JobCleanupFinalizer)
+	}
+}
+
+func TestReleasePayloadDeletionFinalizerCycle(t *testing.T) {
+	releasePayload := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ci-release",
+		},
+	}
+
+	releasePayloadClient := fake.NewSimpleClientset(releasePayload)
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+	kubeClient := fake2.NewSimpleClientset(job)
+	kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, controllerDefaultResyncDuration)
+
+	c, err := NewReleasePayloadDeletionController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), map[string]batchv1informers.JobInformer{metav1.NamespaceAll: kubeInformerFactory.Batch().V1().Jobs()}, kubeClient.BatchV1(), events.NewInMemoryRecorder("release-payload-deletion-controller-test"))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	defer c.queue.ShutDown()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	releasePayloadInformerFactory.Start(stopCh)
+	kubeInformerFactory.Start(stopCh)
+	if !cache.WaitForNamedCacheSync("ReleasePayloadDeletionController", stopCh, c.cachesToSync...) {
+		t.Fatalf("error waiting for caches to sync")
+	}
+
+	key := "ocp/4.11.0-0.nightly-2022-02-09-091559"
+	get := func() *v1alpha1.ReleasePayload {
+		current, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads("ocp").Get(context.TODO(), releasePayload.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		return current
+	}
+	// update writes the ReleasePayload and waits for the informer to observe it
+	update := func(mutate func(*v1alpha1.ReleasePayload)) {
+		current := get()
+		mutate(current)
+		updated, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads("ocp").Update(context.TODO(), current, metav1.UpdateOptions{})
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		waitForLister(t, c, updated)
+	}
+	sync := func() {
+		if err := c.sync(context.TODO(), key); err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		waitForLister(t, c, get())
+	}
+
+	// The finalizer is added to the new ReleasePayload
+	sync()
+	if !hasFinalizer(get(), JobCleanupFinalizer) {
+		t.Fatalf("Expected the %s finalizer to be added", JobCleanupFinalizer)
+	}
+
+	// The release creation job is created and the ReleasePayload is deleted while the job is still running
+	update(func(releasePayload *v1alpha1.ReleasePayload) {
+		releasePayload.Status.ReleaseCreationJobResult = v1alpha1.ReleaseCreationJobResult{
+			Status:      v1alpha1.ReleaseCreationJobUnknown,
+			Coordinates: v1alpha1.ReleaseCreationJobCoordinates{Name: job.Name, Namespace: job.Namespace},
+		}
+		now := metav1.Now()
+		releasePayload.DeletionTimestamp = &now
+	})
+	sync()
+	if !hasFinalizer(get(), JobCleanupFinalizer) {
+		t.Fatalf("Expected the %s finalizer to be kept while the release creation job is running", JobCleanupFinalizer)
+	}
+	if _, err := kubeClient.BatchV1().Jobs(job.Namespace).Get(context.TODO(), job.Name, metav1.GetOptions{}); err != nil {
+		t.Fatalf("Expected the running release creation job to be kept, got: %v", err)
+	}
+
+	// The release creation job finishes, after which it is deleted and the finalizer is removed
+	update(func(releasePayload *v1alpha1.ReleasePayload) {
+		releasePayload.Status.ReleaseCreationJobResult.Status = v1alpha1.ReleaseCreationJobSuccess
+	})
+	sync()
+	if hasFinalizer(get(), JobCleanupFinalizer) {
+		t.Errorf("Expected the %s finalizer to be removed", JobCleanupFinalizer)
+	}
+	if _, err := kubeClient.BatchV1().Jobs(job.Namespace).Get(context.TODO(), job.Name, metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("Expected the release creation job to be deleted, got: %v", err)
+	}
+}
+
+// waitForLister waits for the controller's lister to return the resource version of the ReleasePayload
+func waitForLister(t *testing.T, c *ReleasePayloadDeletionController, releasePayload *v1alpha1.ReleasePayload) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		cached, err := c.releasePayloadLister.ReleasePayloads(releasePayload.Namespace).Get(releasePayload.Name)
+		if err == nil && cmp.Equal(cached, releasePayload) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for the lister to observe ReleasePayload %s/%s", releasePayload.Namespace, releasePayload.Name)
+}
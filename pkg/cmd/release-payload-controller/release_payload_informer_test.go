@@ -0,0 +1,87 @@
+package release_payload_controller
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// TestReleasePayloadInformerResumesWatch verifies that, when the watch of ReleasePayloads is dropped, the informer
+// resumes watching from the last resource version it has seen (including bookmarks) instead of re-listing everything
+func TestReleasePayloadInformerResumesWatch(t *testing.T) {
+	releasePayloadClient := fake.NewSimpleClientset(&v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+	})
+
+	var lock sync.Mutex
+	var lists int
+	var watchResourceVersions []string
+	watchers := []*watch.FakeWatcher{watch.NewFake(), watch.NewFake()}
+
+	releasePayloadClient.PrependReactor("list", "releasepayloads", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		lists++
+		return false, nil, nil
+	})
+	releasePayloadClient.PrependWatchReactor("releasepayloads", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		watchResourceVersions = append(watchResourceVersions, action.(clienttesting.WatchActionImpl).GetWatchRestrictions().ResourceVersion)
+		if len(watchResourceVersions) > len(watchers) {
+			return true, watch.NewFake(), nil
+		}
+		return true, watchers[len(watchResourceVersions)-1], nil
+	})
+
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+	releasePayloadInformer := releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads().Informer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	releasePayloadInformerFactory.Start(ctx.Done())
+
+	watchStarted := func(count int) wait.ConditionFunc {
+		return func() (bool, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			return len(watchResourceVersions) >= count, nil
+		}
+	}
+	if err := wait.PollImmediate(10*time.Millisecond, 10*time.Second, watchStarted(1)); err != nil {
+		t.Fatalf("the informer did not start watching: %v", err)
+	}
+
+	// A bookmark advances the resource version of the informer, without any object changing, then the connection drops
+	watchers[0].Action(watch.Bookmark, &v1alpha1.ReleasePayload{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "10"}})
+	watchers[0].Stop()
+
+	if err := wait.PollImmediate(10*time.Millisecond, 10*time.Second, watchStarted(2)); err != nil {
+		t.Fatalf("the informer did not resume watching: %v", err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if lists != 1 {
+		t.Errorf("Expected the ReleasePayloads to be listed once, got %d", lists)
+	}
+	if watchResourceVersions[1] != "10" {
+		t.Errorf("Expected the watch to resume from resource version 10, got %q", watchResourceVersions[1])
+	}
+	if releasePayloadInformer.LastSyncResourceVersion() != "10" {
+		t.Errorf("Expected the informer to be at resource version 10, got %q", releasePayloadInformer.LastSyncResourceVersion())
+	}
+}
@@ -0,0 +1,327 @@
+package release_payload_controller
+
+import (
+	"fmt"
+	"time"
+
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
+	releasepayloadlister "github.com/openshift/release-controller/pkg/client/listers/release/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+)
+
+// newReleasePayloadInformerFactories returns a SharedInformerFactory for each of the specified namespaces.  If no
+// namespaces are specified, a single SharedInformerFactory, watching all namespaces, is returned.
+func newReleasePayloadInformerFactories(releasePayloadClient releasepayloadclient.Interface, namespaces []string, tweakListOptions func(*metav1.ListOptions)) map[string]releasepayloadinformers.SharedInformerFactory {
+	if len(namespaces) == 0 {
+		return map[string]releasepayloadinformers.SharedInformerFactory{
+			metav1.NamespaceAll: releasepayloadinformers.NewSharedInformerFactoryWithOptions(releasePayloadClient, controllerDefaultResyncDuration, releasepayloadinformers.WithTweakListOptions(tweakListOptions)),
+		}
+	}
+	factories := make(map[string]releasepayloadinformers.SharedInformerFactory)
+	for _, namespace := range namespaces {
+		factories[namespace] = releasepayloadinformers.NewSharedInformerFactoryWithOptions(releasePayloadClient, controllerDefaultResyncDuration, releasepayloadinformers.WithNamespace(namespace), releasepayloadinformers.WithTweakListOptions(tweakListOptions))
+	}
+	return factories
+}
+
+// newReleasePayloadInformer returns a ReleasePayloadInformer that spans the ReleasePayload informers of all the
+// factories.  The informer of the factory is returned, as is, if there is only one.
+func newReleasePayloadInformer(factories map[string]releasepayloadinformers.SharedInformerFactory) releasepayloadinformer.ReleasePayloadInformer {
+	informers := make(map[string]cache.SharedIndexInformer)
+	for namespace, factory := range factories {
+		if len(factories) == 1 {
+			return factory.Release().V1alpha1().ReleasePayloads()
+		}
+		informers[namespace] = factory.Release().V1alpha1().ReleasePayloads().Informer()
+	}
+	return &multiNamespaceReleasePayloadInformer{
+		informer: &multiNamespaceInformer{informers: informers},
+	}
+}
+
+// multiNamespaceReleasePayloadInformer implements releasepayloadinformer.ReleasePayloadInformer on top of several
+// namespace scoped informers
+type multiNamespaceReleasePayloadInformer struct {
+	informer *multiNamespaceInformer
+}
+
+func (i *multiNamespaceReleasePayloadInformer) Informer() cache.SharedIndexInformer {
+	return i.informer
+}
+
+func (i *multiNamespaceReleasePayloadInformer) Lister() releasepayloadlister.ReleasePayloadLister {
+	return releasepayloadlister.NewReleasePayloadLister(i.informer.GetIndexer())
+}
+
+// multiNamespaceInformer implements cache.SharedIndexInformer by fanning out to the informers of several namespaces.
+// Event handlers are registered with every informer and the informer has only synced once all of them have.  Its
+// store is read only, like the stores of any other informer are meant to be.
+type multiNamespaceInformer struct {
+	informers map[string]cache.SharedIndexInformer
+}
+
+var _ cache.SharedIndexInformer = &multiNamespaceInformer{}
+
+// multiNamespaceEventHandlerRegistration holds the registrations of an event handler with each of the informers
+type multiNamespaceEventHandlerRegistration struct {
+	registrations map[string]cache.ResourceEventHandlerRegistration
+}
+
+func (r *multiNamespaceEventHandlerRegistration) HasSynced() bool {
+	for _, registration := range r.registrations {
+		if !registration.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+func (i *multiNamespaceInformer) AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error) {
+	return i.addEventHandler(func(informer cache.SharedIndexInformer) (cache.ResourceEventHandlerRegistration, error) {
+		return informer.AddEventHandler(handler)
+	})
+}
+
+func (i *multiNamespaceInformer) AddEventHandlerWithResyncPeriod(handler cache.ResourceEventHandler, resyncPeriod time.Duration) (cache.ResourceEventHandlerRegistration, error) {
+	return i.addEventHandler(func(informer cache.SharedIndexInformer) (cache.ResourceEventHandlerRegistration, error) {
+		return informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	})
+}
+
+func (i *multiNamespaceInformer) addEventHandler(add func(cache.SharedIndexInformer) (cache.ResourceEventHandlerRegistration, error)) (cache.ResourceEventHandlerRegistration, error) {
+	registration := &multiNamespaceEventHandlerRegistration{registrations: make(map[string]cache.ResourceEventHandlerRegistration)}
+	for namespace, informer := range i.informers {
+		r, err := add(informer)
+		if err != nil {
+			return nil, fmt.Errorf("unable to add event handler to the informer of namespace %s: %w", namespace, err)
+		}
+		registration.registrations[namespace] = r
+	}
+	return registration, nil
+}
+
+func (i *multiNamespaceInformer) RemoveEventHandler(handle cache.ResourceEventHandlerRegistration) error {
+	registration, ok := handle.(*multiNamespaceEventHandlerRegistration)
+	if !ok {
+		return fmt.Errorf("unknown event handler registration: %T", handle)
+	}
+	for namespace, r := range registration.registrations {
+		if err := i.informers[namespace].RemoveEventHandler(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *multiNamespaceInformer) GetStore() cache.Store {
+	return i.GetIndexer()
+}
+
+func (i *multiNamespaceInformer) GetIndexer() cache.Indexer {
+	indexers := make(map[string]cache.Indexer)
+	for namespace, informer := range i.informers {
+		indexers[namespace] = informer.GetIndexer()
+	}
+	return &multiNamespaceIndexer{indexers: indexers}
+}
+
+// GetController is not supported, the informers are run by their factories
+func (i *multiNamespaceInformer) GetController() cache.Controller {
+	return nil
+}
+
+func (i *multiNamespaceInformer) Run(stopCh <-chan struct{}) {
+	for _, informer := range i.informers {
+		go informer.Run(stopCh)
+	}
+	<-stopCh
+}
+
+func (i *multiNamespaceInformer) HasSynced() bool {
+	for _, informer := range i.informers {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// LastSyncResourceVersion is meaningless across several watches and is always empty
+func (i *multiNamespaceInformer) LastSyncResourceVersion() string {
+	return ""
+}
+
+func (i *multiNamespaceInformer) SetWatchErrorHandler(handler cache.WatchErrorHandler) error {
+	for namespace, informer := range i.informers {
+		if err := informer.SetWatchErrorHandler(handler); err != nil {
+			return fmt.Errorf("unable to set the watch error handler of the informer of namespace %s: %w", namespace, err)
+		}
+	}
+	return nil
+}
+
+func (i *multiNamespaceInformer) SetTransform(handler cache.TransformFunc) error {
+	for namespace, informer := range i.informers {
+		if err := informer.SetTransform(handler); err != nil {
+			return fmt.Errorf("unable to set the transform of the informer of namespace %s: %w", namespace, err)
+		}
+	}
+	return nil
+}
+
+func (i *multiNamespaceInformer) IsStopped() bool {
+	for _, informer := range i.informers {
+		if !informer.IsStopped() {
+			return false
+		}
+	}
+	return true
+}
+
+func (i *multiNamespaceInformer) AddIndexers(indexers cache.Indexers) error {
+	for namespace, informer := range i.informers {
+		if err := informer.AddIndexers(indexers); err != nil {
+			return fmt.Errorf("unable to add indexers to the informer of namespace %s: %w", namespace, err)
+		}
+	}
+	return nil
+}
+
+// multiNamespaceIndexer implements a read only cache.Indexer on top of the indexers of several namespace scoped
+// informers.  Lookups in a namespace that is not being watched behave as if the namespace was empty.
+type multiNamespaceIndexer struct {
+	indexers map[string]cache.Indexer
+}
+
+var errReadOnlyIndexer = fmt.Errorf("the indexer of a multi namespace informer is read only")
+
+func (i *multiNamespaceIndexer) Add(obj interface{}) error {
+	return errReadOnlyIndexer
+}
+
+func (i *multiNamespaceIndexer) Update(obj interface{}) error {
+	return errReadOnlyIndexer
+}
+
+func (i *multiNamespaceIndexer) Delete(obj interface{}) error {
+	return errReadOnlyIndexer
+}
+
+func (i *multiNamespaceIndexer) Replace(list []interface{}, resourceVersion string) error {
+	return errReadOnlyIndexer
+}
+
+func (i *multiNamespaceIndexer) Resync() error {
+	return nil
+}
+
+func (i *multiNamespaceIndexer) List() []interface{} {
+	var ret []interface{}
+	for _, indexer := range i.indexers {
+		ret = append(ret, indexer.List()...)
+	}
+	return ret
+}
+
+func (i *multiNamespaceIndexer) ListKeys() []string {
+	var ret []string
+	for _, indexer := range i.indexers {
+		ret = append(ret, indexer.ListKeys()...)
+	}
+	return ret
+}
+
+func (i *multiNamespaceIndexer) Get(obj interface{}) (interface{}, bool, error) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return nil, false, err
+	}
+	return i.GetByKey(key)
+}
+
+func (i *multiNamespaceIndexer) GetByKey(key string) (interface{}, bool, error) {
+	namespace, _, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	indexer, ok := i.indexers[namespace]
+	if !ok {
+		return nil, false, nil
+	}
+	return indexer.GetByKey(key)
+}
+
+func (i *multiNamespaceIndexer) Index(indexName string, obj interface{}) ([]interface{}, error) {
+	var ret []interface{}
+	for _, indexer := range i.indexers {
+		items, err := indexer.Index(indexName, obj)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, items...)
+	}
+	return ret, nil
+}
+
+func (i *multiNamespaceIndexer) IndexKeys(indexName, indexedValue string) ([]string, error) {
+	var ret []string
+	for _, indexer := range i.indexersFor(indexName, indexedValue) {
+		keys, err := indexer.IndexKeys(indexName, indexedValue)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, keys...)
+	}
+	return ret, nil
+}
+
+func (i *multiNamespaceIndexer) ListIndexFuncValues(indexName string) []string {
+	values := sets.NewString()
+	for _, indexer := range i.indexers {
+		values.Insert(indexer.ListIndexFuncValues(indexName)...)
+	}
+	return values.List()
+}
+
+func (i *multiNamespaceIndexer) ByIndex(indexName, indexedValue string) ([]interface{}, error) {
+	var ret []interface{}
+	for _, indexer := range i.indexersFor(indexName, indexedValue) {
+		items, err := indexer.ByIndex(indexName, indexedValue)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, items...)
+	}
+	return ret, nil
+}
+
+func (i *multiNamespaceIndexer) GetIndexers() cache.Indexers {
+	for _, indexer := range i.indexers {
+		return indexer.GetIndexers()
+	}
+	return cache.Indexers{}
+}
+
+func (i *multiNamespaceIndexer) AddIndexers(newIndexers cache.Indexers) error {
+	return errReadOnlyIndexer
+}
+
+// indexersFor returns only the indexer of the namespace, for lookups by the namespace index (i.e. the namespace
+// listers), and all the indexers otherwise
+func (i *multiNamespaceIndexer) indexersFor(indexName, indexedValue string) []cache.Indexer {
+	if indexName == cache.NamespaceIndex {
+		if indexer, ok := i.indexers[indexedValue]; ok {
+			return []cache.Indexer{indexer}
+		}
+		return nil
+	}
+	var ret []cache.Indexer
+	for _, indexer := range i.indexers {
+		ret = append(ret, indexer)
+	}
+	return ret
+}
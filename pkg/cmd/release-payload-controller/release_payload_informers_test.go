@@ -0,0 +1,110 @@
+package release_payload_controller
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newNamespacedReleasePayload(namespace, name string) *v1alpha1.ReleasePayload {
+	return &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+}
+
+func TestMultiNamespaceReleasePayloadInformer(t *testing.T) {
+	releasePayloadClient := fake.NewSimpleClientset(
+		newNamespacedReleasePayload("ocp", "4.11.0-0.nightly-2022-02-09-091559"),
+		newNamespacedReleasePayload("ocp-arm64", "4.11.0-0.nightly-arm64-2022-02-09-091559"),
+		newNamespacedReleasePayload("origin", "4.11.0-0.okd-2022-02-09-091559"),
+	)
+
+	factories := newReleasePayloadInformerFactories(releasePayloadClient, []string{"ocp", "ocp-arm64"}, nil)
+	if len(factories) != 2 {
+		t.Fatalf("expected 2 informer factories, got %d", len(factories))
+	}
+	informer := newReleasePayloadInformer(factories)
+
+	var lock sync.Mutex
+	added := sets.NewString()
+	registration, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			key, _ := cache.MetaNamespaceKeyFunc(obj)
+			lock.Lock()
+			defer lock.Unlock()
+			added.Insert(key)
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to add event handler: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, factory := range factories {
+		factory.Start(ctx.Done())
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), informer.Informer().HasSynced, registration.HasSynced) {
+		t.Fatalf("informers did not sync")
+	}
+
+	expected := sets.NewString("ocp/4.11.0-0.nightly-2022-02-09-091559", "ocp-arm64/4.11.0-0.nightly-arm64-2022-02-09-091559")
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		return added.Equal(expected), nil
+	}); err != nil {
+		t.Errorf("expected added events for %v, got %v", expected.List(), added.List())
+	}
+
+	if keys := sets.NewString(informer.Informer().GetStore().ListKeys()...); !keys.Equal(expected) {
+		t.Errorf("unexpected store keys: %s", cmp.Diff(expected.List(), keys.List()))
+	}
+
+	payloads, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		t.Fatalf("unable to list release payloads: %v", err)
+	}
+	if len(payloads) != 2 {
+		t.Errorf("expected 2 release payloads, got %d", len(payloads))
+	}
+
+	if _, err := informer.Lister().ReleasePayloads("ocp-arm64").Get("4.11.0-0.nightly-arm64-2022-02-09-091559"); err != nil {
+		t.Errorf("unable to get release payload from a watched namespace: %v", err)
+	}
+	if _, err := informer.Lister().ReleasePayloads("ocp").Get("4.11.0-0.nightly-arm64-2022-02-09-091559"); err == nil {
+		t.Errorf("expected release payload to be found only in its own namespace")
+	}
+	if payloads, err := informer.Lister().ReleasePayloads("origin").List(labels.Everything()); err != nil || len(payloads) != 0 {
+		t.Errorf("expected no release payloads in a namespace that is not watched, got %d: %v", len(payloads), err)
+	}
+
+	if err := informer.Informer().GetStore().Add(newNamespacedReleasePayload("ocp", "4.11.0-0.nightly-2022-02-10-091559")); err == nil {
+		t.Errorf("expected the store to be read only")
+	}
+}
+
+func TestReleasePayloadInformerAllNamespaces(t *testing.T) {
+	releasePayloadClient := fake.NewSimpleClientset()
+
+	factories := newReleasePayloadInformerFactories(releasePayloadClient, nil, nil)
+	if _, ok := factories[metav1.NamespaceAll]; !ok || len(factories) != 1 {
+		t.Fatalf("expected a single informer factory for all namespaces, got %d", len(factories))
+	}
+	if _, ok := newReleasePayloadInformer(factories).(*multiNamespaceReleasePayloadInformer); ok {
+		t.Errorf("expected the informer of the factory to be used as is")
+	}
+}
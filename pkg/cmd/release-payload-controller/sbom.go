@@ -0,0 +1,119 @@
+package release_payload_controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// SBOMGenerator generates the Software Bill of Materials of the component images of a release
+type SBOMGenerator interface {
+	// ComponentImages returns the pull specs, by component name, of the component images of the release image
+	ComponentImages(ctx context.Context, releasePullSpec string) (map[string]string, error)
+
+	// ImageSBOM returns the SBOM, as JSON, of the image
+	ImageSBOM(ctx context.Context, pullSpec string) ([]byte, error)
+}
+
+// execSBOMGenerator lists the component images of a release with `oc adm release info`, and generates the SPDX SBOM
+// of each of them with `syft`.  Both binaries must be on the PATH.
+type execSBOMGenerator struct{}
+
+func newExecSBOMGenerator() (SBOMGenerator, error) {
+	for _, binary := range []string{"oc", "syft"} {
+		if _, err := exec.LookPath(binary); err != nil {
+			return nil, fmt.Errorf("unable to find %s: %w", binary, err)
+		}
+	}
+	return &execSBOMGenerator{}, nil
+}
+
+func (g *execSBOMGenerator) ComponentImages(ctx context.Context, releasePullSpec string) (map[string]string, error) {
+	out, err := runCommand(ctx, "oc", "adm", "release", "info", "--output=json", releasePullSpec)
+	if err != nil {
+		return nil, err
+	}
+	var info struct {
+		References struct {
+			Spec struct {
+				Tags []struct {
+					Name string `json:"name"`
+					From struct {
+						Name string `json:"name"`
+					} `json:"from"`
+				} `json:"tags"`
+			} `json:"spec"`
+		} `json:"references"`
+	}
+	if err := json.Unmarshal(out, &info); err != nil {
+		return nil, fmt.Errorf("unable to parse the release info of %s: %w", releasePullSpec, err)
+	}
+	images := make(map[string]string)
+	for _, tag := range info.References.Spec.Tags {
+		if len(tag.From.Name) > 0 {
+			images[tag.Name] = tag.From.Name
+		}
+	}
+	return images, nil
+}
+
+func (g *execSBOMGenerator) ImageSBOM(ctx context.Context, pullSpec string) ([]byte, error) {
+	return runCommand(ctx, "syft", "scan", "--quiet", "--output", "spdx-json", "registry:"+pullSpec)
+}
+
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// releaseSBOM is the aggregated SBOM of a release
+type releaseSBOM struct {
+	// Release is the pull spec, by digest, of the release image
+	Release string `json:"release"`
+
+	// Components holds the SBOM of every component image of the release, sorted by name
+	Components []componentSBOM `json:"components"`
+}
+
+// componentSBOM is the SBOM of a component image of a release
+type componentSBOM struct {
+	Name  string          `json:"name"`
+	Image string          `json:"image"`
+	SBOM  json.RawMessage `json:"sbom"`
+}
+
+// generateReleaseSBOM aggregates the SBOMs, of every component image of the release, into a single SBOM
+func generateReleaseSBOM(ctx context.Context, generator SBOMGenerator, releasePullSpec string) ([]byte, error) {
+	images, err := generator.ComponentImages(ctx, releasePullSpec)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the component images of %s: %w", releasePullSpec, err)
+	}
+	names := make([]string, 0, len(images))
+	for name := range images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sbom := releaseSBOM{Release: releasePullSpec, Components: make([]componentSBOM, 0, len(names))}
+	for _, name := range names {
+		data, err := generator.ImageSBOM(ctx, images[name])
+		if err != nil {
+			return nil, fmt.Errorf("unable to generate the SBOM of component %s (%s): %w", name, images[name], err)
+		}
+		if !json.Valid(data) {
+			return nil, fmt.Errorf("the SBOM of component %s (%s) is not valid JSON", name, images[name])
+		}
+		sbom.Components = append(sbom.Components, componentSBOM{Name: name, Image: images[name], SBOM: data})
+	}
+	return json.Marshal(sbom)
+}
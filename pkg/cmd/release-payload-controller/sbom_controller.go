@@ -0,0 +1,192 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+
+	imagev1informer "github.com/openshift/client-go/image/informers/externalversions/image/v1"
+	imagev1lister "github.com/openshift/client-go/image/listers/image/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// SBOMConfigMapSuffix is appended to the name of a ReleasePayload to name the ConfigMap holding its SBOM
+	SBOMConfigMapSuffix = "-sbom"
+
+	// SBOMKey is the key, of the SBOM ConfigMap, holding the aggregated SBOM of the release
+	SBOMKey = "sbom.json"
+
+	// maxSBOMSize is the size, in bytes, above which the SBOM does not fit in a ConfigMap
+	maxSBOMSize = 1024 * 1024
+)
+
+// SBOMController is responsible for generating the Software Bill of Materials of an Accepted ReleasePayload.  The SBOM
+// of every component image of the release image is generated, by the SBOMGenerator, and aggregated into a single JSON
+// document that is stored in a ConfigMap, named {releasepayload-name}-sbom, alongside the ReleasePayload.
+// The SBOMController reads the following pieces of information:
+//   - .spec.payloadCoordinates.namespace
+//   - .spec.payloadCoordinates.imagestreamName
+//   - .status.conditions.PayloadAccepted
+//   - .status.releaseDigest
+//   - .status.sbomConfigMapRef
+//   - .status.sbomFailureMessage
+//
+// and writes the following information:
+//   - the {releasepayload-name}-sbom ConfigMap
+//   - .status.sbomConfigMapRef
+//   - .status.sbomFailureMessage
+type SBOMController struct {
+	*ReleasePayloadController
+
+	imageStreamLister imagev1lister.ImageStreamLister
+	configMapClient   corev1client.ConfigMapsGetter
+	sbomGenerator     SBOMGenerator
+}
+
+func NewSBOMController(
+	releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer,
+	releasePayloadClient releasepayloadclient.ReleaseV1alpha1Interface,
+	imageStreamInformer imagev1informer.ImageStreamInformer,
+	configMapClient corev1client.ConfigMapsGetter,
+	eventRecorder events.Recorder,
+	sbomGenerator SBOMGenerator,
+) (*SBOMController, error) {
+	c := &SBOMController{
+		ReleasePayloadController: NewReleasePayloadController("SBOM Controller",
+			releasePayloadInformer,
+			releasePayloadClient,
+			eventRecorder.WithComponentSuffix("sbom-controller"),
+			workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "SBOMController")),
+		imageStreamLister: imageStreamInformer.Lister(),
+		configMapClient:   configMapClient,
+		sbomGenerator:     sbomGenerator,
+	}
+
+	c.syncFn = c.sync
+	c.cachesToSync = append(c.cachesToSync, imageStreamInformer.Informer().HasSynced)
+
+	releasePayloadInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			releasePayload, ok := obj.(*v1alpha1.ReleasePayload)
+			return ok && needsSBOM(releasePayload)
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.Enqueue,
+			UpdateFunc: func(old, new interface{}) { c.Enqueue(new) },
+		},
+	})
+
+	return c, nil
+}
+
+// needsSBOM returns true if the ReleasePayload is Accepted, and the digest of its release image has been recorded by
+// the ReleaseCreationStatusController, but its SBOM has neither been generated nor failed to be stored yet
+func needsSBOM(releasePayload *v1alpha1.ReleasePayload) bool {
+	return v1helpers.IsConditionTrue(releasePayload.Status.Conditions, v1alpha1.ConditionPayloadAccepted) &&
+		len(releasePayload.Status.ReleaseDigest) > 0 &&
+		len(releasePayload.Status.SBOMConfigMapRef.Name) == 0 &&
+		len(releasePayload.Status.SBOMFailureMessage) == 0
+}
+
+func (c *SBOMController) sync(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting SBOMController sync")
+	defer logger.V(4).Info("SBOMController sync done")
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	// Get the ReleasePayload resource with this namespace/name
+	originalReleasePayload, err := c.releasePayloadLister.ReleasePayloads(namespace).Get(name)
+	// The ReleasePayload resource may no longer exist, in which case we stop processing.
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(originalReleasePayload) {
+		return nil
+	}
+
+	if !needsSBOM(originalReleasePayload) {
+		return nil
+	}
+
+	repository, err := releaseRepository(c.imageStreamLister, originalReleasePayload)
+	if err != nil {
+		return err
+	}
+	pullSpec := fmt.Sprintf("%s@%s", repository, originalReleasePayload.Status.ReleaseDigest)
+
+	sbom, err := generateReleaseSBOM(ctx, c.sbomGenerator, pullSpec)
+	if err != nil {
+		return fmt.Errorf("unable to generate the SBOM of ReleasePayload %s/%s: %w", originalReleasePayload.Namespace, originalReleasePayload.Name, err)
+	}
+	// An SBOM that does not fit in a ConfigMap never will, so the failure is recorded rather than generating it again
+	if len(sbom) > maxSBOMSize {
+		message := fmt.Sprintf("The %d byte SBOM does not fit in a ConfigMap", len(sbom))
+		c.eventRecorder.Warningf("ReleasePayloadSBOMTooLarge", "%s: ReleasePayload %s/%s", message, originalReleasePayload.Namespace, originalReleasePayload.Name)
+		releasePayload := originalReleasePayload.DeepCopy()
+		releasePayload.Status.SBOMFailureMessage = message
+		_, err = c.releasePayloadClient.ReleasePayloads(releasePayload.Namespace).UpdateStatus(ctx, releasePayload, metav1.UpdateOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      originalReleasePayload.Name + SBOMConfigMapSuffix,
+			Namespace: originalReleasePayload.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(originalReleasePayload, v1alpha1.SchemeGroupVersion.WithKind("ReleasePayload")),
+			},
+		},
+		Data: map[string]string{
+			SBOMKey: string(sbom),
+		},
+	}
+
+	klog.V(4).Infof("Storing SBOM of ReleasePayload %s/%s in ConfigMap %s/%s", originalReleasePayload.Namespace, originalReleasePayload.Name, configMap.Namespace, configMap.Name)
+	existing, err := c.configMapClient.ConfigMaps(configMap.Namespace).Get(ctx, configMap.Name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		_, err = c.configMapClient.ConfigMaps(configMap.Namespace).Create(ctx, configMap, metav1.CreateOptions{})
+	case err == nil:
+		configMap.ResourceVersion = existing.ResourceVersion
+		_, err = c.configMapClient.ConfigMaps(configMap.Namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	releasePayload := originalReleasePayload.DeepCopy()
+	releasePayload.Status.SBOMConfigMapRef = corev1.LocalObjectReference{Name: configMap.Name}
+
+	klog.V(4).Infof("Syncing SBOM ConfigMap reference for ReleasePayload: %s/%s", releasePayload.Namespace, releasePayload.Name)
+	_, err = c.releasePayloadClient.ReleasePayloads(releasePayload.Namespace).UpdateStatus(ctx, releasePayload, metav1.UpdateOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,231 @@
+package release_payload_controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	imagefake "github.com/openshift/client-go/image/clientset/versioned/fake"
+	imageinformers "github.com/openshift/client-go/image/informers/externalversions"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fake2 "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	sbomTestReleaseName   = "4.11.0-0.nightly-2022-02-09-091559"
+	sbomTestReleaseDigest = "sha256:5a9a79a3c2a1b5b7c2f1e6d4a8b3c0d9e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2"
+	sbomTestPullSpec      = "registry.ci.openshift.org/ocp/release@" + sbomTestReleaseDigest
+)
+
+func newSBOMReleasePayload(accepted bool, digest string, sbomConfigMap string) *v1alpha1.ReleasePayload {
+	acceptedStatus := metav1.ConditionFalse
+	if accepted {
+		acceptedStatus = metav1.ConditionTrue
+	}
+	return &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sbomTestReleaseName,
+			Namespace: "ocp",
+			UID:       "sbom-release-payload",
+		},
+		Spec: v1alpha1.ReleasePayloadSpec{
+			PayloadCoordinates: v1alpha1.PayloadCoordinates{
+				Namespace:          "ocp",
+				ImagestreamName:    "release",
+				ImagestreamTagName: sbomTestReleaseName,
+			},
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   v1alpha1.ConditionPayloadAccepted,
+					Status: acceptedStatus,
+				},
+			},
+			ReleaseDigest:    digest,
+			SBOMConfigMapRef: corev1.LocalObjectReference{Name: sbomConfigMap},
+		},
+	}
+}
+
+func TestSBOMSync(t *testing.T) {
+	imageStream := &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "release",
+			Namespace: "ocp",
+		},
+		Status: imagev1.ImageStreamStatus{
+			DockerImageRepository:       "image-registry.openshift-image-registry.svc:5000/ocp/release",
+			PublicDockerImageRepository: "registry.ci.openshift.org/ocp/release",
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		input        *v1alpha1.ReleasePayload
+		release      string
+		expectedErr  bool
+		expectedSBOM bool
+	}{
+		{
+			name:    "NotAccepted",
+			input:   newSBOMReleasePayload(false, sbomTestReleaseDigest, ""),
+			release: sbomTestPullSpec,
+		},
+		{
+			name:    "NoReleaseDigest",
+			input:   newSBOMReleasePayload(true, "", ""),
+			release: sbomTestPullSpec,
+		},
+		{
+			name:    "AlreadyGenerated",
+			input:   newSBOMReleasePayload(true, sbomTestReleaseDigest, sbomTestReleaseName+SBOMConfigMapSuffix),
+			release: sbomTestPullSpec,
+		},
+		{
+			name:         "Generated",
+			input:        newSBOMReleasePayload(true, sbomTestReleaseDigest, ""),
+			release:      sbomTestPullSpec,
+			expectedSBOM: true,
+		},
+		{
+			name:        "GeneratorError",
+			input:       newSBOMReleasePayload(true, sbomTestReleaseDigest, ""),
+			release:     "registry.ci.openshift.org/ocp/release:" + sbomTestReleaseName,
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			releasePayloadClient := fake.NewSimpleClientset(testCase.input)
+			releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+			imageStreamInformerFactory := imageinformers.NewSharedInformerFactory(imagefake.NewSimpleClientset(imageStream), controllerDefaultResyncDuration)
+			kubeClient := fake2.NewSimpleClientset()
+			generator := newFakeSBOMGenerator(testCase.release)
+
+			c, err := NewSBOMController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), imageStreamInformerFactory.Image().V1().ImageStreams(), kubeClient.CoreV1(), events.NewInMemoryRecorder("sbom-controller-test"), generator)
+			if err != nil {
+				t.Fatalf("%s: unable to create controller: %v", testCase.name, err)
+			}
+			defer c.queue.ShutDown()
+
+			releasePayloadInformerFactory.Start(context.Background().Done())
+			imageStreamInformerFactory.Start(context.Background().Done())
+
+			if !cache.WaitForNamedCacheSync("SBOMController", context.Background().Done(), c.cachesToSync...) {
+				t.Fatalf("%s: error waiting for caches to sync", testCase.name)
+			}
+
+			key := fmt.Sprintf("%s/%s", testCase.input.Namespace, testCase.input.Name)
+			if err := c.sync(context.TODO(), key); (err != nil) != testCase.expectedErr {
+				t.Fatalf("%s: expected error: %t, got: %v", testCase.name, testCase.expectedErr, err)
+			}
+
+			releasePayload, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads(testCase.input.Namespace).Get(context.TODO(), testCase.input.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("%s: unable to get ReleasePayload: %v", testCase.name, err)
+			}
+			configMap, err := kubeClient.CoreV1().ConfigMaps("ocp").Get(context.TODO(), sbomTestReleaseName+SBOMConfigMapSuffix, metav1.GetOptions{})
+			if !testCase.expectedSBOM {
+				if !errors.IsNotFound(err) {
+					t.Errorf("%s: expected no SBOM ConfigMap, got: %v", testCase.name, err)
+				}
+				if releasePayload.Status.SBOMConfigMapRef != testCase.input.Status.SBOMConfigMapRef {
+					t.Errorf("%s: expected the SBOM ConfigMap reference to be unchanged, got: %v", testCase.name, releasePayload.Status.SBOMConfigMapRef)
+				}
+				if len(generator.generated) > 0 {
+					t.Errorf("%s: expected no SBOMs to be generated, got: %v", testCase.name, generator.generated)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unable to get SBOM ConfigMap: %v", testCase.name, err)
+			}
+			if name := releasePayload.Status.SBOMConfigMapRef.Name; name != configMap.Name {
+				t.Errorf("%s: expected the SBOM ConfigMap reference %q, got: %q", testCase.name, configMap.Name, name)
+			}
+			if len(configMap.OwnerReferences) != 1 || configMap.OwnerReferences[0].UID != testCase.input.UID {
+				t.Errorf("%s: expected the ConfigMap to be owned by the ReleasePayload, got: %v", testCase.name, configMap.OwnerReferences)
+			}
+			var sbom releaseSBOM
+			if err := json.Unmarshal([]byte(configMap.Data[SBOMKey]), &sbom); err != nil {
+				t.Fatalf("%s: unable to parse the SBOM: %v", testCase.name, err)
+			}
+			if sbom.Release != sbomTestPullSpec {
+				t.Errorf("%s: expected the SBOM of release %q, got: %q", testCase.name, sbomTestPullSpec, sbom.Release)
+			}
+			if len(sbom.Components) != len(generator.images) {
+				t.Errorf("%s: expected %d components, got: %d", testCase.name, len(generator.images), len(sbom.Components))
+			}
+			for image, count := range generator.generated {
+				if count != 1 {
+					t.Errorf("%s: expected the SBOM of %s to be generated once, got: %d", testCase.name, image, count)
+				}
+			}
+		})
+	}
+}
+
+// TestSBOMSyncTooLarge verifies that an SBOM that does not fit in a ConfigMap is recorded as failed, so that it is not
+// generated again
+func TestSBOMSyncTooLarge(t *testing.T) {
+	imageStream := &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "release",
+			Namespace: "ocp",
+		},
+		Status: imagev1.ImageStreamStatus{
+			PublicDockerImageRepository: "registry.ci.openshift.org/ocp/release",
+		},
+	}
+	input := newSBOMReleasePayload(true, sbomTestReleaseDigest, "")
+
+	releasePayloadClient := fake.NewSimpleClientset(input)
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+	imageStreamInformerFactory := imageinformers.NewSharedInformerFactory(imagefake.NewSimpleClientset(imageStream), controllerDefaultResyncDuration)
+	kubeClient := fake2.NewSimpleClientset()
+	generator := newFakeSBOMGenerator(sbomTestPullSpec)
+	for image := range generator.sboms {
+		generator.sboms[image] = fmt.Sprintf(`{"padding":%q}`, strings.Repeat("x", maxSBOMSize))
+	}
+
+	c, err := NewSBOMController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), imageStreamInformerFactory.Image().V1().ImageStreams(), kubeClient.CoreV1(), events.NewInMemoryRecorder("sbom-controller-test"), generator)
+	if err != nil {
+		t.Fatalf("unable to create controller: %v", err)
+	}
+	defer c.queue.ShutDown()
+
+	releasePayloadInformerFactory.Start(context.Background().Done())
+	imageStreamInformerFactory.Start(context.Background().Done())
+	if !cache.WaitForNamedCacheSync("SBOMController", context.Background().Done(), c.cachesToSync...) {
+		t.Fatalf("error waiting for caches to sync")
+	}
+
+	if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := kubeClient.CoreV1().ConfigMaps("ocp").Get(context.TODO(), sbomTestReleaseName+SBOMConfigMapSuffix, metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expected no SBOM ConfigMap, got: %v", err)
+	}
+	releasePayload, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get ReleasePayload: %v", err)
+	}
+	if len(releasePayload.Status.SBOMFailureMessage) == 0 {
+		t.Errorf("expected the SBOM failure to be recorded")
+	}
+	if needsSBOM(releasePayload) {
+		t.Errorf("expected the SBOM not to be generated again once its failure is recorded")
+	}
+}
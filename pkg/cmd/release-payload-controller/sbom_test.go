@@ -0,0 +1,126 @@
+package release_payload_controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// fakeSBOMGenerator generates the SBOMs of the component images of a single release
+type fakeSBOMGenerator struct {
+	release string
+	images  map[string]string
+	sboms   map[string]string
+
+	// generated counts the SBOMs generated per image
+	generated map[string]int
+}
+
+func newFakeSBOMGenerator(release string) *fakeSBOMGenerator {
+	return &fakeSBOMGenerator{
+		release: release,
+		images: map[string]string{
+			"cli":             "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:1111111111111111111111111111111111111111111111111111111111111111",
+			"cluster-version": "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:2222222222222222222222222222222222222222222222222222222222222222",
+		},
+		sboms: map[string]string{
+			"quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:1111111111111111111111111111111111111111111111111111111111111111": `{"spdxVersion":"SPDX-2.3","name":"cli","packages":[{"name":"openshift-clients","versionInfo":"4.11.0"}]}`,
+			"quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:2222222222222222222222222222222222222222222222222222222222222222": `{"spdxVersion":"SPDX-2.3","name":"cluster-version","packages":[{"name":"cluster-version-operator","versionInfo":"4.11.0"}]}`,
+		},
+		generated: map[string]int{},
+	}
+}
+
+func (g *fakeSBOMGenerator) ComponentImages(ctx context.Context, releasePullSpec string) (map[string]string, error) {
+	if releasePullSpec != g.release {
+		return nil, fmt.Errorf("unknown release %s", releasePullSpec)
+	}
+	return g.images, nil
+}
+
+func (g *fakeSBOMGenerator) ImageSBOM(ctx context.Context, pullSpec string) ([]byte, error) {
+	sbom, ok := g.sboms[pullSpec]
+	if !ok {
+		return nil, fmt.Errorf("unknown image %s", pullSpec)
+	}
+	g.generated[pullSpec]++
+	return []byte(sbom), nil
+}
+
+func TestGenerateReleaseSBOM(t *testing.T) {
+	const release = "registry.ci.openshift.org/ocp/release@sha256:5a9a79a3c2a1b5b7c2f1e6d4a8b3c0d9e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2"
+
+	testCases := []struct {
+		name        string
+		release     string
+		modify      func(generator *fakeSBOMGenerator)
+		expected    []string
+		expectedErr bool
+	}{
+		{
+			name:     "Aggregated",
+			release:  release,
+			expected: []string{"cli", "cluster-version"},
+		},
+		{
+			name:        "UnknownRelease",
+			release:     "registry.ci.openshift.org/ocp/release@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			expectedErr: true,
+		},
+		{
+			name:    "MissingImage",
+			release: release,
+			modify: func(generator *fakeSBOMGenerator) {
+				generator.images["installer"] = "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:3333333333333333333333333333333333333333333333333333333333333333"
+			},
+			expectedErr: true,
+		},
+		{
+			name:    "InvalidSBOM",
+			release: release,
+			modify: func(generator *fakeSBOMGenerator) {
+				generator.sboms[generator.images["cli"]] = "not json"
+			},
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			generator := newFakeSBOMGenerator(release)
+			if testCase.modify != nil {
+				testCase.modify(generator)
+			}
+
+			data, err := generateReleaseSBOM(context.TODO(), generator, testCase.release)
+			if (err != nil) != testCase.expectedErr {
+				t.Fatalf("%s: expected error: %t, got: %v", testCase.name, testCase.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			var sbom releaseSBOM
+			if err := json.Unmarshal(data, &sbom); err != nil {
+				t.Fatalf("%s: unable to parse the SBOM: %v", testCase.name, err)
+			}
+			if sbom.Release != testCase.release {
+				t.Errorf("%s: expected release %q, got: %q", testCase.name, testCase.release, sbom.Release)
+			}
+			var names []string
+			for _, component := range sbom.Components {
+				names = append(names, component.Name)
+				if component.Image != generator.images[component.Name] {
+					t.Errorf("%s: expected image %q of component %s, got: %q", testCase.name, generator.images[component.Name], component.Name, component.Image)
+				}
+				if string(component.SBOM) != generator.sboms[component.Image] {
+					t.Errorf("%s: expected the SBOM of component %s to be %s, got: %s", testCase.name, component.Name, generator.sboms[component.Image], component.SBOM)
+				}
+			}
+			if !reflect.DeepEqual(names, testCase.expected) {
+				t.Errorf("%s: expected components %v, got: %v", testCase.name, testCase.expected, names)
+			}
+		})
+	}
+}
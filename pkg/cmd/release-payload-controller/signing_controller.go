@@ -0,0 +1,235 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+
+	imagev1informer "github.com/openshift/client-go/image/informers/externalversions/image/v1"
+	imagev1lister "github.com/openshift/client-go/image/listers/image/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/signer"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// SignatureConfigMapSuffix is appended to the name of a ReleasePayload to name the ConfigMap holding its signature
+	SignatureConfigMapSuffix = "-signature"
+
+	// SignatureDigestKey is the key, of the signature ConfigMap, holding the digest of the release image that was signed
+	SignatureDigestKey = "digest"
+
+	// SignaturePullSpecKey is the key, of the signature ConfigMap, holding the pull spec of the release image that was signed
+	SignaturePullSpecKey = "pullSpec"
+
+	// SignatureKey is the key, of the binary data of the signature ConfigMap, holding the GPG signature
+	SignatureKey = "signature"
+)
+
+// SigningController is responsible for GPG signing the release image of a ReleasePayload, once it has been created,
+// with the key referenced by its SigningConfig.  The signature is an atomic container signature, of the release image
+// digest and pull spec, stored in a ConfigMap, named {releasepayload-name}-signature, alongside the ReleasePayload.
+// The SigningController reads the following pieces of information:
+//   - .spec.payloadCoordinates.namespace
+//   - .spec.payloadCoordinates.imagestreamName
+//   - .spec.payloadCoordinates.imagestreamTagName
+//   - .spec.payloadCreationConfig.signingConfig
+//   - .status.releaseCreationJobResult.status
+//   - .status.releaseDigest
+//
+// and writes the following information:
+//   - the {releasepayload-name}-signature ConfigMap
+type SigningController struct {
+	*ReleasePayloadController
+
+	imageStreamLister imagev1lister.ImageStreamLister
+	secretClient      corev1client.SecretsGetter
+	configMapClient   corev1client.ConfigMapsGetter
+}
+
+func NewSigningController(
+	releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer,
+	releasePayloadClient releasepayloadclient.ReleaseV1alpha1Interface,
+	imageStreamInformer imagev1informer.ImageStreamInformer,
+	secretClient corev1client.SecretsGetter,
+	configMapClient corev1client.ConfigMapsGetter,
+	eventRecorder events.Recorder,
+) (*SigningController, error) {
+	c := &SigningController{
+		ReleasePayloadController: NewReleasePayloadController("Signing Controller",
+			releasePayloadInformer,
+			releasePayloadClient,
+			eventRecorder.WithComponentSuffix("signing-controller"),
+			workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "SigningController")),
+		imageStreamLister: imageStreamInformer.Lister(),
+		secretClient:      secretClient,
+		configMapClient:   configMapClient,
+	}
+
+	c.syncFn = c.sync
+	c.cachesToSync = append(c.cachesToSync, imageStreamInformer.Informer().HasSynced)
+
+	releasePayloadInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			releasePayload, ok := obj.(*v1alpha1.ReleasePayload)
+			return ok && readyToSign(releasePayload)
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.Enqueue,
+			UpdateFunc: func(old, new interface{}) { c.Enqueue(new) },
+		},
+	})
+
+	return c, nil
+}
+
+// readyToSign returns true if the ReleasePayload should be signed and its release image has been created.  The
+// ReleaseCreationStatusController records the digest, of the release image, once the release imagestream tag points at
+// it.
+func readyToSign(releasePayload *v1alpha1.ReleasePayload) bool {
+	return releasePayload.Spec.PayloadCreationConfig.SigningConfig != nil &&
+		releasePayload.Status.ReleaseCreationJobResult.Status == v1alpha1.ReleaseCreationJobSuccess &&
+		len(releasePayload.Status.ReleaseDigest) > 0
+}
+
+func (c *SigningController) sync(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting SigningController sync")
+	defer logger.V(4).Info("SigningController sync done")
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	// Get the ReleasePayload resource with this namespace/name
+	releasePayload, err := c.releasePayloadLister.ReleasePayloads(namespace).Get(name)
+	// The ReleasePayload resource may no longer exist, in which case we stop processing.
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(releasePayload) {
+		return nil
+	}
+
+	if !readyToSign(releasePayload) {
+		return nil
+	}
+	digest := releasePayload.Status.ReleaseDigest
+
+	// Only sign each digest once
+	configMapName := releasePayload.Name + SignatureConfigMapSuffix
+	existing, err := c.configMapClient.ConfigMaps(releasePayload.Namespace).Get(ctx, configMapName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		existing = nil
+	case err != nil:
+		return err
+	case existing.Data[SignatureDigestKey] == digest:
+		return nil
+	}
+
+	pullSpec, err := c.releasePullSpec(releasePayload)
+	if err != nil {
+		return err
+	}
+
+	keyRef := releasePayload.Spec.PayloadCreationConfig.SigningConfig.GPGKeyRef
+	secret, err := c.secretClient.Secrets(releasePayload.Namespace).Get(ctx, keyRef.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) && keyRef.Optional != nil && *keyRef.Optional {
+		klog.V(4).Infof("Optional signing key secret %s/%s not found, not signing ReleasePayload %s/%s", releasePayload.Namespace, keyRef.Name, releasePayload.Namespace, releasePayload.Name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get signing key secret %s/%s: %w", releasePayload.Namespace, keyRef.Name, err)
+	}
+	keyring, ok := secret.Data[keyRef.Key]
+	if !ok {
+		if keyRef.Optional != nil && *keyRef.Optional {
+			return nil
+		}
+		return fmt.Errorf("signing key secret %s/%s has no key %q", releasePayload.Namespace, keyRef.Name, keyRef.Key)
+	}
+
+	releaseSigner, err := signer.NewFromKeyringData(keyRef.Name, keyring)
+	if err != nil {
+		return fmt.Errorf("unable to load signing key from secret %s/%s: %w", releasePayload.Namespace, keyRef.Name, err)
+	}
+	signature, err := releaseSigner.Sign(digest, pullSpec)
+	if err != nil {
+		return fmt.Errorf("unable to sign release %s: %w", pullSpec, err)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configMapName,
+			Namespace: releasePayload.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(releasePayload, v1alpha1.SchemeGroupVersion.WithKind("ReleasePayload")),
+			},
+		},
+		Data: map[string]string{
+			SignatureDigestKey:   digest,
+			SignaturePullSpecKey: pullSpec,
+		},
+		BinaryData: map[string][]byte{
+			SignatureKey: signature,
+		},
+	}
+
+	klog.V(4).Infof("Storing signature of ReleasePayload %s/%s in ConfigMap %s/%s", releasePayload.Namespace, releasePayload.Name, configMap.Namespace, configMap.Name)
+	if existing == nil {
+		_, err = c.configMapClient.ConfigMaps(configMap.Namespace).Create(ctx, configMap, metav1.CreateOptions{})
+	} else {
+		configMap.ResourceVersion = existing.ResourceVersion
+		_, err = c.configMapClient.ConfigMaps(configMap.Namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	c.eventRecorder.Eventf("ReleasePayloadSigned", "Signed release %s@%s of ReleasePayload %s/%s", pullSpec, digest, releasePayload.Namespace, releasePayload.Name)
+	return nil
+}
+
+// releasePullSpec returns the pull spec, by tag, of the release image of the ReleasePayload
+func (c *SigningController) releasePullSpec(releasePayload *v1alpha1.ReleasePayload) (string, error) {
+	repository, err := releaseRepository(c.imageStreamLister, releasePayload)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%s", repository, releasePayload.Spec.PayloadCoordinates.ImagestreamTagName), nil
+}
+
+// releaseRepository returns the repository, of the release imagestream, that the release image of the ReleasePayload
+// is pushed to
+func releaseRepository(imageStreamLister imagev1lister.ImageStreamLister, releasePayload *v1alpha1.ReleasePayload) (string, error) {
+	coordinates := releasePayload.Spec.PayloadCoordinates
+	imageStream, err := imageStreamLister.ImageStreams(coordinates.Namespace).Get(coordinates.ImagestreamName)
+	if err != nil {
+		return "", fmt.Errorf("unable to get release imagestream %s/%s: %w", coordinates.Namespace, coordinates.ImagestreamName, err)
+	}
+	repository := imageStream.Status.PublicDockerImageRepository
+	if len(repository) == 0 {
+		repository = imageStream.Status.DockerImageRepository
+	}
+	if len(repository) == 0 {
+		return "", fmt.Errorf("release imagestream %s/%s does not have a docker image repository", coordinates.Namespace, coordinates.ImagestreamName)
+	}
+	return repository, nil
+}
@@ -0,0 +1,274 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	imagefake "github.com/openshift/client-go/image/clientset/versioned/fake"
+	imageinformers "github.com/openshift/client-go/image/informers/externalversions"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	"github.com/openshift/release-controller/pkg/signer"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fake2 "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	signingTestReleaseName   = "4.11.0-0.nightly-2022-02-09-091559"
+	signingTestReleaseDigest = "sha256:5a9a79a3c2a1b5b7c2f1e6d4a8b3c0d9e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2"
+	signingTestPullSpec      = "registry.ci.openshift.org/ocp/release:4.11.0-0.nightly-2022-02-09-091559"
+)
+
+func newSigningReleasePayload(signingConfig *v1alpha1.SigningConfig, status v1alpha1.ReleaseCreationJobStatus, digest string) *v1alpha1.ReleasePayload {
+	return &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      signingTestReleaseName,
+			Namespace: "ocp",
+			UID:       "signing-release-payload",
+		},
+		Spec: v1alpha1.ReleasePayloadSpec{
+			PayloadCoordinates: v1alpha1.PayloadCoordinates{
+				Namespace:          "ocp",
+				ImagestreamName:    "release",
+				ImagestreamTagName: signingTestReleaseName,
+			},
+			PayloadCreationConfig: v1alpha1.PayloadCreationConfig{
+				SigningConfig: signingConfig,
+			},
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Status: status,
+			},
+			ReleaseDigest: digest,
+		},
+	}
+}
+
+func newSigningConfig(optional bool) *v1alpha1.SigningConfig {
+	return &v1alpha1.SigningConfig{
+		GPGKeyRef: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "release-signing-key"},
+			Key:                  "private.key",
+			Optional:             pointer.Bool(optional),
+		},
+	}
+}
+
+func TestSigningSync(t *testing.T) {
+	signingKey, err := os.ReadFile("testdata/signing-key.asc")
+	if err != nil {
+		t.Fatalf("unable to read signing key: %v", err)
+	}
+	verifier, err := signer.NewFromKeyringData("signing-key", signingKey)
+	if err != nil {
+		t.Fatalf("unable to load signing key: %v", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "release-signing-key",
+			Namespace: "ocp",
+		},
+		Data: map[string][]byte{
+			"private.key": signingKey,
+		},
+	}
+	imageStream := &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "release",
+			Namespace: "ocp",
+		},
+		Status: imagev1.ImageStreamStatus{
+			DockerImageRepository:       "image-registry.openshift-image-registry.svc:5000/ocp/release",
+			PublicDockerImageRepository: "registry.ci.openshift.org/ocp/release",
+		},
+	}
+
+	testCases := []struct {
+		name              string
+		input             *v1alpha1.ReleasePayload
+		kubeObjects       []runtime.Object
+		expectedErr       bool
+		expectedSignature bool
+	}{
+		{
+			name:        "NoSigningConfig",
+			input:       newSigningReleasePayload(nil, v1alpha1.ReleaseCreationJobSuccess, signingTestReleaseDigest),
+			kubeObjects: []runtime.Object{secret},
+		},
+		{
+			name:        "ReleaseCreationJobPending",
+			input:       newSigningReleasePayload(newSigningConfig(false), v1alpha1.ReleaseCreationJobPending, ""),
+			kubeObjects: []runtime.Object{secret},
+		},
+		{
+			name:              "Signed",
+			input:             newSigningReleasePayload(newSigningConfig(false), v1alpha1.ReleaseCreationJobSuccess, signingTestReleaseDigest),
+			kubeObjects:       []runtime.Object{secret},
+			expectedSignature: true,
+		},
+		{
+			name:        "MissingSecret",
+			input:       newSigningReleasePayload(newSigningConfig(false), v1alpha1.ReleaseCreationJobSuccess, signingTestReleaseDigest),
+			expectedErr: true,
+		},
+		{
+			name:  "MissingOptionalSecret",
+			input: newSigningReleasePayload(newSigningConfig(true), v1alpha1.ReleaseCreationJobSuccess, signingTestReleaseDigest),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			releasePayloadClient := fake.NewSimpleClientset(testCase.input)
+			releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+			imageStreamInformerFactory := imageinformers.NewSharedInformerFactory(imagefake.NewSimpleClientset(imageStream), controllerDefaultResyncDuration)
+			kubeClient := fake2.NewSimpleClientset(testCase.kubeObjects...)
+
+			c, err := NewSigningController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), imageStreamInformerFactory.Image().V1().ImageStreams(), kubeClient.CoreV1(), kubeClient.CoreV1(), events.NewInMemoryRecorder("signing-controller-test"))
+			if err != nil {
+				t.Fatalf("%s: unable to create controller: %v", testCase.name, err)
+			}
+			defer c.queue.ShutDown()
+
+			releasePayloadInformerFactory.Start(context.Background().Done())
+			imageStreamInformerFactory.Start(context.Background().Done())
+
+			if !cache.WaitForNamedCacheSync("SigningController", context.Background().Done(), c.cachesToSync...) {
+				t.Fatalf("%s: error waiting for caches to sync", testCase.name)
+			}
+
+			key := fmt.Sprintf("%s/%s", testCase.input.Namespace, testCase.input.Name)
+			if err := c.sync(context.TODO(), key); (err != nil) != testCase.expectedErr {
+				t.Fatalf("%s: expected error: %t, got: %v", testCase.name, testCase.expectedErr, err)
+			}
+
+			configMap, err := kubeClient.CoreV1().ConfigMaps("ocp").Get(context.TODO(), signingTestReleaseName+SignatureConfigMapSuffix, metav1.GetOptions{})
+			if !testCase.expectedSignature {
+				if !errors.IsNotFound(err) {
+					t.Errorf("%s: expected no signature ConfigMap, got: %v", testCase.name, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unable to get signature ConfigMap: %v", testCase.name, err)
+			}
+			if digest := configMap.Data[SignatureDigestKey]; digest != signingTestReleaseDigest {
+				t.Errorf("%s: expected digest %q, got: %q", testCase.name, signingTestReleaseDigest, digest)
+			}
+			if pullSpec := configMap.Data[SignaturePullSpecKey]; pullSpec != signingTestPullSpec {
+				t.Errorf("%s: expected pull spec %q, got: %q", testCase.name, signingTestPullSpec, pullSpec)
+			}
+			if len(configMap.OwnerReferences) != 1 || configMap.OwnerReferences[0].UID != testCase.input.UID {
+				t.Errorf("%s: expected the ConfigMap to be owned by the ReleasePayload, got: %v", testCase.name, configMap.OwnerReferences)
+			}
+			if err := verifier.Verify(context.TODO(), signingTestReleaseDigest, configMap.Name, configMap.BinaryData[SignatureKey]); err != nil {
+				t.Errorf("%s: unable to verify signature: %v", testCase.name, err)
+			}
+			if err := verifier.Verify(context.TODO(), "sha256:0000000000000000000000000000000000000000000000000000000000000000", configMap.Name, configMap.BinaryData[SignatureKey]); err == nil {
+				t.Errorf("%s: expected the signature to not verify a different digest", testCase.name)
+			}
+
+			// A digest is only signed once
+			kubeClient.ClearActions()
+			if err := c.sync(context.TODO(), key); err != nil {
+				t.Fatalf("%s: unexpected error on resync: %v", testCase.name, err)
+			}
+			for _, action := range kubeClient.Actions() {
+				if action.GetVerb() != "get" {
+					t.Errorf("%s: unexpected action on resync: %v", testCase.name, action)
+				}
+			}
+		})
+	}
+}
+
+// TestSigningSyncReleaseCreationStatusController signs the ReleasePayload as it is recorded by the
+// ReleaseCreationStatusController, once its release creation job has completed and the release image has been imported
+// into the release imagestream
+func TestSigningSyncReleaseCreationStatusController(t *testing.T) {
+	signingKey, err := os.ReadFile("testdata/signing-key.asc")
+	if err != nil {
+		t.Fatalf("unable to read signing key: %v", err)
+	}
+	verifier, err := signer.NewFromKeyringData("signing-key", signingKey)
+	if err != nil {
+		t.Fatalf("unable to load signing key: %v", err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "release-signing-key",
+			Namespace: "ocp",
+		},
+		Data: map[string][]byte{
+			"private.key": signingKey,
+		},
+	}
+	imageStream := newReleaseImageStream(map[string]string{signingTestReleaseName: releaseDigest})
+
+	job := newReleaseCreationJob("ci-release", signingTestReleaseName, "ocp/release")
+	job.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+	input := newReleaseDigestTestReleasePayload(job, v1alpha1.ReleaseCreationJobResult{})
+	input.Spec.PayloadCreationConfig.SigningConfig = newSigningConfig(false)
+
+	statusController := newReleasePayloadControllerTestBuilder(t).
+		WithReleasePayload(input).
+		WithBatchJob(job).
+		WithImageStream(imageStream).
+		Build()
+	if err := statusController.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+		t.Fatalf("unable to sync the release creation status: %v", err)
+	}
+	releasePayload, err := statusController.releasePayloadClient.ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get the ReleasePayload: %v", err)
+	}
+	if !readyToSign(releasePayload) {
+		t.Fatalf("expected the ReleasePayload to be ready to sign, got: %v", releasePayload.Status)
+	}
+
+	releasePayloadClient := fake.NewSimpleClientset(releasePayload)
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+	imageStreamInformerFactory := imageinformers.NewSharedInformerFactory(imagefake.NewSimpleClientset(imageStream), controllerDefaultResyncDuration)
+	kubeClient := fake2.NewSimpleClientset(secret)
+
+	c, err := NewSigningController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), imageStreamInformerFactory.Image().V1().ImageStreams(), kubeClient.CoreV1(), kubeClient.CoreV1(), events.NewInMemoryRecorder("signing-controller-test"))
+	if err != nil {
+		t.Fatalf("unable to create controller: %v", err)
+	}
+	defer c.queue.ShutDown()
+
+	releasePayloadInformerFactory.Start(context.Background().Done())
+	imageStreamInformerFactory.Start(context.Background().Done())
+	if !cache.WaitForNamedCacheSync("SigningController", context.Background().Done(), c.cachesToSync...) {
+		t.Fatalf("error waiting for caches to sync")
+	}
+
+	if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", releasePayload.Namespace, releasePayload.Name)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	configMap, err := kubeClient.CoreV1().ConfigMaps("ocp").Get(context.TODO(), signingTestReleaseName+SignatureConfigMapSuffix, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get signature ConfigMap: %v", err)
+	}
+	if digest := configMap.Data[SignatureDigestKey]; digest != releaseDigest {
+		t.Errorf("expected digest %q, got: %q", releaseDigest, digest)
+	}
+	if pullSpec := configMap.Data[SignaturePullSpecKey]; pullSpec != signingTestPullSpec {
+		t.Errorf("expected pull spec %q, got: %q", signingTestPullSpec, pullSpec)
+	}
+	if err := verifier.Verify(context.TODO(), releaseDigest, configMap.Name, configMap.BinaryData[SignatureKey]); err != nil {
+		t.Errorf("unable to verify signature: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package release_payload_controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
+	"gomodules.xyz/jsonpatch/v2"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// defaultStatusDiffCacheSize is the number of statuses, of each ReleasePayload, that are kept in the statusDiffCache
+const defaultStatusDiffCacheSize = 10
+
+// statusDiffCacheEntry is a status of a ReleasePayload, as observed by the informer
+type statusDiffCacheEntry struct {
+	ResourceVersion string
+	Status          v1alpha1.ReleasePayloadStatus
+}
+
+// statusDiffCache keeps, in memory, the most recent statuses of every ReleasePayload, so that the debug server can show
+// how a ReleasePayload got to its current state.  The history is lost when the controller restarts.
+type statusDiffCache struct {
+	size int
+
+	lock    sync.RWMutex
+	entries map[string][]statusDiffCacheEntry
+}
+
+func newStatusDiffCache(releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer, size int) *statusDiffCache {
+	h := &statusDiffCache{
+		size:    size,
+		entries: make(map[string][]statusDiffCacheEntry),
+	}
+
+	releasePayloadInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if releasePayload, ok := obj.(*v1alpha1.ReleasePayload); ok {
+				h.record(releasePayload)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldReleasePayload, ok := oldObj.(*v1alpha1.ReleasePayload)
+			if !ok {
+				return
+			}
+			newReleasePayload, ok := newObj.(*v1alpha1.ReleasePayload)
+			if !ok {
+				return
+			}
+			if reflect.DeepEqual(oldReleasePayload.Status, newReleasePayload.Status) {
+				return
+			}
+			h.record(newReleasePayload)
+		},
+		DeleteFunc: func(obj interface{}) {
+			key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+			if err != nil {
+				klog.Errorf("Unable to determine key of deleted ReleasePayload: %v", err)
+				return
+			}
+			h.forget(key)
+		},
+	})
+
+	return h
+}
+
+// record appends the status of the ReleasePayload to its history, dropping the oldest status once the history is full
+func (h *statusDiffCache) record(releasePayload *v1alpha1.ReleasePayload) {
+	key := fmt.Sprintf("%s/%s", releasePayload.Namespace, releasePayload.Name)
+	entry := statusDiffCacheEntry{
+		ResourceVersion: releasePayload.ResourceVersion,
+		Status:          *releasePayload.Status.DeepCopy(),
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	entries := h.entries[key]
+	if len(entries) >= h.size {
+		entries = append(entries[:0], entries[len(entries)-h.size+1:]...)
+	}
+	h.entries[key] = append(entries, entry)
+}
+
+func (h *statusDiffCache) forget(key string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.entries, key)
+}
+
+// last returns up to count of the most recent statuses of the ReleasePayload, oldest first
+func (h *statusDiffCache) last(key string, count int) []statusDiffCacheEntry {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	entries := h.entries[key]
+	if len(entries) > count {
+		entries = entries[len(entries)-count:]
+	}
+	result := make([]statusDiffCacheEntry, len(entries))
+	copy(result, entries)
+	return result
+}
+
+// diffStatuses returns the JSON Patch that transforms the from status into the to status
+func diffStatuses(from, to v1alpha1.ReleasePayloadStatus) ([]jsonpatch.Operation, error) {
+	fromData, err := json.Marshal(from)
+	if err != nil {
+		return nil, err
+	}
+	toData, err := json.Marshal(to)
+	if err != nil {
+		return nil, err
+	}
+	return jsonpatch.CreatePatch(fromData, toData)
+}
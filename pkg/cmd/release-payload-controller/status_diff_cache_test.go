@@ -0,0 +1,229 @@
+package release_payload_controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	evanphxjsonpatch "github.com/evanphx/json-patch"
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"gomodules.xyz/jsonpatch/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStatusDiffCacheRecord(t *testing.T) {
+	h := &statusDiffCache{size: 3, entries: make(map[string][]statusDiffCacheEntry)}
+	for i := 1; i <= 5; i++ {
+		h.record(&v1alpha1.ReleasePayload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "4.11.0-0.nightly-2022-02-09-091559",
+				Namespace:       "ocp",
+				ResourceVersion: fmt.Sprintf("%d", i),
+			},
+		})
+	}
+
+	var resourceVersions []string
+	for _, entry := range h.last("ocp/4.11.0-0.nightly-2022-02-09-091559", 10) {
+		resourceVersions = append(resourceVersions, entry.ResourceVersion)
+	}
+	if expected := []string{"3", "4", "5"}; !cmp.Equal(resourceVersions, expected) {
+		t.Errorf("Expected %v, got: %v", expected, resourceVersions)
+	}
+
+	var last []string
+	for _, entry := range h.last("ocp/4.11.0-0.nightly-2022-02-09-091559", 2) {
+		last = append(last, entry.ResourceVersion)
+	}
+	if expected := []string{"4", "5"}; !cmp.Equal(last, expected) {
+		t.Errorf("Expected %v, got: %v", expected, last)
+	}
+
+	h.forget("ocp/4.11.0-0.nightly-2022-02-09-091559")
+	if entries := h.last("ocp/4.11.0-0.nightly-2022-02-09-091559", 2); len(entries) != 0 {
+		t.Errorf("Expected no entries, got: %v", entries)
+	}
+}
+
+func TestDiffStatuses(t *testing.T) {
+	testCases := []struct {
+		name     string
+		from     v1alpha1.ReleasePayloadStatus
+		to       v1alpha1.ReleasePayloadStatus
+		expected []jsonpatch.Operation
+	}{
+		{
+			name:     "NoChanges",
+			from:     v1alpha1.ReleasePayloadStatus{ReleaseDigest: "sha256:1234"},
+			to:       v1alpha1.ReleasePayloadStatus{ReleaseDigest: "sha256:1234"},
+			expected: []jsonpatch.Operation{},
+		},
+		{
+			name: "FieldAdded",
+			from: v1alpha1.ReleasePayloadStatus{},
+			to:   v1alpha1.ReleasePayloadStatus{ReleaseDigest: "sha256:1234"},
+			expected: []jsonpatch.Operation{
+				{Operation: "add", Path: "/releaseDigest", Value: "sha256:1234"},
+			},
+		},
+		{
+			name: "FieldReplaced",
+			from: v1alpha1.ReleasePayloadStatus{
+				ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{Status: v1alpha1.ReleaseCreationJobUnknown},
+			},
+			to: v1alpha1.ReleasePayloadStatus{
+				ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{Status: v1alpha1.ReleaseCreationJobSuccess},
+			},
+			expected: []jsonpatch.Operation{
+				{Operation: "replace", Path: "/releaseCreationJobResult/status", Value: string(v1alpha1.ReleaseCreationJobSuccess)},
+			},
+		},
+		{
+			name: "ConditionsChanged",
+			from: v1alpha1.ReleasePayloadStatus{
+				Conditions: []metav1.Condition{
+					{Type: v1alpha1.ConditionPayloadCreated, Status: metav1.ConditionFalse},
+					{Type: v1alpha1.ConditionPayloadFailed, Status: metav1.ConditionFalse},
+				},
+			},
+			to: v1alpha1.ReleasePayloadStatus{
+				Conditions: []metav1.Condition{
+					{Type: v1alpha1.ConditionPayloadCreated, Status: metav1.ConditionTrue},
+				},
+			},
+			expected: []jsonpatch.Operation{
+				{Operation: "remove", Path: "/conditions/1"},
+				{Operation: "replace", Path: "/conditions/0/status", Value: string(metav1.ConditionTrue)},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			patch, err := diffStatuses(testCase.from, testCase.to)
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			if !cmp.Equal(patch, testCase.expected) {
+				t.Errorf("%s: %s", testCase.name, cmp.Diff(testCase.expected, patch))
+			}
+		})
+	}
+}
+
+func TestDiffStatusesApply(t *testing.T) {
+	startTime := metav1.NewTime(time.Date(2022, time.February, 9, 9, 15, 59, 0, time.UTC))
+	jobStatus := func(name string, states ...v1alpha1.JobRunState) v1alpha1.JobStatus {
+		status := v1alpha1.JobStatus{
+			CIConfigurationName:    name,
+			CIConfigurationJobName: fmt.Sprintf("periodic-ci-openshift-release-master-nightly-4.11-e2e-%s", name),
+			AggregateState:         v1alpha1.JobStatePending,
+		}
+		for i, state := range states {
+			status.JobRunResults = append(status.JobRunResults, v1alpha1.JobRunResult{
+				Coordinates: v1alpha1.JobRunCoordinates{
+					Name:      fmt.Sprintf("4.11.0-0.nightly-2022-02-09-091559-%s-%d", name, i),
+					Namespace: "ci",
+					Cluster:   "build04",
+				},
+				State: state,
+			})
+		}
+		return status
+	}
+
+	testCases := []struct {
+		name string
+		from v1alpha1.ReleasePayloadStatus
+		to   v1alpha1.ReleasePayloadStatus
+	}{
+		{
+			name: "Empty",
+		},
+		{
+			name: "ReleaseCreated",
+			from: v1alpha1.ReleasePayloadStatus{
+				ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{Status: v1alpha1.ReleaseCreationJobUnknown},
+			},
+			to: v1alpha1.ReleasePayloadStatus{
+				ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+					Status:    v1alpha1.ReleaseCreationJobSuccess,
+					Message:   "Release created",
+					StartTime: &startTime,
+				},
+				ReleaseDigest: "sha256:1234",
+				Conditions: []metav1.Condition{
+					{Type: v1alpha1.ConditionPayloadCreated, Status: metav1.ConditionTrue, LastTransitionTime: startTime},
+				},
+			},
+		},
+		{
+			name: "JobsAddedAndRemoved",
+			from: v1alpha1.ReleasePayloadStatus{
+				BlockingJobResults: []v1alpha1.JobStatus{
+					jobStatus("aws", v1alpha1.JobRunStatePending),
+					jobStatus("gcp", v1alpha1.JobRunStatePending),
+					jobStatus("azure", v1alpha1.JobRunStateFailure, v1alpha1.JobRunStatePending),
+				},
+				InformingJobResults: []v1alpha1.JobStatus{
+					jobStatus("metal", v1alpha1.JobRunStatePending),
+				},
+			},
+			to: v1alpha1.ReleasePayloadStatus{
+				BlockingJobResults: []v1alpha1.JobStatus{
+					jobStatus("aws", v1alpha1.JobRunStateSuccess),
+				},
+				InformingJobResults: []v1alpha1.JobStatus{
+					jobStatus("metal", v1alpha1.JobRunStateFailure, v1alpha1.JobRunStateFailure, v1alpha1.JobRunStateSuccess),
+					jobStatus("vsphere", v1alpha1.JobRunStatePending),
+				},
+			},
+		},
+		{
+			name: "StatusCleared",
+			from: v1alpha1.ReleasePayloadStatus{
+				ReleaseDigest: "sha256:1234",
+				Conditions: []metav1.Condition{
+					{Type: v1alpha1.ConditionPayloadCreated, Status: metav1.ConditionTrue, LastTransitionTime: startTime},
+					{Type: v1alpha1.ConditionPayloadFailed, Status: metav1.ConditionFalse, LastTransitionTime: startTime},
+				},
+				BlockingJobResults: []v1alpha1.JobStatus{
+					jobStatus("aws", v1alpha1.JobRunStateSuccess),
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			patch, err := diffStatuses(testCase.from, testCase.to)
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			// Verify the patch, by applying it to the from status, rather than by comparing it to a hand written one
+			patchData, err := json.Marshal(patch)
+			if err != nil {
+				t.Fatalf("%s: unable to marshal the patch: %v", testCase.name, err)
+			}
+			decoded, err := evanphxjsonpatch.DecodePatch(patchData)
+			if err != nil {
+				t.Fatalf("%s: unable to decode the patch %s: %v", testCase.name, patchData, err)
+			}
+			fromData, err := json.Marshal(testCase.from)
+			if err != nil {
+				t.Fatalf("%s: unable to marshal the from status: %v", testCase.name, err)
+			}
+			patched, err := decoded.Apply(fromData)
+			if err != nil {
+				t.Fatalf("%s: unable to apply the patch %s: %v", testCase.name, patchData, err)
+			}
+			var actual v1alpha1.ReleasePayloadStatus
+			if err := json.Unmarshal(patched, &actual); err != nil {
+				t.Fatalf("%s: unable to unmarshal the patched status: %v", testCase.name, err)
+			}
+			if !cmp.Equal(actual, testCase.to) {
+				t.Errorf("%s: patch %s: %s", testCase.name, patchData, cmp.Diff(testCase.to, actual))
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package release_payload_controller
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
+	releasepayloadlister "github.com/openshift/release-controller/pkg/client/listers/release/v1alpha1"
+	"golang.org/x/net/websocket"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// statusWatchPathPrefix is the prefix of the /ws/releasePayloads/{namespace}/{name}/status endpoint
+const statusWatchPathPrefix = "/ws/releasePayloads/"
+
+// statusWatchHeartbeatInterval is how often the status watch connections are pinged, so that dead connections are
+// detected and closed
+const statusWatchHeartbeatInterval = 30 * time.Second
+
+// statusBroadcaster pushes the status changes, of the ReleasePayloads observed by the informer, to the subscribers of
+// the changed ReleasePayload
+type statusBroadcaster struct {
+	releasePayloadLister releasepayloadlister.ReleasePayloadLister
+
+	lock        sync.Mutex
+	subscribers map[string]map[chan v1alpha1.ReleasePayloadStatus]struct{}
+}
+
+func newStatusBroadcaster(releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer) *statusBroadcaster {
+	b := &statusBroadcaster{
+		releasePayloadLister: releasePayloadInformer.Lister(),
+		subscribers:          make(map[string]map[chan v1alpha1.ReleasePayloadStatus]struct{}),
+	}
+
+	releasePayloadInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldReleasePayload, ok := oldObj.(*v1alpha1.ReleasePayload)
+			if !ok {
+				return
+			}
+			newReleasePayload, ok := newObj.(*v1alpha1.ReleasePayload)
+			if !ok {
+				return
+			}
+			if reflect.DeepEqual(oldReleasePayload.Status, newReleasePayload.Status) {
+				return
+			}
+			key, err := cache.MetaNamespaceKeyFunc(newReleasePayload)
+			if err != nil {
+				return
+			}
+			b.publish(key, newReleasePayload.Status)
+		},
+	})
+
+	return b
+}
+
+// subscribe returns a channel that receives the statuses of the ReleasePayload, and a function that unsubscribes it.
+// A subscriber that falls behind only receives the most recent status.
+func (b *statusBroadcaster) subscribe(key string) (<-chan v1alpha1.ReleasePayloadStatus, func()) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	ch := make(chan v1alpha1.ReleasePayloadStatus, 1)
+	if _, ok := b.subscribers[key]; !ok {
+		b.subscribers[key] = make(map[chan v1alpha1.ReleasePayloadStatus]struct{})
+	}
+	b.subscribers[key][ch] = struct{}{}
+	return ch, func() {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+		delete(b.subscribers[key], ch)
+		if len(b.subscribers[key]) == 0 {
+			delete(b.subscribers, key)
+		}
+	}
+}
+
+func (b *statusBroadcaster) publish(key string, status v1alpha1.ReleasePayloadStatus) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for ch := range b.subscribers[key] {
+		// Replace the status that the subscriber has yet to receive
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- status
+	}
+}
+
+// subscriberCount returns the number of subscribers of the ReleasePayload
+func (b *statusBroadcaster) subscriberCount(key string) int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return len(b.subscribers[key])
+}
+
+// statusWatchHandler serves the status of a ReleasePayload over a WebSocket.  The current status is sent, as JSON, once
+// the connection is established and every change of the status is sent as it is observed.
+func statusWatchHandler(b *statusBroadcaster, heartbeatInterval time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		parts := strings.Split(strings.TrimPrefix(req.URL.Path, statusWatchPathPrefix), "/")
+		if len(parts) != 3 || len(parts[0]) == 0 || len(parts[1]) == 0 || parts[2] != "status" {
+			http.NotFound(w, req)
+			return
+		}
+		namespace, name := parts[0], parts[1]
+
+		// Subscribe before reading the current status, so that no change is missed in between
+		updates, unsubscribe := b.subscribe(fmt.Sprintf("%s/%s", namespace, name))
+		defer unsubscribe()
+
+		releasePayload, err := b.releasePayloadLister.ReleasePayloads(namespace).Get(name)
+		if errors.IsNotFound(err) {
+			http.Error(w, fmt.Sprintf("ReleasePayload %s/%s not found", namespace, name), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		websocket.Server{
+			Handler: func(conn *websocket.Conn) {
+				streamStatus(conn, releasePayload.Status, updates, heartbeatInterval)
+			},
+		}.ServeHTTP(w, req)
+	}
+}
+
+// streamStatus sends the current status, followed by the updates, until the connection is closed or found to be dead
+func streamStatus(conn *websocket.Conn, current v1alpha1.ReleasePayloadStatus, updates <-chan v1alpha1.ReleasePayloadStatus, heartbeatInterval time.Duration) {
+	// A live client answers every ping, so the connection is considered dead once no frame is received for two heartbeats
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		if err := receiveFrames(conn, 2*heartbeatInterval); err != nil && err != io.EOF {
+			klog.V(4).Infof("Closing status watch connection from %s: %v", conn.Request().RemoteAddr, err)
+		}
+	}()
+
+	if err := sendStatus(conn, current, heartbeatInterval); err != nil {
+		klog.V(4).Infof("Unable to send the status to %s: %v", conn.Request().RemoteAddr, err)
+		return
+	}
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case status := <-updates:
+			if err := sendStatus(conn, status, heartbeatInterval); err != nil {
+				klog.V(4).Infof("Unable to send the status to %s: %v", conn.Request().RemoteAddr, err)
+				return
+			}
+		case <-ticker.C:
+			if err := ping(conn, heartbeatInterval); err != nil {
+				klog.V(4).Infof("Closing dead status watch connection from %s: %v", conn.Request().RemoteAddr, err)
+				return
+			}
+		}
+	}
+}
+
+// receiveFrames discards the frames sent by the client, which is only expected to send pong and close frames, until
+// the connection is closed or no frame is received within the timeout.  websocket.Message.Receive does not return the
+// control frames, so the frames are read one at a time in order to refresh the read deadline on every pong.
+func receiveFrames(conn *websocket.Conn, timeout time.Duration) error {
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return err
+		}
+		frame, err := conn.NewFrameReader()
+		if err != nil {
+			return err
+		}
+		// Answers pings and returns nothing for the control frames, or io.EOF once the client closes the connection
+		payload, err := conn.HandleFrame(frame)
+		if err != nil {
+			return err
+		}
+		if payload != nil {
+			if _, err := io.Copy(io.Discard, payload); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func sendStatus(conn *websocket.Conn, status v1alpha1.ReleasePayloadStatus, timeout time.Duration) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	return websocket.JSON.Send(conn, status)
+}
+
+// ping sends a ping frame, which the client answers with a pong frame
+func ping(conn *websocket.Conn, timeout time.Duration) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	conn.PayloadType = websocket.PingFrame
+	defer func() { conn.PayloadType = websocket.TextFrame }()
+	_, err := conn.Write(nil)
+	return err
+}
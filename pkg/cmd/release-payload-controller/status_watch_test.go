@@ -0,0 +1,227 @@
+package release_payload_controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	"golang.org/x/net/websocket"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// newStatusWatchTestServer serves the status watch endpoint, over the ReleasePayloads in the fake client
+func newStatusWatchTestServer(t *testing.T, heartbeatInterval time.Duration, releasePayloads ...*v1alpha1.ReleasePayload) (*httptest.Server, *statusBroadcaster, *fake.Clientset) {
+	t.Helper()
+	var objects []runtime.Object
+	for _, releasePayload := range releasePayloads {
+		objects = append(objects, releasePayload)
+	}
+	releasePayloadClient := fake.NewSimpleClientset(objects...)
+	releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+	releasePayloadInformer := releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads()
+	broadcaster := newStatusBroadcaster(releasePayloadInformer)
+
+	stopCh := make(chan struct{})
+	releasePayloadInformerFactory.Start(stopCh)
+	if !cache.WaitForNamedCacheSync("StatusWatch", stopCh, releasePayloadInformer.Informer().HasSynced) {
+		t.Fatalf("error waiting for caches to sync")
+	}
+
+	server := httptest.NewServer(statusWatchHandler(broadcaster, heartbeatInterval))
+	t.Cleanup(func() {
+		server.Close()
+		close(stopCh)
+	})
+	return server, broadcaster, releasePayloadClient
+}
+
+func dialStatusWatch(t *testing.T, server *httptest.Server, path string) *websocket.Conn {
+	t.Helper()
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+path, "", server.URL)
+	if err != nil {
+		t.Fatalf("unable to connect to %s: %v", path, err)
+	}
+	return conn
+}
+
+// receiveStatuses reads the statuses sent over the connection, in the background, so that the pings are answered while
+// the test is not waiting for a status
+func receiveStatuses(t *testing.T, conn *websocket.Conn) <-chan v1alpha1.ReleasePayloadStatus {
+	t.Helper()
+	statuses := make(chan v1alpha1.ReleasePayloadStatus)
+	go func() {
+		defer close(statuses)
+		for {
+			var status v1alpha1.ReleasePayloadStatus
+			if err := websocket.JSON.Receive(conn, &status); err != nil {
+				return
+			}
+			statuses <- status
+		}
+	}()
+	return statuses
+}
+
+func receiveStatus(t *testing.T, statuses <-chan v1alpha1.ReleasePayloadStatus) v1alpha1.ReleasePayloadStatus {
+	t.Helper()
+	select {
+	case status, ok := <-statuses:
+		if !ok {
+			t.Fatalf("unable to receive the status: the connection was closed")
+		}
+		return status
+	case <-time.After(10 * time.Second):
+		t.Fatalf("unable to receive the status: timed out")
+	}
+	return v1alpha1.ReleasePayloadStatus{}
+}
+
+func TestStatusWatchHandler(t *testing.T) {
+	releasePayload := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Status: v1alpha1.ReleaseCreationJobUnknown,
+			},
+		},
+	}
+	// A heartbeat interval, much shorter than the test, makes sure that answering the pings keeps the connection alive
+	server, broadcaster, releasePayloadClient := newStatusWatchTestServer(t, 10*time.Millisecond, releasePayload)
+
+	conn := dialStatusWatch(t, server, "/ws/releasePayloads/ocp/4.11.0-0.nightly-2022-02-09-091559/status")
+	statuses := receiveStatuses(t, conn)
+
+	if status := receiveStatus(t, statuses); !cmp.Equal(status, releasePayload.Status) {
+		t.Errorf("Unexpected initial status: %s", cmp.Diff(releasePayload.Status, status))
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	for _, jobStatus := range []v1alpha1.ReleaseCreationJobStatus{v1alpha1.ReleaseCreationJobPending, v1alpha1.ReleaseCreationJobSuccess} {
+		current, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads("ocp").Get(context.TODO(), releasePayload.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		current.Status.ReleaseCreationJobResult.Status = jobStatus
+		updated, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads("ocp").UpdateStatus(context.TODO(), current, metav1.UpdateOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if status := receiveStatus(t, statuses); !cmp.Equal(status, updated.Status) {
+			t.Errorf("Unexpected status: %s", cmp.Diff(updated.Status, status))
+		}
+	}
+
+	// Closing the connection unsubscribes it
+	if err := conn.Close(); err != nil {
+		t.Fatalf("unable to close the connection: %v", err)
+	}
+	key := "ocp/4.11.0-0.nightly-2022-02-09-091559"
+	for i := 0; broadcaster.subscriberCount(key) > 0; i++ {
+		if i == 100 {
+			t.Fatalf("Expected the closed connection to be unsubscribed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStatusWatchHandlerDeadConnection(t *testing.T) {
+	releasePayload := &v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+	}
+	server, broadcaster, _ := newStatusWatchTestServer(t, 10*time.Millisecond, releasePayload)
+
+	// The connection is never read, so the pings are not answered
+	conn := dialStatusWatch(t, server, "/ws/releasePayloads/ocp/4.11.0-0.nightly-2022-02-09-091559/status")
+	defer conn.Close()
+
+	key := "ocp/4.11.0-0.nightly-2022-02-09-091559"
+	for i := 0; broadcaster.subscriberCount(key) > 0; i++ {
+		if i == 100 {
+			t.Fatalf("Expected the dead connection to be closed and unsubscribed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStatusWatchHandlerErrors(t *testing.T) {
+	server, broadcaster, _ := newStatusWatchTestServer(t, statusWatchHeartbeatInterval)
+
+	testCases := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{
+			name:           "UnknownReleasePayload",
+			path:           "/ws/releasePayloads/ocp/4.11.0-0.nightly-2022-02-09-091559/status",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "MissingName",
+			path:           "/ws/releasePayloads/ocp/status",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "UnknownSubresource",
+			path:           "/ws/releasePayloads/ocp/4.11.0-0.nightly-2022-02-09-091559/diff",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + testCase.path)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", testCase.name, err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != testCase.expectedStatus {
+				t.Errorf("%s: Expected status %d, got %d", testCase.name, testCase.expectedStatus, resp.StatusCode)
+			}
+		})
+	}
+
+	if count := broadcaster.subscriberCount("ocp/4.11.0-0.nightly-2022-02-09-091559"); count != 0 {
+		t.Errorf("Expected the failed requests to be unsubscribed, got %d subscribers", count)
+	}
+}
+
+func TestStatusBroadcasterLatestStatus(t *testing.T) {
+	b := &statusBroadcaster{subscribers: make(map[string]map[chan v1alpha1.ReleasePayloadStatus]struct{})}
+	updates, unsubscribe := b.subscribe("ocp/4.11.0-0.nightly-2022-02-09-091559")
+	defer unsubscribe()
+
+	for _, jobStatus := range []v1alpha1.ReleaseCreationJobStatus{v1alpha1.ReleaseCreationJobUnknown, v1alpha1.ReleaseCreationJobPending, v1alpha1.ReleaseCreationJobFailed} {
+		b.publish("ocp/4.11.0-0.nightly-2022-02-09-091559", v1alpha1.ReleasePayloadStatus{ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{Status: jobStatus}})
+	}
+	b.publish("ocp/4.11.0-0.nightly-2022-02-10-091559", v1alpha1.ReleasePayloadStatus{ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{Status: v1alpha1.ReleaseCreationJobSuccess}})
+
+	select {
+	case status := <-updates:
+		if status.ReleaseCreationJobResult.Status != v1alpha1.ReleaseCreationJobFailed {
+			t.Errorf("Expected only the most recent status, got %s", status.ReleaseCreationJobResult.Status)
+		}
+	default:
+		t.Fatalf("Expected a status")
+	}
+	select {
+	case status := <-updates:
+		t.Errorf("Unexpected status: %v", status)
+	default:
+	}
+}
@@ -0,0 +1,161 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned/typed/release/v1alpha1"
+	releasepayloadinformer "github.com/openshift/release-controller/pkg/client/informers/externalversions/release/v1alpha1"
+	releasepayloadhelpers "github.com/openshift/release-controller/pkg/releasepayload/v1alpha1helpers"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// StreamQuotaExceededReason programmatic identifier indicating that the ReleasePayload was failed because too many
+// ReleasePayloads, of the same release stream, were already in progress
+const StreamQuotaExceededReason string = "StreamQuotaExceeded"
+
+// StreamQuotaController is responsible for failing new ReleasePayloads when the release stream, that they belong to,
+// already has the maximum number of ReleasePayloads in progress.  Only the ReleasePayloads created before a
+// ReleasePayload count towards its quota, so a ReleasePayload that was allowed to proceed is never failed later on.
+// The StreamQuotaController reads the following pieces of information:
+//   - .metadata.creationTimestamp
+//   - .status.conditions.PayloadAccepted
+//   - .status.conditions.PayloadRejected
+//   - .status.conditions.PayloadFailed
+//
+// and populates the following conditions:
+//   - .status.conditions.PayloadCreated
+//   - .status.conditions.PayloadFailed
+type StreamQuotaController struct {
+	*ReleasePayloadController
+
+	// maxPayloadsPerStream is the number of ReleasePayloads, of a release stream, that may be in progress at once
+	maxPayloadsPerStream int
+}
+
+func NewStreamQuotaController(
+	releasePayloadInformer releasepayloadinformer.ReleasePayloadInformer,
+	releasePayloadClient releasepayloadclient.ReleaseV1alpha1Interface,
+	eventRecorder events.Recorder,
+	maxPayloadsPerStream int,
+) (*StreamQuotaController, error) {
+	c := &StreamQuotaController{
+		ReleasePayloadController: NewReleasePayloadController("Stream Quota Controller",
+			releasePayloadInformer,
+			releasePayloadClient,
+			eventRecorder.WithComponentSuffix("stream-quota-controller"),
+			workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "StreamQuotaController")),
+		maxPayloadsPerStream: maxPayloadsPerStream,
+	}
+
+	c.syncFn = c.sync
+
+	releasePayloadInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			releasePayload, ok := obj.(*v1alpha1.ReleasePayload)
+			return ok && !isTerminal(releasePayload)
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: c.Enqueue,
+		},
+	})
+
+	return c, nil
+}
+
+// createdBefore returns true if ReleasePayload a was created before ReleasePayload b.  ReleasePayloads created within
+// the same second are ordered by name, which includes the timestamp of the release.
+func createdBefore(a, b *v1alpha1.ReleasePayload) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+func (c *StreamQuotaController) sync(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Starting StreamQuotaController sync")
+	defer logger.V(4).Info("StreamQuotaController sync done")
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	// Get the ReleasePayload resource with this namespace/name
+	originalReleasePayload, err := c.releasePayloadLister.ReleasePayloads(namespace).Get(name)
+	// The ReleasePayload resource may no longer exist, in which case we stop processing.
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// If reconciliation of the ReleasePayload has been paused, then leave it alone...
+	if c.reconciliationPaused(originalReleasePayload) {
+		return nil
+	}
+
+	if isTerminal(originalReleasePayload) {
+		return nil
+	}
+
+	// Count the ReleasePayloads, of the same stream, that were created earlier and are still in progress
+	stream := releasepayloadhelpers.ReleasePayloadStream(originalReleasePayload)
+	releasePayloads, err := c.releasePayloadLister.ReleasePayloads(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	var inProgress int
+	for _, releasePayload := range releasePayloads {
+		if releasePayload.UID == originalReleasePayload.UID || isTerminal(releasePayload) || !createdBefore(releasePayload, originalReleasePayload) {
+			continue
+		}
+		if releasepayloadhelpers.ReleasePayloadStream(releasePayload) == stream {
+			inProgress++
+		}
+	}
+	if inProgress < c.maxPayloadsPerStream {
+		return nil
+	}
+
+	// Both conditions are set, so that the PayloadCreationController leaves the ReleasePayload alone
+	message := fmt.Sprintf("%d ReleasePayloads of the %s stream are already in progress, the maximum is %d", inProgress, stream, c.maxPayloadsPerStream)
+	releasePayload := originalReleasePayload.DeepCopy()
+	v1helpers.SetCondition(&releasePayload.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.ConditionPayloadCreated,
+		Status:  metav1.ConditionFalse,
+		Reason:  StreamQuotaExceededReason,
+		Message: message,
+	})
+	v1helpers.SetCondition(&releasePayload.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.ConditionPayloadFailed,
+		Status:  metav1.ConditionTrue,
+		Reason:  StreamQuotaExceededReason,
+		Message: message,
+	})
+	releasepayloadhelpers.CanonicalizeReleasePayloadStatus(releasePayload)
+
+	klog.V(4).Infof("Failing ReleasePayload %s/%s: %s", releasePayload.Namespace, releasePayload.Name, message)
+	_, err = c.releasePayloadClient.ReleasePayloads(releasePayload.Namespace).UpdateStatus(ctx, releasePayload, metav1.UpdateOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c.eventRecorder.Warningf("ReleasePayloadStreamQuotaExceeded", "Failed ReleasePayload %s/%s: %s", releasePayload.Namespace, releasePayload.Name, message)
+	return nil
+}
@@ -0,0 +1,168 @@
+package release_payload_controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"github.com/openshift/release-controller/pkg/client/clientset/versioned/fake"
+	releasepayloadinformers "github.com/openshift/release-controller/pkg/client/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestStreamQuotaSync(t *testing.T) {
+	now := time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC)
+
+	newReleasePayload := func(name string, created time.Time, conditions ...string) *v1alpha1.ReleasePayload {
+		releasePayload := &v1alpha1.ReleasePayload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "ocp",
+				UID:               types.UID(name),
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: v1alpha1.ReleasePayloadSpec{
+				PayloadCoordinates: v1alpha1.PayloadCoordinates{
+					Namespace:          "ocp",
+					ImagestreamName:    "release",
+					ImagestreamTagName: name,
+				},
+			},
+		}
+		for _, condition := range conditions {
+			releasePayload.Status.Conditions = append(releasePayload.Status.Conditions, metav1.Condition{Type: condition, Status: metav1.ConditionTrue})
+		}
+		return releasePayload
+	}
+	// inProgress returns count in progress ReleasePayloads of the 4.11.0-0.nightly stream, created before now
+	inProgress := func(count int) []runtime.Object {
+		var releasePayloads []runtime.Object
+		for i := 0; i < count; i++ {
+			created := now.Add(-time.Duration(count-i) * time.Hour)
+			releasePayloads = append(releasePayloads, newReleasePayload(fmt.Sprintf("4.11.0-0.nightly-%s", created.Format("2006-01-02-150405")), created))
+		}
+		return releasePayloads
+	}
+
+	testCases := []struct {
+		name           string
+		existing       []runtime.Object
+		input          *v1alpha1.ReleasePayload
+		expectedFailed bool
+		expectedUpdate bool
+	}{
+		{
+			name:     "NoOtherReleasePayloads",
+			existing: nil,
+		},
+		{
+			name:     "BelowQuota",
+			existing: inProgress(1),
+		},
+		{
+			name:     "AtQuota",
+			existing: inProgress(2),
+		},
+		{
+			name:           "AboveQuota",
+			existing:       inProgress(3),
+			expectedFailed: true,
+			expectedUpdate: true,
+		},
+		{
+			name: "TerminalReleasePayloadsNotCounted",
+			existing: []runtime.Object{
+				newReleasePayload("4.11.0-0.nightly-2022-02-09-061559", now.Add(-3*time.Hour), v1alpha1.ConditionPayloadAccepted),
+				newReleasePayload("4.11.0-0.nightly-2022-02-09-071559", now.Add(-2*time.Hour), v1alpha1.ConditionPayloadRejected),
+				newReleasePayload("4.11.0-0.nightly-2022-02-09-081559", now.Add(-1*time.Hour), v1alpha1.ConditionPayloadFailed),
+			},
+		},
+		{
+			name: "OtherStreamsNotCounted",
+			existing: []runtime.Object{
+				newReleasePayload("4.11.0-0.ci-2022-02-09-061559", now.Add(-3*time.Hour)),
+				newReleasePayload("4.12.0-0.nightly-2022-02-09-071559", now.Add(-2*time.Hour)),
+				newReleasePayload("4.11.0-0.nightly-arm64-2022-02-09-081559", now.Add(-1*time.Hour)),
+			},
+		},
+		{
+			name: "NewerReleasePayloadsNotCounted",
+			existing: []runtime.Object{
+				newReleasePayload("4.11.0-0.nightly-2022-02-09-101559", now.Add(1*time.Hour)),
+				newReleasePayload("4.11.0-0.nightly-2022-02-09-111559", now.Add(2*time.Hour)),
+				newReleasePayload("4.11.0-0.nightly-2022-02-09-121559", now.Add(3*time.Hour)),
+			},
+		},
+		{
+			name:           "AlreadyFailed",
+			existing:       inProgress(3),
+			input:          newReleasePayload("4.11.0-0.nightly-2022-02-09-091559", now, v1alpha1.ConditionPayloadFailed),
+			expectedFailed: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			input := newReleasePayload("4.11.0-0.nightly-2022-02-09-091559", now)
+			if testCase.input != nil {
+				input = testCase.input
+			}
+			objects := append([]runtime.Object{input}, testCase.existing...)
+			releasePayloadClient := fake.NewSimpleClientset(objects...)
+			releasePayloadInformerFactory := releasepayloadinformers.NewSharedInformerFactory(releasePayloadClient, controllerDefaultResyncDuration)
+
+			c, err := NewStreamQuotaController(releasePayloadInformerFactory.Release().V1alpha1().ReleasePayloads(), releasePayloadClient.ReleaseV1alpha1(), events.NewInMemoryRecorder("stream-quota-controller-test"), 3)
+			if err != nil {
+				t.Fatalf("%s: unable to create controller: %v", testCase.name, err)
+			}
+			defer c.queue.ShutDown()
+
+			releasePayloadInformerFactory.Start(context.Background().Done())
+
+			if !cache.WaitForNamedCacheSync("StreamQuotaController", context.Background().Done(), c.cachesToSync...) {
+				t.Fatalf("%s: error waiting for caches to sync", testCase.name)
+			}
+
+			releasePayloadClient.ClearActions()
+
+			if err := c.sync(context.TODO(), fmt.Sprintf("%s/%s", input.Namespace, input.Name)); err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+
+			output, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads(input.Namespace).Get(context.TODO(), input.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			if failed := v1helpers.IsConditionTrue(output.Status.Conditions, v1alpha1.ConditionPayloadFailed); failed != testCase.expectedFailed {
+				t.Errorf("%s: Expected failed: %t, got: %t", testCase.name, testCase.expectedFailed, failed)
+			}
+
+			var updated bool
+			for _, action := range releasePayloadClient.Actions() {
+				if action.GetVerb() == "update" && action.GetSubresource() == "status" {
+					updated = true
+				}
+			}
+			if updated != testCase.expectedUpdate {
+				t.Errorf("%s: Expected updated: %t, got: %t", testCase.name, testCase.expectedUpdate, updated)
+			}
+			if !updated {
+				return
+			}
+			for _, conditionType := range []string{v1alpha1.ConditionPayloadCreated, v1alpha1.ConditionPayloadFailed} {
+				if condition := v1helpers.FindCondition(output.Status.Conditions, conditionType); condition == nil || condition.Reason != StreamQuotaExceededReason {
+					t.Errorf("%s: Expected the %s condition to have reason %s, got: %v", testCase.name, conditionType, StreamQuotaExceededReason, condition)
+				}
+			}
+			if !v1helpers.IsConditionFalse(output.Status.Conditions, v1alpha1.ConditionPayloadCreated) {
+				t.Errorf("%s: Expected the %s condition to be False", testCase.name, v1alpha1.ConditionPayloadCreated)
+			}
+		})
+	}
+}
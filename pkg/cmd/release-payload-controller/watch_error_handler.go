@@ -0,0 +1,77 @@
+package release_payload_controller
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// The strategies for handling the errors of the informers' list and watch calls.  In every case, the reflector
+// keeps retrying the failed call with backoff.
+const (
+	// WatchErrorStrategyBackoff handles watch errors like client-go does by default
+	WatchErrorStrategyBackoff = "backoff"
+
+	// WatchErrorStrategyLogOnly logs every watch error, including the ones client-go considers to be benign
+	WatchErrorStrategyLogOnly = "log-only"
+
+	// WatchErrorStrategyFailFast exits the process once an informer hits the configured number of consecutive
+	// watch errors, so that Kubernetes restarts the pod
+	WatchErrorStrategyFailFast = "fail-fast"
+)
+
+var watchErrorStrategies = []string{WatchErrorStrategyBackoff, WatchErrorStrategyLogOnly, WatchErrorStrategyFailFast}
+
+// newWatchErrorHandler returns the cache.WatchErrorHandler, for the named informer, that implements the strategy.
+// Handlers must not be shared between informers, because the fail-fast strategy counts the errors of its informer.
+func newWatchErrorHandler(name, strategy string, failFastThreshold int) (cache.WatchErrorHandler, error) {
+	switch strategy {
+	case WatchErrorStrategyBackoff:
+		return cache.DefaultWatchErrorHandler, nil
+	case WatchErrorStrategyLogOnly:
+		return func(r *cache.Reflector, err error) {
+			klog.Warningf("%s: watch failed: %v", name, err)
+		}, nil
+	case WatchErrorStrategyFailFast:
+		return (&failFastWatchErrorHandler{name: name, threshold: failFastThreshold, exit: os.Exit}).handle, nil
+	}
+	return nil, fmt.Errorf("unknown watch error strategy %q, must be one of %v", strategy, watchErrorStrategies)
+}
+
+// failFastWatchErrorHandler exits the process when its informer fails to watch threshold times in a row.  Watches
+// that are closed normally, or whose resource version expired, are not failures.  The count is reset whenever the
+// informer has synced a newer resource version since the previous failure.
+type failFastWatchErrorHandler struct {
+	name      string
+	threshold int
+	exit      func(code int)
+
+	lock                sync.Mutex
+	failures            int
+	lastResourceVersion string
+}
+
+func (h *failFastWatchErrorHandler) handle(r *cache.Reflector, err error) {
+	cache.DefaultWatchErrorHandler(r, err)
+	if err == io.EOF || err == io.ErrUnexpectedEOF || errors.IsResourceExpired(err) || errors.IsGone(err) {
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if resourceVersion := r.LastSyncResourceVersion(); resourceVersion != h.lastResourceVersion {
+		h.lastResourceVersion = resourceVersion
+		h.failures = 0
+	}
+	h.failures++
+	if h.failures >= h.threshold {
+		klog.Errorf("%s: watch failed %d times in a row, exiting: %v", h.name, h.failures, err)
+		h.exit(1)
+	}
+}
@@ -0,0 +1,88 @@
+package release_payload_controller
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestNewWatchErrorHandler(t *testing.T) {
+	for _, strategy := range watchErrorStrategies {
+		if _, err := newWatchErrorHandler("Jobs", strategy, 1); err != nil {
+			t.Errorf("%s: unexpected err: %v", strategy, err)
+		}
+	}
+	if _, err := newWatchErrorHandler("Jobs", "retry-forever", 1); err == nil {
+		t.Errorf("expected an error for an unknown strategy")
+	}
+}
+
+func TestWatchErrorStrategies(t *testing.T) {
+	forbidden := errors.NewForbidden(schema.GroupResource{Group: "batch", Resource: "jobs"}, "", fmt.Errorf("RBAC: access denied"))
+	expired := errors.NewResourceExpired("too old resource version")
+
+	testCases := []struct {
+		name         string
+		strategy     string
+		errors       []error
+		expectedExit bool
+	}{
+		{
+			name:     "BackoffNeverExits",
+			strategy: WatchErrorStrategyBackoff,
+			errors:   []error{forbidden, forbidden, forbidden, forbidden},
+		},
+		{
+			name:     "LogOnlyNeverExits",
+			strategy: WatchErrorStrategyLogOnly,
+			errors:   []error{forbidden, forbidden, forbidden, forbidden},
+		},
+		{
+			name:     "FailFastBelowThreshold",
+			strategy: WatchErrorStrategyFailFast,
+			errors:   []error{forbidden, forbidden},
+		},
+		{
+			name:         "FailFastAtThreshold",
+			strategy:     WatchErrorStrategyFailFast,
+			errors:       []error{forbidden, forbidden, forbidden},
+			expectedExit: true,
+		},
+		{
+			name:     "FailFastIgnoresBenignErrors",
+			strategy: WatchErrorStrategyFailFast,
+			errors:   []error{forbidden, io.EOF, io.ErrUnexpectedEOF, expired, forbidden},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler, err := newWatchErrorHandler("Jobs", testCase.strategy, 3)
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+
+			var exitCode *int
+			if testCase.strategy == WatchErrorStrategyFailFast {
+				failFast := &failFastWatchErrorHandler{name: "Jobs", threshold: 3, exit: func(code int) { exitCode = &code }}
+				handler = failFast.handle
+			}
+
+			reflector := cache.NewReflector(&cache.ListWatch{}, &batchv1.Job{}, cache.NewStore(cache.MetaNamespaceKeyFunc), 0)
+			for _, err := range testCase.errors {
+				handler(reflector, err)
+			}
+
+			if exited := exitCode != nil; exited != testCase.expectedExit {
+				t.Fatalf("%s: Expected exit: %t, got: %t", testCase.name, testCase.expectedExit, exited)
+			}
+			if exitCode != nil && *exitCode != 1 {
+				t.Errorf("%s: Expected exit code 1, got %d", testCase.name, *exitCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package releasecontroller
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/russross/blackfriday"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// images returns the images, keyed by name, that are new, rebuilt or updated in the changelog
+func (a ChangeLog) images() map[string]ChangeLogImageInfo {
+	images := make(map[string]ChangeLogImageInfo)
+	for _, list := range [][]ChangeLogImageInfo{a.NewImages, a.RebuiltImages, a.UpdatedImages} {
+		for _, image := range list {
+			images[image.Name] = image
+		}
+	}
+	return images
+}
+
+// commitIDs returns the IDs of the commits of the image, in order
+func (i ChangeLogImageInfo) commitIDs() []string {
+	var ids []string
+	for _, commit := range i.Commits {
+		ids = append(ids, commit.CommitID)
+	}
+	return ids
+}
+
+// Diff returns the components that were added, removed or changed going from changelog a to changelog b.  A
+// component is one of the new, rebuilt or updated images of a changelog, and is considered changed when it appears
+// in both changelogs with a different list of commits.  Each slice of the ChangeLogDiff is sorted by component name.
+func (a ChangeLog) Diff(b ChangeLog) ChangeLogDiff {
+	var diff ChangeLogDiff
+	from, to := a.images(), b.images()
+	for name, image := range to {
+		previous, ok := from[name]
+		if !ok {
+			diff.Added = append(diff.Added, image)
+			continue
+		}
+		if !reflect.DeepEqual(previous.commitIDs(), image.commitIDs()) {
+			diff.Changed = append(diff.Changed, image)
+		}
+	}
+	for name, image := range from {
+		if _, ok := to[name]; !ok {
+			diff.Removed = append(diff.Removed, image)
+		}
+	}
+	for _, list := range [][]ChangeLogImageInfo{diff.Added, diff.Removed, diff.Changed} {
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].Name < list[j].Name
+		})
+	}
+	return diff
+}
+
+// markdown renders the changelog in the same layout as the markdown changelog that oc generates
+func (a ChangeLog) markdown() string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "## Changes from %s\n\n", a.From.Name)
+	if !a.To.Created.IsZero() {
+		fmt.Fprintf(&out, "Created: %s\n\n", a.To.Created.UTC().Format("2006-01-02 15:04:05 -0700 MST"))
+	}
+	if len(a.To.Digest) > 0 {
+		fmt.Fprintf(&out, "Image Digest: `%s`\n\n", a.To.Digest)
+	}
+	if len(a.To.PromotedFrom) > 0 {
+		fmt.Fprintf(&out, "Promoted from %s\n\n", a.To.PromotedFrom)
+	}
+
+	if len(a.Components) > 0 {
+		fmt.Fprintf(&out, "### Components\n\n")
+		for _, component := range a.Components {
+			switch {
+			case len(component.From) > 0 && len(component.DiffUrl) > 0:
+				fmt.Fprintf(&out, "* %s %s [upgraded from %s](%s)\n", component.Name, component.Version, component.From, component.DiffUrl)
+			case len(component.From) > 0:
+				fmt.Fprintf(&out, "* %s %s upgraded from %s\n", component.Name, component.Version, component.From)
+			default:
+				fmt.Fprintf(&out, "* %s %s\n", component.Name, component.Version)
+			}
+		}
+		fmt.Fprintln(&out)
+	}
+
+	imageList := func(title string, images []ChangeLogImageInfo) {
+		if len(images) == 0 {
+			return
+		}
+		fmt.Fprintf(&out, "### %s\n\n", title)
+		for _, image := range images {
+			if len(image.Path) > 0 && len(image.Commit) > 0 {
+				fmt.Fprintf(&out, "* [%s](%s) git [%s](%s/commit/%s)\n", image.Name, image.Path, image.ShortCommit, image.Path, image.Commit)
+			} else {
+				fmt.Fprintf(&out, "* %s\n", image.Name)
+			}
+		}
+		fmt.Fprintln(&out)
+	}
+	imageList("New images", a.NewImages)
+	imageList("Removed images", a.RemovedImages)
+	imageList("Rebuilt images without code change", a.RebuiltImages)
+
+	for _, image := range a.UpdatedImages {
+		if len(image.Path) > 0 {
+			fmt.Fprintf(&out, "### [%s](%s)\n\n", image.Name, image.Path)
+		} else {
+			fmt.Fprintf(&out, "### %s\n\n", image.Name)
+		}
+		for _, commit := range image.Commits {
+			if len(commit.PullURL) > 0 {
+				fmt.Fprintf(&out, "* %s [#%d](%s)\n", commit.Subject, commit.PullID, commit.PullURL)
+			} else {
+				fmt.Fprintf(&out, "* %s\n", commit.Subject)
+			}
+		}
+		if len(image.FullChangeLog) > 0 {
+			fmt.Fprintf(&out, "* [Full changelog](%s)\n", image.FullChangeLog)
+		}
+		fmt.Fprintln(&out)
+	}
+	return out.String()
+}
+
+// prefixedElements are the elements, of the rendered changelog, whose classes are prefixed by RenderHTML
+var prefixedElements = map[atom.Atom]bool{
+	atom.Pre:   true,
+	atom.Code:  true,
+	atom.Ul:    true,
+	atom.Table: true,
+}
+
+// prefixClasses adds the ${cssPrefix}-<element> class to every pre, code, ul and table element of the tree rooted at
+// node, and prefixes any class the element already has, so that the changelog can be styled without conflicting with
+// the stylesheet of the page that contains it
+func prefixClasses(node *html.Node, cssPrefix string) {
+	if node.Type == html.ElementNode && prefixedElements[node.DataAtom] {
+		classes := []string{fmt.Sprintf("%s-%s", cssPrefix, node.Data)}
+		index := -1
+		for i, attr := range node.Attr {
+			if attr.Key != "class" {
+				continue
+			}
+			index = i
+			for _, class := range strings.Fields(attr.Val) {
+				classes = append(classes, fmt.Sprintf("%s-%s", cssPrefix, class))
+			}
+		}
+		class := html.Attribute{Key: "class", Val: strings.Join(classes, " ")}
+		if index >= 0 {
+			node.Attr[index] = class
+		} else {
+			node.Attr = append(node.Attr, class)
+		}
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		prefixClasses(child, cssPrefix)
+	}
+}
+
+// RenderHTML renders the changelog as HTML.  Every pre, code, ul and table element is given a class, prefixed with
+// cssPrefix, so that the changelog can be embedded in pages whose stylesheet would otherwise apply to it.
+func (a ChangeLog) RenderHTML(cssPrefix string) ([]byte, error) {
+	rendered := blackfriday.Run([]byte(a.markdown()))
+	if len(cssPrefix) == 0 {
+		return rendered, nil
+	}
+
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(bytes.NewReader(rendered), body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse rendered changelog: %w", err)
+	}
+	var out bytes.Buffer
+	for _, node := range nodes {
+		prefixClasses(node, cssPrefix)
+		if err := html.Render(&out, node); err != nil {
+			return nil, fmt.Errorf("unable to render changelog: %w", err)
+		}
+	}
+	return out.Bytes(), nil
+}
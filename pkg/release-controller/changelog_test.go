@@ -0,0 +1,181 @@
+package releasecontroller
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func TestChangeLogDiff(t *testing.T) {
+	image := func(name string, commits ...string) ChangeLogImageInfo {
+		info := ChangeLogImageInfo{Name: name, Path: "github.com/openshift/" + name}
+		for _, commit := range commits {
+			info.Commits = append(info.Commits, CommitInfo{CommitID: commit, Subject: "Commit " + commit})
+		}
+		return info
+	}
+
+	testCases := []struct {
+		name     string
+		a        ChangeLog
+		b        ChangeLog
+		expected ChangeLogDiff
+	}{
+		{
+			name: "EmptyChangeLogs",
+		},
+		{
+			name: "IdenticalChangeLogs",
+			a:    ChangeLog{UpdatedImages: []ChangeLogImageInfo{image("cli", "a1", "a2")}},
+			b:    ChangeLog{UpdatedImages: []ChangeLogImageInfo{image("cli", "a1", "a2")}},
+		},
+		{
+			name: "ComponentAdded",
+			a:    ChangeLog{UpdatedImages: []ChangeLogImageInfo{image("cli", "a1")}},
+			b:    ChangeLog{UpdatedImages: []ChangeLogImageInfo{image("cli", "a1")}, NewImages: []ChangeLogImageInfo{image("installer", "b1")}},
+			expected: ChangeLogDiff{
+				Added: []ChangeLogImageInfo{image("installer", "b1")},
+			},
+		},
+		{
+			name: "ComponentRemoved",
+			a:    ChangeLog{UpdatedImages: []ChangeLogImageInfo{image("cli", "a1"), image("installer", "b1")}},
+			b:    ChangeLog{UpdatedImages: []ChangeLogImageInfo{image("cli", "a1")}},
+			expected: ChangeLogDiff{
+				Removed: []ChangeLogImageInfo{image("installer", "b1")},
+			},
+		},
+		{
+			name: "ComponentCommitsChanged",
+			a:    ChangeLog{UpdatedImages: []ChangeLogImageInfo{image("cli", "a1")}},
+			b:    ChangeLog{UpdatedImages: []ChangeLogImageInfo{image("cli", "a1", "a2")}},
+			expected: ChangeLogDiff{
+				Changed: []ChangeLogImageInfo{image("cli", "a1", "a2")},
+			},
+		},
+		{
+			name: "ComponentCommitsReordered",
+			a:    ChangeLog{UpdatedImages: []ChangeLogImageInfo{image("cli", "a1", "a2")}},
+			b:    ChangeLog{UpdatedImages: []ChangeLogImageInfo{image("cli", "a2", "a1")}},
+			expected: ChangeLogDiff{
+				Changed: []ChangeLogImageInfo{image("cli", "a2", "a1")},
+			},
+		},
+		{
+			name: "ComponentMovedBetweenImageLists",
+			a:    ChangeLog{NewImages: []ChangeLogImageInfo{image("cli", "a1")}},
+			b:    ChangeLog{UpdatedImages: []ChangeLogImageInfo{image("cli", "a1")}},
+		},
+		{
+			name: "RemovedImagesAreNotComponents",
+			a:    ChangeLog{RemovedImages: []ChangeLogImageInfo{image("cli")}},
+			b:    ChangeLog{},
+		},
+		{
+			name: "MultipleChangesSortedByName",
+			a: ChangeLog{
+				UpdatedImages: []ChangeLogImageInfo{image("machine-config-operator", "c1"), image("cli", "a1"), image("etcd", "d1")},
+				RebuiltImages: []ChangeLogImageInfo{image("console")},
+			},
+			b: ChangeLog{
+				NewImages:     []ChangeLogImageInfo{image("ovn-kubernetes", "e1"), image("installer", "b1")},
+				UpdatedImages: []ChangeLogImageInfo{image("machine-config-operator", "c2"), image("cli", "a1", "a2")},
+			},
+			expected: ChangeLogDiff{
+				Added:   []ChangeLogImageInfo{image("installer", "b1"), image("ovn-kubernetes", "e1")},
+				Removed: []ChangeLogImageInfo{image("console"), image("etcd", "d1")},
+				Changed: []ChangeLogImageInfo{image("cli", "a1", "a2"), image("machine-config-operator", "c2")},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := tc.a.Diff(tc.b); !cmp.Equal(diff, tc.expected) {
+				t.Errorf("%s: %s", tc.name, cmp.Diff(tc.expected, diff))
+			}
+		})
+	}
+}
+
+func TestChangeLogRenderHTML(t *testing.T) {
+	changeLog := ChangeLog{
+		From: ChangeLogReleaseInfo{Name: "4.11.0-0.nightly-2022-02-08-091559"},
+		To: ChangeLogReleaseInfo{
+			Name:    "4.11.0-0.nightly-2022-02-09-091559",
+			Created: time.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC),
+			Digest:  "sha256:5e1b5d3a2b1c0f4e8d6a7b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e",
+		},
+		Components: []ChangeLogComponentInfo{
+			{Name: "Kubernetes", Version: "1.24.0", From: "1.23.3"},
+		},
+		UpdatedImages: []ChangeLogImageInfo{
+			{
+				Name: "cli",
+				Path: "https://github.com/openshift/oc",
+				Commits: []CommitInfo{
+					{Subject: "Fix the thing", PullID: 1, PullURL: "https://github.com/openshift/oc/pull/1"},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		cssPrefix string
+		expected  []string
+	}{
+		{
+			name:      "Prefixed",
+			cssPrefix: "rc",
+			expected: []string{
+				`<ul class="rc-ul">`,
+				`<code class="rc-code">sha256:5e1b5d3a2b1c0f4e8d6a7b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e</code>`,
+				`<a href="https://github.com/openshift/oc/pull/1">#1</a>`,
+			},
+		},
+		{
+			name: "NoPrefix",
+			expected: []string{
+				"<ul>",
+				"<code>sha256:5e1b5d3a2b1c0f4e8d6a7b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e</code>",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := changeLog.RenderHTML(tc.cssPrefix)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tc.name, err)
+			}
+			for _, expected := range tc.expected {
+				if !strings.Contains(string(out), expected) {
+					t.Errorf("%s: Expected %q in:\n%s", tc.name, expected, out)
+				}
+			}
+		})
+	}
+}
+
+func TestPrefixClasses(t *testing.T) {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(`<div class="keep"><pre><code class="language-go">x</code></pre><table><tr><td>1</td></tr></table><ul><li>a</li></ul><ol><li>b</li></ol></div>`), body)
+	if err != nil {
+		t.Fatalf("unable to parse fragment: %v", err)
+	}
+	var out bytes.Buffer
+	for _, node := range nodes {
+		prefixClasses(node, "rc")
+		if err := html.Render(&out, node); err != nil {
+			t.Fatalf("unable to render fragment: %v", err)
+		}
+	}
+	expected := `<div class="keep"><pre class="rc-pre"><code class="rc-code rc-language-go">x</code></pre><table class="rc-table"><tbody><tr><td>1</td></tr></tbody></table><ul class="rc-ul"><li>a</li></ul><ol><li>b</li></ol></div>`
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
@@ -54,13 +54,14 @@ type dockerImageConfig struct {
 	Os           string `json:"os"`
 }
 
-type imageInfoConfig struct {
+// ImageInfoConfig is the image info, of the release image of an architecture, returned by GetImageInfo
+type ImageInfoConfig struct {
 	Config *dockerImageConfig `json:"config"`
 	Digest string             `json:"digest"`
 	Name   string             `json:"name"`
 }
 
-func (c imageInfoConfig) GenerateDigestPullSpec() string {
+func (c ImageInfoConfig) GenerateDigestPullSpec() string {
 	if strings.Contains(c.Name, "@sha256:") {
 		return fmt.Sprintf("%s@%s", strings.Split(c.Name, "@sha256:")[0], c.Digest)
 	}
@@ -521,19 +522,42 @@ func LatestForStream(rcCache *lru.Cache, eventRecorder record.EventRecorder, lis
 	return nil, nil, ErrStreamNotFound
 }
 
-func GetImageInfo(releaseInfo ReleaseInfo, architecture, pullSpec string) (*imageInfoConfig, error) {
+func GetImageInfo(releaseInfo ReleaseInfo, architecture, pullSpec string) (*ImageInfoConfig, error) {
 	// Get the ImageInfo
 	imageInfo, err := releaseInfo.ImageInfo(pullSpec, architecture)
 	if err != nil {
 		return nil, fmt.Errorf("could not get image info for from pullSpec %s: %v", pullSpec, err)
 	}
-	config := imageInfoConfig{}
+	config := ImageInfoConfig{}
 	if err := json.Unmarshal([]byte(imageInfo), &config); err != nil {
 		return nil, fmt.Errorf("could not unmarshal image info for from pullSpec %s: %v", pullSpec, err)
 	}
+	if err := validateImageInfo(&config); err != nil {
+		return nil, fmt.Errorf("invalid image info for pullSpec %s: %v", pullSpec, err)
+	}
 	return &config, nil
 }
 
+// validateImageInfo returns an error if the image info is missing any of the fields needed to identify the image,
+// i.e. the fields that GenerateDigestPullSpec builds the pull spec, of the image, from
+func validateImageInfo(config *ImageInfoConfig) error {
+	var missing []string
+	if config.Config == nil || len(config.Config.Architecture) == 0 {
+		missing = append(missing, "config.architecture")
+	}
+	if len(config.Digest) == 0 {
+		missing = append(missing, "digest")
+	}
+	// The repository, of the digest pull spec, is taken from the name
+	if len(strings.TrimSuffix(config.GenerateDigestPullSpec(), "@"+config.Digest)) == 0 {
+		missing = append(missing, "name")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required fields: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 func GetVerificationJobs(rcCache *lru.Cache, eventRecorder record.EventRecorder, lister *MultiImageStreamLister, release *Release, releaseTag *imagev1.TagReference, artSuffix string) (map[string]ReleaseVerification, error) {
 	if release.Config.As != ReleaseConfigModeStable || artSuffix == "" {
 		return release.Config.Verify, nil
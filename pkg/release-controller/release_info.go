@@ -220,6 +220,16 @@ func (r *ExecReleaseInfo) ReleaseInfo(image string) (string, error) {
 	return out.String(), nil
 }
 
+// changeLogCommand returns the command that generates the changelog between the from and to release images, using
+// gitDir to cache the cloned repositories
+func changeLogCommand(gitDir, from, to string, isJson bool) []string {
+	cmd := []string{"oc", "adm", "release", "info", fmt.Sprintf("--changelog=%s", gitDir), from, to}
+	if isJson {
+		cmd = append(cmd, "--output=json")
+	}
+	return cmd
+}
+
 func (r *ExecReleaseInfo) ChangeLog(from, to string, isJson bool) (string, error) {
 	if _, err := imagereference.Parse(from); err != nil {
 		return "", fmt.Errorf("%s is not an image reference: %v", from, err)
@@ -231,10 +241,7 @@ func (r *ExecReleaseInfo) ChangeLog(from, to string, isJson bool) (string, error
 		return "", fmt.Errorf("not a valid reference")
 	}
 
-	cmd := []string{"oc", "adm", "release", "info", "--changelog=/tmp/git/", from, to}
-	if isJson {
-		cmd = append(cmd, "--output=json")
-	}
+	cmd := changeLogCommand("/tmp/git/", from, to, isJson)
 	klog.V(4).Infof("Running changelog command: %s", strings.Join(cmd, " "))
 	u := r.client.CoreV1().RESTClient().Post().Resource("pods").Namespace(r.namespace).Name("git-cache-0").SubResource("exec").VersionedParams(&corev1.PodExecOptions{
 		Container: "git",
@@ -0,0 +1,80 @@
+package releasecontroller
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestChangeLogIntegration generates a changelog, with the real oc binary, exactly like the git-cache pod does.
+// oc determines the repositories and commits to compare from the source labels of the release images, so the
+// test needs a pair of pullable release images:
+//
+//	RELEASE_CONTROLLER_CHANGELOG_FROM=quay.io/openshift-release-dev/ocp-release:4.14.0-x86_64 \
+//	RELEASE_CONTROLLER_CHANGELOG_TO=quay.io/openshift-release-dev/ocp-release:4.14.1-x86_64 \
+//	RELEASE_CONTROLLER_CHANGELOG_EXPECT="commit message one,commit message two" \
+//	go test ./pkg/release-controller/ -run TestChangeLogIntegration
+//
+// The test is skipped when oc, git or the release images are not available.
+func TestChangeLogIntegration(t *testing.T) {
+	for _, binary := range []string{"oc", "git"} {
+		if _, err := exec.LookPath(binary); err != nil {
+			t.Skipf("%s binary not available: %v", binary, err)
+		}
+	}
+	from, to := os.Getenv("RELEASE_CONTROLLER_CHANGELOG_FROM"), os.Getenv("RELEASE_CONTROLLER_CHANGELOG_TO")
+	if len(from) == 0 || len(to) == 0 {
+		t.Skip("RELEASE_CONTROLLER_CHANGELOG_FROM and RELEASE_CONTROLLER_CHANGELOG_TO must be set")
+	}
+	var expected []string
+	if value := os.Getenv("RELEASE_CONTROLLER_CHANGELOG_EXPECT"); len(value) > 0 {
+		expected = strings.Split(value, ",")
+	}
+
+	gitDir := t.TempDir()
+
+	testCases := []struct {
+		name   string
+		isJson bool
+	}{
+		{
+			name: "Markdown",
+		},
+		{
+			name:   "JSON",
+			isJson: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			args := changeLogCommand(gitDir, from, to, testCase.isJson)
+			cmd := exec.Command(args[0], args[1:]...)
+			out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+			cmd.Stdout, cmd.Stderr = out, errOut
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("%s failed: %v\n%s", strings.Join(args, " "), err, errOut.String())
+			}
+
+			if testCase.isJson {
+				var changeLog ChangeLog
+				if err := json.Unmarshal(out.Bytes(), &changeLog); err != nil {
+					t.Fatalf("invalid changelog JSON: %v", err)
+				}
+				if len(changeLog.Components) == 0 && len(changeLog.UpdatedImages) == 0 {
+					t.Errorf("expected changelog to contain components or updated images")
+				}
+			} else if !strings.Contains(out.String(), "## Changes from") {
+				t.Errorf("expected changelog to contain a changes section, got:\n%s", out.String())
+			}
+
+			for _, message := range expected {
+				if !strings.Contains(out.String(), message) {
+					t.Errorf("expected changelog to contain %q", message)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package releasecontroller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type fakeImageInfoReleaseInfo struct {
+	imageInfo string
+}
+
+func (r *fakeImageInfoReleaseInfo) Bugs(from, to string) ([]BugDetails, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (r *fakeImageInfoReleaseInfo) ChangeLog(from, to string, json bool) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (r *fakeImageInfoReleaseInfo) ReleaseInfo(image string) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (r *fakeImageInfoReleaseInfo) UpgradeInfo(image string) (ReleaseUpgradeInfo, error) {
+	return ReleaseUpgradeInfo{}, fmt.Errorf("not implemented")
+}
+
+func (r *fakeImageInfoReleaseInfo) ImageInfo(image, architecture string) (string, error) {
+	return r.imageInfo, nil
+}
+
+func (r *fakeImageInfoReleaseInfo) IssuesInfo(changelog string) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (r *fakeImageInfoReleaseInfo) GetFeatureChildren(featuresList []string, validityPeriod time.Duration) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func TestGetImageInfo(t *testing.T) {
+	const pullSpec = "registry.ci.openshift.org/ocp/release:4.11.0-0.nightly-2022-02-09-091559"
+	testCases := []struct {
+		name             string
+		imageInfo        string
+		expectedPullSpec string
+		expectedErr      bool
+	}{
+		{
+			name:             "Valid",
+			imageInfo:        `{"name":"registry.ci.openshift.org/ocp/release:4.11.0-0.nightly-2022-02-09-091559","digest":"sha256:0123456789abcdef","config":{"architecture":"amd64"}}`,
+			expectedPullSpec: "registry.ci.openshift.org/ocp/release@sha256:0123456789abcdef",
+		},
+		{
+			name:        "MissingConfig",
+			imageInfo:   `{"name":"registry.ci.openshift.org/ocp/release:4.11.0-0.nightly-2022-02-09-091559","digest":"sha256:0123456789abcdef"}`,
+			expectedErr: true,
+		},
+		{
+			name:        "MissingArchitecture",
+			imageInfo:   `{"name":"registry.ci.openshift.org/ocp/release:4.11.0-0.nightly-2022-02-09-091559","digest":"sha256:0123456789abcdef","config":{"os":"linux"}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "MissingDigest",
+			imageInfo:   `{"name":"registry.ci.openshift.org/ocp/release:4.11.0-0.nightly-2022-02-09-091559","config":{"architecture":"amd64"}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "MissingName",
+			imageInfo:   `{"digest":"sha256:0123456789abcdef","config":{"architecture":"amd64"}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "MissingRepository",
+			imageInfo:   `{"name":":4.11.0-0.nightly-2022-02-09-091559","digest":"sha256:0123456789abcdef","config":{"architecture":"amd64"}}`,
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			imageInfo, err := GetImageInfo(&fakeImageInfoReleaseInfo{imageInfo: testCase.imageInfo}, "amd64", pullSpec)
+			if (err != nil) != testCase.expectedErr {
+				t.Fatalf("%s: expected error: %t, got: %v", testCase.name, testCase.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if digestPullSpec := imageInfo.GenerateDigestPullSpec(); digestPullSpec != testCase.expectedPullSpec {
+				t.Errorf("%s: expected pull spec %q, got %q", testCase.name, testCase.expectedPullSpec, digestPullSpec)
+			}
+		})
+	}
+}
@@ -34,6 +34,22 @@ type APIRelease struct {
 	Tags []APITag `json:"tags"`
 }
 
+// APIStreamStats contains the number of ReleasePayloads, of a release stream, in each phase.
+type APIStreamStats struct {
+	// Stream is the name of the release stream.
+	Stream string `json:"stream"`
+	// Accepted is the number of accepted releases.
+	Accepted int `json:"accepted"`
+	// Rejected is the number of rejected releases.
+	Rejected int `json:"rejected"`
+	// Failed is the number of releases whose payload could not be created.
+	Failed int `json:"failed"`
+	// Pending is the number of releases that are still being verified.
+	Pending int `json:"pending"`
+	// AcceptanceRate7d is the fraction, of the releases created in the last 7 days that completed, that were accepted.
+	AcceptanceRate7d float64 `json:"acceptanceRate7d"`
+}
+
 // APIReleaseInfo encapsulates the release verification results and upgrade history for a release tag.
 type APIReleaseInfo struct {
 	// Name is the name of the release tag.
@@ -691,6 +707,16 @@ type ChangeLog struct {
 	UpdatedImages []ChangeLogImageInfo     `json:"updatedImages,omitempty"`
 }
 
+// ChangeLogDiff represents the component (image) level changes between two changelogs
+type ChangeLogDiff struct {
+	// Added are the components that only appear in the newer changelog
+	Added []ChangeLogImageInfo `json:"added,omitempty"`
+	// Removed are the components that only appear in the older changelog
+	Removed []ChangeLogImageInfo `json:"removed,omitempty"`
+	// Changed are the components, from the newer changelog, whose commits differ between the changelogs
+	Changed []ChangeLogImageInfo `json:"changed,omitempty"`
+}
+
 type ChangeLogReleaseInfo struct {
 	Name         string        `json:"name"`
 	Created      time.Time     `json:"created"`
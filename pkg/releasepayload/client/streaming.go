@@ -0,0 +1,106 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadscheme "github.com/openshift/release-controller/pkg/client/clientset/versioned/scheme"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+)
+
+// StreamingReleasePayloadClient watches ReleasePayloads by long-polling the watch endpoint of the API server and
+// decoding the stream of JSON encoded events, as they arrive, in the chunked response.
+type StreamingReleasePayloadClient struct {
+	host   string
+	client *http.Client
+}
+
+// NewStreamingReleasePayloadClient returns a StreamingReleasePayloadClient that talks to the API server described
+// by config.
+func NewStreamingReleasePayloadClient(config *rest.Config) (*StreamingReleasePayloadClient, error) {
+	client, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create http client: %w", err)
+	}
+	return &StreamingReleasePayloadClient{
+		host:   strings.TrimSuffix(config.Host, "/"),
+		client: client,
+	}, nil
+}
+
+// WatchReleasePayloads watches the ReleasePayloads in the specified namespace, or in all namespaces if namespace
+// is empty, until the returned watch.Interface is stopped or the server ends the response.
+func (c *StreamingReleasePayloadClient) WatchReleasePayloads(namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	query, err := releasepayloadscheme.ParameterCodec.EncodeParameters(&opts, v1alpha1.SchemeGroupVersion)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode list options: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s?%s", c.host, watchPath(namespace), query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", runtime.ContentTypeJSON)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, errors.NewGenericServerResponse(resp.StatusCode, http.MethodGet, v1alpha1.Resource("releasepayloads"), "", strings.TrimSpace(string(body)), 0, true)
+	}
+
+	return watch.NewStreamWatcher(
+		&eventDecoder{body: resp.Body, decoder: json.NewDecoder(resp.Body)},
+		errors.NewClientErrorReporter(http.StatusInternalServerError, http.MethodGet, "ClientWatchDecoding"),
+	), nil
+}
+
+func watchPath(namespace string) string {
+	if len(namespace) == 0 {
+		return "/apis/release.openshift.io/v1alpha1/watch/releasepayloads"
+	}
+	return fmt.Sprintf("/apis/release.openshift.io/v1alpha1/watch/namespaces/%s/releasepayloads", namespace)
+}
+
+// eventDecoder decodes the metav1.WatchEvents, sent by the API server, into watch.Events
+type eventDecoder struct {
+	body    io.ReadCloser
+	decoder *json.Decoder
+}
+
+func (d *eventDecoder) Decode() (watch.EventType, runtime.Object, error) {
+	var event metav1.WatchEvent
+	if err := d.decoder.Decode(&event); err != nil {
+		return "", nil, err
+	}
+
+	var object runtime.Object
+	switch eventType := watch.EventType(event.Type); eventType {
+	case watch.Added, watch.Modified, watch.Deleted, watch.Bookmark:
+		object = &v1alpha1.ReleasePayload{}
+	case watch.Error:
+		object = &metav1.Status{}
+	default:
+		return "", nil, fmt.Errorf("got invalid watch event type: %v", eventType)
+	}
+	if err := json.Unmarshal(event.Object.Raw, object); err != nil {
+		return "", nil, fmt.Errorf("unable to decode %s event: %w", event.Type, err)
+	}
+	return watch.EventType(event.Type), object, nil
+}
+
+func (d *eventDecoder) Close() {
+	d.body.Close()
+}
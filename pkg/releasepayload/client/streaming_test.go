@@ -0,0 +1,215 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+)
+
+func newReleasePayload(name string) *v1alpha1.ReleasePayload {
+	return &v1alpha1.ReleasePayload{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "ReleasePayload",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ocp",
+		},
+	}
+}
+
+func writeEvent(t *testing.T, w http.ResponseWriter, eventType watch.EventType, object runtime.Object) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		t.Errorf("unable to encode object: %v", err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(&metav1.WatchEvent{Type: string(eventType), Object: runtime.RawExtension{Raw: data}}); err != nil {
+		t.Errorf("unable to encode event: %v", err)
+		return
+	}
+	w.(http.Flusher).Flush()
+}
+
+func TestWatchReleasePayloads(t *testing.T) {
+	testCases := []struct {
+		name          string
+		namespace     string
+		expectedPath  string
+		serverEvents  []watch.Event
+		expectedNames []string
+		expectedTypes []watch.EventType
+	}{
+		{
+			name:         "Namespaced",
+			namespace:    "ocp",
+			expectedPath: "/apis/release.openshift.io/v1alpha1/watch/namespaces/ocp/releasepayloads",
+			serverEvents: []watch.Event{
+				{Type: watch.Added, Object: newReleasePayload("4.11.0-0.nightly-2022-02-09-091559")},
+				{Type: watch.Modified, Object: newReleasePayload("4.11.0-0.nightly-2022-02-09-091559")},
+				{Type: watch.Deleted, Object: newReleasePayload("4.11.0-0.nightly-2022-02-09-091559")},
+			},
+			expectedNames: []string{"4.11.0-0.nightly-2022-02-09-091559", "4.11.0-0.nightly-2022-02-09-091559", "4.11.0-0.nightly-2022-02-09-091559"},
+			expectedTypes: []watch.EventType{watch.Added, watch.Modified, watch.Deleted},
+		},
+		{
+			name:         "AllNamespaces",
+			expectedPath: "/apis/release.openshift.io/v1alpha1/watch/releasepayloads",
+			serverEvents: []watch.Event{
+				{Type: watch.Added, Object: newReleasePayload("4.11.0-0.nightly-2022-02-09-091559")},
+				{Type: watch.Added, Object: newReleasePayload("4.11.0-0.nightly-2022-02-09-101559")},
+			},
+			expectedNames: []string{"4.11.0-0.nightly-2022-02-09-091559", "4.11.0-0.nightly-2022-02-09-101559"},
+			expectedTypes: []watch.EventType{watch.Added, watch.Added},
+		},
+		{
+			name:         "Error",
+			namespace:    "ocp",
+			expectedPath: "/apis/release.openshift.io/v1alpha1/watch/namespaces/ocp/releasepayloads",
+			serverEvents: []watch.Event{
+				{Type: watch.Error, Object: &errors.NewResourceExpired("too old resource version").ErrStatus},
+			},
+			expectedNames: []string{""},
+			expectedTypes: []watch.EventType{watch.Error},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != testCase.expectedPath {
+					t.Errorf("%s: Expected path %q, got %q", testCase.name, testCase.expectedPath, req.URL.Path)
+				}
+				if watch := req.URL.Query().Get("watch"); watch != "true" {
+					t.Errorf("%s: Expected watch=true, got %q", testCase.name, watch)
+				}
+				if selector := req.URL.Query().Get("labelSelector"); selector != "release.openshift.io/stream=4.11.0-0.nightly" {
+					t.Errorf("%s: unexpected labelSelector: %q", testCase.name, selector)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				// Send the events in separate chunks, like the API server does
+				for _, event := range testCase.serverEvents {
+					writeEvent(t, w, event.Type, event.Object)
+				}
+			}))
+			defer server.Close()
+
+			c, err := NewStreamingReleasePayloadClient(&rest.Config{Host: server.URL})
+			if err != nil {
+				t.Fatalf("%s: unable to create client: %v", testCase.name, err)
+			}
+			w, err := c.WatchReleasePayloads(testCase.namespace, metav1.ListOptions{LabelSelector: "release.openshift.io/stream=4.11.0-0.nightly"})
+			if err != nil {
+				t.Fatalf("%s: unexpected err: %v", testCase.name, err)
+			}
+			defer w.Stop()
+
+			var names []string
+			var types []watch.EventType
+			timeout := time.After(5 * time.Second)
+		loop:
+			for {
+				select {
+				case event, ok := <-w.ResultChan():
+					if !ok {
+						break loop
+					}
+					types = append(types, event.Type)
+					switch object := event.Object.(type) {
+					case *v1alpha1.ReleasePayload:
+						names = append(names, object.Name)
+					case *metav1.Status:
+						if object.Reason != metav1.StatusReasonExpired {
+							t.Errorf("%s: Expected reason %q, got %q", testCase.name, metav1.StatusReasonExpired, object.Reason)
+						}
+						names = append(names, "")
+					default:
+						t.Errorf("%s: unexpected object: %T", testCase.name, event.Object)
+					}
+				case <-timeout:
+					t.Fatalf("%s: timed out waiting for the watch to end", testCase.name)
+				}
+			}
+
+			if !cmp.Equal(types, testCase.expectedTypes) {
+				t.Errorf("%s: Expected %v, got %v", testCase.name, testCase.expectedTypes, types)
+			}
+			if !cmp.Equal(names, testCase.expectedNames) {
+				t.Errorf("%s: Expected %v, got %v", testCase.name, testCase.expectedNames, names)
+			}
+		})
+	}
+}
+
+func TestWatchReleasePayloadsStop(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		writeEvent(t, w, watch.Added, newReleasePayload("4.11.0-0.nightly-2022-02-09-091559"))
+		// Long-poll until the client goes away
+		select {
+		case <-req.Context().Done():
+		case <-done:
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	c, err := NewStreamingReleasePayloadClient(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	w, err := c.WatchReleasePayloads("ocp", metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	select {
+	case event := <-w.ResultChan():
+		if event.Type != watch.Added {
+			t.Errorf("Expected %s event, got %s", watch.Added, event.Type)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for an event")
+	}
+
+	w.Stop()
+
+	select {
+	case _, ok := <-w.ResultChan():
+		if ok {
+			t.Errorf("Expected the result channel to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for the watch to stop")
+	}
+}
+
+func TestWatchReleasePayloadsErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, "releasepayloads.release.openshift.io is forbidden")
+	}))
+	defer server.Close()
+
+	c, err := NewStreamingReleasePayloadClient(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	if _, err := c.WatchReleasePayloads("ocp", metav1.ListOptions{}); !errors.IsForbidden(err) {
+		t.Errorf("Expected a forbidden error, got: %v", err)
+	}
+}
@@ -1,13 +1,21 @@
 package v1alpha1helpers
 
 import (
+	"fmt"
 	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
 	"github.com/openshift/release-controller/pkg/releasepayload/conditions"
 	"github.com/openshift/release-controller/pkg/releasepayload/jobrunresult"
 	"github.com/openshift/release-controller/pkg/releasepayload/jobstatus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"regexp"
 	"sort"
+	"strings"
 )
 
+// reReleaseTimestamp matches the timestamp that is appended to the name of the releases of a stream
+// (i.e. 4.11.0-0.nightly-2022-02-09-091559)
+var reReleaseTimestamp = regexp.MustCompile(`-\d{4}-\d{2}-\d{2}-\d{6}$`)
+
 func CanonicalizeReleasePayloadStatus(in *v1alpha1.ReleasePayload) {
 	sort.Sort(jobstatus.ByJobStatusCIConfigurationName(in.Status.BlockingJobResults))
 	CanonicalizeJobRunResults(in.Status.BlockingJobResults)
@@ -23,3 +31,39 @@ func CanonicalizeJobRunResults(jobs []v1alpha1.JobStatus) {
 		sort.Sort(jobrunresult.ByCoordinatesName(results.JobRunResults))
 	}
 }
+
+// ReleasePayloadStream returns the name of the release stream that the ReleasePayload belongs to.  Releases without a
+// timestamp (i.e. 4.11.1) are grouped by the imagestream that they were created in.
+func ReleasePayloadStream(releasePayload *v1alpha1.ReleasePayload) string {
+	if loc := reReleaseTimestamp.FindStringIndex(releasePayload.Name); loc != nil {
+		return releasePayload.Name[:loc[0]]
+	}
+	return releasePayload.Spec.PayloadCoordinates.ImagestreamName
+}
+
+// ReleaseCreationJobNameForArchitecture returns the name of the release creation job, of the architecture, of a
+// ReleasePayload that lists its Architectures (i.e. 4.11.0-0.nightly-2022-02-09-091559-arm64)
+func ReleaseCreationJobNameForArchitecture(releaseCreationJobName, architecture string) string {
+	return fmt.Sprintf("%s-%s", releaseCreationJobName, architecture)
+}
+
+// ReleaseCreationJobBaseMessage returns the message of a ReleaseCreationJobResult without the logs, of a failed
+// release creation job, that follow it on the next lines
+func ReleaseCreationJobBaseMessage(message string) string {
+	base, _, _ := strings.Cut(message, "\n")
+	return base
+}
+
+// AppendStatusHistory records the current Status and base Message, of the ReleaseCreationJobResult, at the end of the
+// StatusHistory of the ReleasePayload.  The oldest snapshots are dropped to keep, at most, StatusHistoryLimit of them.
+func AppendStatusHistory(releasePayload *v1alpha1.ReleasePayload, transitionTime metav1.Time) {
+	history := append(releasePayload.Status.StatusHistory, v1alpha1.ReleasePayloadStatusSnapshot{
+		Status:         releasePayload.Status.ReleaseCreationJobResult.Status,
+		Message:        ReleaseCreationJobBaseMessage(releasePayload.Status.ReleaseCreationJobResult.Message),
+		TransitionTime: transitionTime,
+	})
+	if overflow := len(history) - v1alpha1.StatusHistoryLimit; overflow > 0 {
+		history = append([]v1alpha1.ReleasePayloadStatusSnapshot(nil), history[overflow:]...)
+	}
+	releasePayload.Status.StatusHistory = history
+}
@@ -1,10 +1,12 @@
 package v1alpha1helpers
 
 import (
+	"fmt"
 	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestCanonicalize(t *testing.T) {
@@ -202,3 +204,136 @@ func TestCanonicalize(t *testing.T) {
 		})
 	}
 }
+
+func TestReleasePayloadStream(t *testing.T) {
+	testCases := []struct {
+		name            string
+		releaseName     string
+		imagestreamName string
+		expected        string
+	}{
+		{
+			name:            "NightlyRelease",
+			releaseName:     "4.11.0-0.nightly-2022-02-09-091559",
+			imagestreamName: "release",
+			expected:        "4.11.0-0.nightly",
+		},
+		{
+			name:            "ArchitectureRelease",
+			releaseName:     "4.11.0-0.nightly-arm64-2022-02-09-091559",
+			imagestreamName: "release-arm64",
+			expected:        "4.11.0-0.nightly-arm64",
+		},
+		{
+			name:            "StableRelease",
+			releaseName:     "4.11.1",
+			imagestreamName: "4-stable",
+			expected:        "4-stable",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			releasePayload := &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{Name: testCase.releaseName, Namespace: "ocp"},
+				Spec: v1alpha1.ReleasePayloadSpec{
+					PayloadCoordinates: v1alpha1.PayloadCoordinates{ImagestreamName: testCase.imagestreamName},
+				},
+			}
+			if stream := ReleasePayloadStream(releasePayload); stream != testCase.expected {
+				t.Errorf("%s: Expected %q, got %q", testCase.name, testCase.expected, stream)
+			}
+		})
+	}
+}
+
+func TestAppendStatusHistory(t *testing.T) {
+	start := metav1.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC)
+	snapshot := func(i int) v1alpha1.ReleasePayloadStatusSnapshot {
+		return v1alpha1.ReleasePayloadStatusSnapshot{
+			Status:         v1alpha1.ReleaseCreationJobPending,
+			Message:        fmt.Sprintf("transition %d", i),
+			TransitionTime: metav1.NewTime(start.Add(time.Duration(i) * time.Minute)),
+		}
+	}
+	history := func(from, to int) []v1alpha1.ReleasePayloadStatusSnapshot {
+		var snapshots []v1alpha1.ReleasePayloadStatusSnapshot
+		for i := from; i < to; i++ {
+			snapshots = append(snapshots, snapshot(i))
+		}
+		return snapshots
+	}
+
+	testCases := []struct {
+		name     string
+		history  []v1alpha1.ReleasePayloadStatusSnapshot
+		expected []v1alpha1.ReleasePayloadStatusSnapshot
+	}{
+		{
+			name:     "EmptyHistory",
+			expected: history(0, 1),
+		},
+		{
+			name:     "HistoryBelowLimit",
+			history:  history(0, 5),
+			expected: history(0, 6),
+		},
+		{
+			name:     "HistoryReachesLimit",
+			history:  history(0, v1alpha1.StatusHistoryLimit-1),
+			expected: history(0, v1alpha1.StatusHistoryLimit),
+		},
+		{
+			name:     "OldestEvicted",
+			history:  history(0, v1alpha1.StatusHistoryLimit),
+			expected: history(1, v1alpha1.StatusHistoryLimit+1),
+		},
+		{
+			name:     "HistoryAboveLimit",
+			history:  history(0, v1alpha1.StatusHistoryLimit+3),
+			expected: history(4, v1alpha1.StatusHistoryLimit+4),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			next := snapshot(len(testCase.history))
+			releasePayload := &v1alpha1.ReleasePayload{
+				Status: v1alpha1.ReleasePayloadStatus{
+					ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+						Status:  next.Status,
+						Message: next.Message,
+					},
+					StatusHistory: testCase.history,
+				},
+			}
+			AppendStatusHistory(releasePayload, next.TransitionTime)
+			if !reflect.DeepEqual(releasePayload.Status.StatusHistory, testCase.expected) {
+				t.Errorf("%s: Expected %v, got %v", testCase.name, testCase.expected, releasePayload.Status.StatusHistory)
+			}
+		})
+	}
+}
+
+func TestAppendStatusHistoryWithJobLogs(t *testing.T) {
+	transitionTime := metav1.Date(2022, 2, 9, 9, 15, 59, 0, time.UTC)
+	releasePayload := &v1alpha1.ReleasePayload{
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Status:  v1alpha1.ReleaseCreationJobFailed,
+				Message: "BackoffLimitExceeded: Job has reached the specified backoff limit\nerror: unable to push image\nexit status 1",
+			},
+		},
+	}
+	AppendStatusHistory(releasePayload, transitionTime)
+
+	// The logs, of the failed job, are only kept on the ReleaseCreationJobResult
+	expected := []v1alpha1.ReleasePayloadStatusSnapshot{
+		{
+			Status:         v1alpha1.ReleaseCreationJobFailed,
+			Message:        "BackoffLimitExceeded: Job has reached the specified backoff limit",
+			TransitionTime: transitionTime,
+		},
+	}
+	if !reflect.DeepEqual(releasePayload.Status.StatusHistory, expected) {
+		t.Errorf("Expected %v, got %v", expected, releasePayload.Status.StatusHistory)
+	}
+}
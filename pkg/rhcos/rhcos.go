@@ -13,6 +13,9 @@ import (
 const (
 	rhelCoreOs         = "Red Hat Enterprise Linux CoreOS"
 	centosStreamCoreOs = "CentOS Stream CoreOS"
+
+	// multiArchitecture is the architecture of manifest list images, whose RHCOS versions can not be diffed
+	multiArchitecture = "multi"
 )
 
 var (
@@ -32,7 +35,9 @@ var (
 	reCoreOsVersion = regexp.MustCompile(`((\d)(\d+))\.(\d+)\.(\d+)-(\d+)`)
 )
 
-func TransformMarkDownOutput(markdown, fromTag, toTag, architecture, architectureExtension string) (string, error) {
+// TransformMarkDownOutput decorates the markdown changelog with links.  Links to other releases are prefixed with
+// basePath, so they resolve when the release-controller-api is served from behind a path-prefixing proxy.
+func TransformMarkDownOutput(markdown, fromTag, toTag, basePath, architecture, architectureExtension string) (string, error) {
 	// replace references to the previous version with links
 	rePrevious, err := regexp.Compile(fmt.Sprintf(`([^\w:])%s(\W)`, regexp.QuoteMeta(fromTag)))
 	if err != nil {
@@ -44,10 +49,10 @@ func TransformMarkDownOutput(markdown, fromTag, toTag, architecture, architectur
 	if changed := strings.Replace(markdown, fmt.Sprintf(`## Changes from %s`, fromTag), "", -1); len(changed) != len(markdown) {
 		markdown = fmt.Sprintf("## Changes from %s\n%s", fromTag, changed)
 	}
-	markdown = rePrevious.ReplaceAllString(markdown, fmt.Sprintf("$1[%s](/releasetag/%s)$2", fromTag, fromTag))
+	markdown = rePrevious.ReplaceAllString(markdown, fmt.Sprintf("$1[%s](%s/releasetag/%s)$2", fromTag, basePath, fromTag))
 
 	// add link to tag from which current version promoted from
-	markdown = reMdPromotedFrom.ReplaceAllString(markdown, fmt.Sprintf("Release %s was created from [$1:$2](/releasetag/$2)", toTag))
+	markdown = reMdPromotedFrom.ReplaceAllString(markdown, fmt.Sprintf("Release %s was created from [$1:$2](%s/releasetag/$2)", toTag, basePath))
 
 	// TODO: As we get more comfortable with these sorts of transformations, we could make them more generic.
 	//       For now, this will have to do.
@@ -87,7 +92,7 @@ func TransformJsonOutput(output, architecture, architectureExtension string) (st
 					Fragment: component.Version,
 					RawQuery: (url.Values{
 						"stream":  []string{toStream},
-						"arch":    []string{architecture},
+						"arch":    []string{streamArchitecture(architecture)},
 						"release": []string{component.Version},
 					}).Encode(),
 				}
@@ -103,25 +108,28 @@ func TransformJsonOutput(output, architecture, architectureExtension string) (st
 						Fragment: component.From,
 						RawQuery: (url.Values{
 							"stream":  []string{fromStream},
-							"arch":    []string{architecture},
+							"arch":    []string{streamArchitecture(architecture)},
 							"release": []string{component.From},
 						}).Encode(),
 					}
 					component.FromUrl = fromUrl.String()
 
-					diffURL := url.URL{
-						Scheme: serviceScheme,
-						Host:   serviceUrl,
-						Path:   "/diff.html",
-						RawQuery: (url.Values{
-							"first_stream":   []string{fromStream},
-							"first_release":  []string{component.From},
-							"second_stream":  []string{toStream},
-							"second_release": []string{component.Version},
-							"arch":           []string{architecture},
-						}).Encode(),
+					// RHCOS versions of manifest list images can not be diffed
+					if architecture != multiArchitecture {
+						diffURL := url.URL{
+							Scheme: serviceScheme,
+							Host:   serviceUrl,
+							Path:   "/diff.html",
+							RawQuery: (url.Values{
+								"first_stream":   []string{fromStream},
+								"first_release":  []string{component.From},
+								"second_stream":  []string{toStream},
+								"second_release": []string{component.Version},
+								"arch":           []string{architecture},
+							}).Encode(),
+						}
+						component.DiffUrl = diffURL.String()
 					}
-					component.DiffUrl = diffURL.String()
 				}
 			}
 			changeLogJson.Components[i] = component
@@ -136,6 +144,15 @@ func TransformJsonOutput(output, architecture, architectureExtension string) (st
 	return string(updated), nil
 }
 
+// streamArchitecture returns the architecture of the RHCOS stream that the versions are linked to.  Manifest list
+// images are linked to the unsuffixed stream, which is that of x86_64, so their versions are as well.
+func streamArchitecture(architecture string) string {
+	if architecture == multiArchitecture {
+		return "x86_64"
+	}
+	return architecture
+}
+
 func getRHCoSReleaseStream(version, architectureExtension string) (string, bool) {
 	if m := reCoreOsVersion.FindStringSubmatch(version); m != nil {
 		ts, err := strconv.Atoi(m[5])
@@ -174,7 +191,7 @@ func transformCoreOSUpgradeLinks(name, architecture, architectureExtension, inpu
 			Fragment: fromRelease,
 			RawQuery: (url.Values{
 				"stream":  []string{fromStream},
-				"arch":    []string{architecture},
+				"arch":    []string{streamArchitecture(architecture)},
 				"release": []string{fromRelease},
 			}).Encode(),
 		}
@@ -189,11 +206,22 @@ func transformCoreOSUpgradeLinks(name, architecture, architectureExtension, inpu
 			Fragment: toRelease,
 			RawQuery: (url.Values{
 				"stream":  []string{toStream},
-				"arch":    []string{architecture},
+				"arch":    []string{streamArchitecture(architecture)},
 				"release": []string{toRelease},
 			}).Encode(),
 		}
 	}
+	if architecture == multiArchitecture {
+		replace := fmt.Sprintf(
+			`* %s upgraded from [%s](%s) to [%s](%s)`+"\n",
+			name,
+			fromRelease,
+			fromURL.String(),
+			toRelease,
+			toURL.String(),
+		)
+		return strings.ReplaceAll(input, matches[0], replace)
+	}
 	diffURL := url.URL{
 		Scheme: serviceScheme,
 		Host:   serviceUrl,
@@ -232,7 +260,7 @@ func transformCoreOSLinks(name, architecture, architectureExtension, input strin
 			Fragment: fromRelease,
 			RawQuery: (url.Values{
 				"stream":  []string{fromStream},
-				"arch":    []string{architecture},
+				"arch":    []string{streamArchitecture(architecture)},
 				"release": []string{fromRelease},
 			}).Encode(),
 		}
@@ -1,8 +1,10 @@
 package rhcos
 
 import (
-	"github.com/google/go-cmp/cmp"
+	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestComputeJobState(t *testing.T) {
@@ -94,3 +96,85 @@ func TestComputeJobState(t *testing.T) {
 		})
 	}
 }
+
+func TestTransformMarkDownOutputBasePath(t *testing.T) {
+	markdown := "# 4.14.1\n\nCreated: 2023-06-01 00:00:00 +0000 UTC\n\nImage Digest: `sha256:abc`\nPromoted from registry.ci.openshift.org/ocp/release:4.14.0-0.nightly-2023-06-01-000000\n\n## Changes from 4.14.0\n\nUpgrade from 4.14.0 is supported.\n"
+	testCases := []struct {
+		name     string
+		basePath string
+		expected []string
+	}{
+		{
+			name:     "NoBasePath",
+			basePath: "",
+			expected: []string{
+				"[4.14.0](/releasetag/4.14.0)",
+				"[registry.ci.openshift.org/ocp/release:4.14.0-0.nightly-2023-06-01-000000](/releasetag/4.14.0-0.nightly-2023-06-01-000000)",
+			},
+		},
+		{
+			name:     "BasePath",
+			basePath: "/release-controller",
+			expected: []string{
+				"[4.14.0](/release-controller/releasetag/4.14.0)",
+				"[registry.ci.openshift.org/ocp/release:4.14.0-0.nightly-2023-06-01-000000](/release-controller/releasetag/4.14.0-0.nightly-2023-06-01-000000)",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := TransformMarkDownOutput(markdown, "4.14.0", "4.14.1", tc.basePath, "amd64", "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, s := range tc.expected {
+				if !strings.Contains(out, s) {
+					t.Errorf("expected output to contain %q, got: %s", s, out)
+				}
+			}
+		})
+	}
+}
+
+func TestTransformJsonOutputMultiArchitecture(t *testing.T) {
+	output := `{"from":{"name":"4.10.0"},"to":{"name":"4.10.1"},"components":[{"name":"Red Hat Enterprise Linux CoreOS","version":"410.84.202206011234-0","from":"410.84.202205191234-0"}]}`
+	testCases := []struct {
+		name               string
+		architecture       string
+		extension          string
+		expectArchitecture string
+		expectDiff         bool
+	}{
+		{
+			name:               "x86_64",
+			architecture:       "x86_64",
+			expectArchitecture: "x86_64",
+			expectDiff:         true,
+		},
+		{
+			// The versions are linked to the unsuffixed stream, of x86_64, like the stream itself
+			name:               "multi",
+			architecture:       "multi",
+			expectArchitecture: "x86_64",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := TransformJsonOutput(output, tc.architecture, tc.extension)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, s := range []string{
+				"https://releases-rhcos-art.apps.ocp-virt.prod.psi.redhat.com/?arch=" + tc.expectArchitecture + "\\u0026release=410.84.202205191234-0\\u0026stream=releases%2Frhcos-4.10#410.84.202205191234-0",
+				"https://releases-rhcos-art.apps.ocp-virt.prod.psi.redhat.com/?arch=" + tc.expectArchitecture + "\\u0026release=410.84.202206011234-0\\u0026stream=releases%2Frhcos-4.10#410.84.202206011234-0",
+			} {
+				if !strings.Contains(out, s) {
+					t.Errorf("expected output to contain %q, got: %s", s, out)
+				}
+			}
+			if hasDiff := strings.Contains(out, "diff.html"); hasDiff != tc.expectDiff {
+				t.Errorf("expected diff url: %t, got: %s", tc.expectDiff, out)
+			}
+		})
+	}
+}
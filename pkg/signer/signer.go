@@ -42,6 +42,14 @@ func NewFromKeyring(path string) (Interface, error) {
 	if err != nil {
 		return nil, err
 	}
+	name := filepath.Base(path)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return NewFromKeyringData(name, data)
+}
+
+// NewFromKeyringData returns a signer for the armored, or unarmored, GPG keyring in data.  The name identifies the
+// keyring when describing the signer.
+func NewFromKeyringData(name string, data []byte) (Interface, error) {
 	keyring, err := loadArmoredOrUnarmoredGPGKeyRing(data)
 	if err != nil {
 		return nil, err
@@ -56,8 +64,6 @@ func NewFromKeyring(path string) (Interface, error) {
 	if signer == nil {
 		return nil, fmt.Errorf("the provided keyring must contain a private key capable of signing")
 	}
-	name := filepath.Base(path)
-	name = strings.TrimSuffix(name, filepath.Ext(name))
 	return &releaseSigner{
 		signer: signer,
 		verifiers: map[string]openpgp.EntityList{
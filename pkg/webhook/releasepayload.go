@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ValidateReleasePayloadPath is the path that the ReleasePayload ValidatingAdmissionWebhook is served on
+const ValidateReleasePayloadPath = "/validate-release-openshift-io-v1alpha1-releasepayload"
+
+// ReleaseTagPattern matches the release tags that are safe to be used as label values (i.e.
+// 4.11.0-0.nightly-2022-02-09-091559)
+const ReleaseTagPattern = `^[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$`
+
+var reReleaseTag = regexp.MustCompile(ReleaseTagPattern)
+
+// ReleasePayloadValidator rejects the ReleasePayloads whose ReleaseCreationJobCoordinates can never resolve to a
+// release creation job, instead of leaving the release-payload-controller to retry them indefinitely:
+//   - .status.releaseCreationJobResult.coordinates.name must be a label-safe release tag
+//   - .status.releaseCreationJobResult.coordinates.namespace must exist
+type ReleasePayloadValidator struct {
+	namespaceClient corev1client.NamespacesGetter
+}
+
+var _ admission.Handler = &ReleasePayloadValidator{}
+
+func NewReleasePayloadValidator(namespaceClient corev1client.NamespacesGetter) *ReleasePayloadValidator {
+	return &ReleasePayloadValidator{
+		namespaceClient: namespaceClient,
+	}
+}
+
+func (v *ReleasePayloadValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	releasePayload := &v1alpha1.ReleasePayload{}
+	if err := json.Unmarshal(req.Object.Raw, releasePayload); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("unable to decode ReleasePayload: %w", err))
+	}
+
+	coordinates := releasePayload.Status.ReleaseCreationJobResult.Coordinates
+	if len(coordinates.Name) > 0 && !reReleaseTag.MatchString(coordinates.Name) {
+		return admission.Denied(fmt.Sprintf("release creation job name %q is not a valid release tag", coordinates.Name))
+	}
+	if len(coordinates.Name) == 0 || len(coordinates.Namespace) == 0 {
+		return admission.Allowed("")
+	}
+
+	_, err := v.namespaceClient.Namespaces().Get(ctx, coordinates.Namespace, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return admission.Denied(fmt.Sprintf("release creation job namespace %q does not exist", coordinates.Namespace))
+	case err != nil:
+		klog.Errorf("Unable to lookup namespace %s, of ReleasePayload %s/%s: %v", coordinates.Namespace, releasePayload.Namespace, releasePayload.Name, err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.Allowed("")
+}
+
+// NewServer returns the webhook server, serving the ReleasePayloadValidator, on the port.  The serving certificate,
+// tls.crt and tls.key, is read from the certDir.
+func NewServer(port int, certDir string, namespaceClient corev1client.NamespacesGetter) ctrlwebhook.Server {
+	server := ctrlwebhook.NewServer(ctrlwebhook.Options{
+		Port:    port,
+		CertDir: certDir,
+	})
+	server.Register(ValidateReleasePayloadPath, &ctrlwebhook.Admission{Handler: NewReleasePayloadValidator(namespaceClient)})
+	return server
+}
@@ -0,0 +1,152 @@
+//go:build e2e
+// +build e2e
+
+package webhook
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	releasepayloadclient "github.com/openshift/release-controller/pkg/client/clientset/versioned"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+// TestReleasePayloadValidatorE2E registers the ReleasePayloadValidator, with a real API server started by envtest, and
+// verifies that the ReleasePayloads with invalid release creation job coordinates are rejected.
+//
+// Run with: make test-e2e
+func TestReleasePayloadValidatorE2E(t *testing.T) {
+	failurePolicy := admissionregistrationv1.Fail
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "artifacts")},
+		ErrorIfCRDPathMissing: true,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			ValidatingWebhooks: []*admissionregistrationv1.ValidatingWebhookConfiguration{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "release-payload-validator"},
+					Webhooks: []admissionregistrationv1.ValidatingWebhook{
+						{
+							Name:                    "releasepayloads.release.openshift.io",
+							AdmissionReviewVersions: []string{"v1"},
+							FailurePolicy:           &failurePolicy,
+							SideEffects:             &sideEffects,
+							ClientConfig: admissionregistrationv1.WebhookClientConfig{
+								Service: &admissionregistrationv1.ServiceReference{
+									Name:      "release-payload-controller",
+									Namespace: "ci",
+									// envtest joins the path to the address of the webhook server with a "/"
+									Path: pointer.String(strings.TrimPrefix(ValidateReleasePayloadPath, "/")),
+								},
+							},
+							Rules: []admissionregistrationv1.RuleWithOperations{
+								{
+									Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+									Rule: admissionregistrationv1.Rule{
+										APIGroups:   []string{v1alpha1.SchemeGroupVersion.Group},
+										APIVersions: []string{v1alpha1.SchemeGroupVersion.Version},
+										Resources:   []string{"releasepayloads", "releasepayloads/status"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		t.Fatalf("unable to start the test environment: %v", err)
+	}
+	defer func() {
+		if err := testEnv.Stop(); err != nil {
+			t.Errorf("unable to stop the test environment: %v", err)
+		}
+	}()
+
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		t.Fatalf("unable to create kubernetes client: %v", err)
+	}
+	releasePayloadClient, err := releasepayloadclient.NewForConfig(cfg)
+	if err != nil {
+		t.Fatalf("unable to create releasepayload client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, namespace := range []string{"ocp", "ci-release"} {
+		if _, err := kubeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("unable to create namespace %s: %v", namespace, err)
+		}
+	}
+
+	// Serve the webhook
+	server := NewServer(testEnv.WebhookInstallOptions.LocalServingPort, testEnv.WebhookInstallOptions.LocalServingCertDir, kubeClient.CoreV1())
+	go func() {
+		if err := server.Start(ctx); err != nil {
+			t.Errorf("unable to start the webhook server: %v", err)
+		}
+	}()
+	gomega.NewWithT(t).Eventually(func() error {
+		return server.StartedChecker()(nil)
+	}, 30*time.Second, time.Second).Should(gomega.Succeed())
+
+	testCases := []struct {
+		name        string
+		release     string
+		coordinates v1alpha1.ReleaseCreationJobCoordinates
+		expectError bool
+	}{
+		{
+			name:    "ValidCoordinates",
+			release: "4.11.0-0.nightly-2022-02-09-091559",
+			coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+				Name:      "4.11.0-0.nightly-2022-02-09-091559",
+				Namespace: "ci-release",
+			},
+		},
+		{
+			name:    "NamespaceDoesNotExist",
+			release: "4.11.0-0.nightly-2022-02-09-101559",
+			coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+				Name:      "4.11.0-0.nightly-2022-02-09-101559",
+				Namespace: "ci-release-missing",
+			},
+			expectError: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			payload, err := releasePayloadClient.ReleaseV1alpha1().ReleasePayloads("ocp").Create(ctx, &v1alpha1.ReleasePayload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testCase.release,
+					Namespace: "ocp",
+				},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				t.Fatalf("unable to create releasepayload: %v", err)
+			}
+			payload.Status.ReleaseCreationJobResult.Coordinates = testCase.coordinates
+			_, err = releasePayloadClient.ReleaseV1alpha1().ReleasePayloads("ocp").UpdateStatus(ctx, payload, metav1.UpdateOptions{})
+			if testCase.expectError && err == nil {
+				t.Errorf("Expected the status update to be rejected")
+			}
+			if !testCase.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
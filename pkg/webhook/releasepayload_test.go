@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift/release-controller/pkg/apis/release/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newAdmissionRequest(t *testing.T, operation admissionv1.Operation, coordinates v1alpha1.ReleaseCreationJobCoordinates) admission.Request {
+	t.Helper()
+	raw, err := json.Marshal(&v1alpha1.ReleasePayload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "4.11.0-0.nightly-2022-02-09-091559",
+			Namespace: "ocp",
+		},
+		Status: v1alpha1.ReleasePayloadStatus{
+			ReleaseCreationJobResult: v1alpha1.ReleaseCreationJobResult{
+				Coordinates: coordinates,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to encode ReleasePayload: %v", err)
+	}
+	return admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: operation,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+}
+
+func TestReleasePayloadValidator(t *testing.T) {
+	testCases := []struct {
+		name         string
+		operation    admissionv1.Operation
+		coordinates  v1alpha1.ReleaseCreationJobCoordinates
+		lookupError  error
+		expected     bool
+		expectedCode int32
+	}{
+		{
+			name:      "NoCoordinates",
+			operation: admissionv1.Create,
+			expected:  true,
+		},
+		{
+			name:      "ValidCoordinates",
+			operation: admissionv1.Create,
+			coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+				Name:      "4.11.0-0.nightly-2022-02-09-091559",
+				Namespace: "ci-release",
+			},
+			expected: true,
+		},
+		{
+			name:      "NamespaceDoesNotExist",
+			operation: admissionv1.Update,
+			coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+				Name:      "4.11.0-0.nightly-2022-02-09-091559",
+				Namespace: "ci-release-missing",
+			},
+			expected:     false,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:      "NameNotAReleaseTag",
+			operation: admissionv1.Update,
+			coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+				Name:      "4.11.0-0.nightly-2022-02-09-091559-",
+				Namespace: "ci-release",
+			},
+			expected:     false,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:      "NameWithoutNamespace",
+			operation: admissionv1.Create,
+			coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+				Name: "4.11.0-0.nightly-2022-02-09-091559",
+			},
+			expected: true,
+		},
+		{
+			name:      "NamespaceLookupFailed",
+			operation: admissionv1.Create,
+			coordinates: v1alpha1.ReleaseCreationJobCoordinates{
+				Name:      "4.11.0-0.nightly-2022-02-09-091559",
+				Namespace: "ci-release",
+			},
+			lookupError:  fmt.Errorf("connection refused"),
+			expected:     false,
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name:      "Delete",
+			operation: admissionv1.Delete,
+			expected:  true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ci-release"}})
+			if testCase.lookupError != nil {
+				kubeClient.PrependReactor("get", "namespaces", func(action clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, testCase.lookupError
+				})
+			}
+			v := NewReleasePayloadValidator(kubeClient.CoreV1())
+
+			response := v.Handle(context.TODO(), newAdmissionRequest(t, testCase.operation, testCase.coordinates))
+			if response.Allowed != testCase.expected {
+				t.Fatalf("%s: Expected allowed %t, got %t: %v", testCase.name, testCase.expected, response.Allowed, response.Result)
+			}
+			if !testCase.expected && response.Result.Code != testCase.expectedCode {
+				t.Errorf("%s: Expected code %d, got %d", testCase.name, testCase.expectedCode, response.Result.Code)
+			}
+		})
+	}
+}